@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/ansible"
+	"github.com/florianriquelme/ssherpa/internal/backend/bitwarden"
+	"github.com/florianriquelme/ssherpa/internal/backend/gitrepo"
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/backend/passstore"
+	"github.com/florianriquelme/ssherpa/internal/backend/vault"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+)
+
+// paths holds the well-known file locations under ~/.ssh used by ssherpa.
+type paths struct {
+	sshConfig      string // ~/.ssh/config
+	history        string // ~/.ssh/ssherpa_history.json
+	opCache        string // ~/.ssh/ssherpa_1password_cache.toml
+	bwCache        string // ~/.ssh/ssherpa_bitwarden_cache.toml
+	vaultCache     string // ~/.ssh/ssherpa_vault_cache.toml
+	sshIncludeFile string // ~/.ssh/ssherpa_config (generated from 1Password)
+	sshKeyDir      string // ~/.ssh/ssherpa_keys (public keys of 1Password SSH Key items)
+}
+
+// newPaths derives all ssherpa file locations from the user's home directory.
+func newPaths(homeDir string) paths {
+	sshDir := filepath.Join(homeDir, ".ssh")
+	return paths{
+		sshConfig:      filepath.Join(sshDir, "config"),
+		history:        filepath.Join(sshDir, "ssherpa_history.json"),
+		opCache:        filepath.Join(sshDir, "ssherpa_1password_cache.toml"),
+		bwCache:        filepath.Join(sshDir, "ssherpa_bitwarden_cache.toml"),
+		vaultCache:     filepath.Join(sshDir, "ssherpa_vault_cache.toml"),
+		sshIncludeFile: filepath.Join(sshDir, "ssherpa_config"),
+		sshKeyDir:      filepath.Join(sshDir, "ssherpa_keys"),
+	}
+}
+
+// openBackend constructs the backend stack for the configured backend type,
+// with the Ansible inventory and the git team inventory merged in when
+// configured.
+// Returns the 1Password backend separately (nil if not configured) so callers
+// can start polling or refresh the cache after writes.
+func openBackend(cfg *config.Config, p paths) (backendpkg.Backend, *onepassword.Backend, error) {
+	backend, opBackend, err := openPrimaryBackend(cfg, p)
+	if err != nil || (cfg.Git.Repo == "" && cfg.Ansible.Inventory == "") {
+		return backend, opBackend, err
+	}
+
+	backends := []backendpkg.Backend{backend}
+	if multi, ok := backend.(*backendpkg.MultiBackend); ok {
+		backends = multi.Backends()
+	}
+
+	// The Ansible inventory goes first: the configured backend wins
+	// duplicate names, and the read-only inventory never takes writes
+	if cfg.Ansible.Inventory != "" {
+		ansibleBackend, err := ansible.New(expandHome(cfg.Ansible.Inventory))
+		if err != nil {
+			_ = backend.Close()
+			return nil, nil, fmt.Errorf("opening Ansible inventory: %w", err)
+		}
+		backends = append([]backendpkg.Backend{ansibleBackend}, backends...)
+	}
+
+	// The git inventory goes last: it wins duplicate names. New entries keep
+	// going to the first Writer, the configured backend; edits and removals
+	// go to the backend that holds the server
+	if cfg.Git.Repo != "" {
+		gitBackend, err := newGitBackend(cfg.Git)
+		if err != nil {
+			_ = backendpkg.NewMultiBackend(backends...).Close()
+			return nil, nil, err
+		}
+		backends = append(backends, gitBackend)
+	}
+	return backendpkg.NewMultiBackend(backends...), opBackend, nil
+}
+
+// openPrimaryBackend constructs the backend stack for cfg.Backend.
+func openPrimaryBackend(cfg *config.Config, p paths) (backendpkg.Backend, *onepassword.Backend, error) {
+	switch cfg.Backend {
+	case "sshconfig":
+		// Pure SSH config backend
+		sshBackend, err := newSSHConfigBackend(cfg, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sshBackend, nil, nil
+
+	case "onepassword":
+		opBackend, err := newOnePasswordBackend(cfg, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return opBackend, opBackend, nil
+
+	case "both":
+		// Multi-backend: SSH config + 1Password
+		sshBackend, err := newSSHConfigBackend(cfg, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		opBackend, err := newOnePasswordBackend(cfg, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return backendpkg.NewMultiBackend(sshBackend, opBackend), opBackend, nil
+
+	case "bitwarden":
+		bwBackend, err := newBitwardenBackend(cfg, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bwBackend, nil, nil
+
+	case "pass":
+		passBackend, err := newPassBackend(cfg.Pass)
+		if err != nil {
+			return nil, nil, err
+		}
+		return passBackend, nil, nil
+
+	case "vault":
+		vaultBackend, err := newVaultBackend(cfg, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return vaultBackend, nil, nil
+
+	default:
+		return nil, nil, fmt.Errorf("backend '%s' not supported. Valid options: sshconfig, onepassword, both, bitwarden, pass, vault", cfg.Backend)
+	}
+}
+
+// newSSHConfigBackend parses ~/.ssh/config and applies the [[host]] metadata
+// (favorites, VPN flags, tags) and project membership stored in the ssherpa config.
+func newSSHConfigBackend(cfg *config.Config, p paths) (*sshconfig.Backend, error) {
+	sshBackend, err := sshconfig.New(p.sshConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating SSH config backend: %w", err)
+	}
+
+	metadata := make(map[string]sshconfig.HostMetadata, len(cfg.Hosts))
+	for _, h := range cfg.Hosts {
+		meta := metadata[strings.ToLower(h.Alias)]
+		meta.Favorite = h.Favorite
+		meta.VPNRequired = h.VPNRequired
+		meta.Tags = h.Tags
+		metadata[strings.ToLower(h.Alias)] = meta
+	}
+	for _, proj := range cfg.Projects {
+		for _, alias := range proj.ServerNames {
+			meta := metadata[strings.ToLower(alias)]
+			meta.ProjectIDs = append(meta.ProjectIDs, proj.ID)
+			metadata[strings.ToLower(alias)] = meta
+		}
+	}
+	sshBackend.SetMetadata(metadata)
+
+	return sshBackend, nil
+}
+
+// newOnePasswordBackend creates the 1Password backend with its TOML cache loaded.
+func newOnePasswordBackend(cfg *config.Config, p paths) (*onepassword.Backend, error) {
+	client, err := newOnePasswordClient(cfg.OnePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	opBackend := onepassword.NewWithCache(client, p.opCache)
+	opBackend.SetVaultFilter(cfg.OnePassword.IncludeVaults, cfg.OnePassword.ExcludeVaults)
+	opBackend.SetDefaultVault(cfg.OnePassword.DefaultVault, cfg.ProjectVaults())
+
+	// Load from cache (best-effort, non-fatal) - cached data is shown instantly
+	_ = opBackend.LoadFromCache()
+
+	return opBackend, nil
+}
+
+// newOnePasswordClient creates the configured 1Password client: the op CLI
+// (default) or a 1Password Connect server.
+func newOnePasswordClient(cfg config.OnePasswordConfig) (onepassword.Client, error) {
+	if cfg.Client == "connect" {
+		client, err := onepassword.NewConnectClient(cfg.ConnectURL, cfg.ConnectToken)
+		if err != nil {
+			return nil, fmt.Errorf("creating 1Password Connect client: %w", err)
+		}
+		return client, nil
+	}
+
+	var client *onepassword.CLIClient
+	var err error
+	if cfg.AccountName != "" {
+		client, err = onepassword.NewCLIClientWithAccount(cfg.AccountName)
+	} else {
+		client, err = onepassword.NewCLIClient()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating 1Password CLI client: %w", err)
+	}
+	return client, nil
+}
+
+// newBitwardenBackend creates the Bitwarden backend scoped to the configured
+// folder or collection, with its TOML cache loaded.
+func newBitwardenBackend(cfg *config.Config, p paths) (*bitwarden.Backend, error) {
+	client, err := bitwarden.NewCLIClient()
+	if err != nil {
+		return nil, fmt.Errorf("creating Bitwarden CLI client: %w", err)
+	}
+
+	cachePath := p.bwCache
+	if cfg.Bitwarden.CachePath != "" {
+		cachePath = cfg.Bitwarden.CachePath
+	}
+
+	bwBackend := bitwarden.NewWithCache(client, cachePath)
+	bwBackend.SetScope(cfg.Bitwarden.Folder, cfg.Bitwarden.Collection)
+
+	// Load from cache (best-effort, non-fatal) - cached data is shown instantly
+	_ = bwBackend.LoadFromCache()
+
+	return bwBackend, nil
+}
+
+// newVaultBackend creates the Vault KV backend and loads its TOML cache.
+// The first sync happens in the background (TUI) or on demand (CLI).
+func newVaultBackend(cfg *config.Config, p paths) (*vault.Backend, error) {
+	client, err := vault.NewHTTPClient(vault.Options{
+		Address:      cfg.Vault.Address,
+		Namespace:    cfg.Vault.Namespace,
+		Mount:        cfg.Vault.Mount,
+		Token:        cfg.Vault.Token,
+		RoleID:       cfg.Vault.RoleID,
+		SecretID:     cfg.Vault.SecretID,
+		AppRoleMount: cfg.Vault.AppRoleMount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating Vault client: %w", err)
+	}
+
+	cachePath := p.vaultCache
+	if cfg.Vault.CachePath != "" {
+		cachePath = cfg.Vault.CachePath
+	}
+
+	vaultBackend := vault.NewWithCache(client, cfg.Vault.Path, cachePath)
+
+	// Load from cache (best-effort, non-fatal) - cached data is shown instantly
+	_ = vaultBackend.LoadFromCache()
+
+	return vaultBackend, nil
+}
+
+// newPassBackend reads the servers under the configured prefix of a pass or
+// gopass store. Entries are decrypted up front, so gpg-agent may ask for the
+// passphrase before the TUI starts.
+func newPassBackend(cfg config.PassConfig) (*passstore.Backend, error) {
+	client, err := passstore.NewCLIClient(cfg.Command, expandHome(cfg.StoreDir))
+	if err != nil {
+		return nil, fmt.Errorf("creating password store client: %w", err)
+	}
+
+	passBackend := passstore.New(client, cfg.Prefix)
+	if err := passBackend.Load(context.Background()); err != nil {
+		return nil, fmt.Errorf("reading password store: %w", err)
+	}
+	return passBackend, nil
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// newGitBackend opens the team inventory in a git clone.
+func newGitBackend(cfg config.GitConfig) (*gitrepo.Backend, error) {
+	gitBackend, err := gitrepo.New(expandHome(cfg.Repo), cfg.Files)
+	if err != nil {
+		return nil, fmt.Errorf("opening git inventory: %w", err)
+	}
+	gitBackend.SetAutoCommit(cfg.AutoCommit)
+	if cfg.DefaultFile != "" {
+		gitBackend.SetDefaultFile(cfg.DefaultFile)
+	}
+	return gitBackend, nil
+}
+
+// findBackend returns the backend of type T, looking inside a MultiBackend.
+func findBackend[T backendpkg.Backend](backend backendpkg.Backend) (T, bool) {
+	if found, ok := backend.(T); ok {
+		return found, true
+	}
+	if multi, ok := backend.(*backendpkg.MultiBackend); ok {
+		for _, b := range multi.Backends() {
+			if found, ok := b.(T); ok {
+				return found, true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
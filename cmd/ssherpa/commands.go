@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/bitwarden"
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/backend/vault"
+	"github.com/florianriquelme/ssherpa/internal/cli"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+	"github.com/florianriquelme/ssherpa/internal/tui"
+)
+
+// runCommand executes a non-interactive subcommand and returns the exit code.
+// Unlike the TUI it never runs the setup wizard: scripts must not block on input.
+func runCommand(cfg *config.Config, p paths, args []string) int {
+	// help needs no backend, so it works on a fresh install too
+	if args[0] == "help" {
+		app := &cli.App{Stdout: os.Stdout, Stderr: os.Stderr}
+		return app.Run(context.Background(), args)
+	}
+
+	if cfg == nil || cfg.Backend == "" {
+		fmt.Fprintln(os.Stderr, "No backend configured. Run 'ssherpa --setup' to configure.")
+		return cli.ExitError
+	}
+
+	backend, opBackend, err := openBackend(cfg, p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.ExitError
+	}
+	defer func() { _ = backend.Close() }()
+
+	ctx := context.Background()
+
+	// Without a cache there is nothing to show yet: sync once in the foreground
+	// (sync and migrate do that themselves below)
+	if opBackend != nil && args[0] != "sync" && args[0] != "migrate" {
+		if servers, _ := opBackend.ListServers(ctx); len(servers) == 0 {
+			if err := opBackend.SyncFromOnePassword(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not sync from 1Password (%s)\n", opBackend.GetStatus())
+			}
+		}
+	}
+	if bwBackend, ok := findBackend[*bitwarden.Backend](backend); ok {
+		if servers, _ := bwBackend.ListServers(ctx); len(servers) == 0 {
+			if err := bwBackend.SyncFromBitwarden(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not sync from Bitwarden (%s)\n", bwBackend.GetStatus())
+			}
+		}
+	}
+	if vaultBackend, ok := findBackend[*vault.Backend](backend); ok {
+		if servers, _ := vaultBackend.ListServers(ctx); len(servers) == 0 {
+			if err := vaultBackend.SyncFromVault(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not sync from Vault (%s)\n", vaultBackend.GetStatus())
+			}
+		}
+	}
+
+	app := &cli.App{
+		Backend:     backend,
+		HistoryPath: p.history,
+		Config:      cfg,
+		SSHConfig:   p.sshConfig,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	}
+	if opBackend != nil {
+		app.SSHInclude = p.sshIncludeFile
+		app.AfterWrite = func(ctx context.Context) error {
+			return refreshOnePasswordFiles(ctx, opBackend, p)
+		}
+		app.Sync = func(ctx context.Context) (onepassword.SyncStats, error) {
+			return syncOnePassword(ctx, opBackend, p)
+		}
+		app.Migrate = func(ctx context.Context) ([]onepassword.MigrationResult, error) {
+			return migrateOnePassword(ctx, cfg.OnePassword, opBackend, p)
+		}
+		app.OnePassword = opBackend
+		app.PushWizard = func(ctx context.Context) ([]sync.PushResult, error) {
+			return pushToOnePassword(ctx, opBackend, p)
+		}
+		app.SkippedItems = func(ctx context.Context) []onepassword.SkippedItem {
+			// Validation errors are only known after a sync, not from the cache
+			if err := opBackend.SyncFromOnePassword(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not sync from 1Password (%s)\n", opBackend.GetStatus())
+			}
+			return opBackend.SkippedItems()
+		}
+	}
+
+	return app.Run(ctx, args)
+}
+
+// refreshOnePasswordFiles rewrites the TOML cache and SSH include file after a write,
+// so "ssh <alias>" and the next TUI start see the change without waiting for a poll.
+func refreshOnePasswordFiles(ctx context.Context, opBackend *onepassword.Backend, p paths) error {
+	servers, err := opBackend.ListServers(ctx)
+	if err != nil {
+		return err
+	}
+	if err := sync.WriteTOMLCache(servers, p.opCache); err != nil {
+		return fmt.Errorf("writing 1Password cache: %w", err)
+	}
+	if err := writeOnePasswordInclude(ctx, opBackend, servers, p); err != nil {
+		return err
+	}
+	return sync.EnsureIncludeDirective(p.sshConfig, p.sshIncludeFile)
+}
+
+// writeOnePasswordInclude writes the SSH include file for servers, pointing
+// servers that use a 1Password SSH Key item at its public key.
+func writeOnePasswordInclude(ctx context.Context, opBackend *onepassword.Backend, servers []*domain.Server, p paths) error {
+	creds, err := opBackend.ListCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	servers, err = sync.ApplyVaultKeys(servers, creds, p.sshKeyDir)
+	if err != nil {
+		return fmt.Errorf("writing 1Password public keys: %w", err)
+	}
+	if err := sync.WriteSSHIncludeFile(servers, p.sshIncludeFile); err != nil {
+		return fmt.Errorf("writing SSH include file: %w", err)
+	}
+	return nil
+}
+
+// syncOnePassword runs a sync for the sync command and then rewrites the SSH
+// include file. Failures caused by the session or token wrap
+// errors.ErrAuthentication so cron jobs can tell them apart.
+func syncOnePassword(ctx context.Context, opBackend *onepassword.Backend, p paths) (onepassword.SyncStats, error) {
+	stats, err := opBackend.SyncWithStats(ctx)
+	if err != nil {
+		switch status := opBackend.GetStatus(); status {
+		case backendpkg.StatusLocked, backendpkg.StatusNotSignedIn, backendpkg.StatusTokenError:
+			return stats, fmt.Errorf("%w (%s): %v", errors.ErrAuthentication, status, err)
+		}
+		return stats, err
+	}
+	return stats, refreshOnePasswordFiles(ctx, opBackend, p)
+}
+
+// migrateOnePassword runs the migration wizard, then syncs so migrated items
+// reach the cache and SSH include file right away.
+func migrateOnePassword(ctx context.Context, cfg config.OnePasswordConfig, opBackend *onepassword.Backend, p paths) ([]onepassword.MigrationResult, error) {
+	client, err := newOnePasswordClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	final, err := tea.NewProgram(tui.NewMigrationWizard(client), tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, fmt.Errorf("running migration wizard: %w", err)
+	}
+	wizard, ok := final.(tui.MigrationWizard)
+	if !ok {
+		return nil, nil
+	}
+
+	results := wizard.Results()
+	if results.Migrated > 0 {
+		if _, err := syncOnePassword(ctx, opBackend, p); err != nil {
+			return results.Items, err
+		}
+	}
+	return results.Items, nil
+}
+
+// pushToOnePassword runs the push wizard, then refreshes the cache and SSH
+// include file so pushed hosts keep resolving once their blocks are commented out.
+func pushToOnePassword(ctx context.Context, opBackend *onepassword.Backend, p paths) ([]sync.PushResult, error) {
+	final, err := tea.NewProgram(tui.NewPushWizard(opBackend, p.sshConfig), tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, fmt.Errorf("running push wizard: %w", err)
+	}
+	wizard, ok := final.(tui.PushWizard)
+	if !ok {
+		return nil, nil
+	}
+
+	results := wizard.Results()
+	for _, r := range results {
+		if r.Pushed {
+			return results, refreshOnePasswordFiles(ctx, opBackend, p)
+		}
+	}
+	return results, nil
+}
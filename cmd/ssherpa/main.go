@@ -5,15 +5,16 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
-	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/backend/bitwarden"
+	"github.com/florianriquelme/ssherpa/internal/backend/gitrepo"
+	"github.com/florianriquelme/ssherpa/internal/backend/vault"
+	"github.com/florianriquelme/ssherpa/internal/cli"
 	"github.com/florianriquelme/ssherpa/internal/config"
 	"github.com/florianriquelme/ssherpa/internal/errors"
 	"github.com/florianriquelme/ssherpa/internal/project"
-	"github.com/florianriquelme/ssherpa/internal/sshconfig"
 	"github.com/florianriquelme/ssherpa/internal/sync"
 	"github.com/florianriquelme/ssherpa/internal/tui"
 	"github.com/florianriquelme/ssherpa/internal/version"
@@ -53,6 +54,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Non-interactive subcommands (list, show, connect, ...) skip the TUI entirely
+	if flag.NArg() > 0 {
+		if !cli.IsCommand(flag.Arg(0)) {
+			fmt.Fprintf(os.Stderr, "Unknown command %q. Run 'ssherpa help' for a list of commands.\n", flag.Arg(0))
+			os.Exit(cli.ExitUsage)
+		}
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error determining home directory: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(runCommand(cfg, newPaths(homeDir), flag.Args()))
+	}
+
 	// Run setup wizard if: --setup flag, no config, or no backend configured
 	if *setupFlag || cfg == nil || cfg.Backend == "" {
 		wizard := tui.NewSetupWizard(appConfigPath)
@@ -76,28 +91,15 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Determine backend from config
-	backendType := cfg.Backend
-
-	// Backend validation
-	if backendType != "sshconfig" && backendType != "onepassword" && backendType != "both" {
-		fmt.Fprintf(os.Stderr, "Backend '%s' not supported. Valid options: sshconfig, onepassword, both\n", backendType)
-		os.Exit(1)
-	}
-
 	// Determine SSH config path and history path
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error determining home directory: %v\n", err)
 		os.Exit(1)
 	}
-	sshConfigPath := filepath.Join(homeDir, ".ssh", "config")
-
-	// Determine history path
-	historyPath := ""
-	if homeDir != "" {
-		historyPath = filepath.Join(homeDir, ".ssh", "ssherpa_history.json")
-	}
+	files := newPaths(homeDir)
+	sshConfigPath := files.sshConfig
+	historyPath := files.history
 
 	// Get return-to-TUI config option (default: false = exit after SSH)
 	returnToTUI := cfg.ReturnToTUI
@@ -113,77 +115,18 @@ func main() {
 	projects := cfg.Projects
 
 	// Construct backend based on configuration
-	var backend backendpkg.Backend
+	backend, opBackend, err := openBackend(cfg, files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	var opStatus = backendpkg.StatusUnknown
-	var opBackend *onepassword.Backend
-
-	switch backendType {
-	case "sshconfig":
-		// Pure SSH config backend
-		sshBackend, err := sshconfig.New(sshConfigPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating SSH config backend: %v\n", err)
-			os.Exit(1)
-		}
-		backend = sshBackend
-
-	case "onepassword":
-		// 1Password backend
-		opAccountName := cfg.OnePassword.AccountName
-
-		var client *onepassword.CLIClient
-		if opAccountName != "" {
-			client, err = onepassword.NewCLIClientWithAccount(opAccountName)
-		} else {
-			client, err = onepassword.NewCLIClient()
-		}
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating 1Password CLI client: %v\n", err)
-			os.Exit(1)
-		}
-
-		cachePath := filepath.Join(homeDir, ".ssh", "ssherpa_1password_cache.toml")
-		opBackend = onepassword.NewWithCache(client, cachePath)
-
-		// Load from cache (best-effort, non-fatal) - TUI will show cached data instantly
-		_ = opBackend.LoadFromCache()
-
-		backend = opBackend
-		opStatus = opBackend.GetStatus()
-
-	case "both":
-		// Multi-backend: SSH config + 1Password
-		sshBackend, err := sshconfig.New(sshConfigPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating SSH config backend: %v\n", err)
-			os.Exit(1)
-		}
-
-		opAccountNameBoth := cfg.OnePassword.AccountName
-
-		var clientBoth *onepassword.CLIClient
-		if opAccountNameBoth != "" {
-			clientBoth, err = onepassword.NewCLIClientWithAccount(opAccountNameBoth)
-		} else {
-			clientBoth, err = onepassword.NewCLIClient()
-		}
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating 1Password CLI client: %v\n", err)
-			os.Exit(1)
-		}
-
-		cachePath := filepath.Join(homeDir, ".ssh", "ssherpa_1password_cache.toml")
-		opBackend = onepassword.NewWithCache(clientBoth, cachePath)
-
-		// Load from cache (best-effort, non-fatal) - SSH config data is always available
-		_ = opBackend.LoadFromCache()
-
-		backend = backendpkg.NewMultiBackend(sshBackend, opBackend)
+	if opBackend != nil {
 		opStatus = opBackend.GetStatus()
 	}
 
 	// Create TUI model with backend status and backend
-	model := tui.New(sshConfigPath, historyPath, returnToTUI, currentProjectID, projects, appConfigPath, opStatus, backend)
+	model := tui.New(sshConfigPath, historyPath, returnToTUI, currentProjectID, projects, cfg.Hosts, cfg.VPN, appConfigPath, opStatus, backend)
 
 	// Run TUI with alt screen (doesn't pollute terminal history)
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -201,8 +144,8 @@ func main() {
 			if status == backendpkg.StatusAvailable && !sshIncludeGenerated {
 				servers, err := opBackend.ListServers(context.Background())
 				if err == nil {
-					includeFile := filepath.Join(homeDir, ".ssh", "ssherpa_config")
-					if err := sync.WriteSSHIncludeFile(servers, includeFile); err != nil {
+					includeFile := files.sshIncludeFile
+					if err := writeOnePasswordInclude(context.Background(), opBackend, servers, files); err != nil {
 						fmt.Fprintf(os.Stderr, "Warning: Failed to write SSH include file: %v\n", err)
 					}
 					if err := sync.EnsureIncludeDirective(sshConfigPath, includeFile); err != nil {
@@ -216,11 +159,33 @@ func main() {
 			}
 		}
 		opBackend.StartPolling(0, statusCallback) // 0 = use default interval from env or 5m
-		defer func() { _ = opBackend.Close() }()
-	} else {
-		// Close backend on exit
-		defer func() { _ = backend.Close() }()
 	}
+	if bwBackend, ok := findBackend[*bitwarden.Backend](backend); ok {
+		// Reload the list whenever a background sync brings Bitwarden back
+		bwBackend.StartPolling(0, func(status backendpkg.BackendStatus) {
+			if status == backendpkg.StatusAvailable {
+				p.Send(tui.BackendServersUpdatedMsg{})
+			}
+		})
+	}
+	if vaultBackend, ok := findBackend[*vault.Backend](backend); ok {
+		// Reload the list whenever a background sync brings Vault back
+		vaultBackend.StartPolling(0, func(status backendpkg.BackendStatus) {
+			if status == backendpkg.StatusAvailable {
+				p.Send(tui.BackendServersUpdatedMsg{})
+			}
+		})
+	}
+	if gitBackend, ok := findBackend[*gitrepo.Backend](backend); ok {
+		// Each poll re-reads the inventory files; reload the list once the
+		// clone changes state (e.g. after a pull catches up with upstream)
+		gitBackend.StartPolling(0, func(backendpkg.BackendStatus) {
+			p.Send(tui.BackendServersUpdatedMsg{})
+		})
+	}
+
+	// Close backend on exit (stops the pollers)
+	defer func() { _ = backend.Close() }()
 
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
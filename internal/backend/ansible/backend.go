@@ -0,0 +1,214 @@
+// Package ansible reads Ansible inventories (INI or YAML) as a read-only
+// backend and writes servers back out as an inventory. Hosts become servers
+// and groups become projects.
+package ansible
+
+import (
+	"context"
+	"sync"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// Backend implements backend.Backend and backend.Filterer for an Ansible
+// inventory file. Read-only: the inventory is the playbooks' source of
+// truth, so the backend does NOT implement backend.Writer.
+type Backend struct {
+	path     string            // inventory file
+	mu       sync.RWMutex      // Protects servers, projects and closed flag
+	servers  []*domain.Server  // Hosts from the last load, in inventory order
+	projects []*domain.Project // Groups from the last load, in inventory order
+	closed   bool              // Backend closed flag
+}
+
+// Compile-time interface verification
+var (
+	_ backendpkg.Backend  = (*Backend)(nil)
+	_ backendpkg.Filterer = (*Backend)(nil)
+)
+
+// New creates a backend for the inventory at path and loads it.
+func New(path string) (*Backend, error) {
+	b := &Backend{path: path}
+	if err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Reload re-reads the inventory file.
+func (b *Backend) Reload() error {
+	inv, err := ParseFile(b.path)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "Reload",
+			Backend: "ansible",
+			Err:     err,
+		}
+	}
+
+	servers := make([]*domain.Server, 0, len(inv.Hosts))
+	for _, host := range inv.Hosts {
+		servers = append(servers, HostToServer(inv, host.Name))
+	}
+
+	projects := make([]*domain.Project, 0, len(inv.Groups))
+	seen := make(map[string]bool)
+	for _, group := range inv.Groups {
+		if group.Name == groupAll || group.Name == groupUngrouped {
+			continue
+		}
+		project := GroupToProject(inv, group.Name)
+		if !seen[project.ID] {
+			seen[project.ID] = true
+			projects = append(projects, project)
+		}
+	}
+
+	b.mu.Lock()
+	b.servers = servers
+	b.projects = projects
+	b.mu.Unlock()
+	return nil
+}
+
+// checkClosed returns ErrBackendUnavailable if backend is closed.
+// Must be called with mu held (either RLock or Lock).
+func (b *Backend) checkClosed() error {
+	if b.closed {
+		return &errors.BackendError{
+			Op:      "checkClosed",
+			Backend: "ansible",
+			Err:     errors.ErrBackendUnavailable,
+		}
+	}
+	return nil
+}
+
+// ListServers returns the inventory hosts as servers.
+func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	// Return copies (copy-on-read pattern)
+	result := make([]*domain.Server, len(b.servers))
+	for i, s := range b.servers {
+		serverCopy := *s
+		result[i] = &serverCopy
+	}
+	return result, nil
+}
+
+// GetServer retrieves a server by ID (its inventory host name).
+func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range b.servers {
+		if s.ID == id {
+			serverCopy := *s
+			return &serverCopy, nil
+		}
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetServer",
+		Backend: "ansible",
+		Err:     errors.ErrServerNotFound,
+	}
+}
+
+// FilterServers returns the inventory hosts matching the filter.
+func (b *Backend) FilterServers(ctx context.Context, filters backendpkg.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backendpkg.ApplyFilter(servers, filters), nil
+}
+
+// ListProjects returns the inventory groups as projects.
+func (b *Backend) ListProjects(ctx context.Context) ([]*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Project, len(b.projects))
+	for i, p := range b.projects {
+		projectCopy := *p
+		result[i] = &projectCopy
+	}
+	return result, nil
+}
+
+// GetProject retrieves a project by ID (its group name or ssherpa_project variable).
+func (b *Backend) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range b.projects {
+		if p.ID == id {
+			projectCopy := *p
+			return &projectCopy, nil
+		}
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetProject",
+		Backend: "ansible",
+		Err:     errors.ErrProjectNotFound,
+	}
+}
+
+// ListCredentials returns an empty slice (keys are referenced by ansible_ssh_private_key_file).
+func (b *Backend) ListCredentials(ctx context.Context) ([]*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return []*domain.Credential{}, nil
+}
+
+// GetCredential always returns ErrCredentialNotFound (inventories have no credentials).
+func (b *Backend) GetCredential(ctx context.Context, id string) (*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetCredential",
+		Backend: "ansible",
+		Err:     errors.ErrCredentialNotFound,
+	}
+}
+
+// Close marks the backend as closed. Future operations return ErrBackendUnavailable.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
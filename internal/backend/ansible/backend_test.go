@@ -0,0 +1,117 @@
+package ansible
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/mock"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+func newTestBackend(t *testing.T) (*Backend, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hosts.ini")
+	require.NoError(t, os.WriteFile(path, []byte(testINI), 0644))
+
+	b, err := New(path)
+	require.NoError(t, err)
+	return b, path
+}
+
+func TestBackend_Servers(t *testing.T) {
+	b, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 5)
+	assert.Equal(t, "bastion", servers[0].ID)
+	assert.Equal(t, "db1", servers[4].ID)
+
+	server, err := b.GetServer(ctx, "db1")
+	require.NoError(t, err)
+	assert.Equal(t, "bastion", server.Proxy)
+
+	_, err = b.GetServer(ctx, "missing")
+	assert.ErrorIs(t, err, errors.ErrServerNotFound)
+
+	filtered, err := b.FilterServers(ctx, backendpkg.ServerFilter{ProjectID: "db"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "db1", filtered[0].ID)
+}
+
+func TestBackend_Projects(t *testing.T) {
+	b, _ := newTestBackend(t)
+	ctx := context.Background()
+
+	projects, err := b.ListProjects(ctx)
+	require.NoError(t, err)
+	var ids []string
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	assert.Equal(t, []string{"web", "db", "prod"}, ids)
+
+	project, err := b.GetProject(ctx, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", project.Name)
+
+	_, err = b.GetProject(ctx, "staging")
+	assert.ErrorIs(t, err, errors.ErrProjectNotFound)
+
+	credentials, err := b.ListCredentials(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+}
+
+func TestBackend_ReadOnly(t *testing.T) {
+	b, _ := newTestBackend(t)
+	_, isWriter := any(b).(backendpkg.Writer)
+	assert.False(t, isWriter)
+
+	// In a MultiBackend the inventory's hosts can't be edited or removed,
+	// even with a writable backend configured next to it
+	ctx := context.Background()
+	server, err := b.GetServer(ctx, "db1")
+	require.NoError(t, err)
+	for _, multi := range []*backendpkg.MultiBackend{
+		backendpkg.NewMultiBackend(b),
+		backendpkg.NewMultiBackend(b, mock.New()),
+	} {
+		assert.ErrorIs(t, multi.UpdateServer(ctx, server), errors.ErrReadOnlyBackend)
+		assert.ErrorIs(t, multi.DeleteServer(ctx, "db1"), errors.ErrReadOnlyBackend)
+	}
+}
+
+func TestBackend_Reload(t *testing.T) {
+	b, path := newTestBackend(t)
+	ctx := context.Background()
+
+	require.NoError(t, os.WriteFile(path, []byte("[staging]\nstage1\n"), 0644))
+	require.NoError(t, b.Reload())
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, []string{"staging"}, servers[0].ProjectIDs)
+
+	require.NoError(t, os.WriteFile(path, []byte("[web:nope]\n"), 0644))
+	assert.Error(t, b.Reload())
+
+	_, err = New(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "read inventory")
+}
+
+func TestBackend_Close(t *testing.T) {
+	b, _ := newTestBackend(t)
+	require.NoError(t, b.Close())
+
+	_, err := b.ListServers(context.Background())
+	assert.ErrorIs(t, err, errors.ErrBackendUnavailable)
+}
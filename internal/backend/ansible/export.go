@@ -0,0 +1,365 @@
+package ansible
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+// Format is an inventory file format.
+type Format string
+
+// Supported inventory formats.
+const (
+	FormatINI  Format = "ini"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat parses a format name case-insensitively; "yml" is accepted
+// for YAML.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "ini":
+		return FormatINI, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unknown inventory format %q (valid: ini, yaml)", s)
+}
+
+// hostVarOrder is the order host variables are written in; others follow
+// sorted by name.
+var hostVarOrder = []string{
+	varHost, varUser, varPort, varPrivateKeyFile, varCommonArgs,
+	varTags, varFavorite, varVPNRequired, varRemoteProjectPath,
+}
+
+// invalidGroupChars matches what Ansible does not allow in group names.
+var invalidGroupChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Export writes servers as an Ansible inventory in format.
+// See FromServers for how servers and projects map to hosts and groups.
+func Export(w io.Writer, format Format, servers []*domain.Server, projects []*domain.Project) error {
+	inv := FromServers(servers, projects)
+	switch format {
+	case FormatINI:
+		return inv.WriteINI(w)
+	case FormatYAML:
+		return inv.WriteYAML(w)
+	default:
+		return fmt.Errorf("unknown inventory format %q", format)
+	}
+}
+
+// FromServers builds an inventory from servers, in the shape HostToServer
+// reads back. Each server is a host named after its display name (spaces
+// become dashes, clashes get a numeric suffix) with the ansible_* connection
+// variables; ProxyJump and other SSH options go into
+// ansible_ssh_common_args, and tags, favorites, VPN flags and remote paths
+// into ssherpa_* variables. Every project is a group of its servers, named
+// after the project ID with characters Ansible rejects replaced by "_"; the
+// ID is then kept in the group's ssherpa_project variable. Servers without
+// a project are ungrouped.
+func FromServers(servers []*domain.Server, projects []*domain.Project) *Inventory {
+	inv := NewInventory()
+	inv.addGroup(groupAll)
+	inv.addGroup(groupUngrouped)
+
+	groupNames := make(map[string]string) // project ID -> group name
+	groupFor := func(projectID string) string {
+		if name, ok := groupNames[projectID]; ok {
+			return name
+		}
+		name := uniqueName(invalidGroupChars.ReplaceAllString(projectID, "_"), "_", func(n string) bool {
+			return n == groupAll || n == groupUngrouped || inv.groups[n] != nil
+		})
+		groupNames[projectID] = name
+		g := inv.addGroup(name)
+		if name != projectID {
+			g.Vars[varProject] = projectID
+		}
+		return name
+	}
+
+	for _, p := range projects {
+		if p.ID != "" {
+			groupFor(p.ID)
+		}
+	}
+
+	for _, server := range servers {
+		name := strings.Join(strings.Fields(server.DisplayName), "-")
+		if name == "" {
+			name = server.Host
+		}
+		name = uniqueName(name, "-", func(n string) bool { return inv.hosts[n] != nil })
+
+		host := inv.addHost(name)
+		for k, v := range serverVars(server, name) {
+			host.Vars[k] = v
+		}
+
+		if !slices.ContainsFunc(server.ProjectIDs, func(id string) bool { return id != "" }) {
+			inv.addMember(groupUngrouped, name)
+		}
+		for _, projectID := range server.ProjectIDs {
+			if projectID != "" {
+				inv.addMember(groupFor(projectID), name)
+			}
+		}
+	}
+	return inv
+}
+
+// uniqueName returns name, or name with sep and the first free number
+// appended when taken reports it as used.
+func uniqueName(name, sep string, taken func(string) bool) string {
+	if !taken(name) {
+		return name
+	}
+	for i := 2; ; i++ {
+		if candidate := name + sep + strconv.Itoa(i); !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// serverVars returns the host variables for a server exported as hostName.
+// Empty and default values are left out.
+func serverVars(server *domain.Server, hostName string) map[string]string {
+	vars := make(map[string]string)
+	if server.Host != "" && server.Host != hostName {
+		vars[varHost] = server.Host
+	}
+	if server.User != "" {
+		vars[varUser] = server.User
+	}
+	if server.Port != 0 && server.Port != 22 {
+		vars[varPort] = strconv.Itoa(server.Port)
+	}
+	if server.IdentityFile != "" {
+		vars[varPrivateKeyFile] = server.IdentityFile
+	}
+	if args := sshArgs(server); args != "" {
+		vars[varCommonArgs] = args
+	}
+	if len(server.Tags) > 0 {
+		vars[varTags] = strings.Join(server.Tags, ",")
+	}
+	if server.Favorite {
+		vars[varFavorite] = "true"
+	}
+	if server.VPNRequired {
+		vars[varVPNRequired] = "true"
+	}
+	if server.RemoteProjectPath != "" {
+		vars[varRemoteProjectPath] = server.RemoteProjectPath
+	}
+	return vars
+}
+
+// sshArgs renders the proxy and SSH options as ssh -o arguments.
+func sshArgs(server *domain.Server) string {
+	var args []string
+	if server.Proxy != "" {
+		args = append(args, "-o", shellQuote("ProxyJump="+server.Proxy))
+	}
+	for _, line := range server.SSHOptionLines() {
+		key, value, _ := strings.Cut(line, " ")
+		args = append(args, "-o", shellQuote(key+"="+value))
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote single-quotes s for a shell unless it only holds safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// orderedKeys returns the keys of vars in hostVarOrder, then sorted.
+func orderedKeys(vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	known := make(map[string]bool, len(hostVarOrder))
+	for _, key := range hostVarOrder {
+		known[key] = true
+		if _, ok := vars[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	var rest []string
+	for key := range vars {
+		if !known[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// exportGroups returns the groups to write as sections, sorted by name.
+func (inv *Inventory) exportGroups() []*Group {
+	var groups []*Group
+	for _, g := range inv.Groups {
+		if g.Name != groupAll && g.Name != groupUngrouped {
+			groups = append(groups, g)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// WriteINI writes the inventory in Ansible's INI format. Ungrouped hosts
+// come first; a host's variables are written on its first line only.
+// Child groups and variables of "all" are not written.
+func (inv *Inventory) WriteINI(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "# Ansible inventory exported by ssherpa")
+
+	written := make(map[string]bool)
+	writeHost := func(name string) {
+		line := name
+		if !written[name] {
+			written[name] = true
+			vars := inv.hosts[name].Vars
+			for _, key := range orderedKeys(vars) {
+				line += " " + key + "=" + iniValue(vars[key])
+			}
+		}
+		_, _ = fmt.Fprintln(bw, line)
+	}
+
+	if g := inv.groups[groupUngrouped]; g != nil && len(g.Hosts) > 0 {
+		_, _ = fmt.Fprintln(bw)
+		for _, host := range g.Hosts {
+			writeHost(host)
+		}
+	}
+
+	for _, g := range inv.exportGroups() {
+		_, _ = fmt.Fprintf(bw, "\n[%s]\n", g.Name)
+		for _, host := range g.Hosts {
+			writeHost(host)
+		}
+		if len(g.Vars) > 0 {
+			_, _ = fmt.Fprintf(bw, "\n[%s:vars]\n", g.Name)
+			for _, key := range orderedKeys(g.Vars) {
+				_, _ = fmt.Fprintf(bw, "%s=%s\n", key, iniValue(g.Vars[key]))
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// iniValue double-quotes a value that would not survive the INI parser's
+// word splitting as-is.
+func iniValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\"'\\#=") {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// WriteYAML writes the inventory in Ansible's YAML format: ungrouped hosts
+// under all.hosts, groups under all.children. A host's variables are written
+// at its first occurrence only.
+func (inv *Inventory) WriteYAML(w io.Writer) error {
+	written := make(map[string]bool)
+	hostsNode := func(hosts []string) *yaml.Node {
+		node := mappingNode()
+		for _, name := range hosts {
+			value := nullNode()
+			if !written[name] {
+				written[name] = true
+				if vars := inv.hosts[name].Vars; len(vars) > 0 {
+					value = varsNode(vars)
+				}
+			}
+			node.Content = append(node.Content, stringNode(name), value)
+		}
+		return node
+	}
+
+	all := mappingNode()
+	if g := inv.groups[groupUngrouped]; g != nil && len(g.Hosts) > 0 {
+		all.Content = append(all.Content, stringNode("hosts"), hostsNode(g.Hosts))
+	}
+
+	children := mappingNode()
+	for _, g := range inv.exportGroups() {
+		group := mappingNode()
+		if len(g.Hosts) > 0 {
+			group.Content = append(group.Content, stringNode("hosts"), hostsNode(g.Hosts))
+		}
+		if len(g.Vars) > 0 {
+			group.Content = append(group.Content, stringNode("vars"), varsNode(g.Vars))
+		}
+		if len(group.Content) == 0 {
+			group = nullNode()
+		}
+		children.Content = append(children.Content, stringNode(g.Name), group)
+	}
+	if len(children.Content) > 0 {
+		all.Content = append(all.Content, stringNode("children"), children)
+	}
+
+	root := mappingNode()
+	root.Content = append(root.Content, stringNode(groupAll), all)
+	doc := &yaml.Node{
+		Kind:        yaml.DocumentNode,
+		HeadComment: "Ansible inventory exported by ssherpa",
+		Content:     []*yaml.Node{root},
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// varsNode renders variables as a mapping. Ports and ssherpa flags are
+// written as YAML integers and booleans, everything else as strings.
+func varsNode(vars map[string]string) *yaml.Node {
+	node := mappingNode()
+	for _, key := range orderedKeys(vars) {
+		value := stringNode(vars[key])
+		switch key {
+		case varPort:
+			if _, err := strconv.Atoi(vars[key]); err == nil {
+				value.Tag = "!!int"
+			}
+		case varFavorite, varVPNRequired:
+			value = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(parseBool(vars[key]))}
+		}
+		node.Content = append(node.Content, stringNode(key), value)
+	}
+	return node
+}
+
+func mappingNode() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func nullNode() *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+}
@@ -0,0 +1,153 @@
+package ansible
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+func exportServers() ([]*domain.Server, []*domain.Project) {
+	servers := []*domain.Server{
+		{ID: "bastion", DisplayName: "bastion", Host: "203.0.113.10", User: "admin", Port: 22, Tags: []string{}},
+		{
+			ID: "op-item-1", DisplayName: "web", Host: "web.example.com", User: "deploy", Port: 2222,
+			IdentityFile: "~/.ssh/deploy", Proxy: "bastion", Tags: []string{"prod", "eu"},
+			Favorite: true, VPNRequired: true, RemoteProjectPath: "/srv/app",
+			ProjectIDs: []string{"acme/payments", "shared"},
+			SSHOptions: map[string][]string{"LocalForward": {"8080 localhost:80"}, "ForwardAgent": {"yes"}},
+		},
+		{ID: "db", DisplayName: "db server", Host: "db server", User: "postgres", Port: 5432, ProjectIDs: []string{"shared"}},
+	}
+	projects := []*domain.Project{
+		{ID: "acme/payments", Name: "Payments"},
+		{ID: "shared", Name: "Shared"},
+		{ID: "empty", Name: "No servers yet"},
+	}
+	return servers, projects
+}
+
+func TestWriteINI(t *testing.T) {
+	servers, projects := exportServers()
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, FormatINI, servers, projects))
+
+	assert.Equal(t, `# Ansible inventory exported by ssherpa
+
+bastion ansible_host=203.0.113.10 ansible_user=admin
+
+[acme_payments]
+web ansible_host=web.example.com ansible_user=deploy ansible_port=2222 ansible_ssh_private_key_file=~/.ssh/deploy ansible_ssh_common_args="-o ProxyJump=bastion -o ForwardAgent=yes -o 'LocalForward=8080 localhost:80'" ssherpa_tags=prod,eu ssherpa_favorite=true ssherpa_vpn_required=true ssherpa_remote_project_path=/srv/app
+
+[acme_payments:vars]
+ssherpa_project=acme/payments
+
+[empty]
+
+[shared]
+web
+db-server ansible_host="db server" ansible_user=postgres ansible_port=5432
+`, buf.String())
+}
+
+func TestWriteYAML(t *testing.T) {
+	servers, projects := exportServers()
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, FormatYAML, servers, projects))
+
+	assert.Equal(t, `# Ansible inventory exported by ssherpa
+
+all:
+  hosts:
+    bastion:
+      ansible_host: 203.0.113.10
+      ansible_user: admin
+  children:
+    acme_payments:
+      hosts:
+        web:
+          ansible_host: web.example.com
+          ansible_user: deploy
+          ansible_port: 2222
+          ansible_ssh_private_key_file: ~/.ssh/deploy
+          ansible_ssh_common_args: -o ProxyJump=bastion -o ForwardAgent=yes -o 'LocalForward=8080 localhost:80'
+          ssherpa_tags: prod,eu
+          ssherpa_favorite: true
+          ssherpa_vpn_required: true
+          ssherpa_remote_project_path: /srv/app
+      vars:
+        ssherpa_project: acme/payments
+    empty:
+    shared:
+      hosts:
+        web:
+        db-server:
+          ansible_host: db server
+          ansible_user: postgres
+          ansible_port: 5432
+`, buf.String())
+}
+
+// TestExport_RoundTrip checks that an exported inventory reads back as the
+// servers it was written from.
+func TestExport_RoundTrip(t *testing.T) {
+	servers, projects := exportServers()
+
+	for _, format := range []Format{FormatINI, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Export(&buf, format, servers, projects))
+
+			var inv *Inventory
+			var err error
+			if format == FormatINI {
+				inv, err = ParseINI(buf.Bytes())
+			} else {
+				inv, err = ParseYAML(buf.Bytes())
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"bastion", "web", "db-server"}, hostNames(inv))
+
+			web := HostToServer(inv, "web")
+			want := *servers[1]
+			want.ID = "web"
+			want.Source = "ansible"
+			assert.Equal(t, &want, web)
+
+			db := HostToServer(inv, "db-server")
+			assert.Equal(t, "db server", db.Host)
+			assert.Equal(t, 5432, db.Port)
+			assert.Equal(t, []string{"shared"}, db.ProjectIDs)
+
+			assert.Empty(t, HostToServer(inv, "bastion").ProjectIDs)
+			assert.Equal(t, "acme/payments", GroupToProject(inv, "acme_payments").ID)
+		})
+	}
+}
+
+func TestFromServers_NameClashes(t *testing.T) {
+	inv := FromServers([]*domain.Server{
+		{DisplayName: "web", Host: "a", ProjectIDs: []string{"a-b", "a_b"}},
+		{DisplayName: "web", Host: "b"},
+		{Host: "10.0.0.9"},
+	}, nil)
+
+	assert.Equal(t, []string{"web", "web-2", "10.0.0.9"}, hostNames(inv))
+	assert.Equal(t, "a-b", inv.ProjectID("a_b"))
+	assert.Equal(t, "a_b", inv.ProjectID("a_b_2"))
+	assert.Empty(t, inv.Host("10.0.0.9").Vars)
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"ini": FormatINI, "YAML": FormatYAML, "yml": FormatYAML} {
+		got, err := ParseFormat(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseFormat("json")
+	assert.ErrorContains(t, err, "valid: ini, yaml")
+}
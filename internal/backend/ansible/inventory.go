@@ -0,0 +1,619 @@
+package ansible
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the groups every Ansible inventory has implicitly.
+const (
+	groupAll       = "all"
+	groupUngrouped = "ungrouped"
+)
+
+// Inventory is a parsed Ansible inventory: hosts with their own variables,
+// and groups with variables, member hosts and child groups.
+// Hosts and groups keep the order in which they first appear.
+type Inventory struct {
+	Hosts  []*Host
+	Groups []*Group
+
+	hosts  map[string]*Host
+	groups map[string]*Group
+}
+
+// Host is an inventory host. Vars holds only the variables set on the host
+// itself; see Inventory.HostVars for the merged view.
+type Host struct {
+	Name string
+	Vars map[string]string
+}
+
+// Group is an inventory group.
+type Group struct {
+	Name     string
+	Vars     map[string]string
+	Hosts    []string // direct member hosts, in order
+	Children []string // child group names, in order
+}
+
+// NewInventory returns an empty inventory.
+func NewInventory() *Inventory {
+	return &Inventory{
+		hosts:  make(map[string]*Host),
+		groups: make(map[string]*Group),
+	}
+}
+
+// Host returns the host with the given name, or nil.
+func (inv *Inventory) Host(name string) *Host {
+	return inv.hosts[name]
+}
+
+// Group returns the group with the given name, or nil.
+func (inv *Inventory) Group(name string) *Group {
+	return inv.groups[name]
+}
+
+// addHost returns the named host, creating it if needed.
+func (inv *Inventory) addHost(name string) *Host {
+	if h, ok := inv.hosts[name]; ok {
+		return h
+	}
+	h := &Host{Name: name, Vars: make(map[string]string)}
+	inv.hosts[name] = h
+	inv.Hosts = append(inv.Hosts, h)
+	return h
+}
+
+// addGroup returns the named group, creating it if needed.
+func (inv *Inventory) addGroup(name string) *Group {
+	if g, ok := inv.groups[name]; ok {
+		return g
+	}
+	g := &Group{Name: name, Vars: make(map[string]string)}
+	inv.groups[name] = g
+	inv.Groups = append(inv.Groups, g)
+	return g
+}
+
+// addMember adds a host to a group once.
+func (inv *Inventory) addMember(group, host string) {
+	g := inv.addGroup(group)
+	inv.addHost(host)
+	for _, existing := range g.Hosts {
+		if existing == host {
+			return
+		}
+	}
+	g.Hosts = append(g.Hosts, host)
+}
+
+// addChild makes child a child group of parent once.
+func (inv *Inventory) addChild(parent, child string) {
+	g := inv.addGroup(parent)
+	inv.addGroup(child)
+	for _, existing := range g.Children {
+		if existing == child {
+			return
+		}
+	}
+	g.Children = append(g.Children, child)
+}
+
+// parents returns the groups that list name as a child.
+func (inv *Inventory) parents(name string) []string {
+	var parents []string
+	for _, g := range inv.Groups {
+		for _, child := range g.Children {
+			if child == name {
+				parents = append(parents, g.Name)
+				break
+			}
+		}
+	}
+	return parents
+}
+
+// HostGroups returns the groups a host belongs to, directly or through
+// child groups: direct groups first, then their ancestors, each once.
+// The implicit "all" and "ungrouped" groups are left out.
+func (inv *Inventory) HostGroups(name string) []string {
+	var queue []string
+	for _, g := range inv.Groups {
+		for _, member := range g.Hosts {
+			if member == name {
+				queue = append(queue, g.Name)
+				break
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var groups []string
+	for len(queue) > 0 {
+		group := queue[0]
+		queue = queue[1:]
+		if seen[group] {
+			continue
+		}
+		seen[group] = true
+		if group != groupAll && group != groupUngrouped {
+			groups = append(groups, group)
+		}
+		queue = append(queue, inv.parents(group)...)
+	}
+	return groups
+}
+
+// depth is a group's distance from "all": 0 for "all", 1 for top-level
+// groups, one more than the deepest parent otherwise. Ansible applies group
+// variables from the lowest depth up, so children override parents.
+func (inv *Inventory) depth(name string, visiting map[string]bool) int {
+	if name == groupAll {
+		return 0
+	}
+	if visiting[name] {
+		return 1 // cycle; Ansible rejects these, keep going
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	depth := 1
+	for _, parent := range inv.parents(name) {
+		if d := inv.depth(parent, visiting) + 1; d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// HostVars returns the variables that apply to a host, merged the way
+// Ansible does: "all" first, then the host's groups from parents to
+// children (groups of equal depth by name), then the host's own variables.
+func (inv *Inventory) HostVars(name string) map[string]string {
+	groups := append([]string{groupAll}, inv.HostGroups(name)...)
+	if g := inv.groups[groupUngrouped]; g != nil {
+		for _, member := range g.Hosts {
+			if member == name {
+				groups = append(groups, groupUngrouped)
+			}
+		}
+	}
+
+	depths := make(map[string]int, len(groups))
+	for _, group := range groups {
+		depths[group] = inv.depth(group, make(map[string]bool))
+	}
+	sortGroups(groups, depths)
+
+	vars := make(map[string]string)
+	for _, group := range groups {
+		if g := inv.groups[group]; g != nil {
+			for k, v := range g.Vars {
+				vars[k] = v
+			}
+		}
+	}
+	if h := inv.hosts[name]; h != nil {
+		for k, v := range h.Vars {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// sortGroups orders groups by depth, then by name.
+func sortGroups(groups []string, depths map[string]int) {
+	sort.Slice(groups, func(i, j int) bool {
+		if depths[groups[i]] != depths[groups[j]] {
+			return depths[groups[i]] < depths[groups[j]]
+		}
+		return groups[i] < groups[j]
+	})
+}
+
+// ParseFile reads an inventory file. Files ending in .yml, .yaml or .json
+// are parsed as YAML (JSON is a subset), everything else as INI.
+func ParseFile(path string) (*Inventory, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read inventory: %w", err)
+	}
+
+	var inv *Inventory
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml", ".json":
+		inv, err = ParseYAML(content)
+	default:
+		inv, err = ParseINI(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse inventory %s: %w", path, err)
+	}
+	return inv, nil
+}
+
+// ParseINI parses an inventory in Ansible's INI format:
+//
+//	bastion ansible_host=203.0.113.10
+//
+//	[web]
+//	web[01:03].example.com ansible_user=deploy
+//
+//	[web:vars]
+//	ansible_ssh_common_args='-o ProxyJump=bastion'
+//
+//	[prod:children]
+//	web
+//
+// Hosts before the first section are ungrouped. Host ranges ([01:03],
+// [a:c]) are expanded.
+func ParseINI(content []byte) (*Inventory, error) {
+	inv := NewInventory()
+	inv.addGroup(groupAll)
+	inv.addGroup(groupUngrouped)
+
+	group, kind := groupUngrouped, "hosts"
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			name, suffix, _ := strings.Cut(section, ":")
+			switch suffix {
+			case "":
+				kind = "hosts"
+			case "vars", "children":
+				kind = suffix
+			default:
+				return nil, fmt.Errorf("line %d: unknown section type %q", lineNo, suffix)
+			}
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty group name", lineNo)
+			}
+			group = name
+			inv.addGroup(group)
+			continue
+		}
+
+		switch kind {
+		case "hosts":
+			if err := inv.parseHostLine(group, line); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		case "vars":
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key=value in [%s:vars]", lineNo, group)
+			}
+			inv.groups[group].Vars[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+		case "children":
+			inv.addChild(group, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// parseHostLine adds the host (or host range) on line to group.
+func (inv *Inventory) parseHostLine(group, line string) error {
+	words, err := splitShell(line)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return nil // only a comment
+	}
+
+	names, err := expandHostPattern(words[0])
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		inv.addMember(group, name)
+		for _, word := range words[1:] {
+			key, value, ok := strings.Cut(word, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("expected key=value after host %s, got %q", words[0], word)
+			}
+			inv.hosts[name].Vars[key] = value
+		}
+	}
+	return nil
+}
+
+// expandHostPattern expands one numeric ([01:10], [1:10:2]) or alphabetic
+// ([a:f]) range in a host name; names without a range are returned as-is.
+func expandHostPattern(pattern string) ([]string, error) {
+	start := strings.Index(pattern, "[")
+	end := strings.Index(pattern, "]")
+	if start < 0 || end < start {
+		return []string{pattern}, nil
+	}
+	head, spec, tail := pattern[:start], pattern[start+1:end], pattern[end+1:]
+
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid host range %q", pattern)
+	}
+	step := 1
+	if len(parts) == 3 {
+		var err error
+		if step, err = strconv.Atoi(parts[2]); err != nil || step < 1 {
+			return nil, fmt.Errorf("invalid host range step in %q", pattern)
+		}
+	}
+
+	rest, err := expandHostPattern(tail)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []string
+	from, fromErr := strconv.Atoi(parts[0])
+	to, toErr := strconv.Atoi(parts[1])
+	switch {
+	case fromErr == nil && toErr == nil && from <= to:
+		width := 0
+		if strings.HasPrefix(parts[0], "0") && len(parts[0]) > 1 {
+			width = len(parts[0])
+		}
+		for i := from; i <= to; i += step {
+			items = append(items, fmt.Sprintf("%0*d", width, i))
+		}
+	case len(parts[0]) == 1 && len(parts[1]) == 1 && isLetter(parts[0][0]) && isLetter(parts[1][0]) && parts[0] <= parts[1]:
+		for c := int(parts[0][0]); c <= int(parts[1][0]); c += step {
+			items = append(items, string(rune(c)))
+		}
+	default:
+		return nil, fmt.Errorf("invalid host range %q", pattern)
+	}
+
+	var names []string
+	for _, item := range items {
+		for _, suffix := range rest {
+			names = append(names, head+item+suffix)
+		}
+	}
+	return names, nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// splitShell splits s into words the way Python's shlex does for Ansible
+// inventories: single and double quotes group, backslashes escape outside
+// single quotes, and an unquoted # starts a comment.
+func splitShell(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				word.WriteRune(runes[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case r == '#' && !inWord:
+			i = len(runes)
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// unquote strips one level of matching quotes from a [group:vars] value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		if words, err := splitShell(value); err == nil && len(words) == 1 {
+			return words[0]
+		}
+	}
+	return value
+}
+
+// ParseYAML parses an inventory in Ansible's YAML format:
+//
+//	all:
+//	  hosts:
+//	    bastion:
+//	      ansible_host: 203.0.113.10
+//	  children:
+//	    web:
+//	      hosts:
+//	        web01.example.com:
+//	      vars:
+//	        ansible_user: deploy
+//
+// Host names may use the same ranges as in INI inventories.
+func ParseYAML(content []byte) (*Inventory, error) {
+	inv := NewInventory()
+	inv.addGroup(groupAll)
+	inv.addGroup(groupUngrouped)
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return inv, nil // empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of groups", root.Line)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if err := inv.parseYAMLGroup(root.Content[i].Value, root.Content[i+1]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Hosts listed directly under "all" are ungrouped unless a group has them
+	for _, host := range inv.groups[groupAll].Hosts {
+		if len(inv.HostGroups(host)) == 0 {
+			inv.addMember(groupUngrouped, host)
+		}
+	}
+	return inv, nil
+}
+
+// parseYAMLGroup reads a group's hosts, vars and children; an empty node is
+// a group without any of them.
+func (inv *Inventory) parseYAMLGroup(name string, node *yaml.Node) error {
+	inv.addGroup(name)
+	if isNull(node) {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: group %s: expected a mapping", node.Line, name)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if isNull(value) {
+			continue
+		}
+		if value.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: group %s: %s must be a mapping", value.Line, name, key)
+		}
+
+		switch key {
+		case "hosts":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				names, err := expandHostPattern(value.Content[j].Value)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", value.Content[j].Line, err)
+				}
+				vars, err := yamlVars(value.Content[j+1])
+				if err != nil {
+					return err
+				}
+				for _, host := range names {
+					inv.addMember(name, host)
+					for k, v := range vars {
+						inv.hosts[host].Vars[k] = v
+					}
+				}
+			}
+		case "vars":
+			vars, err := yamlVars(value)
+			if err != nil {
+				return err
+			}
+			for k, v := range vars {
+				inv.groups[name].Vars[k] = v
+			}
+		case "children":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				child := value.Content[j].Value
+				inv.addChild(name, child)
+				if err := inv.parseYAMLGroup(child, value.Content[j+1]); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("line %d: group %s: unknown key %q (expected hosts, vars or children)", node.Content[i].Line, name, key)
+		}
+	}
+	return nil
+}
+
+// yamlVars reads a mapping of variables. Scalars keep their text; lists of
+// scalars become comma-separated strings, other values their YAML flow form.
+func yamlVars(node *yaml.Node) (map[string]string, error) {
+	vars := make(map[string]string)
+	if isNull(node) {
+		return vars, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of variables", node.Line)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if !isNull(value) {
+				vars[key] = value.Value
+			} else {
+				vars[key] = ""
+			}
+		case yaml.SequenceNode:
+			items := make([]string, 0, len(value.Content))
+			for _, item := range value.Content {
+				items = append(items, item.Value)
+			}
+			vars[key] = strings.Join(items, ", ")
+		default:
+			value.Style = yaml.FlowStyle
+			data, err := yaml.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			vars[key] = strings.TrimSpace(string(data))
+		}
+	}
+	return vars, nil
+}
+
+// isNull reports whether node is missing or an explicit YAML null.
+func isNull(node *yaml.Node) bool {
+	return node == nil || (node.Kind == yaml.ScalarNode && node.Tag == "!!null")
+}
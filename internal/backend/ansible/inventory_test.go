@@ -0,0 +1,204 @@
+package ansible
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testINI = `# Production hosts
+bastion ansible_host=203.0.113.10 ansible_user=admin
+
+[web]
+web[01:02].example.com ansible_user=deploy
+api ansible_host=10.0.0.7 ansible_port=2222 # legacy port
+
+[db]
+db1 ansible_host=10.0.0.5 ansible_ssh_common_args='-o ProxyJump=bastion -o ForwardAgent=yes'
+
+[web:vars]
+ansible_ssh_private_key_file=~/.ssh/deploy
+ssherpa_tags="prod, eu"
+
+[prod:children]
+web
+db
+
+[prod:vars]
+ansible_user=ops
+ansible_ssh_private_key_file=~/.ssh/ops
+
+[all:vars]
+ansible_port=22
+`
+
+const testYAML = `all:
+  hosts:
+    bastion:
+      ansible_host: 203.0.113.10
+      ansible_user: admin
+  vars:
+    ansible_port: 22
+  children:
+    prod:
+      vars:
+        ansible_user: ops
+        ansible_ssh_private_key_file: ~/.ssh/ops
+      children:
+        web:
+          hosts:
+            web[01:02].example.com:
+              ansible_user: deploy
+            api:
+              ansible_host: 10.0.0.7
+              ansible_port: 2222
+          vars:
+            ansible_ssh_private_key_file: ~/.ssh/deploy
+            ssherpa_tags: [prod, eu]
+        db:
+          hosts:
+            db1:
+              ansible_host: 10.0.0.5
+              ansible_ssh_common_args: -o ProxyJump=bastion -o ForwardAgent=yes
+`
+
+func hostNames(inv *Inventory) []string {
+	names := make([]string, len(inv.Hosts))
+	for i, h := range inv.Hosts {
+		names[i] = h.Name
+	}
+	return names
+}
+
+// assertTestInventory checks the inventory shared by testINI and testYAML.
+func assertTestInventory(t *testing.T, inv *Inventory) {
+	t.Helper()
+
+	assert.Equal(t, []string{"bastion", "web01.example.com", "web02.example.com", "api", "db1"}, hostNames(inv))
+	assert.Equal(t, []string{"bastion"}, inv.Group("ungrouped").Hosts)
+	assert.Equal(t, []string{"web", "db"}, inv.Group("prod").Children)
+
+	assert.Empty(t, inv.HostGroups("bastion"))
+	assert.Equal(t, []string{"web", "prod"}, inv.HostGroups("api"))
+
+	// Host vars beat child groups, which beat parent groups, which beat all
+	vars := inv.HostVars("web01.example.com")
+	assert.Equal(t, "deploy", vars["ansible_user"])
+	assert.Equal(t, "~/.ssh/deploy", vars["ansible_ssh_private_key_file"])
+	assert.Equal(t, "22", vars["ansible_port"])
+	assert.Equal(t, "prod, eu", vars["ssherpa_tags"])
+
+	vars = inv.HostVars("db1")
+	assert.Equal(t, "ops", vars["ansible_user"])
+	assert.Equal(t, "~/.ssh/ops", vars["ansible_ssh_private_key_file"])
+	assert.Equal(t, "-o ProxyJump=bastion -o ForwardAgent=yes", vars["ansible_ssh_common_args"])
+
+	assert.Equal(t, "2222", inv.HostVars("api")["ansible_port"])
+	assert.Equal(t, "admin", inv.HostVars("bastion")["ansible_user"])
+}
+
+func TestParseINI(t *testing.T) {
+	inv, err := ParseINI([]byte(testINI))
+	require.NoError(t, err)
+	assertTestInventory(t, inv)
+}
+
+func TestParseYAML(t *testing.T) {
+	inv, err := ParseYAML([]byte(testYAML))
+	require.NoError(t, err)
+	assertTestInventory(t, inv)
+}
+
+func TestParseINI_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"bad section", "[web:hosts]\n", "line 1: unknown section type"},
+		{"empty group", "[]\n", "empty group name"},
+		{"bad host var", "[web]\nweb1 ansible_user\n", "line 2: expected key=value"},
+		{"bad group var", "[web:vars]\nansible_user\n", "expected key=value"},
+		{"unterminated quote", "web1 ansible_ssh_common_args='-o x\n", "unterminated quote"},
+		{"bad range", "web[1:x]\n", "invalid host range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseINI([]byte(tt.content))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseYAML_Errors(t *testing.T) {
+	_, err := ParseYAML([]byte("- web1\n"))
+	assert.ErrorContains(t, err, "expected a mapping of groups")
+
+	_, err = ParseYAML([]byte("all:\n  hostz:\n    web1:\n"))
+	assert.ErrorContains(t, err, `unknown key "hostz"`)
+
+	inv, err := ParseYAML(nil)
+	require.NoError(t, err)
+	assert.Empty(t, inv.Hosts)
+}
+
+func TestExpandHostPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"web", []string{"web"}},
+		{"web[1:3]", []string{"web1", "web2", "web3"}},
+		{"web[08:10].example.com", []string{"web08.example.com", "web09.example.com", "web10.example.com"}},
+		{"db-[a:c]", []string{"db-a", "db-b", "db-c"}},
+		{"node[0:4:2]", []string{"node0", "node2", "node4"}},
+		{"r[1:2]n[a:b]", []string{"r1na", "r1nb", "r2na", "r2nb"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := expandHostPattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := expandHostPattern("web[3:1]")
+	assert.Error(t, err)
+}
+
+func TestSplitShell(t *testing.T) {
+	words, err := splitShell(`web1 a='x y' b="say \"hi\"" c=d\ e # comment`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web1", "a=x y", `b=say "hi"`, "c=d e"}, words)
+
+	words, err = splitShell(`-o 'SetEnv=A=it'\''s'`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-o", "SetEnv=A=it's"}, words)
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	iniPath := filepath.Join(dir, "hosts")
+	yamlPath := filepath.Join(dir, "hosts.yml")
+	require.NoError(t, os.WriteFile(iniPath, []byte(testINI), 0644))
+	require.NoError(t, os.WriteFile(yamlPath, []byte(testYAML), 0644))
+
+	for _, path := range []string{iniPath, yamlPath} {
+		inv, err := ParseFile(path)
+		require.NoError(t, err, path)
+		assert.Len(t, inv.Hosts, 5, path)
+	}
+
+	_, err := ParseFile(filepath.Join(dir, "missing.ini"))
+	assert.ErrorContains(t, err, "read inventory")
+
+	bad := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("all: [\n"), 0644))
+	_, err = ParseFile(bad)
+	assert.ErrorContains(t, err, "parse inventory")
+}
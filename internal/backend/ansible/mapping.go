@@ -0,0 +1,197 @@
+package ansible
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+// Ansible connection variables, with the older ansible_ssh_* spellings
+// Ansible still accepts as fallbacks.
+const (
+	varHost           = "ansible_host"
+	varUser           = "ansible_user"
+	varPort           = "ansible_port"
+	varPrivateKeyFile = "ansible_ssh_private_key_file"
+	varCommonArgs     = "ansible_ssh_common_args"
+	varExtraArgs      = "ansible_ssh_extra_args"
+)
+
+var fallbackVars = map[string]string{
+	varHost:           "ansible_ssh_host",
+	varUser:           "ansible_ssh_user",
+	varPort:           "ansible_ssh_port",
+	varPrivateKeyFile: "ansible_private_key_file",
+}
+
+// Variables for ssherpa metadata that has no Ansible equivalent. The
+// exporter writes them so an exported inventory reads back the same.
+const (
+	varTags              = "ssherpa_tags"
+	varFavorite          = "ssherpa_favorite"
+	varVPNRequired       = "ssherpa_vpn_required"
+	varRemoteProjectPath = "ssherpa_remote_project_path"
+
+	// varProject is a group variable holding the project ID a group stands
+	// for, when the ID is not a valid Ansible group name.
+	varProject = "ssherpa_project"
+)
+
+// lookup returns the value of an Ansible variable or its fallback spelling.
+func lookup(vars map[string]string, name string) string {
+	if value := vars[name]; value != "" {
+		return value
+	}
+	return vars[fallbackVars[name]]
+}
+
+// HostToServer converts an inventory host to a domain.Server.
+// The host name is the server's ID and display name; ansible_host (falling
+// back to the name) is what ssh connects to. The groups the host belongs
+// to, directly or through child groups, become its projects.
+// ProxyJump and other -o options are read from ansible_ssh_common_args and
+// ansible_ssh_extra_args.
+func HostToServer(inv *Inventory, name string) *domain.Server {
+	vars := inv.HostVars(name)
+
+	server := &domain.Server{
+		ID:          name,
+		DisplayName: name,
+		Host:        lookup(vars, varHost),
+		User:        lookup(vars, varUser),
+		Port:        22, // default port
+		Source:      "ansible",
+		Tags:        []string{},
+	}
+	if server.Host == "" {
+		server.Host = name
+	}
+	if port, err := strconv.Atoi(lookup(vars, varPort)); err == nil && port > 0 {
+		server.Port = port
+	}
+	server.IdentityFile = lookup(vars, varPrivateKeyFile)
+
+	for _, key := range []string{varCommonArgs, varExtraArgs} {
+		if args := vars[key]; args != "" {
+			applySSHArgs(server, args)
+		}
+	}
+
+	for _, group := range inv.HostGroups(name) {
+		server.ProjectIDs = append(server.ProjectIDs, inv.ProjectID(group))
+	}
+
+	if tags := splitList(vars[varTags]); len(tags) > 0 {
+		server.Tags = tags
+	}
+	server.Favorite = parseBool(vars[varFavorite])
+	server.VPNRequired = parseBool(vars[varVPNRequired])
+	server.RemoteProjectPath = vars[varRemoteProjectPath]
+
+	return server
+}
+
+// ProjectID returns the ID of the project a group stands for: its
+// ssherpa_project variable, or the group name.
+func (inv *Inventory) ProjectID(group string) string {
+	if g := inv.groups[group]; g != nil && g.Vars[varProject] != "" {
+		return g.Vars[varProject]
+	}
+	return group
+}
+
+// GroupToProject converts an inventory group to a domain.Project.
+func GroupToProject(inv *Inventory, group string) *domain.Project {
+	return &domain.Project{
+		ID:   inv.ProjectID(group),
+		Name: group,
+	}
+}
+
+// applySSHArgs reads the ssh options in an ansible_ssh_*_args value:
+// "-J host" and "-o ProxyJump=host" set the proxy (the first one wins, as in
+// ssh), other "-o Key=Value" (or "-o 'Key Value'") options become SSH
+// options. Other flags are ignored.
+func applySSHArgs(server *domain.Server, args string) {
+	words, err := splitShell(args)
+	if err != nil {
+		return
+	}
+
+	setProxy := func(proxy string) {
+		if server.Proxy == "" {
+			server.Proxy = proxy
+		}
+	}
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		var option string
+		switch {
+		case word == "-J" && i+1 < len(words):
+			i++
+			setProxy(words[i])
+			continue
+		case strings.HasPrefix(word, "-J"):
+			setProxy(word[2:])
+			continue
+		case word == "-o" && i+1 < len(words):
+			i++
+			option = words[i]
+		case strings.HasPrefix(word, "-o"):
+			option = word[2:]
+		default:
+			continue
+		}
+
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			key, value, ok = strings.Cut(strings.TrimSpace(option), " ")
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			continue
+		}
+		if strings.EqualFold(key, "ProxyJump") {
+			setProxy(value)
+			continue
+		}
+		setSSHOption(server, key, value)
+	}
+}
+
+// setSSHOption appends value to the server's SSH options under key, reusing
+// the spelling of a keyword that is already present.
+func setSSHOption(server *domain.Server, key, value string) {
+	if server.SSHOptions == nil {
+		server.SSHOptions = make(map[string][]string)
+	}
+	for existing := range server.SSHOptions {
+		if strings.EqualFold(existing, key) {
+			key = existing
+			break
+		}
+	}
+	server.SSHOptions[key] = append(server.SSHOptions[key], value)
+}
+
+// splitList parses a comma-separated list, dropping blanks.
+func splitList(value string) []string {
+	var list []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// parseBool interprets the boolean spellings Ansible accepts.
+func parseBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "on":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,72 @@
+package ansible
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostToServer(t *testing.T) {
+	inv, err := ParseINI([]byte(testINI))
+	require.NoError(t, err)
+
+	server := HostToServer(inv, "db1")
+	assert.Equal(t, "db1", server.ID)
+	assert.Equal(t, "db1", server.DisplayName)
+	assert.Equal(t, "10.0.0.5", server.Host)
+	assert.Equal(t, "ops", server.User)
+	assert.Equal(t, 22, server.Port)
+	assert.Equal(t, "~/.ssh/ops", server.IdentityFile)
+	assert.Equal(t, "bastion", server.Proxy)
+	assert.Equal(t, map[string][]string{"ForwardAgent": {"yes"}}, server.SSHOptions)
+	assert.Equal(t, []string{"db", "prod"}, server.ProjectIDs)
+	assert.Equal(t, "ansible", server.Source)
+	assert.Empty(t, server.Tags)
+
+	server = HostToServer(inv, "web02.example.com")
+	assert.Equal(t, "web02.example.com", server.Host)
+	assert.Equal(t, []string{"prod", "eu"}, server.Tags)
+
+	server = HostToServer(inv, "api")
+	assert.Equal(t, 2222, server.Port)
+}
+
+func TestHostToServer_LegacyVarsAndMetadata(t *testing.T) {
+	inv, err := ParseINI([]byte(`[payments_api]
+web ansible_ssh_host=web.example.com ansible_ssh_user=deploy ansible_ssh_port=2200 ansible_private_key_file=~/.ssh/k ssherpa_favorite=yes ssherpa_vpn_required=True ssherpa_remote_project_path=/srv/app
+
+[payments_api:vars]
+ssherpa_project=payments/api
+ansible_ssh_extra_args="-J jump.example.com -o 'LocalForward 8080 localhost:80'"
+`))
+	require.NoError(t, err)
+
+	server := HostToServer(inv, "web")
+	assert.Equal(t, "web.example.com", server.Host)
+	assert.Equal(t, "deploy", server.User)
+	assert.Equal(t, 2200, server.Port)
+	assert.Equal(t, "~/.ssh/k", server.IdentityFile)
+	assert.Equal(t, "jump.example.com", server.Proxy)
+	assert.Equal(t, []string{"8080 localhost:80"}, server.SSHOptions["LocalForward"])
+	assert.True(t, server.Favorite)
+	assert.True(t, server.VPNRequired)
+	assert.Equal(t, "/srv/app", server.RemoteProjectPath)
+	assert.Equal(t, []string{"payments/api"}, server.ProjectIDs)
+
+	project := GroupToProject(inv, "payments_api")
+	assert.Equal(t, "payments/api", project.ID)
+	assert.Equal(t, "payments_api", project.Name)
+}
+
+func TestApplySSHArgs(t *testing.T) {
+	inv, err := ParseINI([]byte("web ansible_ssh_common_args=\"-oProxyJump=a -o StrictHostKeyChecking=no -v -Jb -o serveraliveinterval=30 -o ServerAliveInterval=60\"\n"))
+	require.NoError(t, err)
+
+	server := HostToServer(inv, "web")
+	assert.Equal(t, "a", server.Proxy) // the first one wins, as in ssh
+	assert.Equal(t, map[string][]string{
+		"StrictHostKeyChecking": {"no"},
+		"serveraliveinterval":   {"30", "60"},
+	}, server.SSHOptions)
+}
@@ -80,11 +80,29 @@ type Syncer interface {
 	GetStatus() BackendStatus
 }
 
+// VaultLister is an optional interface for backends that keep servers in
+// separate vaults (1Password). Writers offer a choice of vault when more than
+// one is writable; Server.VaultID selects the vault on CreateServer.
+type VaultLister interface {
+	// WritableVaults returns the vaults new servers can be created in.
+	WritableVaults() []Vault
+	// DefaultVaultID returns the vault new servers go to for the given
+	// project ("" for no project), or "" if there is no default.
+	DefaultVaultID(projectID string) string
+}
+
+// Vault is a storage location offered by a VaultLister.
+type Vault struct {
+	ID   string
+	Name string
+}
+
 // ServerFilter captures filter criteria for server queries.
-// All fields are optional (zero values ignored).
+// All fields are optional (zero values ignored). See ServerFilter.Match for
+// the semantics every Filterer implementation follows.
 type ServerFilter struct {
 	ProjectID string   // filter by project ID
 	Tags      []string // filter by tags (servers must have all specified tags)
 	Favorite  *bool    // tri-state: nil=any, true=favorites only, false=non-favorites only
-	Query     string   // free text search (implementation-defined scope)
+	Query     string   // fuzzy text search across name, host, user and tags
 }
@@ -0,0 +1,414 @@
+package bitwarden
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// Backend implements the backendpkg.Backend, backendpkg.Writer,
+// backendpkg.Syncer and backendpkg.Filterer interfaces using Bitwarden as the
+// storage layer. Items carrying the MarkerField custom field are servers.
+type Backend struct {
+	client    Client                   // bw client (real or fake executor)
+	mu        sync.RWMutex             // Protects cached servers, status, scope, and closed flag
+	servers   []*domain.Server         // Cached servers from last sync
+	skipped   []SkippedItem            // Marked items the last sync couldn't convert
+	closed    bool                     // Backend closed flag
+	status    backendpkg.BackendStatus // Current availability status
+	cachePath string                   // Path to TOML cache for fallback
+	poller    *backendpkg.Poller       // Background availability poller
+	lastWrite time.Time                // Last write timestamp for debouncing
+
+	folder     string // Folder name/ID to scope to (empty = none)
+	collection string // Collection name/ID to scope to, wins over folder
+	scope      Scope  // folder/collection resolved to IDs by the last sync
+}
+
+// Compile-time interface verification
+var (
+	_ backendpkg.Backend  = (*Backend)(nil)
+	_ backendpkg.Writer   = (*Backend)(nil)
+	_ backendpkg.Syncer   = (*Backend)(nil)
+	_ backendpkg.Filterer = (*Backend)(nil)
+)
+
+// New creates a new Bitwarden backend with the given client.
+// No initial sync is performed - caller should call SyncFromBitwarden to populate cache.
+func New(client Client) *Backend {
+	return &Backend{
+		client:  client,
+		servers: make([]*domain.Server, 0),
+		status:  backendpkg.StatusUnknown,
+	}
+}
+
+// NewWithCache creates a new Bitwarden backend with cache path for offline fallback.
+func NewWithCache(client Client, cachePath string) *Backend {
+	b := New(client)
+	b.cachePath = cachePath
+	return b
+}
+
+// SetScope limits the backend to one folder or one collection, each given
+// by name or ID. A collection takes precedence over a folder; with neither,
+// marked items anywhere in the vault are synced. New servers are created in
+// the scope. Takes effect on the next sync.
+func (b *Backend) SetScope(folder, collection string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.folder = folder
+	b.collection = collection
+	b.scope = Scope{}
+}
+
+// checkClosed returns ErrBackendUnavailable if backend is closed.
+// Must be called with mu held (either RLock or Lock).
+func (b *Backend) checkClosed() error {
+	if b.closed {
+		return &errors.BackendError{
+			Op:      "checkClosed",
+			Backend: "bitwarden",
+			Err:     errors.ErrBackendUnavailable,
+		}
+	}
+	return nil
+}
+
+// ListServers returns cached servers (populated by SyncFromBitwarden or LoadFromCache).
+func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	// Return copies (copy-on-read pattern)
+	result := make([]*domain.Server, len(b.servers))
+	for i, s := range b.servers {
+		serverCopy := *s
+		result[i] = &serverCopy
+	}
+
+	return result, nil
+}
+
+// GetServer retrieves a server by ID from the cache.
+func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	for _, server := range b.servers {
+		if server.ID == id {
+			serverCopy := *server
+			return &serverCopy, nil
+		}
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetServer",
+		Backend: "bitwarden",
+		Err:     errors.ErrServerNotFound,
+	}
+}
+
+// FilterServers returns the cached servers matching the filter.
+// Project IDs come from the "project_tags" field, tags from the "tags" field
+// and favorites from the item's favorite flag.
+func (b *Backend) FilterServers(ctx context.Context, filters backendpkg.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backendpkg.ApplyFilter(servers, filters), nil
+}
+
+// ListProjects returns an empty slice (projects are fields on items, not standalone entities).
+func (b *Backend) ListProjects(ctx context.Context) ([]*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return []*domain.Project{}, nil
+}
+
+// GetProject returns ErrProjectNotFound (projects are fields, not standalone entities).
+func (b *Backend) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetProject",
+		Backend: "bitwarden",
+		Err:     errors.ErrProjectNotFound,
+	}
+}
+
+// ListCredentials returns an empty slice (keys stay on disk, referenced by identity_file).
+func (b *Backend) ListCredentials(ctx context.Context) ([]*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return []*domain.Credential{}, nil
+}
+
+// GetCredential returns ErrCredentialNotFound (credentials are not stored in Bitwarden).
+func (b *Backend) GetCredential(ctx context.Context, id string) (*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetCredential",
+		Backend: "bitwarden",
+		Err:     errors.ErrCredentialNotFound,
+	}
+}
+
+// Close releases resources held by the backend.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+
+	// Stop poller before closing client
+	if b.poller != nil {
+		poller := b.poller
+		b.poller = nil
+		b.mu.Unlock() // Unlock before calling Stop() to avoid deadlock
+		poller.Stop()
+		b.mu.Lock() // Re-lock for closed flag update
+	}
+
+	b.closed = true
+	b.mu.Unlock()
+	return b.client.Close()
+}
+
+// CreateServer creates a new server as a secure note in the configured
+// folder or collection. server.ID is replaced by the ID bw assigns.
+func (b *Backend) CreateServer(ctx context.Context, server *domain.Server) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	scope, err := b.resolveScope(ctx)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "CreateServer",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	item := ServerToItem(server, scope)
+	item.ID = ""
+
+	created, err := b.client.CreateItem(ctx, item)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "CreateServer",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	// Convert back and add to cache
+	newServer, err := ItemToServer(created)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "CreateServer",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	b.servers = append(b.servers, newServer)
+
+	// Update last write timestamp
+	b.lastWrite = time.Now()
+
+	return nil
+}
+
+// UpdateServer updates an existing server in Bitwarden. Custom fields
+// ssherpa doesn't manage are kept.
+func (b *Backend) UpdateServer(ctx context.Context, server *domain.Server) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	index := b.indexOf(server.ID)
+	if index < 0 {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "bitwarden",
+			Err:     errors.ErrServerNotFound,
+		}
+	}
+
+	// Get existing item to preserve fields we don't manage
+	existing, err := b.client.GetItem(ctx, server.ID)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	updated := ServerToItem(server, Scope{})
+	updated.Fields = mergeFields(existing.Fields, updated.Fields)
+
+	saved, err := b.client.UpdateItem(ctx, updated)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	// Update cache
+	serverCopy := *server
+	serverCopy.Source = "bitwarden"
+	serverCopy.Revision = itemRevision(saved)
+	b.servers[index] = &serverCopy
+
+	// Update last write timestamp
+	b.lastWrite = time.Now()
+
+	return nil
+}
+
+// DeleteServer moves a server's item to the Bitwarden trash.
+func (b *Backend) DeleteServer(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	index := b.indexOf(id)
+	if index < 0 {
+		return &errors.BackendError{
+			Op:      "DeleteServer",
+			Backend: "bitwarden",
+			Err:     errors.ErrServerNotFound,
+		}
+	}
+
+	if err := b.client.DeleteItem(ctx, id); err != nil {
+		return &errors.BackendError{
+			Op:      "DeleteServer",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	// Remove from cache
+	b.servers = append(b.servers[:index], b.servers[index+1:]...)
+
+	// Update last write timestamp
+	b.lastWrite = time.Now()
+
+	return nil
+}
+
+// indexOf returns the cache index of the server with id, or -1.
+// Must be called with mu held.
+func (b *Backend) indexOf(id string) int {
+	for i, server := range b.servers {
+		if server.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// CreateProject returns ErrReadOnlyBackend (projects are fields, not standalone entities).
+func (b *Backend) CreateProject(ctx context.Context, project *domain.Project) error {
+	return &errors.BackendError{
+		Op:      "CreateProject",
+		Backend: "bitwarden",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// UpdateProject returns ErrReadOnlyBackend (projects are fields, not standalone entities).
+func (b *Backend) UpdateProject(ctx context.Context, project *domain.Project) error {
+	return &errors.BackendError{
+		Op:      "UpdateProject",
+		Backend: "bitwarden",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// DeleteProject returns ErrReadOnlyBackend (projects are fields, not standalone entities).
+func (b *Backend) DeleteProject(ctx context.Context, id string) error {
+	return &errors.BackendError{
+		Op:      "DeleteProject",
+		Backend: "bitwarden",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// CreateCredential returns ErrReadOnlyBackend (credentials are not stored in Bitwarden).
+func (b *Backend) CreateCredential(ctx context.Context, cred *domain.Credential) error {
+	return &errors.BackendError{
+		Op:      "CreateCredential",
+		Backend: "bitwarden",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// UpdateCredential returns ErrReadOnlyBackend (credentials are not stored in Bitwarden).
+func (b *Backend) UpdateCredential(ctx context.Context, cred *domain.Credential) error {
+	return &errors.BackendError{
+		Op:      "UpdateCredential",
+		Backend: "bitwarden",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// DeleteCredential returns ErrReadOnlyBackend (credentials are not stored in Bitwarden).
+func (b *Backend) DeleteCredential(ctx context.Context, id string) error {
+	return &errors.BackendError{
+		Op:      "DeleteCredential",
+		Backend: "bitwarden",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
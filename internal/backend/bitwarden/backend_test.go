@@ -0,0 +1,209 @@
+package bitwarden
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seededFake returns a fake bw with one marked server, one unmarked login
+// and one marked item that lacks a hostname.
+func seededFake(t *testing.T) *fakeBW {
+	fake := newFakeBW()
+	fake.folders = []Folder{{ID: "f1", Name: "Servers"}}
+	fake.collections = []Collection{{ID: "c1", OrganizationID: "o1", Name: "Ops"}}
+	fake.addItem(t, Item{
+		ID: "a", Type: ItemTypeSecureNote, Name: "web", FolderID: "f1",
+		Fields: []Field{{Name: "ssherpa", Value: "true"}, {Name: "hostname", Value: "web.example.com"}, {Name: "user", Value: "deploy"}},
+	})
+	fake.addItem(t, Item{ID: "b", Type: ItemTypeLogin, Name: "bank", Login: &Login{Username: "me"}})
+	fake.addItem(t, Item{
+		ID: "c", Type: ItemTypeSecureNote, Name: "broken", OrganizationID: "o1", CollectionIDs: []string{"c1"},
+		Fields: []Field{{Name: "ssherpa"}, {Name: "user", Value: "root"}},
+	})
+	return fake
+}
+
+func TestSyncFromBitwarden(t *testing.T) {
+	fake := seededFake(t)
+	cachePath := filepath.Join(t.TempDir(), "cache.toml")
+	b := NewWithCache(newTestClient(fake), cachePath)
+	ctx := context.Background()
+
+	assert.Equal(t, backendpkg.StatusUnknown, b.GetStatus())
+	require.NoError(t, b.SyncFromBitwarden(ctx))
+	assert.Equal(t, backendpkg.StatusAvailable, b.GetStatus())
+
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "web.example.com", servers[0].Host)
+
+	skipped := b.SkippedItems()
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "broken", skipped[0].Name)
+
+	cached, err := sync.ReadTOMLCache(cachePath)
+	require.NoError(t, err)
+	require.Len(t, cached, 1)
+	assert.Equal(t, "a", cached[0].ID)
+}
+
+func TestSyncFromBitwarden_Status(t *testing.T) {
+	tests := []struct {
+		state string
+		want  backendpkg.BackendStatus
+	}{
+		{StatusLocked, backendpkg.StatusLocked},
+		{StatusUnauthenticated, backendpkg.StatusNotSignedIn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			fake := seededFake(t)
+			fake.status = tt.state
+			b := New(newTestClient(fake))
+
+			err := b.SyncFromBackend(context.Background())
+			require.Error(t, err)
+			assert.Equal(t, tt.want, b.GetStatus())
+		})
+	}
+}
+
+func TestSyncFromBitwarden_Unavailable(t *testing.T) {
+	fake := seededFake(t)
+	fake.fail["sync"] = errors.New("connect ECONNREFUSED")
+	b := New(newTestClient(fake))
+
+	require.Error(t, b.SyncFromBitwarden(context.Background()))
+	assert.Equal(t, backendpkg.StatusUnavailable, b.GetStatus())
+}
+
+func TestSetScope(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("folder by name", func(t *testing.T) {
+		b := New(newTestClient(seededFake(t)))
+		b.SetScope("servers", "")
+		require.NoError(t, b.SyncFromBitwarden(ctx))
+		servers, _ := b.ListServers(ctx)
+		assert.Len(t, servers, 1)
+		assert.Empty(t, b.SkippedItems())
+	})
+
+	t.Run("collection by ID", func(t *testing.T) {
+		b := New(newTestClient(seededFake(t)))
+		b.SetScope("Servers", "c1")
+		require.NoError(t, b.SyncFromBitwarden(ctx))
+		servers, _ := b.ListServers(ctx)
+		assert.Empty(t, servers)
+		assert.Len(t, b.SkippedItems(), 1)
+	})
+
+	t.Run("missing folder", func(t *testing.T) {
+		b := New(newTestClient(seededFake(t)))
+		b.SetScope("Nope", "")
+		err := b.SyncFromBitwarden(ctx)
+		assert.ErrorContains(t, err, `folder "Nope" not found`)
+	})
+}
+
+func TestCreateServer(t *testing.T) {
+	fake := seededFake(t)
+	b := New(newTestClient(fake))
+	b.SetScope("", "Ops")
+	ctx := context.Background()
+	require.NoError(t, b.SyncFromBitwarden(ctx))
+
+	err := b.CreateServer(ctx, &domain.Server{DisplayName: "db", Host: "db.example.com", User: "postgres"})
+	require.NoError(t, err)
+
+	created := fake.find("bw-1")
+	require.NotNil(t, created)
+	assert.Equal(t, "o1", created["organizationId"])
+	assert.Equal(t, []any{"c1"}, created["collectionIds"])
+	assert.EqualValues(t, ItemTypeSecureNote, created["type"])
+
+	server, err := b.GetServer(ctx, "bw-1")
+	require.NoError(t, err)
+	assert.Equal(t, "db.example.com", server.Host)
+}
+
+func TestUpdateServer_KeepsOtherFields(t *testing.T) {
+	fake := seededFake(t)
+	fake.items[0]["fields"] = append(fake.items[0]["fields"].([]any), map[string]any{"name": "api_key", "value": "secret", "type": 1})
+	fake.items[0]["notes"] = "runbook"
+	b := New(newTestClient(fake))
+	ctx := context.Background()
+	require.NoError(t, b.SyncFromBitwarden(ctx))
+
+	server, err := b.GetServer(ctx, "a")
+	require.NoError(t, err)
+	server.Host = "web2.example.com"
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	item, err := newTestClient(fake).GetItem(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "runbook", item.Notes)
+	assert.Contains(t, item.Fields, Field{Name: "hostname", Value: "web2.example.com"})
+	assert.Contains(t, item.Fields, Field{Name: "api_key", Value: "secret", Type: FieldTypeHidden})
+
+	cached, err := b.GetServer(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "web2.example.com", cached.Host)
+}
+
+func TestDeleteServer(t *testing.T) {
+	fake := seededFake(t)
+	b := New(newTestClient(fake))
+	ctx := context.Background()
+	require.NoError(t, b.SyncFromBitwarden(ctx))
+
+	require.NoError(t, b.DeleteServer(ctx, "a"))
+	assert.Nil(t, fake.find("a"))
+
+	_, err := b.GetServer(ctx, "a")
+	assert.ErrorIs(t, err, errors.ErrServerNotFound)
+	assert.ErrorIs(t, b.DeleteServer(ctx, "a"), errors.ErrServerNotFound)
+}
+
+func TestProjectAndCredentialWritesAreReadOnly(t *testing.T) {
+	b := New(newTestClient(newFakeBW()))
+	ctx := context.Background()
+
+	assert.ErrorIs(t, b.CreateProject(ctx, &domain.Project{}), errors.ErrReadOnlyBackend)
+	assert.ErrorIs(t, b.CreateCredential(ctx, &domain.Credential{}), errors.ErrReadOnlyBackend)
+}
+
+func TestLoadFromCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache.toml")
+	require.NoError(t, sync.WriteTOMLCache([]*domain.Server{{ID: "a", DisplayName: "web", Host: "h", User: "u", Port: 22}}, cachePath))
+
+	fake := newFakeBW()
+	fake.status = StatusLocked
+	b := NewWithCache(newTestClient(fake), cachePath)
+	require.Error(t, b.SyncFromBitwarden(context.Background()))
+	require.NoError(t, b.LoadFromCache())
+
+	servers, err := b.ListServers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, backendpkg.StatusLocked, b.GetStatus())
+}
+
+func TestClose(t *testing.T) {
+	b := New(newTestClient(seededFake(t)))
+	b.StartPolling(time.Hour, nil)
+	require.NoError(t, b.Close())
+
+	_, err := b.ListServers(context.Background())
+	assert.ErrorIs(t, err, errors.ErrBackendUnavailable)
+}
@@ -0,0 +1,249 @@
+package bitwarden
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// CommandExecutor abstracts command execution for testability.
+type CommandExecutor interface {
+	Run(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error)
+}
+
+// defaultExecutor implements CommandExecutor using os/exec.
+// bw reads the unlocked session from BW_SESSION in the inherited environment.
+type defaultExecutor struct{}
+
+func (e *defaultExecutor) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdout, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return stdout, exitErr.Stderr, err
+		}
+		return stdout, nil, err
+	}
+	return stdout, nil, nil
+}
+
+// SessionEnv is the environment variable bw reads the session key of an
+// unlocked vault from ("bw unlock --raw" prints it).
+const SessionEnv = "BW_SESSION"
+
+// CLIClient implements the Client interface using the bw CLI.
+type CLIClient struct {
+	bwPath   string
+	executor CommandExecutor
+}
+
+// NewCLIClient creates a new CLI-based Bitwarden client.
+// It resolves the bw binary location and verifies it exists.
+func NewCLIClient() (*CLIClient, error) {
+	bwPath, err := exec.LookPath("bw")
+	if err != nil {
+		return nil, fmt.Errorf("bw CLI not found in PATH: %w", err)
+	}
+
+	return &CLIClient{
+		bwPath:   bwPath,
+		executor: &defaultExecutor{},
+	}, nil
+}
+
+// runBW executes a bw command and returns its stdout. --nointeraction keeps
+// bw from prompting for the master password when the vault is locked.
+func (c *CLIClient) runBW(ctx context.Context, args ...string) ([]byte, error) {
+	args = append([]string{"--nointeraction"}, args...)
+	stdout, stderr, err := c.executor.Run(ctx, c.bwPath, args...)
+	if err != nil {
+		// Include stderr in error message for debugging
+		if len(stderr) > 0 {
+			return nil, fmt.Errorf("bw command failed: %w (stderr: %s)", err, string(stderr))
+		}
+		return nil, fmt.Errorf("bw command failed: %w", err)
+	}
+	return stdout, nil
+}
+
+// Status returns the vault state from "bw status": StatusUnlocked,
+// StatusLocked or StatusUnauthenticated.
+func (c *CLIClient) Status(ctx context.Context) (string, error) {
+	output, err := c.runBW(ctx, "status")
+	if err != nil {
+		return "", fmt.Errorf("failed to get status: %w", err)
+	}
+
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(output, &status); err != nil {
+		return "", fmt.Errorf("failed to parse status response: %w", err)
+	}
+	return status.Status, nil
+}
+
+// Sync pulls the latest vault data from the server. bw answers list and get
+// commands from its local copy, so changes by others only show up after a sync.
+func (c *CLIClient) Sync(ctx context.Context) error {
+	if _, err := c.runBW(ctx, "sync"); err != nil {
+		return fmt.Errorf("failed to sync vault: %w", err)
+	}
+	return nil
+}
+
+// ListFolders retrieves the personal folders.
+func (c *CLIClient) ListFolders(ctx context.Context) ([]Folder, error) {
+	output, err := c.runBW(ctx, "list", "folders")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+
+	var folders []Folder
+	if err := json.Unmarshal(output, &folders); err != nil {
+		return nil, fmt.Errorf("failed to parse folder list response: %w", err)
+	}
+	return folders, nil
+}
+
+// ListCollections retrieves the organization collections the user can access.
+func (c *CLIClient) ListCollections(ctx context.Context) ([]Collection, error) {
+	output, err := c.runBW(ctx, "list", "collections")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	var collections []Collection
+	if err := json.Unmarshal(output, &collections); err != nil {
+		return nil, fmt.Errorf("failed to parse collection list response: %w", err)
+	}
+	return collections, nil
+}
+
+// ListItems retrieves the items in scope. Unlike "op item list", bw returns
+// full items with their fields.
+func (c *CLIClient) ListItems(ctx context.Context, scope Scope) ([]Item, error) {
+	args := []string{"list", "items"}
+	switch {
+	case scope.CollectionID != "":
+		args = append(args, "--collectionid", scope.CollectionID)
+	case scope.FolderID != "":
+		args = append(args, "--folderid", scope.FolderID)
+	}
+
+	output, err := c.runBW(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	var items []Item
+	if err := json.Unmarshal(output, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse item list response: %w", err)
+	}
+	return items, nil
+}
+
+// GetItem retrieves a specific item by ID.
+func (c *CLIClient) GetItem(ctx context.Context, id string) (*Item, error) {
+	output, err := c.runBW(ctx, "get", "item", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item %s: %w", id, err)
+	}
+
+	var item Item
+	if err := json.Unmarshal(output, &item); err != nil {
+		return nil, fmt.Errorf("failed to parse item response: %w", err)
+	}
+	return &item, nil
+}
+
+// CreateItem creates a new item. bw takes the item as base64-encoded JSON
+// (what "bw encode" produces).
+func (c *CLIClient) CreateItem(ctx context.Context, item *Item) (*Item, error) {
+	if item == nil {
+		return nil, fmt.Errorf("item cannot be nil")
+	}
+
+	encoded, err := encodeJSON(item)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.runBW(ctx, "create", "item", encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item: %w", err)
+	}
+
+	var created Item
+	if err := json.Unmarshal(output, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse create item response: %w", err)
+	}
+	return &created, nil
+}
+
+// UpdateItem sets an existing item's name, favorite flag and custom fields.
+// "bw edit item" replaces the whole item, so the current item is fetched as
+// raw JSON first and only those keys are changed: the login, password
+// history, notes and anything else ssherpa doesn't model are kept.
+func (c *CLIClient) UpdateItem(ctx context.Context, item *Item) (*Item, error) {
+	if item == nil {
+		return nil, fmt.Errorf("item cannot be nil")
+	}
+	if item.ID == "" {
+		return nil, fmt.Errorf("item ID is required for update")
+	}
+
+	output, err := c.runBW(ctx, "get", "item", item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item %s: %w", item.ID, err)
+	}
+	var current map[string]any
+	if err := json.Unmarshal(output, &current); err != nil {
+		return nil, fmt.Errorf("failed to parse item response: %w", err)
+	}
+
+	current["name"] = item.Name
+	current["favorite"] = item.Favorite
+	current["fields"] = item.Fields
+
+	encoded, err := encodeJSON(current)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = c.runBW(ctx, "edit", "item", item.ID, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item %s: %w", item.ID, err)
+	}
+
+	var updated Item
+	if err := json.Unmarshal(output, &updated); err != nil {
+		return nil, fmt.Errorf("failed to parse update item response: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteItem moves an item to the trash.
+func (c *CLIClient) DeleteItem(ctx context.Context, id string) error {
+	if _, err := c.runBW(ctx, "delete", "item", id); err != nil {
+		return fmt.Errorf("failed to delete item %s: %w", id, err)
+	}
+	return nil
+}
+
+// Close releases resources held by the CLI client.
+// No-op for CLI client as there are no persistent connections.
+func (c *CLIClient) Close() error {
+	return nil
+}
+
+// encodeJSON encodes v as base64 JSON for bw create and edit.
+func encodeJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encode item: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,246 @@
+package bitwarden
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBW implements CommandExecutor as an in-memory bw CLI. Items are kept
+// as raw JSON maps so tests can check that edits keep keys ssherpa doesn't
+// model.
+type fakeBW struct {
+	status      string
+	folders     []Folder
+	collections []Collection
+	items       []map[string]any
+	nextID      int
+	calls       [][]string
+	fail        map[string]error // command ("list", "sync", ...) -> error
+}
+
+func newFakeBW() *fakeBW {
+	return &fakeBW{status: StatusUnlocked, fail: make(map[string]error)}
+}
+
+// newTestClient returns a CLIClient that runs commands against fake.
+func newTestClient(fake *fakeBW) *CLIClient {
+	return &CLIClient{bwPath: "bw", executor: fake}
+}
+
+// addItem stores item as bw would return it.
+func (f *fakeBW) addItem(t *testing.T, item Item) {
+	t.Helper()
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	f.items = append(f.items, raw)
+}
+
+func (f *fakeBW) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	f.calls = append(f.calls, args)
+	if len(args) == 0 || args[0] != "--nointeraction" {
+		return nil, []byte("? Master password:"), errors.New("prompted for input")
+	}
+	args = args[1:]
+	if err := f.fail[args[0]]; err != nil {
+		return nil, []byte(err.Error()), err
+	}
+
+	switch {
+	case args[0] == "status":
+		return marshal(map[string]string{"status": f.status})
+	case args[0] == "sync":
+		return []byte("Syncing complete."), nil, nil
+	case len(args) >= 2 && args[0] == "list" && args[1] == "folders":
+		return marshal(f.folders)
+	case len(args) >= 2 && args[0] == "list" && args[1] == "collections":
+		return marshal(f.collections)
+	case len(args) >= 2 && args[0] == "list" && args[1] == "items":
+		return marshal(f.listItems(args[2:]))
+	case len(args) == 3 && args[0] == "get" && args[1] == "item":
+		if item := f.find(args[2]); item != nil {
+			return marshal(item)
+		}
+		return nil, []byte("Not found."), errors.New("exit status 1")
+	case len(args) == 3 && args[0] == "create" && args[1] == "item":
+		raw, err := decode(args[2])
+		if err != nil {
+			return nil, nil, err
+		}
+		f.nextID++
+		raw["id"] = fmt.Sprintf("bw-%d", f.nextID)
+		raw["revisionDate"] = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Format(time.RFC3339Nano)
+		f.items = append(f.items, raw)
+		return marshal(raw)
+	case len(args) == 4 && args[0] == "edit" && args[1] == "item":
+		raw, err := decode(args[3])
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, item := range f.items {
+			if item["id"] == args[2] {
+				raw["id"] = args[2]
+				raw["revisionDate"] = time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC).Format(time.RFC3339Nano)
+				f.items[i] = raw
+				return marshal(raw)
+			}
+		}
+		return nil, []byte("Not found."), errors.New("exit status 1")
+	case len(args) == 3 && args[0] == "delete" && args[1] == "item":
+		for i, item := range f.items {
+			if item["id"] == args[2] {
+				f.items = slices.Delete(f.items, i, i+1)
+				return nil, nil, nil
+			}
+		}
+		return nil, []byte("Not found."), errors.New("exit status 1")
+	}
+	return nil, []byte("unknown command"), fmt.Errorf("unexpected bw call: %s", strings.Join(args, " "))
+}
+
+// listItems applies the --folderid and --collectionid flags.
+func (f *fakeBW) listItems(flags []string) []map[string]any {
+	result := []map[string]any{}
+	for _, item := range f.items {
+		keep := true
+		for i := 0; i+1 < len(flags); i += 2 {
+			switch flags[i] {
+			case "--folderid":
+				keep = keep && item["folderId"] == flags[i+1]
+			case "--collectionid":
+				ids, _ := item["collectionIds"].([]any)
+				keep = keep && slices.Contains(ids, any(flags[i+1]))
+			}
+		}
+		if keep {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (f *fakeBW) find(id string) map[string]any {
+	for _, item := range f.items {
+		if item["id"] == id {
+			return item
+		}
+	}
+	return nil
+}
+
+func marshal(v any) ([]byte, []byte, error) {
+	data, err := json.Marshal(v)
+	return data, nil, err
+}
+
+func decode(encoded string) (map[string]any, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("item is not base64: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func TestCLIClient_Status(t *testing.T) {
+	fake := newFakeBW()
+	fake.status = StatusLocked
+	client := newTestClient(fake)
+
+	status, err := client.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusLocked, status)
+	assert.Equal(t, []string{"--nointeraction", "status"}, fake.calls[0])
+}
+
+func TestCLIClient_ListItemsScope(t *testing.T) {
+	fake := newFakeBW()
+	fake.addItem(t, Item{ID: "a", Name: "in folder", FolderID: "f1"})
+	fake.addItem(t, Item{ID: "b", Name: "in collection", OrganizationID: "o1", CollectionIDs: []string{"c1"}})
+	client := newTestClient(fake)
+	ctx := context.Background()
+
+	all, err := client.ListItems(ctx, Scope{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	inFolder, err := client.ListItems(ctx, Scope{FolderID: "f1"})
+	require.NoError(t, err)
+	require.Len(t, inFolder, 1)
+	assert.Equal(t, "a", inFolder[0].ID)
+
+	inCollection, err := client.ListItems(ctx, Scope{CollectionID: "c1", OrganizationID: "o1"})
+	require.NoError(t, err)
+	require.Len(t, inCollection, 1)
+	assert.Equal(t, "b", inCollection[0].ID)
+}
+
+func TestCLIClient_CreateItemEncodesJSON(t *testing.T) {
+	fake := newFakeBW()
+	client := newTestClient(fake)
+
+	created, err := client.CreateItem(context.Background(), &Item{
+		Type:       ItemTypeSecureNote,
+		Name:       "web",
+		SecureNote: &SecureNote{},
+		Fields:     []Field{{Name: "hostname", Value: "web.example.com"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "bw-1", created.ID)
+	assert.Equal(t, "web", created.Name)
+	assert.False(t, created.RevisionDate.IsZero())
+	require.Len(t, created.Fields, 1)
+	assert.Equal(t, "web.example.com", created.Fields[0].Value)
+}
+
+func TestCLIClient_UpdateItemKeepsUnmodelledKeys(t *testing.T) {
+	fake := newFakeBW()
+	fake.addItem(t, Item{ID: "a", Type: ItemTypeLogin, Name: "old", Notes: "keep me"})
+	fake.items[0]["passwordHistory"] = []any{map[string]any{"password": "hunter2"}}
+	client := newTestClient(fake)
+
+	updated, err := client.UpdateItem(context.Background(), &Item{
+		ID:     "a",
+		Name:   "new",
+		Fields: []Field{{Name: "user", Value: "deploy"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "new", updated.Name)
+	assert.Equal(t, "keep me", updated.Notes)
+	assert.Equal(t, ItemTypeLogin, updated.Type)
+	assert.NotNil(t, fake.items[0]["passwordHistory"])
+}
+
+func TestCLIClient_ErrorIncludesStderr(t *testing.T) {
+	fake := newFakeBW()
+	fake.fail["sync"] = errors.New("You are not logged in.")
+	client := newTestClient(fake)
+
+	err := client.Sync(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not logged in")
+}
+
+func TestCLIClient_DeleteItem(t *testing.T) {
+	fake := newFakeBW()
+	fake.addItem(t, Item{ID: "a", Name: "web"})
+	client := newTestClient(fake)
+
+	require.NoError(t, client.DeleteItem(context.Background(), "a"))
+	assert.Empty(t, fake.items)
+	assert.Error(t, client.DeleteItem(context.Background(), "a"))
+}
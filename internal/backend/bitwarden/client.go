@@ -0,0 +1,103 @@
+package bitwarden
+
+import (
+	"context"
+	"time"
+)
+
+// Client abstracts Bitwarden operations for testability.
+// CLIClient implements it with the bw CLI; tests swap in a fake executor.
+type Client interface {
+	Status(ctx context.Context) (string, error)
+	Sync(ctx context.Context) error
+	ListFolders(ctx context.Context) ([]Folder, error)
+	ListCollections(ctx context.Context) ([]Collection, error)
+	ListItems(ctx context.Context, scope Scope) ([]Item, error)
+	GetItem(ctx context.Context, id string) (*Item, error)
+	CreateItem(ctx context.Context, item *Item) (*Item, error)
+	UpdateItem(ctx context.Context, item *Item) (*Item, error)
+	DeleteItem(ctx context.Context, id string) error
+	Close() error
+}
+
+// Vault states reported by "bw status".
+const (
+	StatusUnlocked        = "unlocked"
+	StatusLocked          = "locked"
+	StatusUnauthenticated = "unauthenticated"
+)
+
+// Item types used by ssherpa. New servers are stored as secure notes; login
+// items carrying the marker field are read too.
+const (
+	ItemTypeLogin      = 1
+	ItemTypeSecureNote = 2
+)
+
+// Custom field types.
+const (
+	FieldTypeText    = 0
+	FieldTypeHidden  = 1
+	FieldTypeBoolean = 2
+)
+
+// Folder is a personal Bitwarden folder.
+type Folder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Collection is an organization collection.
+type Collection struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organizationId"`
+	Name           string `json:"name"`
+}
+
+// Scope limits listing and creation to one folder or one collection.
+// Bitwarden has no vaults like 1Password; folders and collections play that
+// role. The zero Scope covers every item.
+type Scope struct {
+	FolderID       string
+	CollectionID   string
+	OrganizationID string // owner of CollectionID, needed to create items in it
+}
+
+// Item is a Bitwarden item in the JSON shape bw reads and writes.
+type Item struct {
+	ID             string      `json:"id,omitempty"`
+	OrganizationID string      `json:"organizationId,omitempty"`
+	FolderID       string      `json:"folderId,omitempty"`
+	CollectionIDs  []string    `json:"collectionIds,omitempty"`
+	Type           int         `json:"type"`
+	Name           string      `json:"name"`
+	Notes          string      `json:"notes,omitempty"`
+	Favorite       bool        `json:"favorite"`
+	Fields         []Field     `json:"fields,omitempty"`
+	Login          *Login      `json:"login,omitempty"`
+	SecureNote     *SecureNote `json:"secureNote,omitempty"`
+	RevisionDate   time.Time   `json:"revisionDate,omitzero"`
+}
+
+// Field is a custom field on an item.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+// Login holds the login part of a login item.
+type Login struct {
+	Username string     `json:"username,omitempty"`
+	URIs     []LoginURI `json:"uris,omitempty"`
+}
+
+// LoginURI is a website entry of a login item.
+type LoginURI struct {
+	URI string `json:"uri"`
+}
+
+// SecureNote marks an item as a secure note (bw requires the object).
+type SecureNote struct {
+	Type int `json:"type"`
+}
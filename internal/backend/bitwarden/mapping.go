@@ -0,0 +1,241 @@
+package bitwarden
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+)
+
+// MarkerField is the custom field that marks an item as an ssherpa server.
+// Bitwarden items have no tags, so the field plays the role of 1Password's
+// "ssherpa" tag; its value doesn't matter.
+const MarkerField = "ssherpa"
+
+// managedFields lists the custom fields ServerToItem writes, named like the
+// 1Password fields so items read the same in both managers. UpdateServer
+// replaces these and keeps every other custom field on the item.
+var managedFields = map[string]bool{
+	MarkerField:           true,
+	"hostname":            true,
+	"user":                true,
+	"port":                true,
+	"identity_file":       true,
+	"remote_project_path": true,
+	"project_tags":        true,
+	"proxy_jump":          true,
+	"vpn_required":        true,
+	"forward_agent":       true,
+	"extra_config":        true,
+	"tags":                true,
+}
+
+// HasMarker reports whether the item carries the ssherpa marker field.
+func HasMarker(item *Item) bool {
+	for _, field := range item.Fields {
+		if strings.EqualFold(field.Name, MarkerField) {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemToServer converts a Bitwarden item to a domain.Server.
+// The user falls back to the login username on login items.
+// Returns error if required fields (hostname, user) are missing.
+func ItemToServer(item *Item) (*domain.Server, error) {
+	server := &domain.Server{
+		ID:          item.ID,
+		DisplayName: item.Name,
+		Port:        22, // default port
+		Favorite:    item.Favorite,
+		Source:      "bitwarden",
+		Tags:        []string{},
+		Revision:    itemRevision(item),
+	}
+
+	for _, field := range item.Fields {
+		value := strings.TrimSpace(field.Value)
+
+		switch strings.ToLower(field.Name) {
+		case "hostname":
+			server.Host = value
+		case "user":
+			server.User = value
+		case "port":
+			if port, err := strconv.Atoi(value); err == nil {
+				server.Port = port
+			}
+		case "identity_file":
+			server.IdentityFile = value
+		case "remote_project_path":
+			server.RemoteProjectPath = value
+		case "project_tags":
+			server.ProjectIDs = splitList(value)
+		case "tags":
+			server.Tags = splitList(value)
+		case "proxy_jump":
+			server.Proxy = value
+		case "vpn_required":
+			server.VPNRequired = parseBoolField(value)
+		case "forward_agent":
+			if value != "" {
+				setSSHOption(server, "ForwardAgent", formatBoolField(parseBoolField(value)))
+			}
+		case "extra_config":
+			for key, values := range sshconfig.ParseDirectives(field.Value) {
+				for _, v := range values {
+					setSSHOption(server, key, v)
+				}
+			}
+		}
+	}
+
+	if server.User == "" && item.Login != nil {
+		server.User = strings.TrimSpace(item.Login.Username)
+	}
+
+	// Validate required fields
+	if server.Host == "" {
+		return nil, fmt.Errorf("item %q (id: %s) missing required field: hostname", item.Name, item.ID)
+	}
+	if server.User == "" {
+		return nil, fmt.Errorf("item %q (id: %s) missing required field: user", item.Name, item.ID)
+	}
+
+	return server, nil
+}
+
+// ServerToItem converts a domain.Server to a Bitwarden secure note in scope.
+// Server data goes into custom fields; the marker field is always present.
+func ServerToItem(server *domain.Server, scope Scope) *Item {
+	item := &Item{
+		ID:         server.ID,
+		Type:       ItemTypeSecureNote,
+		Name:       server.DisplayName,
+		Favorite:   server.Favorite,
+		SecureNote: &SecureNote{},
+		Fields:     serverFields(server),
+	}
+
+	switch {
+	case scope.CollectionID != "":
+		item.OrganizationID = scope.OrganizationID
+		item.CollectionIDs = []string{scope.CollectionID}
+	case scope.FolderID != "":
+		item.FolderID = scope.FolderID
+	}
+	return item
+}
+
+// serverFields returns the custom fields for a server, marker first.
+func serverFields(server *domain.Server) []Field {
+	fields := []Field{
+		{Name: MarkerField, Value: "true", Type: FieldTypeText},
+		{Name: "hostname", Value: server.Host, Type: FieldTypeText},
+		{Name: "user", Value: server.User, Type: FieldTypeText},
+	}
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, Field{Name: name, Value: value, Type: FieldTypeText})
+		}
+	}
+
+	if server.Port != 22 && server.Port != 0 {
+		add("port", strconv.Itoa(server.Port))
+	}
+	add("identity_file", server.IdentityFile)
+	add("remote_project_path", server.RemoteProjectPath)
+	add("project_tags", strings.Join(server.ProjectIDs, ","))
+	add("tags", strings.Join(server.Tags, ","))
+	add("proxy_jump", server.Proxy)
+	if server.VPNRequired {
+		add("vpn_required", "true")
+	}
+
+	// ForwardAgent has its own field; every other option goes to extra_config
+	var extra []string
+	forwardAgent := false
+	for _, line := range server.SSHOptionLines() {
+		key, value, _ := strings.Cut(line, " ")
+		lower := strings.ToLower(value)
+		if !forwardAgent && strings.EqualFold(key, "ForwardAgent") && (lower == "yes" || lower == "no") {
+			forwardAgent = true
+			add("forward_agent", lower)
+			continue
+		}
+		extra = append(extra, line)
+	}
+	add("extra_config", strings.Join(extra, "\n"))
+
+	return fields
+}
+
+// mergeFields replaces the managed fields of an existing item with the
+// fields generated from a server and keeps the item's other custom fields
+// (API keys, notes in fields, ...) after them.
+func mergeFields(existing, generated []Field) []Field {
+	merged := append([]Field{}, generated...)
+	for _, f := range existing {
+		if !managedFields[strings.ToLower(f.Name)] {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+// itemRevision identifies an item's state for the cache: bw bumps
+// revisionDate on every change. Empty when bw reported none.
+func itemRevision(item *Item) string {
+	if item.RevisionDate.IsZero() {
+		return ""
+	}
+	return item.RevisionDate.UTC().Format(time.RFC3339Nano)
+}
+
+// splitList parses a comma-separated field, dropping blanks.
+func splitList(value string) []string {
+	var list []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// setSSHOption appends value to the server's SSH options under key, reusing
+// the spelling of a keyword that is already present.
+func setSSHOption(server *domain.Server, key, value string) {
+	if server.SSHOptions == nil {
+		server.SSHOptions = make(map[string][]string)
+	}
+	for existing := range server.SSHOptions {
+		if strings.EqualFold(existing, key) {
+			key = existing
+			break
+		}
+	}
+	server.SSHOptions[key] = append(server.SSHOptions[key], value)
+}
+
+// parseBoolField interprets a yes/no style text field.
+func parseBoolField(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// formatBoolField renders a boolean as the yes/no form ssh_config uses.
+func formatBoolField(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
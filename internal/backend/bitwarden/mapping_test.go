@@ -0,0 +1,135 @@
+package bitwarden
+
+import (
+	"testing"
+	"time"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemToServer(t *testing.T) {
+	item := &Item{
+		ID:           "bw-1",
+		Name:         "Production",
+		Favorite:     true,
+		RevisionDate: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Fields: []Field{
+			{Name: "ssherpa", Value: "true"},
+			{Name: "Hostname", Value: " prod.example.com "},
+			{Name: "user", Value: "deploy"},
+			{Name: "port", Value: "2222"},
+			{Name: "identity_file", Value: "~/.ssh/prod"},
+			{Name: "project_tags", Value: "shop, api"},
+			{Name: "tags", Value: "prod,eu"},
+			{Name: "proxy_jump", Value: "bastion"},
+			{Name: "vpn_required", Value: "yes"},
+			{Name: "forward_agent", Value: "true"},
+			{Name: "extra_config", Value: "ServerAliveInterval 30\nLocalForward 8080 localhost:80"},
+			{Name: "api_key", Value: "secret", Type: FieldTypeHidden},
+		},
+	}
+
+	server, err := ItemToServer(item)
+	require.NoError(t, err)
+	assert.Equal(t, "bw-1", server.ID)
+	assert.Equal(t, "Production", server.DisplayName)
+	assert.Equal(t, "prod.example.com", server.Host)
+	assert.Equal(t, "deploy", server.User)
+	assert.Equal(t, 2222, server.Port)
+	assert.Equal(t, "~/.ssh/prod", server.IdentityFile)
+	assert.Equal(t, []string{"shop", "api"}, server.ProjectIDs)
+	assert.Equal(t, []string{"prod", "eu"}, server.Tags)
+	assert.Equal(t, "bastion", server.Proxy)
+	assert.True(t, server.VPNRequired)
+	assert.True(t, server.Favorite)
+	assert.Equal(t, "bitwarden", server.Source)
+	assert.Equal(t, "2026-03-04T05:06:07Z", server.Revision)
+	assert.Equal(t, []string{"yes"}, server.SSHOptions["ForwardAgent"])
+	assert.Equal(t, []string{"30"}, server.SSHOptions["ServerAliveInterval"])
+	assert.Equal(t, []string{"8080 localhost:80"}, server.SSHOptions["LocalForward"])
+}
+
+func TestItemToServer_LoginUsernameFallback(t *testing.T) {
+	item := &Item{
+		ID:     "bw-1",
+		Type:   ItemTypeLogin,
+		Name:   "web",
+		Login:  &Login{Username: "admin"},
+		Fields: []Field{{Name: "ssherpa"}, {Name: "hostname", Value: "web.example.com"}},
+	}
+
+	server, err := ItemToServer(item)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", server.User)
+	assert.Equal(t, 22, server.Port)
+}
+
+func TestItemToServer_MissingRequiredFields(t *testing.T) {
+	_, err := ItemToServer(&Item{ID: "x", Name: "no host", Fields: []Field{{Name: "user", Value: "u"}}})
+	assert.ErrorContains(t, err, "hostname")
+
+	_, err = ItemToServer(&Item{ID: "x", Name: "no user", Fields: []Field{{Name: "hostname", Value: "h"}}})
+	assert.ErrorContains(t, err, "user")
+}
+
+func TestServerToItem_RoundTrip(t *testing.T) {
+	server := &domain.Server{
+		ID:          "bw-1",
+		DisplayName: "db",
+		Host:        "db.example.com",
+		User:        "postgres",
+		Port:        5022,
+		ProjectIDs:  []string{"shop"},
+		Tags:        []string{"db"},
+		Favorite:    true,
+		SSHOptions: map[string][]string{
+			"ForwardAgent":        {"yes"},
+			"ServerAliveInterval": {"15"},
+		},
+	}
+
+	item := ServerToItem(server, Scope{FolderID: "f1"})
+	assert.Equal(t, ItemTypeSecureNote, item.Type)
+	assert.Equal(t, "f1", item.FolderID)
+	assert.Empty(t, item.CollectionIDs)
+	assert.True(t, HasMarker(item))
+
+	back, err := ItemToServer(item)
+	require.NoError(t, err)
+	assert.Equal(t, server.Host, back.Host)
+	assert.Equal(t, server.User, back.User)
+	assert.Equal(t, server.Port, back.Port)
+	assert.Equal(t, server.ProjectIDs, back.ProjectIDs)
+	assert.Equal(t, server.Tags, back.Tags)
+	assert.True(t, back.Favorite)
+	assert.Equal(t, server.SSHOptions, back.SSHOptions)
+}
+
+func TestServerToItem_CollectionScope(t *testing.T) {
+	item := ServerToItem(&domain.Server{Host: "h", User: "u"}, Scope{CollectionID: "c1", OrganizationID: "o1"})
+	assert.Equal(t, "o1", item.OrganizationID)
+	assert.Equal(t, []string{"c1"}, item.CollectionIDs)
+	assert.Empty(t, item.FolderID)
+}
+
+func TestMergeFields(t *testing.T) {
+	existing := []Field{
+		{Name: "ssherpa", Value: "true"},
+		{Name: "hostname", Value: "old.example.com"},
+		{Name: "proxy_jump", Value: "bastion"},
+		{Name: "api_key", Value: "secret", Type: FieldTypeHidden},
+	}
+	generated := []Field{
+		{Name: "ssherpa", Value: "true"},
+		{Name: "hostname", Value: "new.example.com"},
+	}
+
+	merged := mergeFields(existing, generated)
+	assert.Equal(t, []Field{
+		{Name: "ssherpa", Value: "true"},
+		{Name: "hostname", Value: "new.example.com"},
+		{Name: "api_key", Value: "secret", Type: FieldTypeHidden},
+	}, merged)
+}
@@ -0,0 +1,260 @@
+package bitwarden
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// pollIntervalEnv overrides the default poll interval (e.g. "30s").
+const pollIntervalEnv = "SSHJESUS_BITWARDEN_POLL_INTERVAL"
+
+// GetStatus returns the current backend status (thread-safe).
+func (b *Backend) GetStatus() backendpkg.BackendStatus {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.status
+}
+
+// setStatus updates the backend status (thread-safe).
+func (b *Backend) setStatus(s backendpkg.BackendStatus) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.status = s
+}
+
+// SyncFromBackend implements backend.Syncer.
+// It delegates to SyncFromBitwarden for the actual sync logic.
+func (b *Backend) SyncFromBackend(ctx context.Context) error {
+	return b.SyncFromBitwarden(ctx)
+}
+
+// SyncFromBitwarden pulls the vault with "bw sync" and loads the marked
+// items in the configured folder or collection.
+// On success: sets status to Available, populates cache, writes to TOML cache.
+// On error: sets status to Locked, NotSignedIn or Unavailable.
+func (b *Backend) SyncFromBitwarden(ctx context.Context) error {
+	return b.syncItems(ctx)
+}
+
+// syncItems does the work of SyncFromBitwarden.
+func (b *Backend) syncItems(ctx context.Context) error {
+	// bw status works without a session, so it doubles as the health check
+	state, err := b.client.Status(ctx)
+	if err != nil {
+		b.setStatus(classifyError(err))
+		return &errors.BackendError{
+			Op:      "SyncFromBitwarden",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+	switch state {
+	case StatusLocked:
+		b.setStatus(backendpkg.StatusLocked)
+		return &errors.BackendError{
+			Op:      "SyncFromBitwarden",
+			Backend: "bitwarden",
+			Err:     fmt.Errorf("vault is locked (run \"bw unlock\" and export %s)", SessionEnv),
+		}
+	case StatusUnauthenticated:
+		b.setStatus(backendpkg.StatusNotSignedIn)
+		return &errors.BackendError{
+			Op:      "SyncFromBitwarden",
+			Backend: "bitwarden",
+			Err:     fmt.Errorf("not logged in (run \"bw login\")"),
+		}
+	}
+
+	if err := b.client.Sync(ctx); err != nil {
+		b.setStatus(classifyError(err))
+		return &errors.BackendError{
+			Op:      "SyncFromBitwarden",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	b.mu.Lock()
+	b.scope = Scope{} // folders and collections may have been renamed since
+	scope, err := b.resolveScope(ctx)
+	b.mu.Unlock()
+	if err != nil {
+		b.setStatus(classifyError(err))
+		return &errors.BackendError{
+			Op:      "SyncFromBitwarden",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	items, err := b.client.ListItems(ctx, scope)
+	if err != nil {
+		b.setStatus(classifyError(err))
+		return &errors.BackendError{
+			Op:      "SyncFromBitwarden",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	servers := make([]*domain.Server, 0, len(items))
+	var skippedItems []SkippedItem // Track skipped items for debugging
+	for i := range items {
+		if !HasMarker(&items[i]) {
+			continue
+		}
+		server, err := ItemToServer(&items[i])
+		if err != nil {
+			skippedItems = append(skippedItems, SkippedItem{Name: items[i].Name, Reason: err.Error()})
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	// Report skipped items to help debug missing entries
+	if len(skippedItems) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d items with '%s' field skipped due to validation errors:\n", len(skippedItems), MarkerField)
+		for _, skipped := range skippedItems {
+			fmt.Fprintf(os.Stderr, "  - %s: %s\n", skipped.Name, skipped.Reason)
+		}
+	}
+
+	// Update cache
+	b.mu.Lock()
+	b.servers = servers
+	b.skipped = skippedItems
+	b.status = backendpkg.StatusAvailable
+	b.mu.Unlock()
+
+	// Write to TOML cache for offline fallback
+	if b.cachePath != "" {
+		_ = sync.WriteTOMLCache(servers, b.cachePath)
+	}
+
+	return nil
+}
+
+// resolveScope turns the configured folder or collection into IDs, looking
+// them up by ID or case-insensitive name. The result is kept until the next
+// sync. Must be called with mu held.
+func (b *Backend) resolveScope(ctx context.Context) (Scope, error) {
+	if b.scope != (Scope{}) || (b.folder == "" && b.collection == "") {
+		return b.scope, nil
+	}
+
+	if b.collection != "" {
+		collections, err := b.client.ListCollections(ctx)
+		if err != nil {
+			return Scope{}, err
+		}
+		for _, c := range collections {
+			if c.ID == b.collection || strings.EqualFold(c.Name, b.collection) {
+				b.scope = Scope{CollectionID: c.ID, OrganizationID: c.OrganizationID}
+				return b.scope, nil
+			}
+		}
+		return Scope{}, fmt.Errorf("collection %q not found", b.collection)
+	}
+
+	folders, err := b.client.ListFolders(ctx)
+	if err != nil {
+		return Scope{}, err
+	}
+	for _, f := range folders {
+		if f.ID == b.folder || strings.EqualFold(f.Name, b.folder) {
+			b.scope = Scope{FolderID: f.ID}
+			return b.scope, nil
+		}
+	}
+	return Scope{}, fmt.Errorf("folder %q not found", b.folder)
+}
+
+// classifyError maps a failed bw call to a backend status.
+func classifyError(err error) backendpkg.BackendStatus {
+	errStr := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(errStr, "vault is locked") || strings.Contains(errStr, "master password"):
+		return backendpkg.StatusLocked
+	case strings.Contains(errStr, "not logged in") || strings.Contains(errStr, "you are not logged in"):
+		return backendpkg.StatusNotSignedIn
+	default:
+		return backendpkg.StatusUnavailable
+	}
+}
+
+// SkippedItem is a marked item the last sync could not turn into a server.
+type SkippedItem struct {
+	Name   string // item name
+	Reason string // validation error
+}
+
+// SkippedItems returns the items skipped by the last successful sync (thread-safe).
+func (b *Backend) SkippedItems() []SkippedItem {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]SkippedItem(nil), b.skipped...)
+}
+
+// LoadFromCache loads servers from the TOML cache file.
+// This is called when Bitwarden is unavailable on startup.
+func (b *Backend) LoadFromCache() error {
+	if b.cachePath == "" {
+		return &errors.BackendError{
+			Op:      "LoadFromCache",
+			Backend: "bitwarden",
+			Err:     errors.New("cache path not set"),
+		}
+	}
+
+	servers, err := sync.ReadTOMLCache(b.cachePath)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "LoadFromCache",
+			Backend: "bitwarden",
+			Err:     err,
+		}
+	}
+
+	b.mu.Lock()
+	b.servers = servers
+	b.mu.Unlock()
+
+	return nil
+}
+
+// StartPolling starts a background poller for this backend.
+// interval: how often to poll (use 0 to read from SSHJESUS_BITWARDEN_POLL_INTERVAL env var, defaults to 5m)
+// onChange: optional callback invoked when status changes (nil = no callback)
+func (b *Backend) StartPolling(interval time.Duration, onChange func(backendpkg.BackendStatus)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Stop existing poller if any
+	if b.poller != nil {
+		b.poller.Stop()
+	}
+
+	b.poller = backendpkg.NewPoller(
+		backendpkg.PollInterval(interval, pollIntervalEnv),
+		b.syncItems,
+		b.GetStatus,
+		b.lastWriteTime,
+		onChange,
+	)
+	b.poller.Start()
+}
+
+// lastWriteTime returns the last write timestamp (thread-safe).
+func (b *Backend) lastWriteTime() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastWrite
+}
@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+// tagPrefix marks a tag filter inside a free-text query (e.g. "tag:prod web").
+const tagPrefix = "tag:"
+
+// ParseQuery turns search-bar input into a ServerFilter.
+// "tag:<name>" words become Tags; everything else is kept as the fuzzy Query.
+func ParseQuery(query string) ServerFilter {
+	var filter ServerFilter
+	var words []string
+	for _, word := range strings.Fields(query) {
+		if len(word) > len(tagPrefix) && strings.HasPrefix(strings.ToLower(word), tagPrefix) {
+			filter.Tags = append(filter.Tags, word[len(tagPrefix):])
+			continue
+		}
+		words = append(words, word)
+	}
+	filter.Query = strings.Join(words, " ")
+	return filter
+}
+
+// Match reports whether srv satisfies every criterion of the filter.
+// This is the reference semantics every Filterer implementation follows:
+//   - ProjectID: srv.ProjectIDs contains the ID (exact match)
+//   - Tags: srv has all tags (case-insensitive)
+//   - Favorite: srv.Favorite equals the value when non-nil
+//   - Query: fuzzy match against DisplayName, Host, User and Tags
+func (f ServerFilter) Match(srv *domain.Server) bool {
+	if f.ProjectID != "" && !containsString(srv.ProjectIDs, f.ProjectID) {
+		return false
+	}
+
+	for _, tag := range f.Tags {
+		if !containsFold(srv.Tags, tag) {
+			return false
+		}
+	}
+
+	if f.Favorite != nil && srv.Favorite != *f.Favorite {
+		return false
+	}
+
+	if f.Query != "" {
+		if len(fuzzy.Find(f.Query, []string{SearchText(srv)})) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SearchText returns the text a Query is fuzzy-matched against.
+func SearchText(srv *domain.Server) string {
+	return srv.DisplayName + " " + srv.Host + " " + srv.User + " " + strings.Join(srv.Tags, " ")
+}
+
+// ApplyFilter returns the servers matching the filter, preserving input order.
+// Backends that filter in memory implement FilterServers with it.
+// Returns empty slice (not nil) when nothing matches.
+func ApplyFilter(servers []*domain.Server, filter ServerFilter) []*domain.Server {
+	result := make([]*domain.Server, 0, len(servers))
+	for _, srv := range servers {
+		if filter.Match(srv) {
+			result = append(result, srv)
+		}
+	}
+	return result
+}
+
+// containsString reports whether values contains s.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  backend.ServerFilter
+	}{
+		{"", backend.ServerFilter{}},
+		{"web", backend.ServerFilter{Query: "web"}},
+		{"tag:prod", backend.ServerFilter{Tags: []string{"prod"}}},
+		{"TAG:prod  web  tag:db", backend.ServerFilter{Tags: []string{"prod", "db"}, Query: "web"}},
+		{"tag:", backend.ServerFilter{Query: "tag:"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, backend.ParseQuery(tt.input))
+		})
+	}
+}
+
+func TestApplyFilter_PreservesOrder(t *testing.T) {
+	servers := []*domain.Server{
+		{ID: "c", DisplayName: "c", Tags: []string{"prod"}},
+		{ID: "a", DisplayName: "a"},
+		{ID: "b", DisplayName: "b", Tags: []string{"Prod"}},
+	}
+
+	got := backend.ApplyFilter(servers, backend.ServerFilter{Tags: []string{"prod"}})
+	assert.Equal(t, []*domain.Server{servers[0], servers[2]}, got)
+
+	assert.Equal(t, []*domain.Server{}, backend.ApplyFilter(nil, backend.ServerFilter{}))
+}
@@ -0,0 +1,565 @@
+// Package gitrepo implements a team inventory backend stored as TOML files
+// in a git clone. Servers, projects and credential references are read from
+// the files, writes edit them (and optionally commit), and syncing reports
+// whether the clone is behind its upstream.
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/output"
+	syncpkg "github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// DefaultPattern is the inventory file glob used when none is configured,
+// relative to the repository root.
+const DefaultPattern = "ssherpa/*.toml"
+
+// defaultFileName is the file new entries go to when no inventory file
+// exists yet; it is created next to the pattern.
+const defaultFileName = "inventory.toml"
+
+// Backend implements the backendpkg.Backend, backendpkg.Writer,
+// backendpkg.Syncer and backendpkg.Filterer interfaces on top of TOML files
+// in a git clone.
+type Backend struct {
+	repoDir  string          // root of the clone
+	pattern  string          // inventory file glob, relative to repoDir
+	executor CommandExecutor // runs git (real or fake)
+
+	mu          sync.RWMutex             // Protects files, status, and closed flag
+	files       []*inventoryFile         // Inventory files from the last load, sorted by path
+	closed      bool                     // Backend closed flag
+	status      backendpkg.BackendStatus // Current upstream status
+	behind      int                      // Upstream commits missing locally, from the last sync
+	autoCommit  bool                     // Commit each write
+	defaultFile string                   // File for new entries (empty = first inventory file)
+	poller      *backendpkg.Poller       // Background upstream poller
+	lastWrite   time.Time                // Last write timestamp for debouncing
+}
+
+// Compile-time interface verification
+var (
+	_ backendpkg.Backend  = (*Backend)(nil)
+	_ backendpkg.Writer   = (*Backend)(nil)
+	_ backendpkg.Syncer   = (*Backend)(nil)
+	_ backendpkg.Filterer = (*Backend)(nil)
+)
+
+// New creates a backend for the clone at repoDir and loads the inventory
+// files matching pattern (DefaultPattern when empty).
+// The upstream is not contacted until SyncFromBackend runs.
+func New(repoDir, pattern string) (*Backend, error) {
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+
+	b := &Backend{
+		repoDir:  repoDir,
+		pattern:  pattern,
+		executor: &defaultExecutor{},
+		status:   backendpkg.StatusUnknown,
+	}
+	if err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SetAutoCommit makes every write commit the edited file. Commits are never pushed.
+func (b *Backend) SetAutoCommit(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.autoCommit = enabled
+}
+
+// SetDefaultFile sets the file new servers, projects and credentials are
+// added to, relative to the repository root. It is created if missing.
+func (b *Backend) SetDefaultFile(path string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.defaultFile = filepath.Clean(path)
+}
+
+// Reload re-reads the inventory files from disk.
+func (b *Backend) Reload() error {
+	files, err := loadInventory(b.repoDir, b.pattern)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "Reload",
+			Backend: "git",
+			Err:     err,
+		}
+	}
+
+	b.mu.Lock()
+	b.files = files
+	b.mu.Unlock()
+	return nil
+}
+
+// checkClosed returns ErrBackendUnavailable if backend is closed.
+// Must be called with mu held (either RLock or Lock).
+func (b *Backend) checkClosed() error {
+	if b.closed {
+		return &errors.BackendError{
+			Op:      "checkClosed",
+			Backend: "git",
+			Err:     errors.ErrBackendUnavailable,
+		}
+	}
+	return nil
+}
+
+// toServer converts a stored server and fills in what the files leave out.
+func toServer(cached syncpkg.CachedServer) *domain.Server {
+	server := cached.ToServer()
+	if server.Port == 0 {
+		server.Port = 22
+	}
+	if server.DisplayName == "" {
+		server.DisplayName = server.ID
+	}
+	server.Source = "git"
+	return server
+}
+
+// fromServer converts a server for storage, dropping fields that describe
+// another backend (vault, source, revision).
+func fromServer(server *domain.Server) syncpkg.CachedServer {
+	cached := syncpkg.NewCachedServer(server)
+	cached.VaultID = ""
+	cached.Source = ""
+	cached.Revision = ""
+	return cached
+}
+
+// ListServers returns the servers of all inventory files in file order.
+func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Server, 0)
+	for _, file := range b.files {
+		for _, cached := range file.Servers {
+			result = append(result, toServer(cached))
+		}
+	}
+	return result, nil
+}
+
+// GetServer retrieves a server by ID.
+func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if file, i := b.findServer(id); file != nil {
+		return toServer(file.Servers[i]), nil
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetServer",
+		Backend: "git",
+		Err:     errors.ErrServerNotFound,
+	}
+}
+
+// FilterServers returns the servers matching the filter.
+func (b *Backend) FilterServers(ctx context.Context, filters backendpkg.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backendpkg.ApplyFilter(servers, filters), nil
+}
+
+// ListProjects returns the projects of all inventory files.
+func (b *Backend) ListProjects(ctx context.Context) ([]*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Project, 0)
+	for _, file := range b.files {
+		for _, p := range file.Projects {
+			result = append(result, p.ToProject())
+		}
+	}
+	return result, nil
+}
+
+// GetProject retrieves a project by ID.
+func (b *Backend) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if file, i := b.findProject(id); file != nil {
+		return file.Projects[i].ToProject(), nil
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetProject",
+		Backend: "git",
+		Err:     errors.ErrProjectNotFound,
+	}
+}
+
+// ListCredentials returns the credential references of all inventory files.
+func (b *Backend) ListCredentials(ctx context.Context) ([]*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Credential, 0)
+	for _, file := range b.files {
+		for _, c := range file.Credentials {
+			result = append(result, c.ToCredential())
+		}
+	}
+	return result, nil
+}
+
+// GetCredential retrieves a credential reference by ID.
+func (b *Backend) GetCredential(ctx context.Context, id string) (*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if file, i := b.findCredential(id); file != nil {
+		return file.Credentials[i].ToCredential(), nil
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetCredential",
+		Backend: "git",
+		Err:     errors.ErrCredentialNotFound,
+	}
+}
+
+// Close stops the poller and marks the backend as closed.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+
+	if b.poller != nil {
+		poller := b.poller
+		b.poller = nil
+		b.mu.Unlock() // Unlock before calling Stop() to avoid deadlock
+		poller.Stop()
+		b.mu.Lock()
+	}
+
+	b.closed = true
+	b.mu.Unlock()
+	return nil
+}
+
+// CreateServer adds a server to the default inventory file.
+// An empty ID is set to the display name.
+func (b *Backend) CreateServer(ctx context.Context, server *domain.Server) error {
+	if err := server.Validate(); err != nil {
+		return &errors.BackendError{Op: "CreateServer", Backend: "git", Err: fmt.Errorf("%w: %v", errors.ErrValidation, err)}
+	}
+	if server.ID == "" {
+		server.ID = server.DisplayName
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	if file, _ := b.findServer(server.ID); file != nil {
+		return &errors.BackendError{Op: "CreateServer", Backend: "git", Err: errors.ErrDuplicateID}
+	}
+
+	file := b.targetFile()
+	entry := fromServer(server)
+	file.Servers = append(file.Servers, entry)
+	return b.save(ctx, "CreateServer", file, entryChange{kindServer, server.ID, entry}, "ssherpa: add server "+server.DisplayName)
+}
+
+// UpdateServer replaces a server in the file that holds it.
+func (b *Backend) UpdateServer(ctx context.Context, server *domain.Server) error {
+	if err := server.Validate(); err != nil {
+		return &errors.BackendError{Op: "UpdateServer", Backend: "git", Err: fmt.Errorf("%w: %v", errors.ErrValidation, err)}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	file, i := b.findServer(server.ID)
+	if file == nil {
+		return &errors.BackendError{Op: "UpdateServer", Backend: "git", Err: errors.ErrServerNotFound}
+	}
+
+	file.Servers[i] = fromServer(server)
+	return b.save(ctx, "UpdateServer", file, entryChange{kindServer, server.ID, file.Servers[i]}, "ssherpa: update server "+server.DisplayName)
+}
+
+// DeleteServer removes a server from the file that holds it.
+func (b *Backend) DeleteServer(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	file, i := b.findServer(id)
+	if file == nil {
+		return &errors.BackendError{Op: "DeleteServer", Backend: "git", Err: errors.ErrServerNotFound}
+	}
+
+	name := toServer(file.Servers[i]).DisplayName
+	file.Servers = slices.Delete(file.Servers, i, i+1)
+	return b.save(ctx, "DeleteServer", file, entryChange{kindServer, id, nil}, "ssherpa: remove server "+name)
+}
+
+// CreateProject adds a project to the default inventory file.
+// An empty ID is set to the name.
+func (b *Backend) CreateProject(ctx context.Context, project *domain.Project) error {
+	if err := project.Validate(); err != nil {
+		return &errors.BackendError{Op: "CreateProject", Backend: "git", Err: fmt.Errorf("%w: %v", errors.ErrValidation, err)}
+	}
+	if project.ID == "" {
+		project.ID = project.Name
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	if file, _ := b.findProject(project.ID); file != nil {
+		return &errors.BackendError{Op: "CreateProject", Backend: "git", Err: errors.ErrDuplicateID}
+	}
+
+	file := b.targetFile()
+	entry := output.NewProject(project)
+	file.Projects = append(file.Projects, entry)
+	return b.save(ctx, "CreateProject", file, entryChange{kindProject, project.ID, entry}, "ssherpa: add project "+project.Name)
+}
+
+// UpdateProject replaces a project in the file that holds it.
+func (b *Backend) UpdateProject(ctx context.Context, project *domain.Project) error {
+	if err := project.Validate(); err != nil {
+		return &errors.BackendError{Op: "UpdateProject", Backend: "git", Err: fmt.Errorf("%w: %v", errors.ErrValidation, err)}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	file, i := b.findProject(project.ID)
+	if file == nil {
+		return &errors.BackendError{Op: "UpdateProject", Backend: "git", Err: errors.ErrProjectNotFound}
+	}
+
+	file.Projects[i] = output.NewProject(project)
+	return b.save(ctx, "UpdateProject", file, entryChange{kindProject, project.ID, file.Projects[i]}, "ssherpa: update project "+project.Name)
+}
+
+// DeleteProject removes a project from the file that holds it.
+// Servers keep their reference to the project ID.
+func (b *Backend) DeleteProject(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	file, i := b.findProject(id)
+	if file == nil {
+		return &errors.BackendError{Op: "DeleteProject", Backend: "git", Err: errors.ErrProjectNotFound}
+	}
+
+	name := file.Projects[i].Name
+	file.Projects = slices.Delete(file.Projects, i, i+1)
+	return b.save(ctx, "DeleteProject", file, entryChange{kindProject, id, nil}, "ssherpa: remove project "+name)
+}
+
+// CreateCredential adds a credential reference to the default inventory file.
+// An empty ID is set to the name.
+func (b *Backend) CreateCredential(ctx context.Context, cred *domain.Credential) error {
+	if err := cred.Validate(); err != nil {
+		return &errors.BackendError{Op: "CreateCredential", Backend: "git", Err: fmt.Errorf("%w: %v", errors.ErrValidation, err)}
+	}
+	if cred.ID == "" {
+		cred.ID = cred.Name
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	if file, _ := b.findCredential(cred.ID); file != nil {
+		return &errors.BackendError{Op: "CreateCredential", Backend: "git", Err: errors.ErrDuplicateID}
+	}
+
+	file := b.targetFile()
+	entry := output.NewCredential(cred)
+	file.Credentials = append(file.Credentials, entry)
+	return b.save(ctx, "CreateCredential", file, entryChange{kindCredential, cred.ID, entry}, "ssherpa: add credential "+cred.Name)
+}
+
+// UpdateCredential replaces a credential reference in the file that holds it.
+func (b *Backend) UpdateCredential(ctx context.Context, cred *domain.Credential) error {
+	if err := cred.Validate(); err != nil {
+		return &errors.BackendError{Op: "UpdateCredential", Backend: "git", Err: fmt.Errorf("%w: %v", errors.ErrValidation, err)}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	file, i := b.findCredential(cred.ID)
+	if file == nil {
+		return &errors.BackendError{Op: "UpdateCredential", Backend: "git", Err: errors.ErrCredentialNotFound}
+	}
+
+	file.Credentials[i] = output.NewCredential(cred)
+	return b.save(ctx, "UpdateCredential", file, entryChange{kindCredential, cred.ID, file.Credentials[i]}, "ssherpa: update credential "+cred.Name)
+}
+
+// DeleteCredential removes a credential reference from the file that holds it.
+func (b *Backend) DeleteCredential(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+	file, i := b.findCredential(id)
+	if file == nil {
+		return &errors.BackendError{Op: "DeleteCredential", Backend: "git", Err: errors.ErrCredentialNotFound}
+	}
+
+	name := file.Credentials[i].Name
+	file.Credentials = slices.Delete(file.Credentials, i, i+1)
+	return b.save(ctx, "DeleteCredential", file, entryChange{kindCredential, id, nil}, "ssherpa: remove credential "+name)
+}
+
+// findServer returns the file holding the server with id and its index, or nil.
+// Must be called with mu held.
+func (b *Backend) findServer(id string) (*inventoryFile, int) {
+	for _, file := range b.files {
+		for i, s := range file.Servers {
+			if s.ID == id {
+				return file, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// findProject returns the file holding the project with id and its index, or nil.
+// Must be called with mu held.
+func (b *Backend) findProject(id string) (*inventoryFile, int) {
+	for _, file := range b.files {
+		for i, p := range file.Projects {
+			if p.ID == id {
+				return file, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// findCredential returns the file holding the credential with id and its index, or nil.
+// Must be called with mu held.
+func (b *Backend) findCredential(id string) (*inventoryFile, int) {
+	for _, file := range b.files {
+		for i, c := range file.Credentials {
+			if c.ID == id {
+				return file, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// targetFile returns the file new entries go to: the default file, the
+// first inventory file, or a new file next to the pattern.
+// Must be called with mu held.
+func (b *Backend) targetFile() *inventoryFile {
+	path := b.defaultFile
+	if path == "" {
+		if len(b.files) > 0 {
+			return b.files[0]
+		}
+		path = filepath.Join(filepath.Dir(b.pattern), defaultFileName)
+	}
+
+	for _, file := range b.files {
+		if file.path == path {
+			return file
+		}
+	}
+	file := &inventoryFile{path: path}
+	b.files = append(b.files, file)
+	return file
+}
+
+// save writes change to file and commits it when auto-commit is on. If the
+// write fails the file is re-read so memory matches the disk again.
+// Must be called with mu held.
+func (b *Backend) save(ctx context.Context, op string, file *inventoryFile, change entryChange, message string) error {
+	if err := file.apply(b.repoDir, change); err != nil {
+		if fresh, readErr := readInventoryFile(b.repoDir, file.path); readErr == nil {
+			*file = *fresh
+		}
+		return &errors.BackendError{Op: op, Backend: "git", Err: err}
+	}
+	b.lastWrite = time.Now()
+
+	if b.autoCommit {
+		if err := b.commit(ctx, file.path, message); err != nil {
+			return &errors.BackendError{Op: op, Backend: "git", Err: fmt.Errorf("file written but not committed: %w", err)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package gitrepo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/mock"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+const testInventory = `[[server]]
+id = "web"
+display_name = "web"
+host = "web.example.com"
+user = "deploy"
+project_ids = ["payments"]
+tags = ["prod"]
+
+[[project]]
+id = "payments"
+name = "Payments API"
+
+[[credential]]
+id = "deploy-key"
+name = "Deploy key"
+type = "key_file"
+key_file_path = "~/.ssh/deploy"
+`
+
+// git runs a git command in dir and fails the test on error.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %s: %s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+// newTestRepo creates a bare "upstream" repository and a clone of it holding
+// infra/ssherpa/servers.toml. Returns the clone and the bare repository.
+func newTestRepo(t *testing.T) (clone, upstream string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	upstream = filepath.Join(dir, "upstream.git")
+	clone = filepath.Join(dir, "clone")
+	git(t, dir, "init", "--quiet", "--bare", "--initial-branch=main", upstream)
+	git(t, dir, "clone", "--quiet", upstream, clone)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(clone, "infra", "ssherpa"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(clone, "infra", "ssherpa", "servers.toml"), []byte(testInventory), 0644))
+	git(t, clone, "add", ".")
+	git(t, clone, "commit", "--quiet", "-m", "inventory")
+	git(t, clone, "push", "--quiet", "-u", "origin", "main")
+	return clone, upstream
+}
+
+func newTestBackend(t *testing.T, clone string) *Backend {
+	t.Helper()
+	b, err := New(clone, "infra/ssherpa/*.toml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+	return b
+}
+
+func TestNew_LoadsInventory(t *testing.T) {
+	clone, _ := newTestRepo(t)
+	b := newTestBackend(t, clone)
+	ctx := context.Background()
+
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "web.example.com", servers[0].Host)
+	assert.Equal(t, 22, servers[0].Port)
+	assert.Equal(t, "git", servers[0].Source)
+	assert.Equal(t, []string{"payments"}, servers[0].ProjectIDs)
+
+	project, err := b.GetProject(ctx, "payments")
+	require.NoError(t, err)
+	assert.Equal(t, "Payments API", project.Name)
+
+	cred, err := b.GetCredential(ctx, "deploy-key")
+	require.NoError(t, err)
+	assert.Equal(t, domain.CredentialKeyFile, cred.Type)
+	assert.Equal(t, "~/.ssh/deploy", cred.KeyFilePath)
+
+	filtered, err := b.FilterServers(ctx, backendpkg.ServerFilter{Tags: []string{"prod"}})
+	require.NoError(t, err)
+	assert.Len(t, filtered, 1)
+
+	assert.Equal(t, backendpkg.StatusUnknown, b.GetStatus())
+}
+
+func TestNew_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.toml"), []byte("[[server]\n"), 0644))
+
+	_, err := New(dir, "*.toml")
+	assert.ErrorContains(t, err, "bad.toml")
+}
+
+func TestWriter_EditsFiles(t *testing.T) {
+	clone, _ := newTestRepo(t)
+	b := newTestBackend(t, clone)
+	ctx := context.Background()
+
+	require.NoError(t, b.CreateServer(ctx, &domain.Server{DisplayName: "db", Host: "db.example.com", User: "postgres", Port: 5432, VaultID: "v1", Source: "1password"}))
+	server, err := b.GetServer(ctx, "db")
+	require.NoError(t, err)
+	server.Host = "db2.example.com"
+	require.NoError(t, b.UpdateServer(ctx, server))
+	require.NoError(t, b.DeleteServer(ctx, "web"))
+
+	content, err := os.ReadFile(filepath.Join(clone, "infra", "ssherpa", "servers.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `host = "db2.example.com"`)
+	assert.NotContains(t, string(content), "web.example.com")
+	assert.NotContains(t, string(content), "1password")
+	assert.Contains(t, string(content), `name = "Payments API"`)
+
+	// A fresh backend sees the same state
+	reloaded := newTestBackend(t, clone)
+	servers, err := reloaded.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "db2.example.com", servers[0].Host)
+	assert.Equal(t, 5432, servers[0].Port)
+
+	// Without auto-commit the changes are left for the user to commit
+	assert.Contains(t, git(t, clone, "status", "--porcelain"), "servers.toml")
+}
+
+func TestWriter_PreservesFormatting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "team.toml")
+	original := `# Team inventory, reviewed in PRs
+
+# Production web frontend
+[[server]]
+host = "web.example.com"
+id = "web"   # keep in sync with DNS
+display_name = "web"
+
+# Legacy box, to be retired
+[[server]]
+id = "old"
+display_name = "old"
+host = "old.example.com"
+
+[server.ssh_options]
+HostKeyAlgorithms = ["+ssh-rsa"]
+
+[[project]]
+name = "Payments API"
+id = "payments"
+`
+	require.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+	b, err := New(dir, "*.toml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"HostKeyAlgorithms": {"+ssh-rsa"}}, server.SSHOptions)
+	server.User = "admin"
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	before, _, _ := strings.Cut(original, "[[server]]\nid = \"old\"")
+	_, after, _ := strings.Cut(original, "HostKeyAlgorithms = [\"+ssh-rsa\"]\n")
+	assert.True(t, strings.HasPrefix(string(content), before), "entries before the edit are untouched:\n%s", content)
+	assert.True(t, strings.HasSuffix(string(content), after), "entries after the edit are untouched:\n%s", content)
+	assert.Contains(t, string(content), `user = "admin"`)
+
+	require.NoError(t, b.DeleteServer(ctx, "old"))
+	require.NoError(t, b.CreateCredential(ctx, &domain.Credential{Name: "agent", Type: domain.CredentialSSHAgent}))
+
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	kept, _, _ := strings.Cut(original, "# Legacy box")
+	assert.True(t, strings.HasPrefix(string(content), kept+"[[project]]\nname = \"Payments API\"\nid = \"payments\"\n\n[[credential]]\nid = \"agent\""),
+		"delete removes the entry and its comment, create appends:\n%s", content)
+
+	reloaded, err := New(dir, "*.toml")
+	require.NoError(t, err)
+	servers, err := reloaded.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "web", servers[0].ID)
+	creds, err := reloaded.ListCredentials(ctx)
+	require.NoError(t, err)
+	assert.Len(t, creds, 1)
+}
+
+func TestWriter_InlineArrayEntry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "team.toml")
+	original := `server = [{ id = "web", display_name = "web", host = "web.example.com" }]
+`
+	require.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+	b, err := New(dir, "*.toml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "web")
+	require.NoError(t, err)
+	server.User = "admin"
+	err = b.UpdateServer(ctx, server)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `server entry "web" is not in [[server]] form`)
+	assert.Error(t, b.DeleteServer(ctx, "web"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(content), "the file must not get a duplicate entry")
+}
+
+func TestWriter_BehindWritablePrimary(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "team.toml"), []byte(testInventory), 0644))
+	gitBackend, err := New(dir, "*.toml")
+	require.NoError(t, err)
+
+	primary := mock.New()
+	primary.Seed([]*domain.Server{{ID: "db", DisplayName: "db", Host: "db.example.com"}}, nil, nil)
+
+	// Same stack as openBackend: configured backend first, git inventory last
+	multi := backendpkg.NewMultiBackend(primary, gitBackend)
+	t.Cleanup(func() { _ = multi.Close() })
+	ctx := context.Background()
+
+	server, err := multi.GetServer(ctx, "web")
+	require.NoError(t, err)
+	server.Favorite = true
+	require.NoError(t, multi.UpdateServer(ctx, server))
+
+	content, err := os.ReadFile(filepath.Join(dir, "team.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "favorite = true")
+	_, err = primary.GetServer(ctx, "web")
+	assert.ErrorIs(t, err, errors.ErrServerNotFound, "the primary must not receive the git server")
+
+	require.NoError(t, multi.DeleteServer(ctx, "web"))
+	servers, err := gitBackend.ListServers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, servers)
+
+	// Servers of the primary and new servers still go to the primary
+	require.NoError(t, multi.DeleteServer(ctx, "db"))
+	require.NoError(t, multi.CreateServer(ctx, &domain.Server{ID: "new", DisplayName: "new", Host: "new.example.com"}))
+	_, err = primary.GetServer(ctx, "new")
+	assert.NoError(t, err)
+}
+
+func TestWriter_Errors(t *testing.T) {
+	clone, _ := newTestRepo(t)
+	b := newTestBackend(t, clone)
+	ctx := context.Background()
+
+	err := b.CreateServer(ctx, &domain.Server{ID: "web", DisplayName: "web", Host: "h"})
+	assert.ErrorIs(t, err, errors.ErrDuplicateID)
+
+	err = b.CreateServer(ctx, &domain.Server{DisplayName: "no host"})
+	assert.ErrorIs(t, err, errors.ErrValidation)
+
+	err = b.UpdateServer(ctx, &domain.Server{ID: "nope", DisplayName: "nope", Host: "h"})
+	assert.ErrorIs(t, err, errors.ErrServerNotFound)
+
+	assert.ErrorIs(t, b.DeleteProject(ctx, "nope"), errors.ErrProjectNotFound)
+	assert.ErrorIs(t, b.DeleteCredential(ctx, "nope"), errors.ErrCredentialNotFound)
+}
+
+func TestWriter_AutoCommit(t *testing.T) {
+	clone, _ := newTestRepo(t)
+	b := newTestBackend(t, clone)
+	b.SetAutoCommit(true)
+	ctx := context.Background()
+
+	// Unrelated staged work must not end up in ssherpa's commit
+	require.NoError(t, os.WriteFile(filepath.Join(clone, "README"), []byte("wip"), 0644))
+	git(t, clone, "add", "README")
+
+	require.NoError(t, b.CreateProject(ctx, &domain.Project{Name: "infra"}))
+
+	assert.Equal(t, "ssherpa: add project infra", git(t, clone, "log", "-1", "--format=%s"))
+	assert.Equal(t, "infra/ssherpa/servers.toml", git(t, clone, "show", "--name-only", "--format=", "HEAD"))
+	assert.Equal(t, "A  README", git(t, clone, "status", "--porcelain"))
+}
+
+func TestWriter_DefaultFile(t *testing.T) {
+	clone, _ := newTestRepo(t)
+	b := newTestBackend(t, clone)
+	b.SetDefaultFile("infra/ssherpa/keys.toml")
+	ctx := context.Background()
+
+	require.NoError(t, b.CreateCredential(ctx, &domain.Credential{Name: "agent", Type: domain.CredentialSSHAgent}))
+	require.FileExists(t, filepath.Join(clone, "infra", "ssherpa", "keys.toml"))
+
+	creds, err := newTestBackend(t, clone).ListCredentials(ctx)
+	require.NoError(t, err)
+	assert.Len(t, creds, 2)
+}
+
+func TestWriter_EmptyRepository(t *testing.T) {
+	dir := t.TempDir()
+	b, err := New(dir, "")
+	require.NoError(t, err)
+
+	require.NoError(t, b.CreateServer(context.Background(), &domain.Server{DisplayName: "web", Host: "h"}))
+	assert.FileExists(t, filepath.Join(dir, "ssherpa", "inventory.toml"))
+}
@@ -0,0 +1,70 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// CommandExecutor abstracts command execution for testability.
+type CommandExecutor interface {
+	Run(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error)
+}
+
+// defaultExecutor implements CommandExecutor using os/exec.
+type defaultExecutor struct{}
+
+func (e *defaultExecutor) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdout, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return stdout, exitErr.Stderr, err
+		}
+		return stdout, nil, err
+	}
+	return stdout, nil, nil
+}
+
+// runGit executes a git command in the repository and returns its trimmed stdout.
+func (b *Backend) runGit(ctx context.Context, args ...string) (string, error) {
+	args = append([]string{"-C", b.repoDir}, args...)
+	stdout, stderr, err := b.executor.Run(ctx, "git", args...)
+	if err != nil {
+		// Include stderr in error message for debugging
+		if len(stderr) > 0 {
+			return "", fmt.Errorf("git %s failed: %w (stderr: %s)", args[2], err, strings.TrimSpace(string(stderr)))
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[2], err)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// hasUpstream reports whether the current branch tracks a remote branch.
+func (b *Backend) hasUpstream(ctx context.Context) bool {
+	_, err := b.runGit(ctx, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	return err == nil
+}
+
+// behindUpstream counts the upstream commits the local branch doesn't have.
+func (b *Backend) behindUpstream(ctx context.Context) (int, error) {
+	out, err := b.runGit(ctx, "rev-list", "--count", "HEAD..@{upstream}")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
+
+// commit commits the given inventory file on its own, leaving anything else
+// staged in the clone alone. Nothing is pushed.
+func (b *Backend) commit(ctx context.Context, path, message string) error {
+	if _, err := b.runGit(ctx, "add", "--", path); err != nil {
+		return err
+	}
+	if _, err := b.runGit(ctx, "commit", "--quiet", "-m", message, "--", path); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package gitrepo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/renameio/v2/maybe"
+
+	"github.com/florianriquelme/ssherpa/internal/output"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// inventoryFile is one TOML file of the team inventory. It uses the shapes of
+// "ssherpa list --format toml", so an export can be committed as-is:
+//
+//	[[server]]
+//	id = "web"
+//	display_name = "web"
+//	host = "web.example.com"
+//	user = "deploy"
+//
+//	[[project]]
+//	id = "payments"
+//	name = "Payments API"
+//
+//	[[credential]]
+//	id = "deploy-key"
+//	name = "Deploy key"
+//	type = "key_file"
+//	key_file_path = "~/.ssh/deploy"
+//
+// Credentials are references only; the keys themselves stay out of git.
+type inventoryFile struct {
+	path        string              // relative to the repository root
+	Servers     []sync.CachedServer `toml:"server,omitempty"`
+	Projects    []output.Project    `toml:"project,omitempty"`
+	Credentials []output.Credential `toml:"credential,omitempty"`
+}
+
+// loadInventory reads every file matching pattern (relative to repoDir),
+// sorted by path. A pattern that matches nothing yields no files.
+func loadInventory(repoDir, pattern string) ([]*inventoryFile, error) {
+	matches, err := filepath.Glob(filepath.Join(repoDir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory pattern %q: %w", pattern, err)
+	}
+	sort.Strings(matches)
+
+	files := make([]*inventoryFile, 0, len(matches))
+	for _, match := range matches {
+		rel, err := filepath.Rel(repoDir, match)
+		if err != nil {
+			return nil, err
+		}
+		file, err := readInventoryFile(repoDir, rel)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// readInventoryFile decodes the inventory file at rel.
+func readInventoryFile(repoDir, rel string) (*inventoryFile, error) {
+	content, err := os.ReadFile(filepath.Join(repoDir, rel))
+	if err != nil {
+		return nil, fmt.Errorf("read inventory file: %w", err)
+	}
+
+	file := &inventoryFile{path: rel}
+	if err := toml.Unmarshal(content, file); err != nil {
+		return nil, fmt.Errorf("decode inventory file %s: %w", rel, err)
+	}
+	return file, nil
+}
+
+// Entry kinds: the array-of-tables names of inventory entries.
+const (
+	kindServer     = "server"
+	kindProject    = "project"
+	kindCredential = "credential"
+)
+
+// entryChange is an edit to one [[kind]] entry of an inventory file.
+type entryChange struct {
+	kind  string // kindServer, kindProject or kindCredential
+	id    string // ID of the entry to replace or remove
+	entry any    // new contents; nil removes the entry
+}
+
+// headerPattern matches a TOML table header line, capturing the brackets and
+// the table name: "[[server]]", "[server.ssh_options]  # comment".
+var headerPattern = regexp.MustCompile(`^\s*(\[\[?)\s*([A-Za-z0-9_.\-]+)\s*\]\]?\s*(#.*)?$`)
+
+// apply writes change to the file on disk atomically. Only the lines of the
+// changed entry are rewritten; comments, key order and the other entries of
+// the shared file stay as they are, so a commit shows just that entry.
+func (f *inventoryFile) apply(repoDir string, change entryChange) error {
+	path := filepath.Join(repoDir, f.path)
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read inventory file: %w", err)
+	}
+
+	updated, err := editEntry(content, change)
+	if err != nil {
+		return fmt.Errorf("edit inventory file %s: %w", f.path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create inventory directory: %w", err)
+	}
+	if err := maybe.WriteFile(path, updated, 0644); err != nil {
+		return fmt.Errorf("write inventory file %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// editEntry applies change to the TOML content. The entry with change.id is
+// replaced in place, or removed together with the comment lines directly
+// above it; a new entry is appended at the end of the file.
+func editEntry(content []byte, change entryChange) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+
+	start, end, err := findEntry(lines, change.kind, change.id)
+	if err != nil {
+		return nil, err
+	}
+
+	var replacement []string
+	if change.entry != nil {
+		encoded, err := encodeEntry(change.kind, change.entry)
+		if err != nil {
+			return nil, err
+		}
+		replacement = strings.Split(encoded, "\n")
+	}
+
+	switch {
+	case start >= 0 && change.entry != nil:
+		lines = slices.Replace(lines, start, end, replacement...)
+	case start >= 0:
+		for start > 0 && isComment(lines[start-1]) {
+			start--
+		}
+		// Don't leave two blank lines where the entry was
+		if start > 0 && isBlank(lines[start-1]) && (end == len(lines) || isBlank(lines[end])) {
+			start--
+		}
+		lines = slices.Delete(lines, start, end)
+	case change.entry != nil:
+		for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, replacement...)
+	}
+
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+// findEntry returns the line range [start, end) of the [[kind]] entry with
+// id, or -1 when there is none. An entry with id that isn't written as a
+// [[kind]] table is an error. The range runs from the entry's header up to
+// the next header that isn't one of its sub-tables ([kind.ssh_options]),
+// without the blank and comment lines that precede that header.
+func findEntry(lines []string, kind, id string) (start, end int, err error) {
+	for i := 0; i < len(lines); i++ {
+		m := headerPattern.FindStringSubmatch(lines[i])
+		if m == nil || m[1] != "[[" || m[2] != kind {
+			continue
+		}
+
+		j := i + 1
+		for j < len(lines) {
+			if next := headerPattern.FindStringSubmatch(lines[j]); next != nil && !strings.HasPrefix(next[2], kind+".") {
+				break
+			}
+			j++
+		}
+		for j > i+1 && (isBlank(lines[j-1]) || isComment(lines[j-1])) {
+			j--
+		}
+
+		var decoded map[string][]struct {
+			ID string `toml:"id"`
+		}
+		if _, err := toml.Decode(strings.Join(lines[i:j], "\n"), &decoded); err != nil {
+			return -1, -1, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if entries := decoded[kind]; len(entries) == 1 && entries[0].ID == id {
+			return i, j, nil
+		}
+		i = j - 1
+	}
+
+	// The entry may still exist in a form that can't be edited line by line,
+	// such as an inline array (server = [{ id = "web" }]); appending a new
+	// [[kind]] entry would then duplicate it.
+	var decoded map[string][]struct {
+		ID string `toml:"id"`
+	}
+	if _, err := toml.Decode(strings.Join(lines, "\n"), &decoded); err != nil {
+		return -1, -1, err
+	}
+	for _, entry := range decoded[kind] {
+		if entry.ID == id {
+			return -1, -1, fmt.Errorf("%s entry %q is not in [[%s]] form", kind, id, kind)
+		}
+	}
+	return -1, -1, nil
+}
+
+// encodeEntry renders a single [[kind]] entry without a trailing newline.
+func encodeEntry(kind string, entry any) (string, error) {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(map[string]any{kind: []any{entry}}); err != nil {
+		return "", fmt.Errorf("encode %s entry: %w", kind, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// isBlank reports whether line is empty or whitespace.
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// isComment reports whether line is a TOML comment.
+func isComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
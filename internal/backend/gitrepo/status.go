@@ -0,0 +1,109 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// pollIntervalEnv overrides the default poll interval (e.g. "30s").
+const pollIntervalEnv = "SSHJESUS_GIT_POLL_INTERVAL"
+
+// GetStatus returns the current backend status (thread-safe).
+// StatusBehind means the upstream has commits the clone hasn't pulled.
+func (b *Backend) GetStatus() backendpkg.BackendStatus {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.status
+}
+
+// Behind returns how many upstream commits the clone was missing at the last sync.
+func (b *Backend) Behind() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.behind
+}
+
+// setStatus updates the backend status (thread-safe).
+func (b *Backend) setStatus(s backendpkg.BackendStatus, behind int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.status = s
+	b.behind = behind
+}
+
+// SyncFromBackend implements backend.Syncer.
+// It re-reads the inventory files and fetches the upstream to find out
+// whether the clone is behind it. Nothing is merged: pulling stays with the
+// user, who may have local edits.
+// A clone without an upstream is always Available; a failed fetch leaves the
+// local files loaded and sets Unavailable.
+func (b *Backend) SyncFromBackend(ctx context.Context) error {
+	if err := b.Reload(); err != nil {
+		b.setStatus(backendpkg.StatusUnavailable, 0)
+		return err
+	}
+
+	if !b.hasUpstream(ctx) {
+		b.setStatus(backendpkg.StatusAvailable, 0)
+		return nil
+	}
+
+	if _, err := b.runGit(ctx, "fetch", "--quiet"); err != nil {
+		b.setStatus(backendpkg.StatusUnavailable, 0)
+		return &errors.BackendError{
+			Op:      "SyncFromBackend",
+			Backend: "git",
+			Err:     err,
+		}
+	}
+
+	behind, err := b.behindUpstream(ctx)
+	if err != nil {
+		b.setStatus(backendpkg.StatusUnavailable, 0)
+		return &errors.BackendError{
+			Op:      "SyncFromBackend",
+			Backend: "git",
+			Err:     fmt.Errorf("compare with upstream: %w", err),
+		}
+	}
+
+	if behind > 0 {
+		b.setStatus(backendpkg.StatusBehind, behind)
+	} else {
+		b.setStatus(backendpkg.StatusAvailable, 0)
+	}
+	return nil
+}
+
+// StartPolling starts a background poller for this backend.
+// interval: how often to poll (use 0 to read from SSHJESUS_GIT_POLL_INTERVAL env var, defaults to 5m)
+// onChange: optional callback invoked when status changes (nil = no callback)
+func (b *Backend) StartPolling(interval time.Duration, onChange func(backendpkg.BackendStatus)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Stop existing poller if any
+	if b.poller != nil {
+		b.poller.Stop()
+	}
+
+	b.poller = backendpkg.NewPoller(
+		backendpkg.PollInterval(interval, pollIntervalEnv),
+		b.SyncFromBackend,
+		b.GetStatus,
+		b.lastWriteTime,
+		onChange,
+	)
+	b.poller.Start()
+}
+
+// lastWriteTime returns the last write timestamp (thread-safe).
+func (b *Backend) lastWriteTime() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastWrite
+}
@@ -0,0 +1,81 @@
+package gitrepo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+)
+
+func TestBackend_ImplementsSyncer(t *testing.T) {
+	var _ backendpkg.Syncer = (*Backend)(nil)
+}
+
+func TestSyncFromBackend_UpToDate(t *testing.T) {
+	clone, _ := newTestRepo(t)
+	b := newTestBackend(t, clone)
+
+	require.NoError(t, b.SyncFromBackend(context.Background()))
+	assert.Equal(t, backendpkg.StatusAvailable, b.GetStatus())
+	assert.Equal(t, 0, b.Behind())
+}
+
+func TestSyncFromBackend_Behind(t *testing.T) {
+	clone, upstream := newTestRepo(t)
+	b := newTestBackend(t, clone)
+	ctx := context.Background()
+
+	// A teammate pushes two commits
+	other := filepath.Join(t.TempDir(), "other")
+	git(t, filepath.Dir(other), "clone", "--quiet", upstream, other)
+	for _, name := range []string{"a.toml", "b.toml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(other, "infra", "ssherpa", name), []byte("[[server]]\nid = \""+name+"\"\nhost = \"h\"\n"), 0644))
+		git(t, other, "add", ".")
+		git(t, other, "commit", "--quiet", "-m", name)
+	}
+	git(t, other, "push", "--quiet")
+
+	require.NoError(t, b.SyncFromBackend(ctx))
+	assert.Equal(t, backendpkg.StatusBehind, b.GetStatus())
+	assert.Equal(t, 2, b.Behind())
+
+	// Nothing is merged until the user pulls
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, servers, 1)
+
+	git(t, clone, "pull", "--quiet", "--ff-only")
+	require.NoError(t, b.SyncFromBackend(ctx))
+	assert.Equal(t, backendpkg.StatusAvailable, b.GetStatus())
+	servers, err = b.ListServers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, servers, 3)
+}
+
+func TestSyncFromBackend_UpstreamGone(t *testing.T) {
+	clone, upstream := newTestRepo(t)
+	b := newTestBackend(t, clone)
+	require.NoError(t, os.RemoveAll(upstream))
+
+	require.Error(t, b.SyncFromBackend(context.Background()))
+	assert.Equal(t, backendpkg.StatusUnavailable, b.GetStatus())
+
+	// Local files stay usable
+	servers, err := b.ListServers(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, servers, 1)
+}
+
+func TestSyncFromBackend_NoUpstream(t *testing.T) {
+	dir := t.TempDir()
+	b, err := New(dir, "")
+	require.NoError(t, err)
+
+	require.NoError(t, b.SyncFromBackend(context.Background()))
+	assert.Equal(t, backendpkg.StatusAvailable, b.GetStatus())
+}
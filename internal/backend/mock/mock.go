@@ -10,7 +10,7 @@ import (
 	"github.com/florianriquelme/ssherpa/internal/errors"
 )
 
-// Backend implements backend.Backend and backend.Writer with thread-safe in-memory storage.
+// Backend implements backend.Backend, backend.Writer and backend.Filterer with thread-safe in-memory storage.
 type Backend struct {
 	mu          sync.RWMutex
 	servers     map[string]*domain.Server
@@ -22,6 +22,7 @@ type Backend struct {
 // Compile-time interface verification
 var _ backend.Backend = (*Backend)(nil)
 var _ backend.Writer = (*Backend)(nil)
+var _ backend.Filterer = (*Backend)(nil)
 
 // New creates a new mock backend with initialized storage.
 func New() *Backend {
@@ -189,6 +190,16 @@ func (b *Backend) DeleteServer(ctx context.Context, id string) error {
 	return nil
 }
 
+// FilterServers returns copies of the servers matching the filter.
+// The mock is the reference implementation: it applies backend.ServerFilter.Match as-is.
+func (b *Backend) FilterServers(ctx context.Context, filters backend.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backend.ApplyFilter(servers, filters), nil
+}
+
 // ===== Project Methods =====
 
 // GetProject retrieves a project by ID.
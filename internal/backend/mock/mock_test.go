@@ -782,3 +782,64 @@ func TestListCredentialsEmpty(t *testing.T) {
 
 var _ backend.Backend = (*Backend)(nil)
 var _ backend.Writer = (*Backend)(nil)
+var _ backend.Filterer = (*Backend)(nil)
+
+// ===== Filter Tests =====
+
+// TestFilterServers is the reference behavior for backend.Filterer.
+// Every real backend applies the same backend.ServerFilter semantics.
+func TestFilterServers(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name   string
+		filter backend.ServerFilter
+		want   []string
+	}{
+		{"empty filter returns all", backend.ServerFilter{}, []string{"web", "db", "staging", "bastion"}},
+		{"project", backend.ServerFilter{ProjectID: "payments"}, []string{"web", "db"}},
+		{"project is exact", backend.ServerFilter{ProjectID: "Payments"}, []string{}},
+		{"single tag", backend.ServerFilter{Tags: []string{"prod"}}, []string{"web", "db"}},
+		{"all tags required, case-insensitive", backend.ServerFilter{Tags: []string{"PROD", "db"}}, []string{"db"}},
+		{"unknown tag", backend.ServerFilter{Tags: []string{"qa"}}, []string{}},
+		{"favorites only", backend.ServerFilter{Favorite: &yes}, []string{"web", "bastion"}},
+		{"non-favorites only", backend.ServerFilter{Favorite: &no}, []string{"db", "staging"}},
+		{"query matches user", backend.ServerFilter{Query: "postgres"}, []string{"db"}},
+		{"query matches host", backend.ServerFilter{Query: "jump"}, []string{"bastion"}},
+		{"query matches tags", backend.ServerFilter{Query: "edge"}, []string{"bastion"}},
+		{"query is fuzzy", backend.ServerFilter{Query: "stgng"}, []string{"staging"}},
+		{"criteria combine", backend.ServerFilter{Tags: []string{"prod"}, Favorite: &yes}, []string{"web"}},
+		{"no match", backend.ServerFilter{ProjectID: "payments", Query: "jump"}, []string{}},
+	}
+
+	b := New()
+	b.Seed([]*domain.Server{
+		{ID: "web", DisplayName: "web", Host: "web.example.com", User: "deploy", Tags: []string{"prod", "web"}, ProjectIDs: []string{"payments"}, Favorite: true},
+		{ID: "db", DisplayName: "db", Host: "10.0.0.5", User: "postgres", Tags: []string{"prod", "db"}, ProjectIDs: []string{"payments"}},
+		{ID: "staging", DisplayName: "staging", Host: "staging.example.com", User: "ubuntu", Tags: []string{"staging"}},
+		{ID: "bastion", DisplayName: "bastion", Host: "jump.example.com", User: "admin", Tags: []string{"edge"}, Favorite: true},
+	}, nil, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, err := b.FilterServers(context.Background(), tt.filter)
+			require.NoError(t, err)
+			assert.NotNil(t, servers)
+
+			ids := make([]string, len(servers))
+			for i, s := range servers {
+				ids[i] = s.ID
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
+func TestFilterServersClosed(t *testing.T) {
+	b := New()
+	require.NoError(t, b.Close())
+
+	_, err := b.FilterServers(context.Background(), backend.ServerFilter{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, backendErrors.ErrBackendUnavailable))
+}
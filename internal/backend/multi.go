@@ -10,7 +10,8 @@ import (
 )
 
 // MultiBackend aggregates servers from multiple Backend implementations.
-// Implements Backend interface. Delegates writes to the first Writer-capable backend.
+// Implements Backend interface. Updates and deletes of servers go to the backend
+// that holds the server; other writes go to the first Writer-capable backend.
 //
 // Priority order matters: later backends win conflicts when servers have duplicate DisplayNames.
 // For deduplication, DisplayName comparison is case-insensitive.
@@ -19,8 +20,10 @@ type MultiBackend struct {
 	mu       sync.RWMutex
 }
 
-// Ensure MultiBackend implements Backend interface.
+// Ensure MultiBackend implements Backend, Filterer and VaultLister interfaces.
 var _ Backend = (*MultiBackend)(nil)
+var _ Filterer = (*MultiBackend)(nil)
+var _ VaultLister = (*MultiBackend)(nil)
 
 // NewMultiBackend creates a new multi-backend aggregator.
 // Backends are provided in priority order: later backends win conflicts.
@@ -78,6 +81,18 @@ func (m *MultiBackend) ListServers(ctx context.Context) ([]*domain.Server, error
 	return result, nil
 }
 
+// FilterServers returns the aggregated servers matching the filter.
+// Filtering runs after deduplication rather than being delegated to each
+// backend, so a lower-priority duplicate never shows up in place of the
+// server that actually wins.
+func (m *MultiBackend) FilterServers(ctx context.Context, filters ServerFilter) ([]*domain.Server, error) {
+	servers, err := m.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyFilter(servers, filters), nil
+}
+
 // GetServer retrieves a server by ID from backends in reverse priority order (highest first).
 // Returns the first match found.
 func (m *MultiBackend) GetServer(ctx context.Context, id string) (*domain.Server, error) {
@@ -96,7 +111,7 @@ func (m *MultiBackend) GetServer(ctx context.Context, id string) (*domain.Server
 	return nil, &errors.BackendError{
 		Op:      "GetServer",
 		Backend: "multi",
-		Err:     errors.New("server not found in any backend"),
+		Err:     errors.ErrServerNotFound,
 	}
 }
 
@@ -134,7 +149,7 @@ func (m *MultiBackend) GetProject(ctx context.Context, id string) (*domain.Proje
 	return nil, &errors.BackendError{
 		Op:      "GetProject",
 		Backend: "multi",
-		Err:     errors.New("project not found in any backend"),
+		Err:     errors.ErrProjectNotFound,
 	}
 }
 
@@ -172,7 +187,7 @@ func (m *MultiBackend) GetCredential(ctx context.Context, id string) (*domain.Cr
 	return nil, &errors.BackendError{
 		Op:      "GetCredential",
 		Backend: "multi",
-		Err:     errors.New("credential not found in any backend"),
+		Err:     errors.ErrCredentialNotFound,
 	}
 }
 
@@ -212,46 +227,63 @@ func (m *MultiBackend) CreateServer(ctx context.Context, server *domain.Server)
 	return &errors.BackendError{
 		Op:      "CreateServer",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
-// UpdateServer delegates to the first Writer-capable backend.
+// UpdateServer delegates to the backend that holds the server.
 func (m *MultiBackend) UpdateServer(ctx context.Context, server *domain.Server) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	for _, backend := range m.backends {
-		if writer, ok := backend.(Writer); ok {
-			return writer.UpdateServer(ctx, server)
-		}
+	if writer := m.serverWriter(ctx, server.ID); writer != nil {
+		return writer.UpdateServer(ctx, server)
 	}
 
 	return &errors.BackendError{
 		Op:      "UpdateServer",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
-// DeleteServer delegates to the first Writer-capable backend.
+// DeleteServer delegates to the backend that holds the server.
 func (m *MultiBackend) DeleteServer(ctx context.Context, id string) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	for _, backend := range m.backends {
-		if writer, ok := backend.(Writer); ok {
-			return writer.DeleteServer(ctx, id)
-		}
+	if writer := m.serverWriter(ctx, id); writer != nil {
+		return writer.DeleteServer(ctx, id)
 	}
 
 	return &errors.BackendError{
 		Op:      "DeleteServer",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
+// serverWriter returns the Writer of the backend that holds the server with
+// id, checking backends in the same priority order as GetServer. A server no
+// backend knows goes to the first Writer, which reports it as not found.
+// Returns nil when that backend is read-only.
+// Must be called with mu held.
+func (m *MultiBackend) serverWriter(ctx context.Context, id string) Writer {
+	for i := len(m.backends) - 1; i >= 0; i-- {
+		if server, err := m.backends[i].GetServer(ctx, id); err == nil && server != nil {
+			writer, _ := m.backends[i].(Writer)
+			return writer
+		}
+	}
+
+	for _, backend := range m.backends {
+		if writer, ok := backend.(Writer); ok {
+			return writer
+		}
+	}
+	return nil
+}
+
 // CreateProject delegates to the first Writer-capable backend.
 func (m *MultiBackend) CreateProject(ctx context.Context, project *domain.Project) error {
 	m.mu.RLock()
@@ -266,7 +298,7 @@ func (m *MultiBackend) CreateProject(ctx context.Context, project *domain.Projec
 	return &errors.BackendError{
 		Op:      "CreateProject",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
@@ -284,7 +316,7 @@ func (m *MultiBackend) UpdateProject(ctx context.Context, project *domain.Projec
 	return &errors.BackendError{
 		Op:      "UpdateProject",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
@@ -302,7 +334,7 @@ func (m *MultiBackend) DeleteProject(ctx context.Context, id string) error {
 	return &errors.BackendError{
 		Op:      "DeleteProject",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
@@ -320,7 +352,7 @@ func (m *MultiBackend) CreateCredential(ctx context.Context, cred *domain.Creden
 	return &errors.BackendError{
 		Op:      "CreateCredential",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
@@ -338,7 +370,7 @@ func (m *MultiBackend) UpdateCredential(ctx context.Context, cred *domain.Creden
 	return &errors.BackendError{
 		Op:      "UpdateCredential",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
@@ -356,7 +388,7 @@ func (m *MultiBackend) DeleteCredential(ctx context.Context, id string) error {
 	return &errors.BackendError{
 		Op:      "DeleteCredential",
 		Backend: "multi",
-		Err:     errors.New("no writer-capable backend available"),
+		Err:     errors.ErrReadOnlyBackend,
 	}
 }
 
@@ -367,6 +399,39 @@ func isSsherpaGenerated(server *domain.Server) bool {
 	return server.Source == "ssh-config" && strings.Contains(server.Notes, "ssherpa_config")
 }
 
+// WritableVaults returns the vaults of the first VaultLister backend, or nil.
+func (m *MultiBackend) WritableVaults() []Vault {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, backend := range m.backends {
+		if lister, ok := backend.(VaultLister); ok {
+			return lister.WritableVaults()
+		}
+	}
+	return nil
+}
+
+// DefaultVaultID delegates to the first VaultLister backend.
+func (m *MultiBackend) DefaultVaultID(projectID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, backend := range m.backends {
+		if lister, ok := backend.(VaultLister); ok {
+			return lister.DefaultVaultID(projectID)
+		}
+	}
+	return ""
+}
+
+// Backends returns the aggregated backends in priority order.
+func (m *MultiBackend) Backends() []Backend {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Backend(nil), m.backends...)
+}
+
 // GetOnePasswordBackend finds and returns the 1Password backend if present.
 // Returns nil if no 1Password backend is in the multi-backend.
 func (m *MultiBackend) GetOnePasswordBackend() interface{} {
@@ -564,6 +564,19 @@ func TestMultiBackend_GetOnePasswordBackend_NotFound(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestMultiBackend_Backends(t *testing.T) {
+	backendA := mock.New()
+	backendB := mock.New()
+
+	multi := backend.NewMultiBackend(backendA, backendB)
+	defer func() { _ = multi.Close() }()
+
+	backends := multi.Backends()
+	require.Len(t, backends, 2)
+	assert.Same(t, backendA, backends[0])
+	assert.Same(t, backendB, backends[1])
+}
+
 func TestMultiBackend_UpdateProjectDelegation(t *testing.T) {
 	backendA := mock.New()
 	backendA.Seed(nil, []*domain.Project{
@@ -671,3 +684,77 @@ func TestMultiBackend_PureSshConfigServersNotFiltered(t *testing.T) {
 	assert.Equal(t, "ssh-config", servers[0].Source)
 	assert.Equal(t, "9.8.7.6", servers[0].Host)
 }
+
+func TestMultiBackend_FilterServers(t *testing.T) {
+	// Backend A's "web" is tagged prod, but backend B's duplicate wins
+	backendA := mock.New()
+	backendA.Seed([]*domain.Server{
+		{ID: "a-web", DisplayName: "web", Tags: []string{"prod"}},
+		{ID: "a-db", DisplayName: "db", Tags: []string{"prod"}},
+	}, nil, nil)
+
+	backendB := mock.New()
+	backendB.Seed([]*domain.Server{
+		{ID: "b-web", DisplayName: "web", Tags: []string{"staging"}},
+		{ID: "b-cache", DisplayName: "cache", Tags: []string{"prod"}, Favorite: true},
+	}, nil, nil)
+
+	multi := backend.NewMultiBackend(backendA, backendB)
+	var _ backend.Filterer = multi
+
+	yes := true
+	tests := []struct {
+		name   string
+		filter backend.ServerFilter
+		want   []string
+	}{
+		{"tag after dedup", backend.ServerFilter{Tags: []string{"prod"}}, []string{"a-db", "b-cache"}},
+		{"winning duplicate", backend.ServerFilter{Tags: []string{"staging"}}, []string{"b-web"}},
+		{"favorite", backend.ServerFilter{Favorite: &yes}, []string{"b-cache"}},
+		{"query", backend.ServerFilter{Query: "web"}, []string{"b-web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, err := multi.FilterServers(context.Background(), tt.filter)
+			require.NoError(t, err)
+
+			ids := make([]string, len(servers))
+			for i, s := range servers {
+				ids[i] = s.ID
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
+// vaultBackend is a mock backend that also lists vaults.
+type vaultBackend struct {
+	*mock.Backend
+	vaults []backend.Vault
+}
+
+func (v *vaultBackend) WritableVaults() []backend.Vault { return v.vaults }
+
+func (v *vaultBackend) DefaultVaultID(projectID string) string {
+	if projectID == "acme/payments" {
+		return "v2"
+	}
+	return "v1"
+}
+
+func TestMultiBackend_VaultListerDelegation(t *testing.T) {
+	withVaults := &vaultBackend{Backend: mock.New(), vaults: []backend.Vault{{ID: "v1", Name: "Team"}, {ID: "v2", Name: "Payments"}}}
+	multi := backend.NewMultiBackend(mock.New(), withVaults)
+	defer func() { _ = multi.Close() }()
+
+	assert.Equal(t, withVaults.vaults, multi.WritableVaults())
+	assert.Equal(t, "v1", multi.DefaultVaultID(""))
+	assert.Equal(t, "v2", multi.DefaultVaultID("acme/payments"))
+
+	// Without a VaultLister there is nothing to offer
+	plain := backend.NewMultiBackend(mock.New())
+	defer func() { _ = plain.Close() }()
+	assert.Nil(t, plain.WritableVaults())
+	assert.Empty(t, plain.DefaultVaultID(""))
+}
@@ -10,43 +10,63 @@ import (
 	"github.com/florianriquelme/ssherpa/internal/errors"
 )
 
-// Backend implements the backendpkg.Backend and backendpkg.Writer interfaces
-// using 1Password as the storage layer.
+// Backend implements the backendpkg.Backend, backendpkg.Writer, backendpkg.Filterer and
+// backendpkg.VaultLister interfaces using 1Password as the storage layer.
 type Backend struct {
 	client    Client                   // SDK client (real or mock)
 	mu        sync.RWMutex             // Protects cached servers, status, and closed flag
 	servers   []*domain.Server         // Cached servers from last sync
+	skipped   []SkippedItem            // Tagged items the last sync couldn't convert
+	keys      []*domain.Credential     // SSH Key items from the last sync
+	keyRevs   map[string]string        // Item revision of each key, keyed by item ID
 	closed    bool                     // Backend closed flag
 	status    backendpkg.BackendStatus // Current availability status
 	cachePath string                   // Path to TOML cache for fallback
 	poller    *Poller                  // Background availability poller
 	lastWrite time.Time                // Last write timestamp for debouncing
+
+	syncWorkers int // Concurrent GetItem calls during a sync
+
+	vaults        []Vault           // Vaults passing the filter, from the last sync (nil = not listed yet)
+	vaultInclude  []string          // Vault names/IDs to sync (empty = all)
+	vaultExclude  []string          // Vault names/IDs never synced
+	defaultVault  string            // Vault name/ID for new servers
+	projectVaults map[string]string // Per-project default vault, keyed by project ID
 }
 
+// defaultSyncWorkers bounds concurrent "op item get" calls during a sync.
+// Each call is a separate op process, so a handful is enough to hide latency
+// without tripping 1Password's rate limits.
+const defaultSyncWorkers = 8
+
 // Compile-time interface verification
 var (
-	_ backendpkg.Backend = (*Backend)(nil)
-	_ backendpkg.Writer  = (*Backend)(nil)
-	_ backendpkg.Syncer  = (*Backend)(nil)
+	_ backendpkg.Backend     = (*Backend)(nil)
+	_ backendpkg.Writer      = (*Backend)(nil)
+	_ backendpkg.Syncer      = (*Backend)(nil)
+	_ backendpkg.Filterer    = (*Backend)(nil)
+	_ backendpkg.VaultLister = (*Backend)(nil)
 )
 
 // New creates a new 1Password backend with the given client.
 // No initial sync is performed - caller should call ListServers to populate cache.
 func New(client Client) *Backend {
 	return &Backend{
-		client:  client,
-		servers: make([]*domain.Server, 0),
-		status:  backendpkg.StatusUnknown,
+		client:      client,
+		servers:     make([]*domain.Server, 0),
+		status:      backendpkg.StatusUnknown,
+		syncWorkers: defaultSyncWorkers,
 	}
 }
 
 // NewWithCache creates a new 1Password backend with cache path for offline fallback.
 func NewWithCache(client Client, cachePath string) *Backend {
 	return &Backend{
-		client:    client,
-		servers:   make([]*domain.Server, 0),
-		status:    backendpkg.StatusUnknown,
-		cachePath: cachePath,
+		client:      client,
+		servers:     make([]*domain.Server, 0),
+		status:      backendpkg.StatusUnknown,
+		cachePath:   cachePath,
+		syncWorkers: defaultSyncWorkers,
 	}
 }
 
@@ -108,6 +128,17 @@ func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, err
 	}
 }
 
+// FilterServers returns the cached servers matching the filter.
+// Project IDs come from the "project_tags" field, tags from the item tags and
+// favorites from the "favorite" field.
+func (b *Backend) FilterServers(ctx context.Context, filters backendpkg.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backendpkg.ApplyFilter(servers, filters), nil
+}
+
 // ListProjects returns an empty slice (projects are tags on items, not standalone entities).
 func (b *Backend) ListProjects(ctx context.Context) ([]*domain.Project, error) {
 	b.mu.RLock()
@@ -136,7 +167,9 @@ func (b *Backend) GetProject(ctx context.Context, id string) (*domain.Project, e
 	}
 }
 
-// ListCredentials returns an empty slice (credentials are embedded in items).
+// ListCredentials returns the SSH Key items found by the last sync, sorted
+// by name. Only their public keys are loaded; the 1Password SSH agent holds
+// the private keys.
 func (b *Backend) ListCredentials(ctx context.Context) ([]*domain.Credential, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -145,10 +178,17 @@ func (b *Backend) ListCredentials(ctx context.Context) ([]*domain.Credential, er
 		return nil, err
 	}
 
-	return []*domain.Credential{}, nil
+	// Return copies (copy-on-read pattern)
+	result := make([]*domain.Credential, len(b.keys))
+	for i, c := range b.keys {
+		credCopy := *c
+		result[i] = &credCopy
+	}
+
+	return result, nil
 }
 
-// GetCredential returns ErrCredentialNotFound (credentials are embedded in items).
+// GetCredential retrieves an SSH Key item by ID from the cache.
 func (b *Backend) GetCredential(ctx context.Context, id string) (*domain.Credential, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -157,6 +197,13 @@ func (b *Backend) GetCredential(ctx context.Context, id string) (*domain.Credent
 		return nil, err
 	}
 
+	for _, c := range b.keys {
+		if c.ID == id {
+			credCopy := *c
+			return &credCopy, nil
+		}
+	}
+
 	return nil, &errors.BackendError{
 		Op:      "GetCredential",
 		Backend: "onepassword",
@@ -188,7 +235,8 @@ func (b *Backend) Close() error {
 }
 
 // CreateServer creates a new server in 1Password.
-// The server.VaultID must be set to specify the target vault.
+// server.VaultID names the target vault by ID or name; when empty, the
+// project's vault, the default vault or the only writable vault is used.
 func (b *Backend) CreateServer(ctx context.Context, server *domain.Server) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -197,16 +245,17 @@ func (b *Backend) CreateServer(ctx context.Context, server *domain.Server) error
 		return err
 	}
 
-	if server.VaultID == "" {
+	vaultID, err := b.resolveVault(ctx, server.VaultID, server.ProjectIDs)
+	if err != nil {
 		return &errors.BackendError{
 			Op:      "CreateServer",
 			Backend: "onepassword",
-			Err:     errors.New("VaultID must be set"),
+			Err:     err,
 		}
 	}
 
 	// Convert to item
-	item := ServerToItem(server, server.VaultID)
+	item := ServerToItem(server, vaultID)
 
 	// Create in 1Password
 	created, err := b.client.CreateItem(ctx, item)
@@ -280,6 +329,7 @@ func (b *Backend) UpdateServer(ctx context.Context, server *domain.Server) error
 	updated := ServerToItem(server, vaultID)
 	updated.ID = existing.ID
 	updated.VaultID = existing.VaultID
+	updated.Fields = mergeItemFields(existing.Fields, updated.Fields)
 
 	// Update in 1Password
 	_, err = b.client.UpdateItem(ctx, updated)
@@ -239,6 +239,116 @@ func TestUpdateServer(t *testing.T) {
 	assert.Equal(t, "newuser", server.User)
 }
 
+func TestUpdateServer_TagsAndFlags(t *testing.T) {
+	client := NewMockClient()
+	client.AddVault(Vault{ID: "vault-1", Name: "Personal"})
+	client.AddItem(Item{
+		ID:       "item-1",
+		Title:    "Pinned",
+		VaultID:  "vault-1",
+		Category: "server",
+		Tags:     []string{"ssherpa", "old"},
+		Fields: []ItemField{
+			{Title: "hostname", Value: "pinned.example.com", FieldType: "Text"},
+			{Title: "user", Value: "ops", FieldType: "Text"},
+			{Title: "favorite", Value: "true", FieldType: "Text"},
+			{Title: "forward_agent", Value: "yes", FieldType: "Text"},
+		},
+	})
+
+	b := New(client)
+	ctx := context.Background()
+	require.NoError(t, b.SyncFromOnePassword(ctx))
+
+	server, err := b.GetServer(ctx, "item-1")
+	require.NoError(t, err)
+	require.True(t, server.Favorite)
+	require.Equal(t, []string{"old"}, server.Tags)
+
+	// Unpin and retag
+	server.Favorite = false
+	server.Tags = []string{"prod"}
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	item, err := client.GetItem(ctx, "vault-1", "item-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ssherpa", "prod"}, item.Tags)
+
+	reparsed, err := ItemToServer(item)
+	require.NoError(t, err)
+	assert.False(t, reparsed.Favorite)
+	assert.Equal(t, []string{"prod"}, reparsed.Tags)
+
+	// Fields ssherpa doesn't manage survive the update
+	var forwardAgent string
+	for _, f := range item.Fields {
+		if f.Title == "forward_agent" {
+			forwardAgent = f.Value
+		}
+	}
+	assert.Equal(t, "yes", forwardAgent)
+}
+
+func TestFilterServers(t *testing.T) {
+	client := NewMockClient()
+	client.AddVault(Vault{ID: "vault-1", Name: "Personal"})
+	client.AddItem(Item{
+		ID:       "item-1",
+		Title:    "web",
+		VaultID:  "vault-1",
+		Category: "server",
+		Tags:     []string{"ssherpa", "prod"},
+		Fields: []ItemField{
+			{Title: "hostname", Value: "web.example.com", FieldType: "Text"},
+			{Title: "user", Value: "deploy", FieldType: "Text"},
+			{Title: "project_tags", Value: "payments", FieldType: "Text"},
+			{Title: "favorite", Value: "true", FieldType: "Text"},
+		},
+	})
+	client.AddItem(Item{
+		ID:       "item-2",
+		Title:    "db",
+		VaultID:  "vault-1",
+		Category: "server",
+		Tags:     []string{"ssherpa", "prod", "db"},
+		Fields: []ItemField{
+			{Title: "hostname", Value: "10.0.0.5", FieldType: "Text"},
+			{Title: "user", Value: "postgres", FieldType: "Text"},
+		},
+	})
+
+	b := New(client)
+	ctx := context.Background()
+	require.NoError(t, b.SyncFromOnePassword(ctx))
+
+	yes := true
+	tests := []struct {
+		name   string
+		filter backend.ServerFilter
+		want   []string
+	}{
+		{"empty filter", backend.ServerFilter{}, []string{"item-1", "item-2"}},
+		{"marker tag is not a tag", backend.ServerFilter{Tags: []string{"ssherpa"}}, []string{}},
+		{"item tags", backend.ServerFilter{Tags: []string{"db"}}, []string{"item-2"}},
+		{"project_tags field", backend.ServerFilter{ProjectID: "payments"}, []string{"item-1"}},
+		{"favorite field", backend.ServerFilter{Favorite: &yes}, []string{"item-1"}},
+		{"query", backend.ServerFilter{Query: "postgres"}, []string{"item-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, err := b.FilterServers(ctx, tt.filter)
+			require.NoError(t, err)
+
+			ids := make([]string, len(servers))
+			for i, s := range servers {
+				ids[i] = s.ID
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
 func TestDeleteServer(t *testing.T) {
 	client := NewMockClient()
 	client.AddVault(Vault{ID: "vault-1", Name: "Personal"})
@@ -326,3 +436,40 @@ func TestInterfaceCompliance(t *testing.T) {
 	// If we get here, the test passes
 	assert.True(t, true)
 }
+
+func TestSyncRecordsSkippedItems(t *testing.T) {
+	client := NewMockClient()
+	client.AddVault(Vault{ID: "vault-1", Name: "Personal"})
+	client.AddItem(Item{
+		ID:       "item-good",
+		Title:    "Good Server",
+		VaultID:  "vault-1",
+		Category: "server",
+		Tags:     []string{"ssherpa"},
+		Fields: []ItemField{
+			{Title: "hostname", Value: "good.example.com", FieldType: "Text"},
+			{Title: "user", Value: "admin", FieldType: "Text"},
+		},
+	})
+	client.AddItem(Item{
+		ID:       "item-bad",
+		Title:    "No Hostname",
+		VaultID:  "vault-1",
+		Category: "server",
+		Tags:     []string{"ssherpa"},
+		Fields: []ItemField{
+			{Title: "user", Value: "admin", FieldType: "Text"},
+		},
+	})
+
+	b := New(client)
+	assert.Empty(t, b.SkippedItems(), "nothing skipped before the first sync")
+
+	require.NoError(t, b.SyncFromOnePassword(context.Background()))
+
+	skipped := b.SkippedItems()
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "No Hostname", skipped[0].Title)
+	assert.Equal(t, "vault-1", skipped[0].VaultID)
+	assert.NotEmpty(t, skipped[0].Reason)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 // CommandExecutor abstracts command execution for testability.
@@ -30,11 +31,17 @@ func (e *defaultExecutor) Run(ctx context.Context, name string, args ...string)
 	return stdout, nil, nil
 }
 
+// ServiceAccountTokenEnv is the environment variable op reads a service
+// account token from. When it is set, op authenticates with the token instead
+// of a desktop-app session.
+const ServiceAccountTokenEnv = "OP_SERVICE_ACCOUNT_TOKEN"
+
 // CLIClient implements the Client interface using the op CLI.
 type CLIClient struct {
-	opPath   string
-	account  string // 1Password account identifier (e.g. "my.1password.com")
-	executor CommandExecutor
+	opPath         string
+	account        string // 1Password account identifier (e.g. "my.1password.com")
+	serviceAccount bool   // OP_SERVICE_ACCOUNT_TOKEN is set; op ignores sessions and rejects --account
+	executor       CommandExecutor
 }
 
 // NewCLIClient creates a new CLI-based 1Password client.
@@ -46,31 +53,36 @@ func NewCLIClient() (*CLIClient, error) {
 	}
 
 	return &CLIClient{
-		opPath:   opPath,
-		executor: &defaultExecutor{},
+		opPath:         opPath,
+		serviceAccount: os.Getenv(ServiceAccountTokenEnv) != "",
+		executor:       &defaultExecutor{},
 	}, nil
 }
 
 // NewCLIClientWithAccount creates a new CLI-based 1Password client with an account identifier.
 // The account is prepended as --account to all op commands, ensuring the correct
 // 1Password account is used (matches the raycast-1password-extension approach).
+// With a service account token the account is ignored: the token names its account.
 func NewCLIClientWithAccount(account string) (*CLIClient, error) {
-	opPath, err := exec.LookPath("op")
+	client, err := NewCLIClient()
 	if err != nil {
-		return nil, fmt.Errorf("op CLI not found in PATH: %w", err)
+		return nil, err
 	}
+	client.account = account
+	return client, nil
+}
 
-	return &CLIClient{
-		opPath:   opPath,
-		account:  account,
-		executor: &defaultExecutor{},
-	}, nil
+// UsesServiceAccount reports whether op authenticates with a service account
+// token rather than a desktop-app session.
+func (c *CLIClient) UsesServiceAccount() bool {
+	return c.serviceAccount
 }
 
 // runOP executes an op command with the given arguments and returns the stdout.
-// When an account is configured, --account is prepended to all commands.
+// When an account is configured, --account is prepended to all commands,
+// except with a service account token, which op doesn't combine with --account.
 func (c *CLIClient) runOP(ctx context.Context, args ...string) ([]byte, error) {
-	if c.account != "" {
+	if c.account != "" && !c.serviceAccount {
 		args = append([]string{"--account", c.account}, args...)
 	}
 	stdout, stderr, err := c.executor.Run(ctx, c.opPath, args...)
@@ -112,7 +124,8 @@ func (c *CLIClient) ListVaults(ctx context.Context) ([]Vault, error) {
 }
 
 // ListItems retrieves all items in a vault using the op CLI.
-// This method requests full item data to avoid N+1 queries.
+// op only returns item metadata here (including version and updated_at);
+// fields need a GetItem call.
 func (c *CLIClient) ListItems(ctx context.Context, vaultID string) ([]Item, error) {
 	output, err := c.runOP(ctx, "item", "list", "--vault", vaultID, "--format", "json")
 	if err != nil {
@@ -127,7 +140,9 @@ func (c *CLIClient) ListItems(ctx context.Context, vaultID string) ([]Item, erro
 		Vault    struct {
 			ID string `json:"id"`
 		} `json:"vault"`
-		Fields []struct {
+		Version   int       `json:"version"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Fields    []struct {
 			ID      string `json:"id"`
 			Label   string `json:"label"`
 			Type    string `json:"type"`
@@ -146,12 +161,14 @@ func (c *CLIClient) ListItems(ctx context.Context, vaultID string) ([]Item, erro
 	items := make([]Item, 0, len(cliItems))
 	for _, cliItem := range cliItems {
 		item := Item{
-			ID:       cliItem.ID,
-			Title:    cliItem.Title,
-			VaultID:  cliItem.Vault.ID,
-			Category: strings.ToLower(cliItem.Category),
-			Tags:     cliItem.Tags,
-			Fields:   make([]ItemField, 0, len(cliItem.Fields)),
+			ID:        cliItem.ID,
+			Title:     cliItem.Title,
+			VaultID:   cliItem.Vault.ID,
+			Category:  strings.ToLower(cliItem.Category),
+			Tags:      cliItem.Tags,
+			Fields:    make([]ItemField, 0, len(cliItem.Fields)),
+			Version:   cliItem.Version,
+			UpdatedAt: cliItem.UpdatedAt,
 		}
 
 		// Map fields
@@ -192,7 +209,9 @@ func (c *CLIClient) GetItem(ctx context.Context, vaultID, itemID string) (*Item,
 		Vault    struct {
 			ID string `json:"id"`
 		} `json:"vault"`
-		Fields []struct {
+		Version   int       `json:"version"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Fields    []struct {
 			ID      string `json:"id"`
 			Label   string `json:"label"`
 			Type    string `json:"type"`
@@ -201,6 +220,7 @@ func (c *CLIClient) GetItem(ctx context.Context, vaultID, itemID string) (*Item,
 				ID string `json:"id"`
 			} `json:"section"`
 		} `json:"fields"`
+		URLs []itemURL `json:"urls"`
 	}
 
 	if err := json.Unmarshal(output, &cliItem); err != nil {
@@ -209,12 +229,15 @@ func (c *CLIClient) GetItem(ctx context.Context, vaultID, itemID string) (*Item,
 
 	// Map CLI response to our Item structure
 	item := &Item{
-		ID:       cliItem.ID,
-		Title:    cliItem.Title,
-		VaultID:  cliItem.Vault.ID,
-		Category: strings.ToLower(cliItem.Category), // CLI returns uppercase like "SERVER"
-		Tags:     cliItem.Tags,
-		Fields:   make([]ItemField, 0, len(cliItem.Fields)),
+		ID:        cliItem.ID,
+		Title:     cliItem.Title,
+		VaultID:   cliItem.Vault.ID,
+		Category:  strings.ToLower(cliItem.Category), // CLI returns uppercase like "SERVER"
+		Tags:      cliItem.Tags,
+		Fields:    make([]ItemField, 0, len(cliItem.Fields)),
+		URLs:      urlHrefs(cliItem.URLs),
+		Version:   cliItem.Version,
+		UpdatedAt: cliItem.UpdatedAt,
 	}
 
 	// Map fields with proper field name mapping
@@ -237,6 +260,28 @@ func (c *CLIClient) GetItem(ctx context.Context, vaultID, itemID string) (*Item,
 	return item, nil
 }
 
+// itemURL is an entry of an item's "urls" list, as returned by op and Connect.
+type itemURL struct {
+	Href    string `json:"href"`
+	Primary bool   `json:"primary"`
+}
+
+// urlHrefs returns the URLs' addresses with the primary one first.
+func urlHrefs(urls []itemURL) []string {
+	var hrefs []string
+	for _, u := range urls {
+		if u.Href == "" {
+			continue
+		}
+		if u.Primary {
+			hrefs = append([]string{u.Href}, hrefs...)
+		} else {
+			hrefs = append(hrefs, u.Href)
+		}
+	}
+	return hrefs
+}
+
 // mapCLIFieldType maps CLI field types to our internal field types.
 func mapCLIFieldType(cliType string) string {
 	// CLI uses different type names than SDK
@@ -317,6 +362,11 @@ func (c *CLIClient) UpdateItem(ctx context.Context, item *Item) (*Item, error) {
 		args = append(args, "--title", item.Title)
 	}
 
+	// Replace tags (ServerToItem always includes the "ssherpa" marker)
+	if len(item.Tags) > 0 {
+		args = append(args, "--tags", strings.Join(item.Tags, ","))
+	}
+
 	// Update fields as key=value pairs after the -- separator
 	if len(item.Fields) > 0 {
 		args = append(args, "--")
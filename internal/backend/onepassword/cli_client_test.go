@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"os/exec"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // mockExecutor implements CommandExecutor for testing.
@@ -202,6 +204,29 @@ func TestListItems(t *testing.T) {
 	}
 }
 
+func TestListItems_Revision(t *testing.T) {
+	mock := newMockExecutor()
+	client := &CLIClient{opPath: "op", executor: mock}
+
+	response := `[{"id": "item1", "title": "Server 1", "category": "SERVER", "tags": ["ssherpa"],
+		"vault": {"id": "vault1"}, "version": 7, "updated_at": "2026-03-01T10:20:30Z"}]`
+	mock.setResponse("op", []string{"item", "list", "--vault", "vault1", "--format", "json"}, []byte(response), nil, nil)
+
+	items, err := client.ListItems(context.Background(), "vault1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	if items[0].Version != 7 {
+		t.Errorf("expected version 7, got %d", items[0].Version)
+	}
+	if want := time.Date(2026, 3, 1, 10, 20, 30, 0, time.UTC); !items[0].UpdatedAt.Equal(want) {
+		t.Errorf("expected updated_at %v, got %v", want, items[0].UpdatedAt)
+	}
+}
+
 func TestGetItem(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -281,6 +306,30 @@ func TestGetItem(t *testing.T) {
 	}
 }
 
+func TestGetItem_URLs(t *testing.T) {
+	mock := newMockExecutor()
+	client := &CLIClient{opPath: "op", executor: mock}
+	mock.setResponse("op", []string{"item", "get", "item1", "--vault", "vault1", "--format", "json"}, []byte(`{
+		"id": "item1",
+		"title": "Bastion",
+		"category": "LOGIN",
+		"vault": {"id": "vault1"},
+		"urls": [
+			{"label": "admin", "href": "https://admin.example.com"},
+			{"label": "ssh", "primary": true, "href": "ssh://ops@bastion.example.com"}
+		]
+	}`), nil, nil)
+
+	item, err := client.GetItem(context.Background(), "vault1", "item1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"ssh://ops@bastion.example.com", "https://admin.example.com"}
+	if !reflect.DeepEqual(item.URLs, want) {
+		t.Errorf("expected URLs %v (primary first), got %v", want, item.URLs)
+	}
+}
+
 func TestCreateItem(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -499,6 +548,48 @@ func TestDeleteItem(t *testing.T) {
 	}
 }
 
+func TestRunOP_ServiceAccountSkipsAccountFlag(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceAccount bool
+		wantArgs       []string
+	}{
+		{"desktop session", false, []string{"--account", "my.1password.com", "vault", "list", "--format", "json"}},
+		{"service account", true, []string{"vault", "list", "--format", "json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockExecutor()
+			client := &CLIClient{
+				opPath:         "op",
+				account:        "my.1password.com",
+				serviceAccount: tt.serviceAccount,
+				executor:       mock,
+			}
+			mock.setResponse("op", tt.wantArgs, []byte(`[]`), nil, nil)
+
+			if _, err := client.ListVaults(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if client.UsesServiceAccount() != tt.serviceAccount {
+				t.Errorf("UsesServiceAccount() = %v, want %v", client.UsesServiceAccount(), tt.serviceAccount)
+			}
+		})
+	}
+}
+
+func TestNewCLIClient_DetectsServiceAccount(t *testing.T) {
+	t.Setenv(ServiceAccountTokenEnv, "ops_token")
+	client, err := NewCLIClientWithAccount("my.1password.com")
+	if err != nil {
+		t.Skipf("op CLI not available: %v", err)
+	}
+	if !client.UsesServiceAccount() {
+		t.Error("expected a service account client when OP_SERVICE_ACCOUNT_TOKEN is set")
+	}
+}
+
 func TestClose(t *testing.T) {
 	client := &CLIClient{
 		opPath:   "op",
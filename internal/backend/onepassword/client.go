@@ -2,6 +2,7 @@ package onepassword
 
 import (
 	"context"
+	"time"
 )
 
 // Client abstracts 1Password operations for testability.
@@ -24,13 +25,18 @@ type Vault struct {
 }
 
 // Item represents a 1Password item with simplified structure.
+// Version and UpdatedAt come with "op item list", so a sync can tell which
+// items changed without fetching their fields.
 type Item struct {
-	ID       string
-	Title    string
-	VaultID  string
-	Category string
-	Tags     []string
-	Fields   []ItemField
+	ID        string
+	Title     string
+	VaultID   string
+	Category  string
+	Tags      []string
+	Fields    []ItemField
+	URLs      []string // website entries (Login items), primary first
+	Version   int
+	UpdatedAt time.Time
 }
 
 // ItemField represents a field within a 1Password item.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // MockClient is an in-memory implementation of Client for testing.
@@ -13,7 +14,15 @@ type MockClient struct {
 	items       map[string]*Item // keyed by itemID
 	errors      map[string]error // configurable errors by operation
 	vaultErrors map[string]error // configurable errors by vaultID (for ListItems)
+	itemErrors  map[string]error // configurable errors by itemID (for GetItem)
 	closed      bool
+
+	serviceAccount bool // reported by UsesServiceAccount
+
+	getDelay    time.Duration // simulated latency of GetItem
+	getCalls    int           // GetItem calls made
+	inFlight    int           // GetItem calls currently running
+	maxInFlight int           // highest inFlight seen
 }
 
 // NewMockClient creates a new MockClient with empty storage.
@@ -23,6 +32,7 @@ func NewMockClient() *MockClient {
 		items:       make(map[string]*Item),
 		errors:      make(map[string]error),
 		vaultErrors: make(map[string]error),
+		itemErrors:  make(map[string]error),
 	}
 }
 
@@ -55,6 +65,43 @@ func (m *MockClient) SetVaultError(vaultID string, err error) {
 	m.vaultErrors[vaultID] = err
 }
 
+// SetItemError configures an error for a specific item (affects GetItem only).
+func (m *MockClient) SetItemError(itemID string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.itemErrors[itemID] = err
+}
+
+// SetServiceAccount makes the mock report service account authentication,
+// like a CLIClient with OP_SERVICE_ACCOUNT_TOKEN set.
+func (m *MockClient) SetServiceAccount(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.serviceAccount = enabled
+}
+
+// UsesServiceAccount reports the value set with SetServiceAccount.
+func (m *MockClient) UsesServiceAccount() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.serviceAccount
+}
+
+// SetGetItemDelay makes every GetItem call take at least d.
+func (m *MockClient) SetGetItemDelay(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.getDelay = d
+}
+
+// GetItemCalls returns how many times GetItem was called and the highest
+// number of calls that ran at the same time.
+func (m *MockClient) GetItemCalls() (calls, maxConcurrent int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCalls, m.maxInFlight
+}
+
 // ClearError removes a configured error for an operation.
 func (m *MockClient) ClearError(operation string) {
 	m.mu.Lock()
@@ -121,9 +168,32 @@ func (m *MockClient) GetItem(ctx context.Context, vaultID, itemID string) (*Item
 		return nil, err
 	}
 
+	m.mu.Lock()
+	m.getCalls++
+	m.inFlight++
+	m.maxInFlight = max(m.maxInFlight, m.inFlight)
+	delay := m.getDelay
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.inFlight--
+		m.mu.Unlock()
+	}()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	if err := m.itemErrors[itemID]; err != nil {
+		return nil, err
+	}
 	item, ok := m.items[itemID]
 	if !ok {
 		return nil, fmt.Errorf("item not found: %s", itemID)
@@ -188,12 +258,15 @@ func (m *MockClient) UpdateItem(ctx context.Context, item *Item) (*Item, error)
 	defer m.mu.Unlock()
 
 	// Check item exists
-	if _, exists := m.items[item.ID]; !exists {
+	existing, exists := m.items[item.ID]
+	if !exists {
 		return nil, fmt.Errorf("item not found: %s", item.ID)
 	}
 
-	// Store copy
+	// Store copy, bumping the version like 1Password does
 	itemCopy := *item
+	itemCopy.Version = existing.Version + 1
+	itemCopy.UpdatedAt = time.Now()
 	itemCopy.Fields = make([]ItemField, len(item.Fields))
 	copy(itemCopy.Fields, item.Fields)
 	itemCopy.Tags = make([]string, len(item.Tags))
@@ -0,0 +1,366 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewConnectClient when the URL or token is not
+// configured. The names match the ones used by the 1Password Connect SDKs.
+const (
+	ConnectHostEnv  = "OP_CONNECT_HOST"
+	ConnectTokenEnv = "OP_CONNECT_TOKEN"
+)
+
+// connectTimeout bounds a single Connect API request.
+const connectTimeout = 30 * time.Second
+
+// ConnectClient implements the Client interface against a 1Password Connect
+// server's REST API. It needs no op binary or desktop app, which suits CI
+// runners and headless jump boxes.
+type ConnectClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewConnectClient creates a client for the Connect server at host using the
+// given access token. An empty host or token falls back to OP_CONNECT_HOST
+// and OP_CONNECT_TOKEN.
+func NewConnectClient(host, token string) (*ConnectClient, error) {
+	if host == "" {
+		host = os.Getenv(ConnectHostEnv)
+	}
+	if token == "" {
+		token = os.Getenv(ConnectTokenEnv)
+	}
+	if host == "" {
+		return nil, fmt.Errorf("1Password Connect URL not set (connect_url or %s)", ConnectHostEnv)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("1Password Connect token not set (connect_token or %s)", ConnectTokenEnv)
+	}
+
+	u, err := url.Parse(host)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid 1Password Connect URL %q", host)
+	}
+
+	return &ConnectClient{
+		baseURL:    strings.TrimRight(host, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: connectTimeout},
+	}, nil
+}
+
+// ConnectError is a non-2xx response from the Connect server.
+// 401 and 403 mean the token is invalid, expired or lacks access to a vault.
+type ConnectError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ConnectError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("1Password Connect: %s (status %d)", msg, e.StatusCode)
+}
+
+// connectVault is a vault as returned by the Connect API.
+type connectVault struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// connectItem is an item as returned and accepted by the Connect API.
+// Sections and URLs are carried through untouched so a full-replacement PUT
+// keeps them.
+type connectItem struct {
+	ID        string          `json:"id,omitempty"`
+	Title     string          `json:"title"`
+	Vault     connectVaultRef `json:"vault"`
+	Category  string          `json:"category"`
+	Tags      []string        `json:"tags,omitempty"`
+	Favorite  bool            `json:"favorite,omitempty"`
+	Version   int             `json:"version,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt,omitzero"`
+	Sections  json.RawMessage `json:"sections,omitempty"`
+	URLs      json.RawMessage `json:"urls,omitempty"`
+	Fields    []connectField  `json:"fields,omitempty"`
+}
+
+type connectVaultRef struct {
+	ID string `json:"id"`
+}
+
+// connectField is an item field in the Connect API.
+type connectField struct {
+	ID      string             `json:"id,omitempty"`
+	Label   string             `json:"label,omitempty"`
+	Type    string             `json:"type,omitempty"`
+	Purpose string             `json:"purpose,omitempty"`
+	Value   string             `json:"value"`
+	Section *connectSectionRef `json:"section,omitempty"`
+}
+
+type connectSectionRef struct {
+	ID string `json:"id"`
+}
+
+// do sends a request to the Connect API and decodes a JSON response into out
+// (if non-nil).
+func (c *ConnectClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("1Password Connect request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return &ConnectError{StatusCode: resp.StatusCode, Message: apiErr.Message}
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// itemsPath returns the items endpoint of a vault, or of one item in it.
+func itemsPath(vaultID string, itemID ...string) string {
+	path := "/v1/vaults/" + url.PathEscape(vaultID) + "/items"
+	for _, id := range itemID {
+		path += "/" + url.PathEscape(id)
+	}
+	return path
+}
+
+// ListVaults retrieves the vaults the Connect token can access.
+func (c *ConnectClient) ListVaults(ctx context.Context) ([]Vault, error) {
+	var apiVaults []connectVault
+	if err := c.do(ctx, http.MethodGet, "/v1/vaults", nil, &apiVaults); err != nil {
+		return nil, fmt.Errorf("failed to list vaults: %w", err)
+	}
+
+	vaults := make([]Vault, 0, len(apiVaults))
+	for _, v := range apiVaults {
+		vaults = append(vaults, Vault{ID: v.ID, Name: v.Name})
+	}
+	return vaults, nil
+}
+
+// ListItems retrieves the items in a vault. Like "op item list", Connect only
+// returns item metadata here; fields need a GetItem call.
+func (c *ConnectClient) ListItems(ctx context.Context, vaultID string) ([]Item, error) {
+	var apiItems []connectItem
+	if err := c.do(ctx, http.MethodGet, itemsPath(vaultID), nil, &apiItems); err != nil {
+		return nil, fmt.Errorf("failed to list items in vault %s: %w", vaultID, err)
+	}
+
+	items := make([]Item, 0, len(apiItems))
+	for i := range apiItems {
+		items = append(items, *apiItems[i].toItem())
+	}
+	return items, nil
+}
+
+// GetItem retrieves a specific item with its fields.
+func (c *ConnectClient) GetItem(ctx context.Context, vaultID, itemID string) (*Item, error) {
+	apiItem, err := c.getItem(ctx, vaultID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return apiItem.toItem(), nil
+}
+
+// getItem fetches the raw Connect representation of an item.
+func (c *ConnectClient) getItem(ctx context.Context, vaultID, itemID string) (*connectItem, error) {
+	var apiItem connectItem
+	if err := c.do(ctx, http.MethodGet, itemsPath(vaultID, itemID), nil, &apiItem); err != nil {
+		return nil, fmt.Errorf("failed to get item %s from vault %s: %w", itemID, vaultID, err)
+	}
+	return &apiItem, nil
+}
+
+// CreateItem creates a new item in the item's vault.
+func (c *ConnectClient) CreateItem(ctx context.Context, item *Item) (*Item, error) {
+	if item == nil {
+		return nil, fmt.Errorf("item cannot be nil")
+	}
+
+	apiItem := connectItem{
+		Title:    item.Title,
+		Vault:    connectVaultRef{ID: item.VaultID},
+		Category: strings.ToUpper(item.Category),
+		Tags:     item.Tags,
+		Fields:   make([]connectField, 0, len(item.Fields)),
+	}
+	for _, f := range item.Fields {
+		apiItem.Fields = append(apiItem.Fields, newConnectField(f))
+	}
+
+	var created connectItem
+	if err := c.do(ctx, http.MethodPost, itemsPath(item.VaultID), apiItem, &created); err != nil {
+		return nil, fmt.Errorf("failed to create item: %w", err)
+	}
+	return created.toItem(), nil
+}
+
+// UpdateItem updates an existing item. Connect replaces the whole item, so
+// the current item is fetched first: fields are matched by ID or label and
+// updated in place, new fields are appended, and fields, sections and URLs
+// the update doesn't mention are kept, as with "op item edit".
+func (c *ConnectClient) UpdateItem(ctx context.Context, item *Item) (*Item, error) {
+	if item == nil {
+		return nil, fmt.Errorf("item cannot be nil")
+	}
+	if item.ID == "" {
+		return nil, fmt.Errorf("item ID is required for update")
+	}
+
+	current, err := c.getItem(ctx, item.VaultID, item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item %s: %w", item.ID, err)
+	}
+
+	if item.Title != "" {
+		current.Title = item.Title
+	}
+	if len(item.Tags) > 0 {
+		current.Tags = item.Tags
+	}
+	for _, f := range item.Fields {
+		if existing := current.field(f); existing != nil {
+			existing.Value = f.Value
+			continue
+		}
+		current.Fields = append(current.Fields, newConnectField(f))
+	}
+
+	var updated connectItem
+	if err := c.do(ctx, http.MethodPut, itemsPath(item.VaultID, item.ID), current, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update item %s: %w", item.ID, err)
+	}
+	return updated.toItem(), nil
+}
+
+// DeleteItem deletes an item from a vault.
+func (c *ConnectClient) DeleteItem(ctx context.Context, vaultID, itemID string) error {
+	if err := c.do(ctx, http.MethodDelete, itemsPath(vaultID, itemID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete item %s from vault %s: %w", itemID, vaultID, err)
+	}
+	return nil
+}
+
+// Close releases idle HTTP connections.
+func (c *ConnectClient) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// field returns the item's field matching f by ID, or by label when f has no
+// ID. Returns nil if there is none.
+func (i *connectItem) field(f ItemField) *connectField {
+	for j := range i.Fields {
+		existing := &i.Fields[j]
+		if f.ID != "" && existing.ID == f.ID {
+			return existing
+		}
+		if f.ID == "" && strings.EqualFold(existing.Label, f.Title) {
+			return existing
+		}
+	}
+	return nil
+}
+
+// toItem converts a Connect item to our Item structure.
+func (i *connectItem) toItem() *Item {
+	item := &Item{
+		ID:        i.ID,
+		Title:     i.Title,
+		VaultID:   i.Vault.ID,
+		Category:  strings.ToLower(i.Category), // Connect returns uppercase like "SERVER"
+		Tags:      i.Tags,
+		Fields:    make([]ItemField, 0, len(i.Fields)),
+		Version:   i.Version,
+		UpdatedAt: i.UpdatedAt,
+	}
+
+	var urls []itemURL
+	if len(i.URLs) > 0 && json.Unmarshal(i.URLs, &urls) == nil {
+		item.URLs = urlHrefs(urls)
+	}
+
+	for _, f := range i.Fields {
+		var sectionID *string
+		if f.Section != nil {
+			sectionID = &f.Section.ID
+		}
+		item.Fields = append(item.Fields, ItemField{
+			ID:        f.ID,
+			Title:     f.Label,
+			SectionID: sectionID,
+			Value:     f.Value,
+			FieldType: mapCLIFieldType(f.Type), // Connect uses the same type names as op
+		})
+	}
+	return item
+}
+
+// newConnectField converts one of our fields to the Connect representation.
+func newConnectField(f ItemField) connectField {
+	field := connectField{
+		ID:    f.ID,
+		Label: f.Title,
+		Type:  "STRING",
+		Value: f.Value,
+	}
+	if f.FieldType == "Concealed" {
+		field.Type = "CONCEALED"
+	}
+	if f.SectionID != nil {
+		field.Section = &connectSectionRef{ID: *f.SectionID}
+	}
+	return field
+}
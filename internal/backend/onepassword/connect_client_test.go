@@ -0,0 +1,264 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+const testConnectToken = "test-token"
+
+// fakeConnect is an in-memory stand-in for a 1Password Connect server.
+type fakeConnect struct {
+	mu     sync.Mutex
+	vaults []connectVault
+	items  map[string]*connectItem // keyed by item ID
+	nextID int
+	puts   []connectItem // bodies of PUT requests, in order
+}
+
+func newFakeConnect(t *testing.T) (*fakeConnect, *ConnectClient) {
+	t.Helper()
+	fake := &fakeConnect{
+		vaults: []connectVault{{ID: "v1", Name: "Infra"}},
+		items:  make(map[string]*connectItem),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewConnectClient(server.URL, testConnectToken)
+	require.NoError(t, err)
+	return fake, client
+}
+
+func (f *fakeConnect) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+testConnectToken {
+		writeConnectJSON(w, http.StatusUnauthorized, map[string]any{"status": 401, "message": "Invalid token signature"})
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/vaults":
+		writeConnectJSON(w, http.StatusOK, f.vaults)
+
+	case len(parts) == 4 && parts[3] == "items" && r.Method == http.MethodGet:
+		// List omits fields, like the real server
+		list := []connectItem{}
+		for _, item := range f.items {
+			if item.Vault.ID == parts[2] {
+				summary := *item
+				summary.Fields = nil
+				list = append(list, summary)
+			}
+		}
+		writeConnectJSON(w, http.StatusOK, list)
+
+	case len(parts) == 4 && parts[3] == "items" && r.Method == http.MethodPost:
+		var item connectItem
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			writeConnectJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+			return
+		}
+		f.nextID++
+		item.ID = "item" + string(rune('0'+f.nextID))
+		item.Version = 1
+		item.UpdatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := range item.Fields {
+			if item.Fields[i].ID == "" {
+				item.Fields[i].ID = "f" + string(rune('0'+i))
+			}
+		}
+		f.items[item.ID] = &item
+		writeConnectJSON(w, http.StatusOK, item)
+
+	case len(parts) == 5 && parts[3] == "items":
+		item, ok := f.items[parts[4]]
+		if !ok || item.Vault.ID != parts[2] {
+			writeConnectJSON(w, http.StatusNotFound, map[string]any{"status": 404, "message": "item not found"})
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeConnectJSON(w, http.StatusOK, item)
+		case http.MethodPut:
+			var updated connectItem
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				writeConnectJSON(w, http.StatusBadRequest, map[string]any{"message": err.Error()})
+				return
+			}
+			f.puts = append(f.puts, updated)
+			updated.Version = item.Version + 1
+			f.items[item.ID] = &updated
+			writeConnectJSON(w, http.StatusOK, updated)
+		case http.MethodDelete:
+			delete(f.items, item.ID)
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+	default:
+		writeConnectJSON(w, http.StatusNotFound, map[string]any{"message": "no route"})
+	}
+}
+
+func writeConnectJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNewConnectClient(t *testing.T) {
+	t.Setenv(ConnectHostEnv, "")
+	t.Setenv(ConnectTokenEnv, "")
+
+	_, err := NewConnectClient("", "token")
+	assert.ErrorContains(t, err, ConnectHostEnv)
+
+	_, err = NewConnectClient("http://connect:8080", "")
+	assert.ErrorContains(t, err, ConnectTokenEnv)
+
+	_, err = NewConnectClient("connect:8080", "token")
+	assert.ErrorContains(t, err, "invalid 1Password Connect URL")
+
+	t.Setenv(ConnectHostEnv, "http://connect:8080/")
+	t.Setenv(ConnectTokenEnv, "env-token")
+	client, err := NewConnectClient("", "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://connect:8080", client.baseURL)
+	assert.Equal(t, "env-token", client.token)
+
+	client, err = NewConnectClient("https://op.example.com", "config-token")
+	require.NoError(t, err)
+	assert.Equal(t, "https://op.example.com", client.baseURL, "configured values win over the environment")
+	assert.Equal(t, "config-token", client.token)
+}
+
+func TestConnectClient_ItemLifecycle(t *testing.T) {
+	ctx := context.Background()
+	_, client := newFakeConnect(t)
+
+	vaults, err := client.ListVaults(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Vault{{ID: "v1", Name: "Infra"}}, vaults)
+
+	server := &domain.Server{DisplayName: "web", Host: "web.example.com", User: "deploy", Port: 22}
+	created, err := client.CreateItem(ctx, ServerToItem(server, "v1"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "server", created.Category)
+	assert.Equal(t, "v1", created.VaultID)
+	assert.Equal(t, 1, created.Version)
+
+	listed, err := client.ListItems(ctx, "v1")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "web", listed[0].Title)
+	assert.Contains(t, listed[0].Tags, "ssherpa")
+	assert.Empty(t, listed[0].Fields)
+	assert.False(t, listed[0].UpdatedAt.IsZero())
+
+	item, err := client.GetItem(ctx, "v1", created.ID)
+	require.NoError(t, err)
+	got, err := ItemToServer(item)
+	require.NoError(t, err)
+	assert.Equal(t, "web.example.com", got.Host)
+	assert.Equal(t, "deploy", got.User)
+
+	require.NoError(t, client.DeleteItem(ctx, "v1", created.ID))
+	_, err = client.GetItem(ctx, "v1", created.ID)
+	var connectErr *ConnectError
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, http.StatusNotFound, connectErr.StatusCode)
+}
+
+func TestConnectClient_UpdateKeepsUnknownFields(t *testing.T) {
+	ctx := context.Background()
+	fake, client := newFakeConnect(t)
+	fake.items["item1"] = &connectItem{
+		ID:       "item1",
+		Title:    "web",
+		Vault:    connectVaultRef{ID: "v1"},
+		Category: "SERVER",
+		Tags:     []string{"ssherpa"},
+		Sections: json.RawMessage(`[{"id":"s1","label":"Admin"}]`),
+		Fields: []connectField{
+			{ID: "hostname", Label: "hostname", Type: "STRING", Value: "old.example.com"},
+			{ID: "admin", Label: "admin console", Type: "CONCEALED", Value: "secret", Section: &connectSectionRef{ID: "s1"}},
+		},
+	}
+
+	updated, err := client.UpdateItem(ctx, &Item{
+		ID:      "item1",
+		VaultID: "v1",
+		Title:   "web-1",
+		Tags:    []string{"ssherpa", "prod"},
+		Fields: []ItemField{
+			{Title: "hostname", Value: "new.example.com", FieldType: "Text"},
+			{Title: "user", Value: "deploy", FieldType: "Text"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "web-1", updated.Title)
+	assert.Equal(t, []string{"ssherpa", "prod"}, updated.Tags)
+	assert.Equal(t, 1, updated.Version)
+
+	require.Len(t, fake.puts, 1)
+	put := fake.puts[0]
+	assert.JSONEq(t, `[{"id":"s1","label":"Admin"}]`, string(put.Sections))
+	require.Len(t, put.Fields, 3)
+	assert.Equal(t, connectField{ID: "hostname", Label: "hostname", Type: "STRING", Value: "new.example.com"}, put.Fields[0])
+	assert.Equal(t, "secret", put.Fields[1].Value)
+	assert.Equal(t, "s1", put.Fields[1].Section.ID)
+	assert.Equal(t, connectField{Label: "user", Type: "STRING", Value: "deploy"}, put.Fields[2])
+}
+
+func TestConnectClient_Unauthorized(t *testing.T) {
+	fake, _ := newFakeConnect(t)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewConnectClient(server.URL, "wrong-token")
+	require.NoError(t, err)
+
+	_, err = client.ListVaults(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid token signature")
+
+	// The backend maps the error to the token status
+	backend := New(client)
+	require.Error(t, backend.SyncFromOnePassword(context.Background()))
+	assert.Equal(t, backendpkg.StatusTokenError, backend.GetStatus())
+}
+
+func TestConnectClient_Sync(t *testing.T) {
+	ctx := context.Background()
+	_, client := newFakeConnect(t)
+
+	server := &domain.Server{DisplayName: "web", Host: "web.example.com", User: "deploy", Port: 2222}
+	_, err := client.CreateItem(ctx, ServerToItem(server, "v1"))
+	require.NoError(t, err)
+
+	backend := New(client)
+	require.NoError(t, backend.SyncFromOnePassword(ctx))
+
+	servers, err := backend.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "web.example.com", servers[0].Host)
+	assert.Equal(t, 2222, servers[0].Port)
+	assert.Equal(t, "v1", servers[0].VaultID)
+}
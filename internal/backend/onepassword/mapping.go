@@ -4,10 +4,35 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
 
 	"github.com/florianriquelme/ssherpa/internal/domain"
 )
 
+// managedFields lists the item fields ServerToItem writes.
+// UpdateServer clears any of these that the updated server no longer sets;
+// all other fields on the item (notes, passwords, ...) are left untouched.
+var managedFields = map[string]bool{
+	"hostname":            true,
+	"user":                true,
+	"port":                true,
+	"identity_file":       true,
+	"remote_project_path": true,
+	"project_tags":        true,
+	"proxy_jump":          true,
+	"vpn_required":        true,
+	"favorite":            true,
+	"forward_agent":       true,
+	"extra_config":        true,
+	"ssh_key":             true,
+}
+
+// sshKeyCategory is the category of 1Password's native "SSH Key" items
+// (lowercased, as the clients normalize it).
+const sshKeyCategory = "ssh_key"
+
 // ItemToServer converts a 1Password item to a domain.Server.
 // Returns error if required fields (hostname, user) are missing.
 func ItemToServer(item *Item) (*domain.Server, error) {
@@ -17,6 +42,15 @@ func ItemToServer(item *Item) (*domain.Server, error) {
 		VaultID:     item.VaultID,
 		Port:        22, // default port
 		Source:      "1password",
+		Tags:        []string{},
+		Revision:    itemRevision(item),
+	}
+
+	// Item tags become server tags; the "ssherpa" marker tag is implied
+	for _, tag := range item.Tags {
+		if !strings.EqualFold(tag, "ssherpa") {
+			server.Tags = append(server.Tags, tag)
+		}
 	}
 
 	// Extract fields by title (case-insensitive)
@@ -51,10 +85,26 @@ func ItemToServer(item *Item) (*domain.Server, error) {
 			}
 		case "proxy_jump":
 			server.Proxy = value
+		case "vpn_required":
+			server.VPNRequired = parseBoolField(value)
+		case "favorite":
+			server.Favorite = parseBoolField(value)
+		case "ssh_key":
+			server.CredentialID = strings.TrimSpace(value)
 		case "forward_agent":
-			// Store as note or ignore (not a direct Server field)
+			// ForwardAgent also takes a socket path or an environment
+			// variable such as $SSH_AUTH_SOCK; those pass through unchanged
+			if value := strings.TrimSpace(value); isBoolField(value) {
+				setSSHOption(server, "ForwardAgent", formatBoolField(parseBoolField(value)))
+			} else if value != "" {
+				setSSHOption(server, "ForwardAgent", value)
+			}
 		case "extra_config":
-			// Store as note or ignore (not a direct Server field)
+			for _, line := range strings.Split(value, "\n") {
+				if key, val, ok := parseSSHOption(line); ok {
+					setSSHOption(server, key, val)
+				}
+			}
 		}
 	}
 
@@ -149,9 +199,194 @@ func ServerToItem(server *domain.Server, vaultID string) *Item {
 		})
 	}
 
+	if server.VPNRequired {
+		item.Fields = append(item.Fields, ItemField{
+			Title:     "vpn_required",
+			Value:     "true",
+			FieldType: "Text",
+		})
+	}
+
+	if server.Favorite {
+		item.Fields = append(item.Fields, ItemField{
+			Title:     "favorite",
+			Value:     "true",
+			FieldType: "Text",
+		})
+	}
+
+	if server.CredentialID != "" {
+		item.Fields = append(item.Fields, ItemField{
+			Title:     "ssh_key",
+			Value:     server.CredentialID,
+			FieldType: "Text",
+		})
+	}
+
+	// ForwardAgent has its own field; every other option goes to extra_config
+	var extra []string
+	forwardAgent := false
+	for _, line := range server.SSHOptionLines() {
+		key, value, _ := parseSSHOption(line)
+		lower := strings.ToLower(value)
+		if !forwardAgent && strings.EqualFold(key, "ForwardAgent") && (lower == "yes" || lower == "no") {
+			forwardAgent = true
+			item.Fields = append(item.Fields, ItemField{
+				Title:     "forward_agent",
+				Value:     lower,
+				FieldType: "Text",
+			})
+			continue
+		}
+		extra = append(extra, line)
+	}
+
+	if len(extra) > 0 {
+		item.Fields = append(item.Fields, ItemField{
+			Title:     "extra_config",
+			Value:     strings.Join(extra, "\n"),
+			FieldType: "Text",
+		})
+	}
+
 	return item
 }
 
+// parseSSHOption splits an ssh_config line in "Key value" or "Key=value" form.
+// Blank lines and comments report ok == false.
+func parseSSHOption(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return "", "", false
+	}
+	key = line[:end]
+	value = strings.TrimSpace(line[end:])
+	value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+	if value == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// setSSHOption appends value to the server's SSH options under key, reusing
+// the spelling of a keyword that is already present.
+func setSSHOption(server *domain.Server, key, value string) {
+	if server.SSHOptions == nil {
+		server.SSHOptions = make(map[string][]string)
+	}
+	for existing := range server.SSHOptions {
+		if strings.EqualFold(existing, key) {
+			key = existing
+			break
+		}
+	}
+	server.SSHOptions[key] = append(server.SSHOptions[key], value)
+}
+
+// ItemToCredential converts a 1Password "SSH Key" item to a credential for
+// use with the 1Password SSH agent. Only the public key and fingerprint are
+// kept; the private key field is never read. Returns an error if the item is
+// not an SSH Key item or has no valid public key.
+func ItemToCredential(item *Item) (*domain.Credential, error) {
+	if item.Category != sshKeyCategory {
+		return nil, fmt.Errorf("item %q (id: %s) is not an SSH Key item", item.Title, item.ID)
+	}
+
+	cred := &domain.Credential{
+		ID:   item.ID,
+		Name: item.Title,
+		Type: domain.CredentialSSHAgent,
+	}
+	for _, field := range item.Fields {
+		switch {
+		case field.ID == "public_key" || strings.EqualFold(field.Title, "public key"):
+			cred.PublicKey = strings.TrimSpace(field.Value)
+		case field.ID == "fingerprint" || strings.EqualFold(field.Title, "fingerprint"):
+			cred.Fingerprint = strings.TrimSpace(field.Value)
+		}
+	}
+
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cred.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("item %q (id: %s) has no valid public key: %w", item.Title, item.ID, err)
+	}
+	if cred.Fingerprint == "" {
+		cred.Fingerprint = ssh.FingerprintSHA256(pubKey)
+	}
+	return cred, nil
+}
+
+// mergeItemFields combines the fields of an existing item with the fields
+// generated from a server. Managed fields the server no longer sets are sent
+// with an empty value so "op item edit" clears them; unmanaged fields are kept.
+func mergeItemFields(existing, generated []ItemField) []ItemField {
+	present := make(map[string]bool, len(generated))
+	for _, f := range generated {
+		present[strings.ToLower(f.Title)] = true
+	}
+
+	merged := append([]ItemField{}, generated...)
+	for _, f := range existing {
+		title := strings.ToLower(f.Title)
+		if present[title] {
+			continue
+		}
+		if managedFields[title] {
+			if f.Value != "" {
+				merged = append(merged, ItemField{Title: f.Title, Value: "", FieldType: f.FieldType})
+			}
+			continue
+		}
+		merged = append(merged, f)
+	}
+	return merged
+}
+
+// itemRevision identifies an item's state for incremental sync: it changes
+// whenever 1Password bumps the version or the updated_at timestamp.
+// Empty when the client reported neither, so the item is always re-fetched.
+func itemRevision(item *Item) string {
+	if item.Version == 0 && item.UpdatedAt.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d@%s", item.Version, item.UpdatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// parseBoolField interprets a yes/no style text field.
+// 1Password has no boolean field type, so users type "true", "yes" or "1".
+func parseBoolField(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// isBoolField reports whether value is one of the yes/no style spellings
+// parseBoolField understands.
+func isBoolField(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "on", "false", "no", "n", "0", "off":
+		return true
+	default:
+		return false
+	}
+}
+
+// formatBoolField renders a boolean as the yes/no form ssh_config uses.
+func formatBoolField(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
 // HasSshjesusTag checks if the tags slice contains "ssherpa" (case-insensitive).
 func HasSshjesusTag(tags []string) bool {
 	for _, tag := range tags {
@@ -0,0 +1,246 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migratableCategories are the item categories the migration offers: servers
+// and logins are where SSH hosts usually end up before ssherpa.
+var migratableCategories = map[string]bool{
+	"server": true,
+	"login":  true,
+}
+
+// MigrationCandidate is an untagged Server or Login item together with the
+// ssherpa fields guessed from it. Fields lists what a migration adds; fields
+// the item already has are never overwritten.
+type MigrationCandidate struct {
+	Item      Item   // item as fetched, with fields
+	VaultName string // vault the item lives in
+	Hostname  string // guessed hostname (empty if none found)
+	User      string // guessed SSH user (empty if none found)
+	Port      int    // guessed port (0 = ssh default)
+
+	// HostnameFrom and UserFrom describe where the guesses came from,
+	// e.g. `field "URL"`, for the preview.
+	HostnameFrom string
+	UserFrom     string
+
+	Fields []ItemField // fields the migration adds
+}
+
+// Complete reports whether the candidate has the fields ItemToServer requires.
+func (c MigrationCandidate) Complete() bool {
+	return c.Hostname != "" && c.User != ""
+}
+
+// MigrationResult is the outcome of migrating one item.
+type MigrationResult struct {
+	Title     string
+	VaultName string
+	Err       error // nil on success
+}
+
+// FindMigrationCandidates scans all vaults for Server and Login items that
+// aren't tagged "ssherpa" and guesses their ssherpa fields. Vaults that fail
+// to list and items that fail to fetch are left out. Candidates are sorted by
+// vault and title.
+func FindMigrationCandidates(ctx context.Context, client Client) ([]MigrationCandidate, error) {
+	vaults, err := client.ListVaults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list vaults: %w", err)
+	}
+
+	var candidates []MigrationCandidate
+	for _, vault := range vaults {
+		items, err := client.ListItems(ctx, vault.ID)
+		if err != nil {
+			continue
+		}
+		for _, listed := range items {
+			if !migratableCategories[listed.Category] || HasSshjesusTag(listed.Tags) {
+				continue
+			}
+			item, err := client.GetItem(ctx, vault.ID, listed.ID)
+			if err != nil {
+				continue
+			}
+			item.VaultID = vault.ID
+			candidates = append(candidates, NewMigrationCandidate(item, vault.Name))
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].VaultName != candidates[j].VaultName {
+			return candidates[i].VaultName < candidates[j].VaultName
+		}
+		return strings.ToLower(candidates[i].Item.Title) < strings.ToLower(candidates[j].Item.Title)
+	})
+	return candidates, nil
+}
+
+// NewMigrationCandidate guesses the ssherpa fields of an item.
+// Existing hostname, user and port fields win; otherwise the hostname, port
+// and user come from a URL (URL field or website), a host-like field
+// ("host", "server", "ip address") or the username field, in that order.
+func NewMigrationCandidate(item *Item, vaultName string) MigrationCandidate {
+	c := MigrationCandidate{Item: *item, VaultName: vaultName}
+
+	var hasHostname, hasUser, hasPort bool
+	for _, field := range item.Fields {
+		value := strings.TrimSpace(field.Value)
+		switch strings.ToLower(field.Title) {
+		case "hostname":
+			hasHostname = value != ""
+			c.Hostname, c.HostnameFrom = value, fieldSource(field)
+		case "user":
+			hasUser = value != ""
+			c.User, c.UserFrom = value, fieldSource(field)
+		case "port":
+			hasPort = value != ""
+			c.Port, _ = strconv.Atoi(value)
+		}
+	}
+
+	// Candidate addresses, most specific first
+	type address struct{ value, from string }
+	var addresses []address
+	for _, field := range item.Fields {
+		if field.ID == "url" || strings.EqualFold(field.Title, "url") || strings.EqualFold(field.Title, "website") {
+			addresses = append(addresses, address{field.Value, fieldSource(field)})
+		}
+	}
+	for _, u := range item.URLs {
+		addresses = append(addresses, address{u, "website"})
+	}
+	for _, field := range item.Fields {
+		switch strings.ToLower(field.Title) {
+		case "host", "server", "ip", "ip address", "address":
+			addresses = append(addresses, address{field.Value, fieldSource(field)})
+		}
+	}
+
+	for _, addr := range addresses {
+		host, user, port := parseAddress(addr.value)
+		if host == "" {
+			continue
+		}
+		if !hasHostname && c.Hostname == "" {
+			c.Hostname, c.HostnameFrom = host, addr.from
+		}
+		if !hasUser && c.User == "" && user != "" {
+			c.User, c.UserFrom = user, addr.from
+		}
+		if !hasPort && c.Port == 0 && port != 0 {
+			c.Port = port
+		}
+	}
+
+	if !hasUser && c.User == "" {
+		for _, field := range item.Fields {
+			if field.ID == "username" || strings.EqualFold(field.Title, "username") {
+				if value := strings.TrimSpace(field.Value); value != "" {
+					c.User, c.UserFrom = value, fieldSource(field)
+					break
+				}
+			}
+		}
+	}
+
+	if !hasHostname && c.Hostname != "" {
+		c.Fields = append(c.Fields, ItemField{Title: "hostname", Value: c.Hostname, FieldType: "Text"})
+	}
+	if !hasUser && c.User != "" {
+		c.Fields = append(c.Fields, ItemField{Title: "user", Value: c.User, FieldType: "Text"})
+	}
+	if !hasPort && c.Port != 0 && c.Port != 22 {
+		c.Fields = append(c.Fields, ItemField{Title: "port", Value: strconv.Itoa(c.Port), FieldType: "Text"})
+	}
+	return c
+}
+
+// MigrateItem tags a candidate "ssherpa" and adds its missing fields in place.
+// Incomplete candidates are refused, since the sync would skip them.
+func MigrateItem(ctx context.Context, client Client, c MigrationCandidate) error {
+	switch {
+	case c.Hostname == "":
+		return fmt.Errorf("no hostname found")
+	case c.User == "":
+		return fmt.Errorf("no user found")
+	}
+
+	tags := append([]string{}, c.Item.Tags...)
+	tags = append(tags, "ssherpa")
+
+	_, err := client.UpdateItem(ctx, &Item{
+		ID:      c.Item.ID,
+		VaultID: c.Item.VaultID,
+		Tags:    tags,
+		Fields:  c.Fields,
+	})
+	return err
+}
+
+// MigrateItems migrates each candidate and reports the outcome per item.
+// A failure doesn't stop the remaining items.
+func MigrateItems(ctx context.Context, client Client, candidates []MigrationCandidate) []MigrationResult {
+	results := make([]MigrationResult, 0, len(candidates))
+	for _, c := range candidates {
+		results = append(results, MigrationResult{
+			Title:     c.Item.Title,
+			VaultName: c.VaultName,
+			Err:       MigrateItem(ctx, client, c),
+		})
+	}
+	return results
+}
+
+// parseAddress extracts host, user and port from a URL ("ssh://deploy@web:2222"),
+// a "user@host:port" string or a bare host. Ports are only taken from ssh and
+// sftp URLs. Returns an empty host if value
+// doesn't look like an address.
+func parseAddress(value string) (host, user string, port int) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, " \t") {
+		return "", "", 0
+	}
+
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return "", "", 0
+		}
+		if u.User != nil {
+			user = u.User.Username()
+		}
+		if u.Scheme == "ssh" || u.Scheme == "sftp" {
+			port, _ = strconv.Atoi(u.Port()) // a web URL's port isn't the SSH port
+		}
+		return u.Hostname(), user, port
+	}
+
+	if at := strings.LastIndex(value, "@"); at >= 0 {
+		user, value = value[:at], value[at+1:]
+	}
+	value, _, _ = strings.Cut(value, "/")
+	if h, p, err := net.SplitHostPort(value); err == nil {
+		port, _ = strconv.Atoi(p)
+		value = h
+	}
+	return value, user, port
+}
+
+// fieldSource describes a field for the migration preview.
+func fieldSource(field ItemField) string {
+	label := field.Title
+	if label == "" {
+		label = field.ID
+	}
+	return fmt.Sprintf("field %q", label)
+}
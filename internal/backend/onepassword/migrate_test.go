@@ -0,0 +1,168 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMigrationMock() *MockClient {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "v1", Name: "Infra"})
+	mock.AddVault(Vault{ID: "v2", Name: "Personal"})
+
+	// Server item with the built-in URL and username fields
+	mock.AddItem(Item{
+		ID: "srv", Title: "Web", VaultID: "v1", Category: "server",
+		Fields: []ItemField{
+			{ID: "url", Title: "URL", Value: "ssh://web.example.com:2222"},
+			{ID: "username", Title: "username", Value: "deploy"},
+			{ID: "password", Title: "password", Value: "secret", FieldType: "Concealed"},
+		},
+	})
+	// Login item whose website carries the user
+	mock.AddItem(Item{
+		ID: "login", Title: "Bastion", VaultID: "v2", Category: "login", Tags: []string{"jump"},
+		URLs: []string{"ssh://ops@bastion.example.com"},
+	})
+	// Login item without any address
+	mock.AddItem(Item{
+		ID: "mail", Title: "Mail", VaultID: "v2", Category: "login",
+		Fields: []ItemField{{ID: "username", Title: "username", Value: "me@example.com"}},
+	})
+	// Already managed, and not a server
+	mock.AddItem(Item{
+		ID: "managed", Title: "DB", VaultID: "v1", Category: "server", Tags: []string{"ssherpa"},
+		Fields: []ItemField{{Title: "hostname", Value: "db"}, {Title: "user", Value: "pg"}},
+	})
+	mock.AddItem(Item{ID: "note", Title: "Runbook", VaultID: "v1", Category: "secure_note"})
+	return mock
+}
+
+func TestFindMigrationCandidates(t *testing.T) {
+	candidates, err := FindMigrationCandidates(context.Background(), newMigrationMock())
+	require.NoError(t, err)
+	require.Len(t, candidates, 3)
+
+	web := candidates[0]
+	assert.Equal(t, "Web", web.Item.Title)
+	assert.Equal(t, "Infra", web.VaultName)
+	assert.Equal(t, "web.example.com", web.Hostname)
+	assert.Equal(t, `field "URL"`, web.HostnameFrom)
+	assert.Equal(t, "deploy", web.User)
+	assert.Equal(t, `field "username"`, web.UserFrom)
+	assert.Equal(t, 2222, web.Port)
+	assert.True(t, web.Complete())
+	assert.Equal(t, []ItemField{
+		{Title: "hostname", Value: "web.example.com", FieldType: "Text"},
+		{Title: "user", Value: "deploy", FieldType: "Text"},
+		{Title: "port", Value: "2222", FieldType: "Text"},
+	}, web.Fields)
+
+	bastion := candidates[1]
+	assert.Equal(t, "Bastion", bastion.Item.Title)
+	assert.Equal(t, "bastion.example.com", bastion.Hostname)
+	assert.Equal(t, "ops", bastion.User)
+	assert.Equal(t, "website", bastion.UserFrom)
+	assert.True(t, bastion.Complete())
+
+	mail := candidates[2]
+	assert.Equal(t, "Mail", mail.Item.Title)
+	assert.Empty(t, mail.Hostname)
+	assert.False(t, mail.Complete())
+}
+
+func TestNewMigrationCandidate_KeepsExistingFields(t *testing.T) {
+	c := NewMigrationCandidate(&Item{
+		ID: "x", Title: "API", Category: "server",
+		Fields: []ItemField{
+			{Title: "hostname", Value: "api.internal"},
+			{ID: "url", Title: "URL", Value: "https://api.example.com:8443"},
+			{ID: "username", Title: "username", Value: "admin"},
+		},
+	}, "Infra")
+
+	assert.Equal(t, "api.internal", c.Hostname, "an existing hostname field wins over the URL")
+	assert.Equal(t, "admin", c.User)
+	assert.Zero(t, c.Port, "web URL ports are not SSH ports")
+	assert.Equal(t, []ItemField{{Title: "user", Value: "admin", FieldType: "Text"}}, c.Fields)
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		value, host, user string
+		port              int
+	}{
+		{"ssh://deploy@web.example.com:2222", "web.example.com", "deploy", 2222},
+		{"https://admin.example.com/login", "admin.example.com", "", 0},
+		{"root@10.0.0.5", "10.0.0.5", "root", 0},
+		{"db.internal:2200", "db.internal", "", 2200},
+		{"web.example.com", "web.example.com", "", 0},
+		{"not an address", "", "", 0},
+		{"", "", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			host, user, port := parseAddress(tt.value)
+			assert.Equal(t, tt.host, host)
+			assert.Equal(t, tt.user, user)
+			assert.Equal(t, tt.port, port)
+		})
+	}
+}
+
+func TestMigrateItems(t *testing.T) {
+	ctx := context.Background()
+	mock := newMigrationMock()
+	candidates, err := FindMigrationCandidates(ctx, mock)
+	require.NoError(t, err)
+
+	results := MigrateItems(ctx, mock, candidates)
+	require.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.EqualError(t, results[2].Err, "no hostname found")
+	assert.Equal(t, "Mail", results[2].Title)
+	assert.Equal(t, "Personal", results[2].VaultName)
+
+	// Migrated items are tagged, keep their tags and sync as servers
+	item, err := mock.GetItem(ctx, "v2", "login")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jump", "ssherpa"}, item.Tags)
+	server, err := ItemToServer(item)
+	require.NoError(t, err)
+	assert.Equal(t, "bastion.example.com", server.Host)
+	assert.Equal(t, "ops", server.User)
+	assert.Equal(t, []string{"jump"}, server.Tags)
+
+	// Nothing left to migrate except the incomplete item
+	candidates, err = FindMigrationCandidates(ctx, mock)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "Mail", candidates[0].Item.Title)
+}
+
+func TestMigrateItems_ReportsFailures(t *testing.T) {
+	ctx := context.Background()
+	mock := newMigrationMock()
+	candidates, err := FindMigrationCandidates(ctx, mock)
+	require.NoError(t, err)
+
+	mock.SetError("UpdateItem", fmt.Errorf("item is read-only"))
+	results := MigrateItems(ctx, mock, candidates[:2])
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.ErrorContains(t, r.Err, "read-only")
+	}
+}
+
+func TestFindMigrationCandidates_ListVaultsError(t *testing.T) {
+	mock := NewMockClient()
+	mock.SetError("ListVaults", fmt.Errorf("not signed in"))
+
+	_, err := FindMigrationCandidates(context.Background(), mock)
+	assert.ErrorContains(t, err, "not signed in")
+}
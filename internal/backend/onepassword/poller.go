@@ -2,105 +2,64 @@ package onepassword
 
 import (
 	"context"
-	"os"
 	"sync"
 	"time"
 
 	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
 )
 
+// pollIntervalEnv overrides the default poll interval (e.g. "30s").
+const pollIntervalEnv = "SSHJESUS_1PASSWORD_POLL_INTERVAL"
+
 // Poller periodically checks 1Password availability and auto-recovers when it becomes available.
+// It wraps the shared backendpkg.Poller and records the stats of each sync.
 type Poller struct {
-	backend  *Backend
-	interval time.Duration
-	ticker   *time.Ticker
-	stopCh   chan struct{}
-	wg       sync.WaitGroup
-	onChange func(backendpkg.BackendStatus) // Callback when status changes (for TUI notification)
+	*backendpkg.Poller
+
+	statsMu sync.Mutex
+	stats   PollStats
+}
+
+// PollStats reports the poller's sync activity.
+type PollStats struct {
+	Syncs    int       // syncs run (polls skipped after a recent write don't count)
+	LastSync SyncStats // duration and item counts of the most recent sync
+	LastErr  error     // error of the most recent sync, nil on success
 }
 
 // NewPoller creates a new poller for the backend.
 // interval: how often to poll (use 0 to read from SSHJESUS_1PASSWORD_POLL_INTERVAL env var, defaults to 5m)
 // onChange: optional callback invoked when status changes (nil = no callback)
 func NewPoller(backend *Backend, interval time.Duration, onChange func(backendpkg.BackendStatus)) *Poller {
-	// Check environment variable for interval override
-	if interval == 0 {
-		interval = 5 * time.Minute // default - poll every 5 minutes
-		if envInterval := os.Getenv("SSHJESUS_1PASSWORD_POLL_INTERVAL"); envInterval != "" {
-			if parsed, err := time.ParseDuration(envInterval); err == nil {
-				interval = parsed
-			}
-		}
-	}
-
-	return &Poller{
-		backend:  backend,
-		interval: interval,
-		stopCh:   make(chan struct{}),
-		onChange: onChange,
-	}
-}
-
-// Start begins polling in a background goroutine.
-// Returns immediately - use Stop() to halt polling.
-func (p *Poller) Start() {
-	p.ticker = time.NewTicker(p.interval)
-	p.wg.Add(1)
-
-	go func() {
-		defer p.wg.Done()
-		defer p.ticker.Stop()
-
-		// Run first poll immediately (don't wait for first tick)
-		p.poll()
-
-		for {
-			select {
-			case <-p.stopCh:
-				return
-			case <-p.ticker.C:
-				p.poll()
-			}
-		}
-	}()
+	p := &Poller{}
+	p.Poller = backendpkg.NewPoller(
+		backendpkg.PollInterval(interval, pollIntervalEnv),
+		func(ctx context.Context) error {
+			stats, err := backend.syncItems(ctx)
+			p.statsMu.Lock()
+			p.stats.Syncs++
+			p.stats.LastSync = stats
+			p.stats.LastErr = err
+			p.statsMu.Unlock()
+			return err
+		},
+		backend.GetStatus,
+		backend.lastWriteTime,
+		onChange,
+	)
+	return p
 }
 
 // poll performs a single poll operation.
 func (p *Poller) poll() {
-	// Check if there was a recent write (within last 10 seconds)
-	p.backend.mu.RLock()
-	lastWrite := p.backend.lastWrite
-	p.backend.mu.RUnlock()
-
-	if !lastWrite.IsZero() && time.Since(lastWrite) < 10*time.Second {
-		// Skip sync - too soon after write
-		return
-	}
-
-	// Get current status before sync
-	oldStatus := p.backend.GetStatus()
-
-	// Attempt sync with timeout (30s allows time for biometric auth)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	_ = p.backend.SyncFromOnePassword(ctx)
-	// Error is OK - status will be set appropriately by SyncFromOnePassword
-
-	// Check if status changed
-	newStatus := p.backend.GetStatus()
-	if newStatus != oldStatus {
-		// Status changed - notify if callback provided
-		if p.onChange != nil {
-			p.onChange(newStatus)
-		}
-	}
+	p.Poll()
 }
 
-// Stop halts the poller and waits for the goroutine to exit.
-func (p *Poller) Stop() {
-	close(p.stopCh)
-	p.wg.Wait()
+// Stats returns the poller's sync metrics (thread-safe).
+func (p *Poller) Stats() PollStats {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	return p.stats
 }
 
 // StartPolling starts a background poller for this backend.
@@ -119,6 +78,19 @@ func (b *Backend) StartPolling(interval time.Duration, onChange func(backendpkg.
 	b.poller.Start()
 }
 
+// PollStats returns the metrics of the running poller, or zero stats when
+// polling hasn't been started.
+func (b *Backend) PollStats() PollStats {
+	b.mu.RLock()
+	poller := b.poller
+	b.mu.RUnlock()
+
+	if poller == nil {
+		return PollStats{}
+	}
+	return poller.Stats()
+}
+
 // UpdateLastWrite updates the last write timestamp to prevent sync loops.
 // This should be called after CreateServer, UpdateServer, or DeleteServer.
 func (b *Backend) UpdateLastWrite() {
@@ -126,3 +98,10 @@ func (b *Backend) UpdateLastWrite() {
 	defer b.mu.Unlock()
 	b.lastWrite = time.Now()
 }
+
+// lastWriteTime returns the last write timestamp (thread-safe).
+func (b *Backend) lastWriteTime() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastWrite
+}
@@ -270,3 +270,34 @@ func TestPoller_NilOnChangeIsOK(t *testing.T) {
 	err := backend.Close()
 	require.NoError(t, err)
 }
+
+func TestPoller_RecordsSyncStats(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "vault-1", Name: "Test Vault"})
+	addVersionedItems(mock, "vault-1", 2)
+
+	backend := New(mock)
+	assert.Equal(t, PollStats{}, backend.PollStats(), "no poller started yet")
+
+	poller := NewPoller(backend, time.Hour, nil)
+	poller.poll()
+	first := poller.Stats()
+	assert.Equal(t, 1, first.Syncs)
+	assert.NoError(t, first.LastErr)
+	assert.Equal(t, 2, first.LastSync.Fetched)
+	assert.Positive(t, first.LastSync.Duration)
+
+	// Second poll finds nothing changed
+	poller.poll()
+	second := poller.Stats()
+	assert.Equal(t, 2, second.Syncs)
+	assert.Equal(t, 2, second.LastSync.Items)
+	assert.Equal(t, 0, second.LastSync.Fetched)
+	assert.Equal(t, 2, second.LastSync.Reused)
+
+	// Failed syncs are recorded too
+	mock.SetError("ListVaults", assert.AnError)
+	poller.poll()
+	assert.Error(t, poller.Stats().LastErr)
+	assert.Equal(t, 3, poller.Stats().Syncs)
+}
@@ -3,8 +3,12 @@ package onepassword
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"sort"
 	"strings"
+	gosync "sync"
+	"time"
 
 	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
 	"github.com/florianriquelme/ssherpa/internal/domain"
@@ -35,77 +39,198 @@ func (b *Backend) SyncFromBackend(ctx context.Context) error {
 // SyncFromOnePassword attempts to sync servers from 1Password.
 // On success: sets status to Available, populates cache, writes to TOML cache.
 // On error: inspects error type to set status to Locked or Unavailable.
+//
+// Vaults excluded by SetVaultFilter are skipped. Only items whose version or
+// updated_at changed since the last sync (or the TOML cache, after a restart)
+// are fetched again; the fetches run on a bounded worker pool and stop when
+// ctx is cancelled.
 func (b *Backend) SyncFromOnePassword(ctx context.Context) error {
+	_, err := b.syncItems(ctx)
+	return err
+}
+
+// SyncWithStats runs SyncFromOnePassword and reports what the sync did.
+// Used by the sync command, which prints the numbers for cron logs.
+func (b *Backend) SyncWithStats(ctx context.Context) (SyncStats, error) {
+	return b.syncItems(ctx)
+}
+
+// SyncStats describes one sync run.
+type SyncStats struct {
+	Started  time.Time
+	Duration time.Duration
+	Items    int // ssherpa-tagged items listed
+	Fetched  int // new or changed items fetched with GetItem and converted
+	Reused   int // unchanged items taken from the previous sync or the TOML cache
+	Skipped  int // items that failed to fetch or convert
+	Keys     int // SSH Key items with a usable public key
+
+	// DeniedVaults names the vaults whose items the token may not read
+	// (service account or Connect token without access to the vault).
+	DeniedVaults []string
+}
+
+// serviceAccountClient is implemented by clients that may authenticate with
+// a service account token instead of a desktop-app session (CLIClient).
+type serviceAccountClient interface {
+	UsesServiceAccount() bool
+}
+
+// usesServiceAccount reports whether the client authenticates with a
+// service account token.
+func (b *Backend) usesServiceAccount() bool {
+	sa, ok := b.client.(serviceAccountClient)
+	return ok && sa.UsesServiceAccount()
+}
+
+// classifyError maps a failed op or Connect call to a backend status.
+// Token problems (invalid, expired or revoked tokens, missing vault access)
+// are only reported for Connect 401/403 responses and, with serviceAccount,
+// for op errors that mention the token; a desktop session is classified as
+// locked, not signed in, or anything else as unavailable.
+func classifyError(err error, serviceAccount bool) backendpkg.BackendStatus {
+	var connectErr *ConnectError
+	if errors.As(err, &connectErr) {
+		if connectErr.StatusCode == http.StatusUnauthorized || connectErr.StatusCode == http.StatusForbidden {
+			return backendpkg.StatusTokenError
+		}
+		return backendpkg.StatusUnavailable
+	}
+
+	errStr := strings.ToLower(err.Error())
+	switch {
+	case serviceAccount && (strings.Contains(errStr, "service account") ||
+		strings.Contains(errStr, "token") ||
+		strings.Contains(errStr, "unauthorized") ||
+		strings.Contains(errStr, "forbidden") ||
+		strings.Contains(errStr, "not authorized") ||
+		strings.Contains(errStr, "aren't authorized") ||
+		strings.Contains(errStr, "permission")):
+		return backendpkg.StatusTokenError
+	case strings.Contains(errStr, "session expired") || strings.Contains(errStr, "locked"):
+		return backendpkg.StatusLocked
+	case strings.Contains(errStr, "not signed in") ||
+		strings.Contains(errStr, "not currently signed in") ||
+		strings.Contains(errStr, "no active session") ||
+		strings.Contains(errStr, "signin"):
+		return backendpkg.StatusNotSignedIn
+	default:
+		return backendpkg.StatusUnavailable
+	}
+}
+
+// fetchResult is the outcome of fetching one listed item.
+type fetchResult struct {
+	server  *domain.Server
+	key     *domain.Credential
+	skipped *SkippedItem
+}
+
+// syncItems does the work of SyncFromOnePassword and reports what it did.
+func (b *Backend) syncItems(ctx context.Context) (stats SyncStats, err error) {
+	stats.Started = time.Now()
+	defer func() { stats.Duration = time.Since(stats.Started) }()
+
 	// Try to list vaults as a health check
 	vaults, err := b.client.ListVaults(ctx)
 	if err != nil {
-		// Inspect error to determine locked vs not-signed-in vs unavailable
-		errStr := strings.ToLower(err.Error())
-		switch {
-		case strings.Contains(errStr, "session expired") || strings.Contains(errStr, "locked"):
-			b.setStatus(backendpkg.StatusLocked)
-		case strings.Contains(errStr, "not signed in") ||
-			strings.Contains(errStr, "not currently signed in") ||
-			strings.Contains(errStr, "no active session") ||
-			strings.Contains(errStr, "signin"):
-			b.setStatus(backendpkg.StatusNotSignedIn)
-		default:
-			b.setStatus(backendpkg.StatusUnavailable)
-		}
-		return &errors.BackendError{
+		b.setStatus(classifyError(err, b.usesServiceAccount()))
+		return stats, &errors.BackendError{
 			Op:      "SyncFromOnePassword",
 			Backend: "onepassword",
 			Err:     err,
 		}
 	}
 
-	// Fetch all tagged servers
-	// Strategy: ListItems for discovery (no field data), then GetItem only for ssherpa-tagged items
-	servers := make([]*domain.Server, 0)
-	var skippedItems []string // Track skipped items for debugging
+	b.mu.RLock()
+	vaults = b.filterVaults(vaults)
+	b.mu.RUnlock()
+
+	// Discover tagged items and SSH Key items. op item list only returns
+	// metadata (no fields), so changed items are fetched with GetItem below.
+	var listed, keyItems []Item
 	for _, vault := range vaults {
 		items, err := b.client.ListItems(ctx, vault.ID)
 		if err != nil {
-			// Skip vaults that error (permission issues, etc.)
+			// Skip vaults that error; a token without access to one is reported
+			if classifyError(err, b.usesServiceAccount()) == backendpkg.StatusTokenError {
+				stats.DeniedVaults = append(stats.DeniedVaults, vault.Name)
+			}
 			continue
 		}
-
 		for _, item := range items {
-			if !HasSshjesusTag(item.Tags) {
-				continue
+			item.VaultID = vault.ID
+			switch {
+			case HasSshjesusTag(item.Tags):
+				listed = append(listed, item)
+			case item.Category == sshKeyCategory:
+				keyItems = append(keyItems, item)
 			}
+		}
+	}
+	stats.Items = len(listed)
 
-			// op item list only returns metadata (no fields).
-			// Fetch full item with fields via GetItem.
-			fullItem, err := b.client.GetItem(ctx, vault.ID, item.ID)
-			if err != nil {
-				skippedItems = append(skippedItems, item.Title+": failed to fetch: "+err.Error())
-				continue
-			}
+	// Reuse servers whose item hasn't changed; queue the rest for fetching
+	previous := b.previousServers()
+	results := make([]fetchResult, len(listed))
+	var pending []int
+	for i := range listed {
+		rev := itemRevision(&listed[i])
+		if cached, ok := previous[serverKey(listed[i].VaultID, listed[i].ID)]; ok && rev != "" && cached.Revision == rev {
+			serverCopy := *cached
+			results[i].server = &serverCopy
+			stats.Reused++
+			continue
+		}
+		pending = append(pending, i)
+	}
 
-			server, err := ItemToServer(fullItem)
-			if err != nil {
-				// Track items that can't be converted (malformed data)
-				// This helps debug why tagged items don't appear in the TUI
-				skippedItems = append(skippedItems, item.Title+": "+err.Error())
-				continue
-			}
+	b.mu.RLock()
+	workers := b.syncWorkers
+	b.mu.RUnlock()
+	fetchItems(ctx, b.client, workers, listed, pending, results, fetchItem)
+	keys, keyRevs := b.syncKeys(ctx, keyItems, workers)
+	if err := ctx.Err(); err != nil {
+		// Keep the previous servers and keys rather than publishing partial lists
+		return stats, &errors.BackendError{
+			Op:      "SyncFromOnePassword",
+			Backend: "onepassword",
+			Err:     err,
+		}
+	}
+	for _, i := range pending {
+		if results[i].server != nil {
+			stats.Fetched++
+		}
+	}
+	stats.Keys = len(keys)
 
-			servers = append(servers, server)
+	servers := make([]*domain.Server, 0, len(listed))
+	var skippedItems []SkippedItem // Track skipped items for debugging and `ssherpa doctor`
+	for _, result := range results {
+		if result.skipped != nil {
+			skippedItems = append(skippedItems, *result.skipped)
+			continue
 		}
+		servers = append(servers, result.server)
 	}
+	stats.Skipped = len(skippedItems)
 
 	// Report skipped items to help debug missing entries
 	if len(skippedItems) > 0 {
 		fmt.Fprintf(os.Stderr, "Warning: %d items with 'ssherpa' tag skipped due to validation errors:\n", len(skippedItems))
-		for _, msg := range skippedItems {
-			fmt.Fprintf(os.Stderr, "  - %s\n", msg)
+		for _, skipped := range skippedItems {
+			fmt.Fprintf(os.Stderr, "  - %s: %s\n", skipped.Title, skipped.Reason)
 		}
 	}
 
 	// Update cache
 	b.mu.Lock()
 	b.servers = servers
+	b.skipped = skippedItems
+	b.keys = keys
+	b.keyRevs = keyRevs
+	b.vaults = vaults
 	b.status = backendpkg.StatusAvailable
 	b.mu.Unlock()
 
@@ -114,7 +239,159 @@ func (b *Backend) SyncFromOnePassword(ctx context.Context) error {
 		_ = sync.WriteTOMLCache(servers, b.cachePath)
 	}
 
-	return nil
+	return stats, nil
+}
+
+// fetchItems fetches listed[i] with fetch for every i in pending on at most
+// workers goroutines and stores the outcome in results[i].
+// It returns early, leaving results incomplete, when ctx is cancelled.
+func fetchItems(ctx context.Context, client Client, workers int, listed []Item, pending []int, results []fetchResult,
+	fetch func(context.Context, Client, *Item) fetchResult) {
+	workers = min(max(workers, 1), len(pending))
+	jobs := make(chan int)
+	var wg gosync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = fetch(ctx, client, &listed[i])
+			}
+		}()
+	}
+
+send:
+	for _, i := range pending {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// fetchItem fetches one item's fields and converts it to a server.
+func fetchItem(ctx context.Context, client Client, item *Item) fetchResult {
+	fullItem, err := client.GetItem(ctx, item.VaultID, item.ID)
+	if err != nil {
+		return fetchResult{skipped: &SkippedItem{Title: item.Title, VaultID: item.VaultID, Reason: "failed to fetch: " + err.Error()}}
+	}
+
+	server, err := ItemToServer(fullItem)
+	if err != nil {
+		// Track items that can't be converted (malformed data)
+		// This helps debug why tagged items don't appear in the TUI
+		return fetchResult{skipped: &SkippedItem{Title: item.Title, VaultID: item.VaultID, Reason: err.Error()}}
+	}
+	return fetchResult{server: server}
+}
+
+// fetchKey fetches one SSH Key item's fields and converts it to a credential.
+// Keys that can't be fetched or have no usable public key are left out
+// silently (the result is empty); only server items are reported as skipped.
+func fetchKey(ctx context.Context, client Client, item *Item) fetchResult {
+	fullItem, err := client.GetItem(ctx, item.VaultID, item.ID)
+	if err != nil {
+		return fetchResult{}
+	}
+
+	key, err := ItemToCredential(fullItem)
+	if err != nil {
+		return fetchResult{}
+	}
+	return fetchResult{key: key}
+}
+
+// syncKeys converts SSH Key items to credentials, fetching only the items
+// that changed since the last sync on the same bounded worker pool as the
+// servers. Items without a valid public key are left out. Returns the
+// credentials sorted by name and their revisions.
+func (b *Backend) syncKeys(ctx context.Context, items []Item, workers int) ([]*domain.Credential, map[string]string) {
+	b.mu.RLock()
+	previous := make(map[string]*domain.Credential, len(b.keys))
+	for _, key := range b.keys {
+		previous[key.ID] = key
+	}
+	previousRevs := b.keyRevs
+	b.mu.RUnlock()
+
+	results := make([]fetchResult, len(items))
+	var pending []int
+	for i := range items {
+		rev := itemRevision(&items[i])
+		if cached, ok := previous[items[i].ID]; ok && rev != "" && previousRevs[items[i].ID] == rev {
+			results[i].key = cached
+			continue
+		}
+		pending = append(pending, i)
+	}
+	fetchItems(ctx, b.client, workers, items, pending, results, fetchKey)
+
+	keys := make([]*domain.Credential, 0, len(items))
+	revs := make(map[string]string, len(items))
+	for i, result := range results {
+		if result.key == nil {
+			continue
+		}
+		keys = append(keys, result.key)
+		revs[items[i].ID] = itemRevision(&items[i])
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(keys[i].Name) < strings.ToLower(keys[j].Name)
+	})
+	return keys, revs
+}
+
+// previousServers returns the servers of the last sync keyed by vault and
+// item ID, falling back to the TOML cache when nothing is loaded yet.
+func (b *Backend) previousServers() map[string]*domain.Server {
+	b.mu.RLock()
+	servers := b.servers
+	b.mu.RUnlock()
+
+	if len(servers) == 0 && b.cachePath != "" {
+		if cached, err := sync.ReadTOMLCache(b.cachePath); err == nil {
+			servers = cached
+		}
+	}
+
+	previous := make(map[string]*domain.Server, len(servers))
+	for _, server := range servers {
+		previous[serverKey(server.VaultID, server.ID)] = server
+	}
+	return previous
+}
+
+// serverKey identifies an item across vaults.
+func serverKey(vaultID, itemID string) string {
+	return vaultID + "/" + itemID
+}
+
+// SetSyncWorkers sets how many items a sync fetches concurrently.
+// Values below 1 are treated as 1.
+func (b *Backend) SetSyncWorkers(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.syncWorkers = n
+}
+
+// SkippedItem is an ssherpa-tagged item the last sync could not turn into a server.
+type SkippedItem struct {
+	Title   string // item title
+	VaultID string // vault the item lives in
+	Reason  string // fetch or validation error
+}
+
+// SkippedItems returns the items skipped by the last successful sync (thread-safe).
+// Empty until SyncFromOnePassword has run.
+func (b *Backend) SkippedItems() []SkippedItem {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]SkippedItem(nil), b.skipped...)
 }
 
 // LoadFromCache loads servers from the TOML cache file.
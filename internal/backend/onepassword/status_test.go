@@ -6,8 +6,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -118,6 +120,99 @@ func TestSyncFromOnePassword_NotSignedIn(t *testing.T) {
 	}
 }
 
+func TestSyncFromOnePassword_TokenError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		serviceAccount bool
+		want           backendpkg.BackendStatus
+	}{
+		{"invalid service account token", fmt.Errorf("[ERROR] invalid service account token"), true, backendpkg.StatusTokenError},
+		{"expired token", fmt.Errorf("(401) Unauthorized: token has expired"), true, backendpkg.StatusTokenError},
+		{"insufficient scope", fmt.Errorf("(403) Forbidden: You aren't authorized to perform this action"), true, backendpkg.StatusTokenError},
+		{"connect 401", &ConnectError{StatusCode: 401, Message: "Invalid token signature"}, false, backendpkg.StatusTokenError},
+		{"connect 403", &ConnectError{StatusCode: 403, Message: "Authorization: Access denied"}, false, backendpkg.StatusTokenError},
+
+		// Without a service account, token-like wording keeps the session classification
+		{"desktop session mentions token", fmt.Errorf("session token expired, app is locked"), false, backendpkg.StatusLocked},
+		{"desktop not signed in", fmt.Errorf("invalid token: you are not currently signed in"), false, backendpkg.StatusNotSignedIn},
+		{"permission denied running op", fmt.Errorf("fork/exec /usr/local/bin/op: permission denied"), false, backendpkg.StatusUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockClient()
+			mock.SetServiceAccount(tt.serviceAccount)
+			mock.SetError("ListVaults", tt.err)
+			backend := New(mock)
+
+			require.Error(t, backend.SyncFromOnePassword(context.Background()))
+			assert.Equal(t, tt.want, backend.GetStatus())
+		})
+	}
+}
+
+func TestSyncWithStats_DeniedVaults(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "v1", Name: "Infra"})
+	mock.AddVault(Vault{ID: "v2", Name: "Finance"})
+	mock.AddVault(Vault{ID: "v3", Name: "Broken"})
+	mock.SetVaultError("v2", fmt.Errorf("(403) Forbidden: service account has no access to vault"))
+	mock.SetVaultError("v3", fmt.Errorf("connection reset"))
+	mock.SetServiceAccount(true)
+	mock.AddItem(Item{
+		ID: "item1", Title: "web", VaultID: "v1", Category: "server", Tags: []string{"ssherpa"},
+		Fields: []ItemField{{Title: "hostname", Value: "web.example.com"}, {Title: "user", Value: "deploy"}},
+	})
+	backend := New(mock)
+
+	stats, err := backend.SyncWithStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Items)
+	assert.Equal(t, []string{"Finance"}, stats.DeniedVaults, "only token errors count as denied")
+	assert.Equal(t, backendpkg.StatusAvailable, backend.GetStatus())
+}
+
+func TestSyncFromOnePassword_SSHKeys(t *testing.T) {
+	pub, fingerprint := testPublicKey(t)
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "v1", Name: "Infra"})
+	mock.AddItem(Item{
+		ID: "key-1", Title: "Deploy key", VaultID: "v1", Category: "ssh_key", Version: 1,
+		Fields: []ItemField{{ID: "public_key", Title: "public key", Value: pub}},
+	})
+	mock.AddItem(Item{
+		ID: "key-2", Title: "Empty key", VaultID: "v1", Category: "ssh_key", Version: 1,
+	})
+	backend := New(mock)
+	ctx := context.Background()
+
+	stats, err := backend.SyncWithStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Items, "SSH Key items are not servers")
+	assert.Equal(t, 1, stats.Keys)
+
+	creds, err := backend.ListCredentials(ctx)
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	assert.Equal(t, "Deploy key", creds[0].Name)
+	assert.Equal(t, fingerprint, creds[0].Fingerprint)
+
+	cred, err := backend.GetCredential(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, pub, cred.PublicKey)
+
+	_, err = backend.GetCredential(ctx, "key-2")
+	assert.ErrorIs(t, err, errors.ErrCredentialNotFound)
+
+	// Unchanged keys are not fetched again
+	calls, _ := mock.GetItemCalls()
+	_, err = backend.SyncWithStats(ctx)
+	require.NoError(t, err)
+	after, _ := mock.GetItemCalls()
+	assert.Equal(t, calls+1, after, "only the invalid key is fetched again")
+}
+
 func TestSyncFromOnePassword_Unavailable(t *testing.T) {
 	mock := NewMockClient()
 
@@ -244,6 +339,7 @@ func TestStatusString(t *testing.T) {
 		{backendpkg.StatusLocked, "Locked"},
 		{backendpkg.StatusNotSignedIn, "NotSignedIn"},
 		{backendpkg.StatusUnavailable, "Unavailable"},
+		{backendpkg.StatusTokenError, "TokenError"},
 	}
 
 	for _, tt := range tests {
@@ -337,3 +433,221 @@ func TestSyncFromOnePassword_SkipsInvalidItems(t *testing.T) {
 	require.Len(t, servers, 1, "Only valid item should be synced")
 	assert.Equal(t, "valid.example.com", servers[0].Host)
 }
+
+// addVersionedItems adds n valid ssherpa items with a version to vaultID.
+func addVersionedItems(mock *MockClient, vaultID string, n int) {
+	updated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range n {
+		mock.AddItem(Item{
+			ID:        fmt.Sprintf("ITEM-%02d", i),
+			Title:     fmt.Sprintf("server-%02d", i),
+			VaultID:   vaultID,
+			Category:  "server",
+			Tags:      []string{"ssherpa"},
+			Version:   1,
+			UpdatedAt: updated,
+			Fields: []ItemField{
+				{Title: "hostname", Value: fmt.Sprintf("host%02d.example.com", i)},
+				{Title: "user", Value: "deploy"},
+			},
+		})
+	}
+}
+
+func TestSyncFromOnePassword_Incremental(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "VAULT-1", Name: "Shared"})
+	addVersionedItems(mock, "VAULT-1", 3)
+
+	backend := New(mock)
+	ctx := context.Background()
+
+	stats, err := backend.syncItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Items)
+	assert.Equal(t, 3, stats.Fetched)
+	assert.Equal(t, 0, stats.Reused)
+
+	// Nothing changed: no GetItem calls
+	stats, err = backend.syncItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Fetched)
+	assert.Equal(t, 3, stats.Reused)
+	calls, _ := mock.GetItemCalls()
+	assert.Equal(t, 3, calls)
+
+	// One item edited in 1Password: only that one is fetched again
+	mock.AddItem(Item{
+		ID:        "ITEM-01",
+		Title:     "server-01",
+		VaultID:   "VAULT-1",
+		Category:  "server",
+		Tags:      []string{"ssherpa"},
+		Version:   2,
+		UpdatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Fields: []ItemField{
+			{Title: "hostname", Value: "renamed.example.com"},
+			{Title: "user", Value: "deploy"},
+		},
+	})
+
+	stats, err = backend.syncItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Fetched)
+	assert.Equal(t, 2, stats.Reused)
+
+	server, err := backend.GetServer(ctx, "ITEM-01")
+	require.NoError(t, err)
+	assert.Equal(t, "renamed.example.com", server.Host)
+}
+
+func TestSyncWithStats_FailedFetchNotCounted(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "VAULT-1", Name: "Shared"})
+	addVersionedItems(mock, "VAULT-1", 3)
+	mock.SetItemError("ITEM-01", fmt.Errorf("item is temporarily unavailable"))
+
+	backend := New(mock)
+	stats, err := backend.SyncWithStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Items)
+	assert.Equal(t, 2, stats.Fetched)
+	assert.Equal(t, 1, stats.Skipped)
+}
+
+func TestSyncFromOnePassword_IncrementalFromTOMLCache(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "VAULT-1", Name: "Shared"})
+	addVersionedItems(mock, "VAULT-1", 2)
+	cachePath := filepath.Join(t.TempDir(), "cache.toml")
+
+	ctx := context.Background()
+	require.NoError(t, NewWithCache(mock, cachePath).SyncFromOnePassword(ctx))
+
+	// A fresh backend (next ssherpa start) compares against the TOML cache
+	restarted := NewWithCache(mock, cachePath)
+	stats, err := restarted.syncItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Fetched)
+	assert.Equal(t, 2, stats.Reused)
+
+	servers, err := restarted.ListServers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, servers, 2)
+}
+
+func TestSyncFromOnePassword_UnversionedItemsAlwaysFetched(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "VAULT-1", Name: "Shared"})
+	mock.AddItem(Item{
+		ID:       "ITEM-1",
+		Title:    "legacy",
+		VaultID:  "VAULT-1",
+		Category: "server",
+		Tags:     []string{"ssherpa"},
+		Fields: []ItemField{
+			{Title: "hostname", Value: "legacy.example.com"},
+			{Title: "user", Value: "deploy"},
+		},
+	})
+
+	backend := New(mock)
+	ctx := context.Background()
+	require.NoError(t, backend.SyncFromOnePassword(ctx))
+
+	stats, err := backend.syncItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Fetched)
+}
+
+func TestSyncFromOnePassword_BoundedWorkers(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "VAULT-1", Name: "Shared"})
+	addVersionedItems(mock, "VAULT-1", 12)
+	mock.SetGetItemDelay(20 * time.Millisecond)
+
+	backend := New(mock)
+	backend.SetSyncWorkers(3)
+
+	require.NoError(t, backend.SyncFromOnePassword(context.Background()))
+
+	calls, maxConcurrent := mock.GetItemCalls()
+	assert.Equal(t, 12, calls)
+	assert.LessOrEqual(t, maxConcurrent, 3)
+	assert.Greater(t, maxConcurrent, 1, "fetches should run in parallel")
+
+	servers, err := backend.ListServers(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, servers, 12)
+}
+
+func TestSyncFromOnePassword_SSHKeysBoundedWorkers(t *testing.T) {
+	pub, _ := testPublicKey(t)
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "VAULT-1", Name: "Shared"})
+	for i := range 9 {
+		mock.AddItem(Item{
+			ID: fmt.Sprintf("KEY-%02d", i), Title: fmt.Sprintf("key-%02d", i), VaultID: "VAULT-1",
+			Category: "ssh_key", Version: 1,
+			Fields: []ItemField{{ID: "public_key", Title: "public key", Value: pub}},
+		})
+	}
+	mock.SetGetItemDelay(20 * time.Millisecond)
+
+	backend := New(mock)
+	backend.SetSyncWorkers(3)
+
+	stats, err := backend.SyncWithStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 9, stats.Keys)
+
+	calls, maxConcurrent := mock.GetItemCalls()
+	assert.Equal(t, 9, calls)
+	assert.LessOrEqual(t, maxConcurrent, 3)
+	assert.Greater(t, maxConcurrent, 1, "key fetches should run in parallel")
+
+	// Unchanged keys are reused
+	stats, err = backend.SyncWithStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 9, stats.Keys)
+	calls, _ = mock.GetItemCalls()
+	assert.Equal(t, 9, calls)
+}
+
+func TestSyncFromOnePassword_Cancelled(t *testing.T) {
+	mock := NewMockClient()
+	mock.AddVault(Vault{ID: "VAULT-1", Name: "Shared"})
+	addVersionedItems(mock, "VAULT-1", 2)
+
+	backend := New(mock)
+	require.NoError(t, backend.SyncFromOnePassword(context.Background()))
+
+	// A changed item whose fetch outlives the context
+	mock.AddItem(Item{
+		ID:       "ITEM-00",
+		Title:    "server-00",
+		VaultID:  "VAULT-1",
+		Category: "server",
+		Tags:     []string{"ssherpa"},
+		Version:  5,
+		Fields:   []ItemField{{Title: "hostname", Value: "changed.example.com"}, {Title: "user", Value: "deploy"}},
+	})
+	mock.SetGetItemDelay(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := backend.SyncFromOnePassword(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// The previous servers are kept rather than a partial list
+	servers, err := backend.ListServers(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, servers, 2)
+	server, err := backend.GetServer(context.Background(), "ITEM-00")
+	require.NoError(t, err)
+	assert.Equal(t, "host00.example.com", server.Host)
+}
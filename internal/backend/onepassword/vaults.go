@@ -0,0 +1,153 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// SetVaultFilter restricts which vaults are synced and written to.
+// Entries are vault names (case-insensitive) or IDs. An empty include list
+// allows every vault; exclude wins over include.
+func (b *Backend) SetVaultFilter(include, exclude []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.vaultInclude = include
+	b.vaultExclude = exclude
+	b.vaults = nil // re-filtered on the next sync or write
+}
+
+// SetDefaultVault sets the vault (name or ID) new servers are created in when
+// they don't name one. projectVaults overrides it per project ID.
+func (b *Backend) SetDefaultVault(vault string, projectVaults map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.defaultVault = vault
+	b.projectVaults = projectVaults
+}
+
+// WritableVaults implements backendpkg.VaultLister.
+// Returns the vaults seen by the last sync that pass the vault filter, sorted by name.
+func (b *Backend) WritableVaults() []backendpkg.Vault {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := make([]backendpkg.Vault, 0, len(b.vaults))
+	for _, v := range b.vaults {
+		result = append(result, backendpkg.Vault{ID: v.ID, Name: v.Name})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
+
+// DefaultVaultID implements backendpkg.VaultLister.
+// The project's vault wins over the default vault; with neither configured
+// and a single writable vault, that vault is the default.
+func (b *Backend) DefaultVaultID(projectID string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var projectIDs []string
+	if projectID != "" {
+		projectIDs = []string{projectID}
+	}
+	ref := b.defaultVaultRef(projectIDs)
+	if ref == "" {
+		if len(b.vaults) == 1 {
+			return b.vaults[0].ID
+		}
+		return ""
+	}
+	if v, ok := findVault(b.vaults, ref); ok {
+		return v.ID
+	}
+	return ""
+}
+
+// defaultVaultRef returns the configured vault for the first project that has
+// one, falling back to the default vault. Must be called with mu held.
+func (b *Backend) defaultVaultRef(projectIDs []string) string {
+	for _, id := range projectIDs {
+		if ref := b.projectVaults[id]; ref != "" {
+			return ref
+		}
+	}
+	return b.defaultVault
+}
+
+// filterVaults returns the vaults allowed by the vault filter.
+// Must be called with mu held.
+func (b *Backend) filterVaults(vaults []Vault) []Vault {
+	allowed := make([]Vault, 0, len(vaults))
+	for _, v := range vaults {
+		if len(b.vaultInclude) > 0 && !matchesVault(v, b.vaultInclude) {
+			continue
+		}
+		if matchesVault(v, b.vaultExclude) {
+			continue
+		}
+		allowed = append(allowed, v)
+	}
+	return allowed
+}
+
+// resolveVault returns the ID of the vault a new server goes to: the vault it
+// names (by name or ID), else its project's or the default vault.
+// Must be called with mu held for writing.
+func (b *Backend) resolveVault(ctx context.Context, ref string, projectIDs []string) (string, error) {
+	if ref == "" {
+		ref = b.defaultVaultRef(projectIDs)
+	}
+
+	if b.vaults == nil {
+		all, err := b.client.ListVaults(ctx)
+		if err != nil {
+			return "", err
+		}
+		b.vaults = b.filterVaults(all)
+	}
+
+	if ref == "" {
+		if len(b.vaults) == 1 {
+			return b.vaults[0].ID, nil
+		}
+		return "", fmt.Errorf("%w: VaultID must be set (no default vault configured)", errors.ErrValidation)
+	}
+
+	v, ok := findVault(b.vaults, ref)
+	if !ok {
+		return "", fmt.Errorf("%w: vault %q does not exist or is excluded from sync", errors.ErrValidation, ref)
+	}
+	return v.ID, nil
+}
+
+// findVault looks a vault up by ID, then by name (case-insensitive).
+func findVault(vaults []Vault, ref string) (Vault, bool) {
+	for _, v := range vaults {
+		if v.ID == ref {
+			return v, true
+		}
+	}
+	for _, v := range vaults {
+		if strings.EqualFold(v.Name, ref) {
+			return v, true
+		}
+	}
+	return Vault{}, false
+}
+
+// matchesVault reports whether v is named by any of refs (ID or name).
+func matchesVault(v Vault, refs []string) bool {
+	for _, ref := range refs {
+		if v.ID == ref || strings.EqualFold(v.Name, ref) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// newVaultsClient returns a mock with a personal and two team vaults, each
+// holding one ssherpa server named after the vault ID.
+func newVaultsClient() *MockClient {
+	client := NewMockClient()
+	for _, v := range []Vault{
+		{ID: "vault-personal", Name: "Private"},
+		{ID: "vault-infra", Name: "Team Infra"},
+		{ID: "vault-web", Name: "Team Web"},
+	} {
+		client.AddVault(v)
+		client.AddItem(Item{
+			ID:       "item-" + v.ID,
+			Title:    v.ID,
+			VaultID:  v.ID,
+			Category: "server",
+			Tags:     []string{"ssherpa"},
+			Fields: []ItemField{
+				{Title: "hostname", Value: v.ID + ".example.com"},
+				{Title: "user", Value: "deploy"},
+			},
+		})
+	}
+	return client
+}
+
+func TestSyncFromOnePassword_VaultFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{"no filter", nil, nil, []string{"vault-infra", "vault-personal", "vault-web"}},
+		{"include by name and ID", []string{"team infra", "vault-web"}, nil, []string{"vault-infra", "vault-web"}},
+		{"exclude by name", nil, []string{"Private"}, []string{"vault-infra", "vault-web"}},
+		{"exclude wins over include", []string{"Team Infra", "Private"}, []string{"vault-personal"}, []string{"vault-infra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(newVaultsClient())
+			b.SetVaultFilter(tt.include, tt.exclude)
+
+			ctx := context.Background()
+			require.NoError(t, b.SyncFromOnePassword(ctx))
+
+			servers, err := b.ListServers(ctx)
+			require.NoError(t, err)
+			var got []string
+			for _, s := range servers {
+				got = append(got, s.VaultID)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+
+			var writable []string
+			for _, v := range b.WritableVaults() {
+				writable = append(writable, v.ID)
+			}
+			assert.ElementsMatch(t, tt.want, writable)
+		})
+	}
+}
+
+func TestCreateServer_VaultSelection(t *testing.T) {
+	tests := []struct {
+		name         string
+		exclude      []string
+		defaultVault string
+		projectVault string
+		server       domain.Server
+		wantVault    string
+		wantErr      bool
+	}{
+		{
+			name:      "explicit vault ID",
+			server:    domain.Server{VaultID: "vault-web"},
+			wantVault: "vault-web",
+		},
+		{
+			name:      "explicit vault name",
+			server:    domain.Server{VaultID: "team web"},
+			wantVault: "vault-web",
+		},
+		{
+			name:         "default vault",
+			defaultVault: "Team Infra",
+			wantVault:    "vault-infra",
+		},
+		{
+			name:         "project vault overrides default",
+			defaultVault: "Team Infra",
+			projectVault: "Team Web",
+			server:       domain.Server{ProjectIDs: []string{"acme/web"}},
+			wantVault:    "vault-web",
+		},
+		{
+			name:    "no default with several vaults",
+			wantErr: true,
+		},
+		{
+			name:    "excluded vault",
+			exclude: []string{"Private"},
+			server:  domain.Server{VaultID: "Private"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newVaultsClient()
+			b := New(client)
+			b.SetVaultFilter(nil, tt.exclude)
+			b.SetDefaultVault(tt.defaultVault, map[string]string{"acme/web": tt.projectVault})
+
+			server := tt.server
+			server.ID = "new-server"
+			server.DisplayName = "new-server"
+			server.Host = "new.example.com"
+			server.User = "ops"
+
+			ctx := context.Background()
+			err := b.CreateServer(ctx, &server)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, errors.ErrValidation))
+				return
+			}
+			require.NoError(t, err)
+
+			item, err := client.GetItem(ctx, tt.wantVault, "new-server")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVault, item.VaultID)
+		})
+	}
+}
+
+func TestCreateServer_SingleVaultIsDefault(t *testing.T) {
+	client := newVaultsClient()
+	b := New(client)
+	b.SetVaultFilter([]string{"Team Infra"}, nil)
+
+	ctx := context.Background()
+	require.NoError(t, b.CreateServer(ctx, &domain.Server{ID: "solo", DisplayName: "solo", Host: "h", User: "u"}))
+
+	_, err := client.GetItem(ctx, "vault-infra", "solo")
+	assert.NoError(t, err)
+}
+
+func TestDefaultVaultID(t *testing.T) {
+	b := New(newVaultsClient())
+	b.SetDefaultVault("team infra", map[string]string{"acme/web": "vault-web", "acme/gone": "Deleted Vault"})
+
+	// Vaults are unknown until the first sync
+	assert.Empty(t, b.DefaultVaultID(""))
+
+	require.NoError(t, b.SyncFromOnePassword(context.Background()))
+
+	assert.Equal(t, "vault-infra", b.DefaultVaultID(""))
+	assert.Equal(t, "vault-web", b.DefaultVaultID("acme/web"))
+	assert.Equal(t, "vault-infra", b.DefaultVaultID("acme/other"))
+	assert.Empty(t, b.DefaultVaultID("acme/gone"), "a vault that doesn't exist is no default")
+
+	assert.Equal(t, []backendpkg.Vault{
+		{ID: "vault-personal", Name: "Private"},
+		{ID: "vault-infra", Name: "Team Infra"},
+		{ID: "vault-web", Name: "Team Web"},
+	}, b.WritableVaults())
+}
@@ -0,0 +1,457 @@
+// Package passstore implements a backend on a pass (or gopass) password
+// store: every entry under a prefix such as "ssh/" is a server.
+package passstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// DefaultPrefix is the store subtree holding servers when none is configured.
+const DefaultPrefix = "ssh"
+
+// Backend implements the backendpkg.Backend, backendpkg.Writer and
+// backendpkg.Filterer interfaces on a password store.
+type Backend struct {
+	client  Client           // pass/gopass client (real or fake)
+	prefix  string           // store subtree holding servers
+	mu      sync.RWMutex     // Protects cached servers and closed flag
+	servers []*domain.Server // Servers from the last Load
+	skipped []SkippedItem    // Entries the last Load couldn't convert
+	closed  bool             // Backend closed flag
+}
+
+// Compile-time interface verification
+var (
+	_ backendpkg.Backend  = (*Backend)(nil)
+	_ backendpkg.Writer   = (*Backend)(nil)
+	_ backendpkg.Filterer = (*Backend)(nil)
+)
+
+// SkippedItem is an entry Load could not turn into a server.
+type SkippedItem struct {
+	Name   string // entry path
+	Reason string // decrypt or validation error
+}
+
+// New creates a backend for the entries under prefix (DefaultPrefix when empty).
+// No entries are read - caller should call Load to populate the cache.
+func New(client Client, prefix string) *Backend {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return &Backend{
+		client:  client,
+		prefix:  prefix,
+		servers: make([]*domain.Server, 0),
+	}
+}
+
+// Load decrypts every entry under the prefix and caches the servers.
+// Entries that fail to decrypt or lack hostname/user are skipped and
+// reported by SkippedItems.
+func (b *Backend) Load(ctx context.Context) error {
+	names, err := b.client.List(ctx, b.prefix)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "Load",
+			Backend: "pass",
+			Err:     err,
+		}
+	}
+
+	servers := make([]*domain.Server, 0, len(names))
+	var skippedItems []SkippedItem
+	for _, name := range names {
+		content, err := b.client.Show(ctx, name)
+		if err != nil {
+			skippedItems = append(skippedItems, SkippedItem{Name: name, Reason: "failed to decrypt: " + err.Error()})
+			continue
+		}
+		server, err := EntryToServer(name, b.prefix, content)
+		if err != nil {
+			skippedItems = append(skippedItems, SkippedItem{Name: name, Reason: err.Error()})
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	// Report skipped items to help debug missing entries
+	if len(skippedItems) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d password store entries under %s/ skipped:\n", len(skippedItems), b.prefix)
+		for _, skipped := range skippedItems {
+			fmt.Fprintf(os.Stderr, "  - %s: %s\n", skipped.Name, skipped.Reason)
+		}
+	}
+
+	b.mu.Lock()
+	b.servers = servers
+	b.skipped = skippedItems
+	b.mu.Unlock()
+	return nil
+}
+
+// SkippedItems returns the entries skipped by the last Load (thread-safe).
+func (b *Backend) SkippedItems() []SkippedItem {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]SkippedItem(nil), b.skipped...)
+}
+
+// checkClosed returns ErrBackendUnavailable if backend is closed.
+// Must be called with mu held (either RLock or Lock).
+func (b *Backend) checkClosed() error {
+	if b.closed {
+		return &errors.BackendError{
+			Op:      "checkClosed",
+			Backend: "pass",
+			Err:     errors.ErrBackendUnavailable,
+		}
+	}
+	return nil
+}
+
+// ListServers returns the cached servers.
+func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	// Return copies (copy-on-read pattern)
+	result := make([]*domain.Server, len(b.servers))
+	for i, s := range b.servers {
+		serverCopy := *s
+		result[i] = &serverCopy
+	}
+	return result, nil
+}
+
+// GetServer retrieves a server by ID (its entry path) from the cache.
+func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if i := b.indexOf(id); i >= 0 {
+		serverCopy := *b.servers[i]
+		return &serverCopy, nil
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetServer",
+		Backend: "pass",
+		Err:     errors.ErrServerNotFound,
+	}
+}
+
+// FilterServers returns the cached servers matching the filter.
+func (b *Backend) FilterServers(ctx context.Context, filters backendpkg.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backendpkg.ApplyFilter(servers, filters), nil
+}
+
+// ListProjects returns an empty slice (projects are directories in the store, not standalone entities).
+func (b *Backend) ListProjects(ctx context.Context) ([]*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return []*domain.Project{}, nil
+}
+
+// GetProject returns ErrProjectNotFound (projects are directories, not standalone entities).
+func (b *Backend) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetProject",
+		Backend: "pass",
+		Err:     errors.ErrProjectNotFound,
+	}
+}
+
+// ListCredentials returns an empty slice (keys are referenced by identity_file).
+func (b *Backend) ListCredentials(ctx context.Context) ([]*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return []*domain.Credential{}, nil
+}
+
+// GetCredential returns ErrCredentialNotFound (credentials are not stored as entries).
+func (b *Backend) GetCredential(ctx context.Context, id string) (*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetCredential",
+		Backend: "pass",
+		Err:     errors.ErrCredentialNotFound,
+	}
+}
+
+// Close marks the backend as closed.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+// CreateServer inserts a new entry at prefix/<first project>/<display name>.
+// server.ID is set to the entry path.
+func (b *Backend) CreateServer(ctx context.Context, server *domain.Server) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	project := ""
+	if len(server.ProjectIDs) > 0 {
+		project = server.ProjectIDs[0]
+	}
+	name := entryName(b.prefix, project, server.DisplayName)
+	if b.indexOf(name) >= 0 {
+		return &errors.BackendError{
+			Op:      "CreateServer",
+			Backend: "pass",
+			Err:     errors.ErrDuplicateID,
+		}
+	}
+
+	// Round-trip before writing so an entry missing hostname/user is never stored
+	content := ServerToEntry(server, name, b.prefix, "")
+	created, err := EntryToServer(name, b.prefix, content)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "CreateServer",
+			Backend: "pass",
+			Err:     err,
+		}
+	}
+	if err := b.client.Insert(ctx, name, content); err != nil {
+		return &errors.BackendError{
+			Op:      "CreateServer",
+			Backend: "pass",
+			Err:     err,
+		}
+	}
+
+	server.ID = name
+	b.servers = append(b.servers, created)
+	return nil
+}
+
+// UpdateServer rewrites a server's entry, keeping its password line and
+// lines ssherpa doesn't manage. A new display name, or dropping the project
+// the entry's directory names, moves the entry first; the server's ID
+// becomes the new path.
+func (b *Backend) UpdateServer(ctx context.Context, server *domain.Server) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	index := b.indexOf(server.ID)
+	if index < 0 {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "pass",
+			Err:     errors.ErrServerNotFound,
+		}
+	}
+
+	existing, err := b.client.Show(ctx, server.ID)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "pass",
+			Err:     err,
+		}
+	}
+
+	name := b.targetName(server)
+	content := ServerToEntry(server, name, b.prefix, existing)
+	updated, err := EntryToServer(name, b.prefix, content)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "pass",
+			Err:     err,
+		}
+	}
+
+	if name != server.ID {
+		if b.indexOf(name) >= 0 {
+			return &errors.BackendError{
+				Op:      "UpdateServer",
+				Backend: "pass",
+				Err:     errors.ErrDuplicateID,
+			}
+		}
+		if err := b.client.Move(ctx, server.ID, name); err != nil {
+			return &errors.BackendError{
+				Op:      "UpdateServer",
+				Backend: "pass",
+				Err:     err,
+			}
+		}
+	}
+
+	if err := b.client.Insert(ctx, name, content); err != nil {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "pass",
+			Err:     err,
+		}
+	}
+
+	server.ID = name
+	b.servers[index] = updated
+	return nil
+}
+
+// targetName returns where a server's entry belongs: the directory stays
+// while the server still belongs to the project it names, and the last
+// element follows the display name.
+func (b *Backend) targetName(server *domain.Server) string {
+	project := pathProject(server.ID, b.prefix)
+	if project != "" && !slices.Contains(server.ProjectIDs, project) {
+		project = ""
+		if len(server.ProjectIDs) > 0 {
+			project = server.ProjectIDs[0]
+		}
+	}
+	return entryName(b.prefix, project, server.DisplayName)
+}
+
+// DeleteServer removes a server's entry from the store.
+func (b *Backend) DeleteServer(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	index := b.indexOf(id)
+	if index < 0 {
+		return &errors.BackendError{
+			Op:      "DeleteServer",
+			Backend: "pass",
+			Err:     errors.ErrServerNotFound,
+		}
+	}
+
+	if err := b.client.Remove(ctx, id); err != nil {
+		return &errors.BackendError{
+			Op:      "DeleteServer",
+			Backend: "pass",
+			Err:     err,
+		}
+	}
+
+	b.servers = slices.Delete(b.servers, index, index+1)
+	return nil
+}
+
+// indexOf returns the cache index of the server with id, or -1.
+// Must be called with mu held.
+func (b *Backend) indexOf(id string) int {
+	for i, server := range b.servers {
+		if server.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// CreateProject returns ErrReadOnlyBackend (projects are directories, not standalone entities).
+func (b *Backend) CreateProject(ctx context.Context, project *domain.Project) error {
+	return &errors.BackendError{
+		Op:      "CreateProject",
+		Backend: "pass",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// UpdateProject returns ErrReadOnlyBackend (projects are directories, not standalone entities).
+func (b *Backend) UpdateProject(ctx context.Context, project *domain.Project) error {
+	return &errors.BackendError{
+		Op:      "UpdateProject",
+		Backend: "pass",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// DeleteProject returns ErrReadOnlyBackend (projects are directories, not standalone entities).
+func (b *Backend) DeleteProject(ctx context.Context, id string) error {
+	return &errors.BackendError{
+		Op:      "DeleteProject",
+		Backend: "pass",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// CreateCredential returns ErrReadOnlyBackend (credentials are not stored as entries).
+func (b *Backend) CreateCredential(ctx context.Context, cred *domain.Credential) error {
+	return &errors.BackendError{
+		Op:      "CreateCredential",
+		Backend: "pass",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// UpdateCredential returns ErrReadOnlyBackend (credentials are not stored as entries).
+func (b *Backend) UpdateCredential(ctx context.Context, cred *domain.Credential) error {
+	return &errors.BackendError{
+		Op:      "UpdateCredential",
+		Backend: "pass",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// DeleteCredential returns ErrReadOnlyBackend (credentials are not stored as entries).
+func (b *Backend) DeleteCredential(ctx context.Context, id string) error {
+	return &errors.BackendError{
+		Op:      "DeleteCredential",
+		Backend: "pass",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
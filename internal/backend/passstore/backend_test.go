@@ -0,0 +1,240 @@
+package passstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// fakeStore implements Client as an in-memory password store.
+type fakeStore struct {
+	entries map[string]string
+	broken  map[string]bool // entries that fail to decrypt
+	calls   []string
+}
+
+func newFakeStore(entries map[string]string) *fakeStore {
+	return &fakeStore{entries: entries, broken: make(map[string]bool)}
+}
+
+func (f *fakeStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	for name := range f.entries {
+		if strings.HasPrefix(name, prefix+"/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *fakeStore) Show(ctx context.Context, name string) (string, error) {
+	if f.broken[name] {
+		return "", fmt.Errorf("gpg: decryption failed: No secret key")
+	}
+	content, ok := f.entries[name]
+	if !ok {
+		return "", fmt.Errorf("%s is not in the password store", name)
+	}
+	return content, nil
+}
+
+func (f *fakeStore) Insert(ctx context.Context, name, content string) error {
+	f.calls = append(f.calls, "insert "+name)
+	f.entries[name] = content
+	return nil
+}
+
+func (f *fakeStore) Move(ctx context.Context, from, to string) error {
+	f.calls = append(f.calls, "mv "+from+" "+to)
+	f.entries[to] = f.entries[from]
+	delete(f.entries, from)
+	return nil
+}
+
+func (f *fakeStore) Remove(ctx context.Context, name string) error {
+	f.calls = append(f.calls, "rm "+name)
+	delete(f.entries, name)
+	return nil
+}
+
+// seededStore returns a store with two servers, an entry outside the
+// prefix, an entry without a hostname and one that can't be decrypted.
+func seededStore() *fakeStore {
+	store := newFakeStore(map[string]string{
+		"ssh/payments/web": "s3cret\nhostname: web.example.com\nuser: deploy\nurl: https://console.example.com\n",
+		"ssh/db":           "\nhostname: db.internal\nuser: root\n",
+		"ssh/broken":       "\nuser: root\n",
+		"ssh/locked":       "\nhostname: locked.internal\nuser: root\n",
+		"bank/login":       "hunter2\nuser: me\n",
+	})
+	store.broken["ssh/locked"] = true
+	return store
+}
+
+func loadedBackend(t *testing.T, store *fakeStore) *Backend {
+	t.Helper()
+	b := New(store, "")
+	require.NoError(t, b.Load(context.Background()))
+	return b
+}
+
+func TestLoad(t *testing.T) {
+	b := loadedBackend(t, seededStore())
+
+	servers, err := b.ListServers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, "ssh/db", servers[0].ID)
+	assert.Equal(t, "ssh/payments/web", servers[1].ID)
+	assert.Equal(t, []string{"payments"}, servers[1].ProjectIDs)
+
+	skipped := b.SkippedItems()
+	require.Len(t, skipped, 2)
+	assert.Equal(t, "ssh/broken", skipped[0].Name)
+	assert.Equal(t, "ssh/locked", skipped[1].Name)
+	assert.Contains(t, skipped[1].Reason, "failed to decrypt")
+}
+
+func TestFilterServers(t *testing.T) {
+	b := loadedBackend(t, seededStore())
+
+	servers, err := b.FilterServers(context.Background(), backendpkg.ServerFilter{ProjectID: "payments"})
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "web", servers[0].DisplayName)
+}
+
+func TestCreateServer(t *testing.T) {
+	store := seededStore()
+	b := loadedBackend(t, store)
+	ctx := context.Background()
+
+	server := &domain.Server{DisplayName: "api", Host: "api.example.com", User: "deploy", Port: 22, ProjectIDs: []string{"payments"}}
+	require.NoError(t, b.CreateServer(ctx, server))
+
+	assert.Equal(t, "ssh/payments/api", server.ID)
+	assert.Equal(t, "\nhostname: api.example.com\nuser: deploy\n", store.entries["ssh/payments/api"])
+
+	got, err := b.GetServer(ctx, "ssh/payments/api")
+	require.NoError(t, err)
+	assert.Equal(t, "api.example.com", got.Host)
+
+	// Same path again
+	err = b.CreateServer(ctx, &domain.Server{DisplayName: "api", Host: "h", User: "u", ProjectIDs: []string{"payments"}})
+	assert.ErrorIs(t, err, errors.ErrDuplicateID)
+
+	// Invalid servers are never written
+	err = b.CreateServer(ctx, &domain.Server{DisplayName: "nohost", User: "u"})
+	assert.ErrorContains(t, err, "hostname")
+	assert.NotContains(t, store.entries, "ssh/nohost")
+}
+
+func TestUpdateServer_InPlace(t *testing.T) {
+	store := seededStore()
+	b := loadedBackend(t, store)
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	server.Port = 2222
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	assert.Equal(t, []string{"insert ssh/payments/web"}, store.calls)
+	assert.Equal(t, "s3cret\nhostname: web.example.com\nuser: deploy\nport: 2222\nurl: https://console.example.com\n", store.entries["ssh/payments/web"])
+}
+
+func TestUpdateServer_Moves(t *testing.T) {
+	store := seededStore()
+	b := loadedBackend(t, store)
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	server.DisplayName = "frontend"
+	server.ProjectIDs = []string{"shop"}
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	assert.Equal(t, "ssh/shop/frontend", server.ID)
+	assert.Equal(t, []string{"mv ssh/payments/web ssh/shop/frontend", "insert ssh/shop/frontend"}, store.calls)
+	assert.NotContains(t, store.entries, "ssh/payments/web")
+	assert.True(t, strings.HasPrefix(store.entries["ssh/shop/frontend"], "s3cret\n"))
+
+	_, err = b.GetServer(ctx, "ssh/payments/web")
+	assert.ErrorIs(t, err, errors.ErrServerNotFound)
+	got, err := b.GetServer(ctx, "ssh/shop/frontend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop"}, got.ProjectIDs)
+}
+
+func TestUpdateServer_TopLevelStays(t *testing.T) {
+	store := seededStore()
+	b := loadedBackend(t, store)
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "ssh/db")
+	require.NoError(t, err)
+	server.ProjectIDs = []string{"payments"}
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	assert.Equal(t, "ssh/db", server.ID)
+	assert.Contains(t, store.entries["ssh/db"], "project_tags: payments\n")
+}
+
+func TestUpdateServer_Conflict(t *testing.T) {
+	b := loadedBackend(t, seededStore())
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	server.DisplayName = "db"
+	server.ProjectIDs = nil
+	assert.ErrorIs(t, b.UpdateServer(ctx, server), errors.ErrDuplicateID)
+
+	err = b.UpdateServer(ctx, &domain.Server{ID: "ssh/missing", Host: "h", User: "u"})
+	assert.ErrorIs(t, err, errors.ErrServerNotFound)
+}
+
+func TestDeleteServer(t *testing.T) {
+	store := seededStore()
+	b := loadedBackend(t, store)
+	ctx := context.Background()
+
+	require.NoError(t, b.DeleteServer(ctx, "ssh/db"))
+	assert.NotContains(t, store.entries, "ssh/db")
+
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, servers, 1)
+
+	assert.ErrorIs(t, b.DeleteServer(ctx, "ssh/db"), errors.ErrServerNotFound)
+}
+
+func TestReadOnlyEntities(t *testing.T) {
+	b := loadedBackend(t, seededStore())
+	ctx := context.Background()
+
+	assert.ErrorIs(t, b.CreateProject(ctx, &domain.Project{}), errors.ErrReadOnlyBackend)
+	assert.ErrorIs(t, b.DeleteCredential(ctx, "x"), errors.ErrReadOnlyBackend)
+
+	projects, err := b.ListProjects(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+}
+
+func TestClose(t *testing.T) {
+	b := loadedBackend(t, seededStore())
+	require.NoError(t, b.Close())
+
+	_, err := b.ListServers(context.Background())
+	assert.ErrorIs(t, err, errors.ErrBackendUnavailable)
+}
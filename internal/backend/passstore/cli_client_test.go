@@ -0,0 +1,131 @@
+package passstore
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingExecutor implements CommandExecutor and records every call.
+type recordingExecutor struct {
+	calls  [][]string
+	inputs []string
+	stdout []byte
+}
+
+func (e *recordingExecutor) Run(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	e.calls = append(e.calls, append([]string{name}, args...))
+	e.inputs = append(e.inputs, string(input))
+	return e.stdout, nil, nil
+}
+
+func TestCLIClient_Commands(t *testing.T) {
+	executor := &recordingExecutor{stdout: []byte("pw\nhostname: h\n")}
+	client := &CLIClient{command: "pass", executor: executor}
+	ctx := context.Background()
+
+	content, err := client.Show(ctx, "ssh/web")
+	require.NoError(t, err)
+	assert.Equal(t, "pw\nhostname: h\n", content)
+	require.NoError(t, client.Insert(ctx, "ssh/web", "pw\nuser: u\n"))
+	require.NoError(t, client.Move(ctx, "ssh/web", "ssh/app/web"))
+	require.NoError(t, client.Remove(ctx, "ssh/app/web"))
+
+	assert.Equal(t, [][]string{
+		{"pass", "show", "ssh/web"},
+		{"pass", "insert", "-m", "-f", "ssh/web"},
+		{"pass", "mv", "-f", "ssh/web", "ssh/app/web"},
+		{"pass", "rm", "-f", "ssh/app/web"},
+	}, executor.calls)
+	assert.Equal(t, "pw\nuser: u\n", executor.inputs[1])
+}
+
+func TestCLIClient_ListStoreDir(t *testing.T) {
+	storeDir := t.TempDir()
+	for _, name := range []string{"ssh/web.gpg", "ssh/payments/db.gpg", "ssh/.git/config.gpg", "ssh/notes.txt", "bank/login.gpg"} {
+		path := filepath.Join(storeDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+	}
+	client := &CLIClient{command: "pass", storeDir: storeDir, executor: &recordingExecutor{}}
+
+	names, err := client.List(context.Background(), "ssh/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ssh/payments/db", "ssh/web"}, names)
+
+	names, err = client.List(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestCLIClient_ListGopass(t *testing.T) {
+	executor := &recordingExecutor{stdout: []byte("ssh/web\nssh/payments/db\n\n")}
+	client := &CLIClient{command: "gopass", gopass: true, executor: executor}
+
+	names, err := client.List(context.Background(), "ssh")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ssh/payments/db", "ssh/web"}, names)
+	assert.Equal(t, []string{"gopass", "ls", "--flat", "ssh"}, executor.calls[0])
+}
+
+// TestCLIClient_PassRoundTrip runs the real pass CLI against a store and
+// GPG home in temporary directories.
+func TestCLIClient_PassRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("pass"); err != nil {
+		t.Skip("pass not installed")
+	}
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg not installed")
+	}
+
+	// Short path: gpg-agent sockets have a length limit
+	gnupgHome, err := os.MkdirTemp("", "gpg")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = exec.Command("gpgconf", "--homedir", gnupgHome, "--kill", "gpg-agent").Run()
+		_ = os.RemoveAll(gnupgHome)
+	})
+	t.Setenv("GNUPGHOME", gnupgHome)
+	storeDir := t.TempDir()
+	t.Setenv("PASSWORD_STORE_DIR", storeDir)
+
+	keyID := "ssherpa-test@example.com"
+	out, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key", keyID, "default", "default", "never").CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command("pass", "init", keyID).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	client, err := NewCLIClient("", storeDir)
+	require.NoError(t, err)
+	b := New(client, "ssh")
+	ctx := context.Background()
+	require.NoError(t, b.Load(ctx))
+
+	require.NoError(t, client.Insert(ctx, "ssh/web", "s3cret\nhostname: web.example.com\nuser: deploy\nurl: https://console.example.com\n"))
+	require.NoError(t, b.Load(ctx))
+	server, err := b.GetServer(ctx, "ssh/web")
+	require.NoError(t, err)
+
+	server.DisplayName = "frontend"
+	server.ProjectIDs = []string{"payments"}
+	server.Port = 2222
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	content, err := client.Show(ctx, "ssh/payments/frontend")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret\nhostname: web.example.com\nuser: deploy\nport: 2222\nurl: https://console.example.com\n", content)
+
+	names, err := client.List(ctx, "ssh")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ssh/payments/frontend"}, names)
+
+	require.NoError(t, b.DeleteServer(ctx, "ssh/payments/frontend"))
+	names, err = client.List(ctx, "ssh")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
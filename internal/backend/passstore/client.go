@@ -0,0 +1,178 @@
+package passstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Client abstracts password-store operations for testability.
+// Entry names are slash-separated paths without the .gpg suffix.
+type Client interface {
+	List(ctx context.Context, prefix string) ([]string, error)
+	Show(ctx context.Context, name string) (string, error)
+	Insert(ctx context.Context, name, content string) error
+	Move(ctx context.Context, from, to string) error
+	Remove(ctx context.Context, name string) error
+}
+
+// CommandExecutor abstracts command execution for testability.
+// input is written to the command's stdin (nil = none).
+type CommandExecutor interface {
+	Run(ctx context.Context, input []byte, name string, args ...string) (stdout, stderr []byte, err error)
+}
+
+// defaultExecutor implements CommandExecutor using os/exec.
+type defaultExecutor struct {
+	env []string // extra environment variables (e.g. PASSWORD_STORE_DIR)
+}
+
+func (e *defaultExecutor) Run(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if len(e.env) > 0 {
+		cmd.Env = append(os.Environ(), e.env...)
+	}
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	return stdout, stderr.Bytes(), err
+}
+
+// CLIClient implements the Client interface with the pass or gopass CLI.
+type CLIClient struct {
+	command  string // "pass" or "gopass" (resolved path)
+	gopass   bool   // gopass lists entries itself; pass entries are read from storeDir
+	storeDir string // pass store root
+	executor CommandExecutor
+}
+
+// NewCLIClient creates a client for command ("pass" when empty, or "gopass").
+// storeDir overrides the pass store location (default: $PASSWORD_STORE_DIR,
+// then ~/.password-store); gopass uses its own store configuration.
+func NewCLIClient(command, storeDir string) (*CLIClient, error) {
+	if command == "" {
+		command = "pass"
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil, fmt.Errorf("%s CLI not found in PATH: %w", command, err)
+	}
+
+	executor := &defaultExecutor{}
+	if storeDir != "" {
+		executor.env = []string{"PASSWORD_STORE_DIR=" + storeDir}
+	} else if storeDir = os.Getenv("PASSWORD_STORE_DIR"); storeDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine home directory: %w", err)
+		}
+		storeDir = filepath.Join(home, ".password-store")
+	}
+
+	return &CLIClient{
+		command:  path,
+		gopass:   filepath.Base(command) == "gopass",
+		storeDir: storeDir,
+		executor: executor,
+	}, nil
+}
+
+// run executes a pass/gopass command and returns its stdout.
+func (c *CLIClient) run(ctx context.Context, input []byte, args ...string) ([]byte, error) {
+	stdout, stderr, err := c.executor.Run(ctx, input, c.command, args...)
+	if err != nil {
+		// Include stderr in error message for debugging
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return nil, fmt.Errorf("%s %s failed: %w (stderr: %s)", filepath.Base(c.command), args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s %s failed: %w", filepath.Base(c.command), args[0], err)
+	}
+	return stdout, nil
+}
+
+// List returns the entries under prefix, sorted. A missing prefix yields none.
+// pass has no machine-readable listing, so its store directory is walked;
+// gopass is asked with "gopass ls --flat".
+func (c *CLIClient) List(ctx context.Context, prefix string) ([]string, error) {
+	prefix = strings.Trim(prefix, "/")
+
+	if c.gopass {
+		output, err := c.run(ctx, nil, "ls", "--flat", prefix)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, line := range strings.Split(string(output), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				names = append(names, line)
+			}
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	root := filepath.Join(c.storeDir, filepath.FromSlash(prefix))
+	var names []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && path != root {
+			return filepath.SkipDir // .git, .extensions
+		}
+		if d.IsDir() || filepath.Ext(path) != ".gpg" {
+			return nil
+		}
+		rel, err := filepath.Rel(c.storeDir, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, strings.TrimSuffix(filepath.ToSlash(rel), ".gpg"))
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list password store: %w", err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Show decrypts an entry.
+func (c *CLIClient) Show(ctx context.Context, name string) (string, error) {
+	output, err := c.run(ctx, nil, "show", name)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// Insert writes an entry with "insert -m -f", replacing any existing one.
+func (c *CLIClient) Insert(ctx context.Context, name, content string) error {
+	_, err := c.run(ctx, []byte(content), "insert", "-m", "-f", name)
+	return err
+}
+
+// Move renames an entry, replacing any entry at the destination.
+func (c *CLIClient) Move(ctx context.Context, from, to string) error {
+	_, err := c.run(ctx, nil, "mv", "-f", from, to)
+	return err
+}
+
+// Remove deletes an entry.
+func (c *CLIClient) Remove(ctx context.Context, name string) error {
+	_, err := c.run(ctx, nil, "rm", "-f", name)
+	return err
+}
@@ -0,0 +1,250 @@
+package passstore
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+// An entry follows the pass convention: the password on the first line and
+// "key: value" lines after it. Keys are the 1Password field names:
+//
+//	<password, may be empty>
+//	hostname: web.example.com
+//	user: deploy
+//	port: 2222
+//	tags: prod, eu
+//	extra_config: LocalForward 8080 localhost:80
+//
+// extra_config is repeated, one SSH option per line. Other lines are kept
+// as they are when ssherpa rewrites an entry.
+
+// managedKeys are the keys ServerToEntry writes, in order.
+var managedKeys = []string{
+	"hostname", "user", "port", "identity_file", "remote_project_path", "project_tags",
+	"tags", "proxy_jump", "vpn_required", "favorite", "ssh_key", "forward_agent", "extra_config",
+}
+
+// parseLine splits a "key: value" line. Keys are single words, so prose
+// lines like "Note to self: ..." are left alone.
+func parseLine(line string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(line, ":")
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// splitEntry returns the password line and the lines after it.
+func splitEntry(content string) (password string, lines []string) {
+	content = strings.TrimRight(content, "\n")
+	password, rest, _ := strings.Cut(content, "\n")
+	if rest != "" {
+		lines = strings.Split(rest, "\n")
+	}
+	return password, lines
+}
+
+// EntryToServer converts a decrypted entry to a domain.Server.
+// name is the entry path; its last element is the display name and the
+// directories between prefix and it name the server's project, so
+// "ssh/payments/web" is the "web" server of project "payments".
+// Returns error if required fields (hostname, user) are missing.
+func EntryToServer(name, prefix, content string) (*domain.Server, error) {
+	server := &domain.Server{
+		ID:          name,
+		DisplayName: path.Base(name),
+		Port:        22, // default port
+		Source:      "pass",
+		Tags:        []string{},
+	}
+	if project := pathProject(name, prefix); project != "" {
+		server.ProjectIDs = []string{project}
+	}
+
+	_, lines := splitEntry(content)
+	for _, line := range lines {
+		key, value, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "hostname":
+			server.Host = value
+		case "user":
+			server.User = value
+		case "port":
+			if port, err := strconv.Atoi(value); err == nil {
+				server.Port = port
+			}
+		case "identity_file":
+			server.IdentityFile = value
+		case "remote_project_path":
+			server.RemoteProjectPath = value
+		case "project_tags":
+			for _, id := range splitList(value) {
+				if !slices.Contains(server.ProjectIDs, id) {
+					server.ProjectIDs = append(server.ProjectIDs, id)
+				}
+			}
+		case "tags":
+			server.Tags = append(server.Tags, splitList(value)...)
+		case "proxy_jump":
+			server.Proxy = value
+		case "vpn_required":
+			server.VPNRequired = parseBoolField(value)
+		case "favorite":
+			server.Favorite = parseBoolField(value)
+		case "ssh_key":
+			server.CredentialID = value
+		case "forward_agent":
+			if value != "" {
+				setSSHOption(server, "ForwardAgent", formatBoolField(parseBoolField(value)))
+			}
+		case "extra_config":
+			if option, optionValue, ok := strings.Cut(value, " "); ok {
+				setSSHOption(server, option, strings.TrimSpace(optionValue))
+			}
+		}
+	}
+
+	// Validate required fields
+	if server.Host == "" {
+		return nil, fmt.Errorf("entry %q missing required field: hostname", name)
+	}
+	if server.User == "" {
+		return nil, fmt.Errorf("entry %q missing required field: user", name)
+	}
+
+	return server, nil
+}
+
+// ServerToEntry renders a server as entry content for name. existing is
+// the entry's current content ("" for a new entry): its password line and
+// every line ssherpa doesn't manage are kept. The project given by the
+// entry path is not repeated in project_tags.
+func ServerToEntry(server *domain.Server, name, prefix, existing string) string {
+	password, oldLines := splitEntry(existing)
+
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, key+": "+value)
+		}
+	}
+
+	add("hostname", server.Host)
+	add("user", server.User)
+	if server.Port != 22 && server.Port != 0 {
+		add("port", strconv.Itoa(server.Port))
+	}
+	add("identity_file", server.IdentityFile)
+	add("remote_project_path", server.RemoteProjectPath)
+	project := pathProject(name, prefix)
+	var projectTags []string
+	for _, id := range server.ProjectIDs {
+		if id != project {
+			projectTags = append(projectTags, id)
+		}
+	}
+	add("project_tags", strings.Join(projectTags, ", "))
+	add("tags", strings.Join(server.Tags, ", "))
+	add("proxy_jump", server.Proxy)
+	if server.VPNRequired {
+		add("vpn_required", "true")
+	}
+	if server.Favorite {
+		add("favorite", "true")
+	}
+	add("ssh_key", server.CredentialID)
+
+	// ForwardAgent has its own key; every other option is an extra_config line
+	forwardAgent := false
+	for _, line := range server.SSHOptionLines() {
+		key, value, _ := strings.Cut(line, " ")
+		lower := strings.ToLower(value)
+		if !forwardAgent && strings.EqualFold(key, "ForwardAgent") && (lower == "yes" || lower == "no") {
+			forwardAgent = true
+			add("forward_agent", lower)
+			continue
+		}
+		add("extra_config", line)
+	}
+
+	for _, line := range oldLines {
+		if key, _, ok := parseLine(line); ok && slices.Contains(managedKeys, key) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return password + "\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// entryName returns the entry path for a server: prefix, then project (when
+// given), then the display name with slashes replaced.
+func entryName(prefix, project, displayName string) string {
+	base := strings.ReplaceAll(strings.TrimSpace(displayName), "/", "-")
+	return path.Join(strings.Trim(prefix, "/"), project, base)
+}
+
+// pathProject returns the directories between prefix and the entry name,
+// or "" for entries directly under prefix.
+func pathProject(name, prefix string) string {
+	rel := strings.TrimPrefix(name, strings.Trim(prefix, "/")+"/")
+	dir := path.Dir(rel)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// splitList parses a comma-separated value, dropping blanks.
+func splitList(value string) []string {
+	var list []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// setSSHOption appends value to the server's SSH options under key, reusing
+// the spelling of a keyword that is already present.
+func setSSHOption(server *domain.Server, key, value string) {
+	if server.SSHOptions == nil {
+		server.SSHOptions = make(map[string][]string)
+	}
+	for existing := range server.SSHOptions {
+		if strings.EqualFold(existing, key) {
+			key = existing
+			break
+		}
+	}
+	server.SSHOptions[key] = append(server.SSHOptions[key], value)
+}
+
+// parseBoolField interprets a yes/no style value.
+func parseBoolField(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// formatBoolField renders a boolean as the yes/no form ssh_config uses.
+func formatBoolField(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,127 @@
+package passstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+func TestEntryToServer(t *testing.T) {
+	content := `s3cret
+hostname: web.example.com
+user: deploy
+port: 2222
+identity_file: ~/.ssh/deploy
+project_tags: shared, payments
+tags: prod, eu
+proxy_jump: bastion
+vpn_required: yes
+favorite: true
+forward_agent: on
+extra_config: LocalForward 8080 localhost:80
+extra_config: LocalForward 9090 localhost:90
+Note to self: rotate in March
+`
+	server, err := EntryToServer("ssh/payments/web", "ssh", content)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ssh/payments/web", server.ID)
+	assert.Equal(t, "web", server.DisplayName)
+	assert.Equal(t, "web.example.com", server.Host)
+	assert.Equal(t, "deploy", server.User)
+	assert.Equal(t, 2222, server.Port)
+	assert.Equal(t, "~/.ssh/deploy", server.IdentityFile)
+	assert.Equal(t, []string{"payments", "shared"}, server.ProjectIDs)
+	assert.Equal(t, []string{"prod", "eu"}, server.Tags)
+	assert.Equal(t, "bastion", server.Proxy)
+	assert.True(t, server.VPNRequired)
+	assert.True(t, server.Favorite)
+	assert.Equal(t, "pass", server.Source)
+	assert.Equal(t, []string{"yes"}, server.SSHOptions["ForwardAgent"])
+	assert.Equal(t, []string{"8080 localhost:80", "9090 localhost:90"}, server.SSHOptions["LocalForward"])
+}
+
+func TestEntryToServer_Defaults(t *testing.T) {
+	server, err := EntryToServer("ssh/db", "ssh", "\nhostname: db.internal\nuser: root\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, 22, server.Port)
+	assert.Empty(t, server.ProjectIDs)
+	assert.False(t, server.Favorite)
+}
+
+func TestEntryToServer_MissingFields(t *testing.T) {
+	_, err := EntryToServer("ssh/db", "ssh", "pw\nuser: root\n")
+	assert.ErrorContains(t, err, "hostname")
+
+	_, err = EntryToServer("ssh/db", "ssh", "pw\nhostname: db.internal\n")
+	assert.ErrorContains(t, err, "user")
+
+	// Only lines after the password count
+	_, err = EntryToServer("ssh/db", "ssh", "hostname: db.internal\nuser: root\n")
+	assert.ErrorContains(t, err, "hostname")
+}
+
+func TestServerToEntry_KeepsUnmanagedLines(t *testing.T) {
+	existing := "s3cret\nhostname: old.example.com\nurl: https://console.example.com\nuser: old\nNote to self: rotate in March\n"
+	server := &domain.Server{
+		DisplayName: "web",
+		Host:        "web.example.com",
+		User:        "deploy",
+		Port:        22,
+		ProjectIDs:  []string{"payments", "shared"},
+		Favorite:    true,
+	}
+
+	content := ServerToEntry(server, "ssh/payments/web", "ssh", existing)
+
+	assert.Equal(t, "s3cret\n"+
+		"hostname: web.example.com\n"+
+		"user: deploy\n"+
+		"project_tags: shared\n"+
+		"favorite: true\n"+
+		"url: https://console.example.com\n"+
+		"Note to self: rotate in March\n", content)
+}
+
+func TestServerToEntry_RoundTrip(t *testing.T) {
+	server := &domain.Server{
+		DisplayName:  "web",
+		Host:         "web.example.com",
+		User:         "deploy",
+		Port:         2222,
+		IdentityFile: "~/.ssh/deploy",
+		ProjectIDs:   []string{"payments"},
+		Tags:         []string{"prod"},
+		Proxy:        "bastion",
+		VPNRequired:  true,
+		SSHOptions: map[string][]string{
+			"ForwardAgent": {"yes"},
+			"LocalForward": {"8080 localhost:80"},
+		},
+	}
+
+	content := ServerToEntry(server, "ssh/payments/web", "ssh", "")
+	assert.Contains(t, content, "forward_agent: yes\n")
+	assert.Contains(t, content, "extra_config: LocalForward 8080 localhost:80\n")
+	assert.NotContains(t, content, "project_tags")
+
+	parsed, err := EntryToServer("ssh/payments/web", "ssh", content)
+	require.NoError(t, err)
+	assert.Equal(t, server.Host, parsed.Host)
+	assert.Equal(t, server.Port, parsed.Port)
+	assert.Equal(t, server.ProjectIDs, parsed.ProjectIDs)
+	assert.Equal(t, server.Tags, parsed.Tags)
+	assert.Equal(t, server.Proxy, parsed.Proxy)
+	assert.True(t, parsed.VPNRequired)
+	assert.Equal(t, server.SSHOptions, parsed.SSHOptions)
+}
+
+func TestEntryName(t *testing.T) {
+	assert.Equal(t, "ssh/web", entryName("ssh/", "", "web"))
+	assert.Equal(t, "ssh/payments/web", entryName("ssh", "payments", "web"))
+	assert.Equal(t, "ssh/a-b", entryName("ssh", "", " a/b "))
+}
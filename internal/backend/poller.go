@@ -0,0 +1,109 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// Polling defaults shared by the backends that sync from a remote store
+// (1Password, Bitwarden).
+const (
+	DefaultPollInterval = 5 * time.Minute  // time between polls when none is configured
+	PollWriteDebounce   = 10 * time.Second // polls this soon after a write are skipped
+	PollSyncTimeout     = 30 * time.Second // bound for one sync (allows time for biometric unlock)
+)
+
+// Poller periodically syncs a backend in the background and reports status
+// changes, so the TUI notices when a locked or unreachable store comes back.
+type Poller struct {
+	interval  time.Duration
+	sync      func(ctx context.Context) error // runs one sync; the error is the sync's to record
+	status    func() BackendStatus            // reads the backend's status
+	lastWrite func() time.Time                // last local write (zero = none)
+	onChange  func(BackendStatus)             // Callback when status changes (for TUI notification)
+	ticker    *time.Ticker
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewPoller creates a poller that calls syncFn every interval.
+// status and lastWrite are read around each poll: a poll within
+// PollWriteDebounce of the last write is skipped, and onChange (optional) is
+// called when the status differs after the sync.
+func NewPoller(interval time.Duration, syncFn func(ctx context.Context) error, status func() BackendStatus, lastWrite func() time.Time, onChange func(BackendStatus)) *Poller {
+	return &Poller{
+		interval:  interval,
+		sync:      syncFn,
+		status:    status,
+		lastWrite: lastWrite,
+		onChange:  onChange,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// PollInterval returns interval, or when it is 0 the duration in the
+// environment variable envVar, or DefaultPollInterval.
+func PollInterval(interval time.Duration, envVar string) time.Duration {
+	if interval != 0 {
+		return interval
+	}
+	if envInterval := os.Getenv(envVar); envInterval != "" {
+		if parsed, err := time.ParseDuration(envInterval); err == nil {
+			return parsed
+		}
+	}
+	return DefaultPollInterval
+}
+
+// Start begins polling in a background goroutine.
+// Returns immediately - use Stop() to halt polling.
+func (p *Poller) Start() {
+	p.ticker = time.NewTicker(p.interval)
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+		defer p.ticker.Stop()
+
+		// Run first poll immediately (don't wait for first tick)
+		p.Poll()
+
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-p.ticker.C:
+				p.Poll()
+			}
+		}
+	}()
+}
+
+// Poll performs a single poll: it syncs unless a write happened within
+// PollWriteDebounce, and reports a status change to onChange.
+func (p *Poller) Poll() {
+	if lastWrite := p.lastWrite(); !lastWrite.IsZero() && time.Since(lastWrite) < PollWriteDebounce {
+		// Skip sync - too soon after write
+		return
+	}
+
+	oldStatus := p.status()
+
+	ctx, cancel := context.WithTimeout(context.Background(), PollSyncTimeout)
+	defer cancel()
+
+	// Error is OK - status will be set appropriately by the sync
+	_ = p.sync(ctx)
+
+	if newStatus := p.status(); newStatus != oldStatus && p.onChange != nil {
+		p.onChange(newStatus)
+	}
+}
+
+// Stop halts the poller and waits for the goroutine to exit.
+func (p *Poller) Stop() {
+	close(p.stopCh)
+	p.wg.Wait()
+}
@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoller_Poll(t *testing.T) {
+	status := StatusUnknown
+	var lastWrite time.Time
+	syncs := 0
+	var changes []BackendStatus
+
+	p := NewPoller(time.Hour,
+		func(ctx context.Context) error {
+			syncs++
+			status = StatusAvailable
+			return nil
+		},
+		func() BackendStatus { return status },
+		func() time.Time { return lastWrite },
+		func(s BackendStatus) { changes = append(changes, s) },
+	)
+
+	p.Poll()
+	p.Poll()
+	assert.Equal(t, 2, syncs)
+	assert.Equal(t, []BackendStatus{StatusAvailable}, changes, "only a change is reported")
+
+	// Right after a write the poll is skipped
+	lastWrite = time.Now()
+	p.Poll()
+	assert.Equal(t, 2, syncs)
+}
+
+func TestPollInterval(t *testing.T) {
+	t.Setenv("SSHERPA_TEST_POLL_INTERVAL", "30s")
+	assert.Equal(t, time.Minute, PollInterval(time.Minute, "SSHERPA_TEST_POLL_INTERVAL"))
+	assert.Equal(t, 30*time.Second, PollInterval(0, "SSHERPA_TEST_POLL_INTERVAL"))
+
+	t.Setenv("SSHERPA_TEST_POLL_INTERVAL", "soon")
+	assert.Equal(t, DefaultPollInterval, PollInterval(0, "SSHERPA_TEST_POLL_INTERVAL"))
+}
@@ -11,6 +11,8 @@ const (
 	StatusLocked                           // Backend is running but locked
 	StatusNotSignedIn                      // CLI not signed in (op CLI needs auth)
 	StatusUnavailable                      // Backend not running or SDK error
+	StatusTokenError                       // Service account or Connect token rejected (expired, revoked, no vault access)
+	StatusBehind                           // Local clone is behind its upstream (git inventory needs a pull)
 )
 
 // String returns the string representation of the status.
@@ -26,6 +28,10 @@ func (s BackendStatus) String() string {
 		return "NotSignedIn"
 	case StatusUnavailable:
 		return "Unavailable"
+	case StatusTokenError:
+		return "TokenError"
+	case StatusBehind:
+		return "Behind"
 	default:
 		return "Unknown"
 	}
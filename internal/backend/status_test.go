@@ -12,6 +12,8 @@ func TestBackendStatus_String_AllValues(t *testing.T) {
 	assert.Equal(t, "Locked", StatusLocked.String())
 	assert.Equal(t, "NotSignedIn", StatusNotSignedIn.String())
 	assert.Equal(t, "Unavailable", StatusUnavailable.String())
+	assert.Equal(t, "TokenError", StatusTokenError.String())
+	assert.Equal(t, "Behind", StatusBehind.String())
 }
 
 func TestBackendStatus_String_UnknownValue(t *testing.T) {
@@ -0,0 +1,464 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// DefaultPath is the path under the mount holding servers when none is configured.
+const DefaultPath = "ssh"
+
+// Backend implements the backendpkg.Backend, backendpkg.Writer,
+// backendpkg.Syncer and backendpkg.Filterer interfaces on a Vault KV v2
+// mount. Every secret below the base path is a server.
+type Backend struct {
+	client    Client                   // KV v2 client (HTTP or fake)
+	basePath  string                   // Path under the mount holding servers
+	mu        sync.RWMutex             // Protects cached servers, status, and closed flag
+	servers   []*domain.Server         // Cached servers from last sync
+	skipped   []SkippedItem            // Secrets the last sync couldn't convert
+	closed    bool                     // Backend closed flag
+	status    backendpkg.BackendStatus // Current availability status
+	cachePath string                   // Path to TOML cache for fallback
+	poller    *backendpkg.Poller       // Background availability poller
+	lastWrite time.Time                // Last write timestamp for debouncing
+	lastStats SyncStats                // Counters from the last successful sync
+}
+
+// Compile-time interface verification
+var (
+	_ backendpkg.Backend  = (*Backend)(nil)
+	_ backendpkg.Writer   = (*Backend)(nil)
+	_ backendpkg.Syncer   = (*Backend)(nil)
+	_ backendpkg.Filterer = (*Backend)(nil)
+)
+
+// New creates a new Vault backend for the secrets under basePath
+// (DefaultPath when empty).
+// No initial sync is performed - caller should call SyncFromVault to populate cache.
+func New(client Client, basePath string) *Backend {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		basePath = DefaultPath
+	}
+	return &Backend{
+		client:   client,
+		basePath: basePath,
+		servers:  make([]*domain.Server, 0),
+		status:   backendpkg.StatusUnknown,
+	}
+}
+
+// NewWithCache creates a new Vault backend with cache path for offline fallback.
+func NewWithCache(client Client, basePath, cachePath string) *Backend {
+	b := New(client, basePath)
+	b.cachePath = cachePath
+	return b
+}
+
+// checkClosed returns ErrBackendUnavailable if backend is closed.
+// Must be called with mu held (either RLock or Lock).
+func (b *Backend) checkClosed() error {
+	if b.closed {
+		return &errors.BackendError{
+			Op:      "checkClosed",
+			Backend: "vault",
+			Err:     errors.ErrBackendUnavailable,
+		}
+	}
+	return nil
+}
+
+// ListServers returns all cached servers.
+func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	// Return copies (copy-on-read pattern)
+	result := make([]*domain.Server, len(b.servers))
+	for i, s := range b.servers {
+		serverCopy := *s
+		result[i] = &serverCopy
+	}
+	return result, nil
+}
+
+// GetServer retrieves a server by ID (its secret path) from the cache.
+func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if i := b.indexOf(id); i >= 0 {
+		serverCopy := *b.servers[i]
+		return &serverCopy, nil
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetServer",
+		Backend: "vault",
+		Err:     errors.ErrServerNotFound,
+	}
+}
+
+// FilterServers returns the cached servers matching the filter.
+func (b *Backend) FilterServers(ctx context.Context, filters backendpkg.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backendpkg.ApplyFilter(servers, filters), nil
+}
+
+// ListProjects returns an empty slice (projects are paths in Vault, not standalone entities).
+func (b *Backend) ListProjects(ctx context.Context) ([]*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return []*domain.Project{}, nil
+}
+
+// GetProject returns ErrProjectNotFound (projects are paths, not standalone entities).
+func (b *Backend) GetProject(ctx context.Context, id string) (*domain.Project, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetProject",
+		Backend: "vault",
+		Err:     errors.ErrProjectNotFound,
+	}
+}
+
+// ListCredentials returns an empty slice (keys are referenced by identity_file).
+func (b *Backend) ListCredentials(ctx context.Context) ([]*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return []*domain.Credential{}, nil
+}
+
+// GetCredential returns ErrCredentialNotFound (credentials are not stored as secrets).
+func (b *Backend) GetCredential(ctx context.Context, id string) (*domain.Credential, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	return nil, &errors.BackendError{
+		Op:      "GetCredential",
+		Backend: "vault",
+		Err:     errors.ErrCredentialNotFound,
+	}
+}
+
+// Close stops the poller and marks the backend as closed.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+
+	if b.poller != nil {
+		poller := b.poller
+		b.poller = nil
+		b.mu.Unlock() // Unlock before calling Stop() to avoid deadlock
+		poller.Stop()
+		b.mu.Lock() // Re-lock for closed flag update
+	}
+
+	b.closed = true
+	b.mu.Unlock()
+	return nil
+}
+
+// CreateServer writes a new secret at <base path>/<first project>/<display name>.
+// The write only succeeds if no secret exists there yet.
+// server.ID is set to the secret path.
+func (b *Backend) CreateServer(ctx context.Context, server *domain.Server) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	project := ""
+	if len(server.ProjectIDs) > 0 {
+		project = server.ProjectIDs[0]
+	}
+	id := secretPath(b.basePath, project, server.DisplayName)
+
+	created, err := b.write(ctx, "CreateServer", server, id, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	server.ID = id
+	b.servers = append(b.servers, created)
+
+	// Update last write timestamp
+	b.lastWrite = time.Now()
+
+	return nil
+}
+
+// UpdateServer writes a new version of a server's secret, keeping keys
+// ssherpa doesn't manage. The write is checked against the version of the
+// last sync, so a change someone else made in between is never overwritten.
+// A new display name, or dropping the project the path names, moves the
+// server: the secret is written at the new path and the old one deleted.
+func (b *Backend) UpdateServer(ctx context.Context, server *domain.Server) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	index := b.indexOf(server.ID)
+	if index < 0 {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "vault",
+			Err:     errors.ErrServerNotFound,
+		}
+	}
+
+	// Get existing secret to preserve keys we don't manage
+	existing, err := b.client.Read(ctx, server.ID)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "vault",
+			Err:     err,
+		}
+	}
+
+	version, _ := strconv.Atoi(b.servers[index].Revision)
+	if version == 0 {
+		version = existing.Version
+	}
+
+	id := b.targetPath(server)
+	if id == server.ID {
+		updated, err := b.write(ctx, "UpdateServer", server, id, existing.Data, version)
+		if err != nil {
+			return err
+		}
+		b.servers[index] = updated
+		b.lastWrite = time.Now()
+		return nil
+	}
+
+	if existing.Version != version {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "vault",
+			Err:     fmt.Errorf("secret %s changed in Vault since the last sync", server.ID),
+		}
+	}
+	moved, err := b.write(ctx, "UpdateServer", server, id, existing.Data, 0)
+	if err != nil {
+		return err
+	}
+	if err := b.client.Delete(ctx, server.ID); err != nil {
+		return &errors.BackendError{
+			Op:      "UpdateServer",
+			Backend: "vault",
+			Err:     err,
+		}
+	}
+
+	server.ID = id
+	b.servers[index] = moved
+	b.lastWrite = time.Now()
+	return nil
+}
+
+// write stores server at id with check-and-set version cas and returns the
+// server as cached. Must be called with mu held.
+func (b *Backend) write(ctx context.Context, op string, server *domain.Server, id string, existing map[string]any, cas int) (*domain.Server, error) {
+	data := ServerToData(server, id, b.basePath, existing)
+
+	// Round-trip before writing so a secret missing hostname/user is never stored
+	written, err := SecretToServer(id, b.basePath, &Secret{Data: data})
+	if err != nil {
+		return nil, &errors.BackendError{
+			Op:      op,
+			Backend: "vault",
+			Err:     err,
+		}
+	}
+
+	version, err := b.client.Write(ctx, id, data, cas)
+	if err != nil && cas == 0 && IsCASMismatch(err) {
+		// A soft-deleted secret still has versions; writing over it is fine
+		if meta, metaErr := b.client.ReadMetadata(ctx, id); metaErr == nil && meta.Deleted {
+			version, err = b.client.Write(ctx, id, data, meta.CurrentVersion)
+		}
+	}
+	if err != nil {
+		switch {
+		case IsCASMismatch(err) && cas == 0:
+			err = errors.ErrDuplicateID
+		case IsCASMismatch(err):
+			err = fmt.Errorf("secret %s changed in Vault since the last sync: %w", id, err)
+		}
+		return nil, &errors.BackendError{
+			Op:      op,
+			Backend: "vault",
+			Err:     err,
+		}
+	}
+
+	written.Revision = strconv.Itoa(version)
+	return written, nil
+}
+
+// targetPath returns where a server's secret belongs: the directory stays
+// while the server still belongs to the project it names, and the last
+// element follows the display name.
+func (b *Backend) targetPath(server *domain.Server) string {
+	project := pathProject(server.ID, b.basePath)
+	if project != "" && !slices.Contains(server.ProjectIDs, project) {
+		project = ""
+		if len(server.ProjectIDs) > 0 {
+			project = server.ProjectIDs[0]
+		}
+	}
+	return secretPath(b.basePath, project, server.DisplayName)
+}
+
+// DeleteServer soft-deletes the current version of a server's secret;
+// "vault kv undelete" brings it back.
+func (b *Backend) DeleteServer(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkClosed(); err != nil {
+		return err
+	}
+
+	index := b.indexOf(id)
+	if index < 0 {
+		return &errors.BackendError{
+			Op:      "DeleteServer",
+			Backend: "vault",
+			Err:     errors.ErrServerNotFound,
+		}
+	}
+
+	if err := b.client.Delete(ctx, id); err != nil {
+		return &errors.BackendError{
+			Op:      "DeleteServer",
+			Backend: "vault",
+			Err:     err,
+		}
+	}
+
+	// Remove from cache
+	b.servers = append(b.servers[:index], b.servers[index+1:]...)
+
+	// Update last write timestamp
+	b.lastWrite = time.Now()
+
+	return nil
+}
+
+// indexOf returns the cache index of the server with id, or -1.
+// Must be called with mu held.
+func (b *Backend) indexOf(id string) int {
+	for i, server := range b.servers {
+		if server.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// CreateProject returns ErrReadOnlyBackend (projects are paths, not standalone entities).
+func (b *Backend) CreateProject(ctx context.Context, project *domain.Project) error {
+	return &errors.BackendError{
+		Op:      "CreateProject",
+		Backend: "vault",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// UpdateProject returns ErrReadOnlyBackend (projects are paths, not standalone entities).
+func (b *Backend) UpdateProject(ctx context.Context, project *domain.Project) error {
+	return &errors.BackendError{
+		Op:      "UpdateProject",
+		Backend: "vault",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// DeleteProject returns ErrReadOnlyBackend (projects are paths, not standalone entities).
+func (b *Backend) DeleteProject(ctx context.Context, id string) error {
+	return &errors.BackendError{
+		Op:      "DeleteProject",
+		Backend: "vault",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// CreateCredential returns ErrReadOnlyBackend (credentials are not stored as secrets).
+func (b *Backend) CreateCredential(ctx context.Context, cred *domain.Credential) error {
+	return &errors.BackendError{
+		Op:      "CreateCredential",
+		Backend: "vault",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// UpdateCredential returns ErrReadOnlyBackend (credentials are not stored as secrets).
+func (b *Backend) UpdateCredential(ctx context.Context, cred *domain.Credential) error {
+	return &errors.BackendError{
+		Op:      "UpdateCredential",
+		Backend: "vault",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
+
+// DeleteCredential returns ErrReadOnlyBackend (credentials are not stored as secrets).
+func (b *Backend) DeleteCredential(ctx context.Context, id string) error {
+	return &errors.BackendError{
+		Op:      "DeleteCredential",
+		Backend: "vault",
+		Err:     errors.ErrReadOnlyBackend,
+	}
+}
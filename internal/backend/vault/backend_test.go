@@ -0,0 +1,246 @@
+package vault
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// seededVault returns a fake Vault with two servers, one secret without a
+// hostname, one soft-deleted secret and a secret outside the base path.
+func seededVault(t *testing.T) *fakeVault {
+	f := newFakeVault(t)
+	f.put("ssh/payments/web", map[string]any{"hostname": "web.example.com", "user": "deploy", "password": "s3cret"})
+	f.put("ssh/db", map[string]any{"hostname": "db.internal", "user": "root"})
+	f.put("ssh/broken", map[string]any{"user": "root"})
+	f.put("ssh/gone", map[string]any{"hostname": "gone", "user": "root"})
+	f.secrets["ssh/gone"][0].deleted = true
+	f.put("apps/api", map[string]any{"hostname": "api", "user": "app"})
+	return f
+}
+
+func syncedBackend(t *testing.T, f *fakeVault) *Backend {
+	t.Helper()
+	b := New(newTestClient(t, f), "")
+	require.NoError(t, b.SyncFromVault(context.Background()))
+	return b
+}
+
+func TestSyncFromVault(t *testing.T) {
+	f := seededVault(t)
+	cachePath := filepath.Join(t.TempDir(), "cache.toml")
+	b := NewWithCache(newTestClient(t, f), "/ssh/", cachePath)
+	ctx := context.Background()
+
+	assert.Equal(t, backendpkg.StatusUnknown, b.GetStatus())
+	require.NoError(t, b.SyncFromBackend(ctx))
+	assert.Equal(t, backendpkg.StatusAvailable, b.GetStatus())
+
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, "ssh/db", servers[0].ID)
+	assert.Equal(t, "ssh/payments/web", servers[1].ID)
+	assert.Equal(t, []string{"payments"}, servers[1].ProjectIDs)
+	assert.Equal(t, "1", servers[1].Revision)
+
+	skipped := b.SkippedItems()
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "ssh/broken", skipped[0].Name)
+
+	// The cache keeps versions, so a restarted backend reads nothing unchanged
+	restarted := NewWithCache(newTestClient(t, f), "ssh", cachePath)
+	require.NoError(t, restarted.LoadFromCache())
+	reads := f.reads()
+	require.NoError(t, restarted.SyncFromVault(ctx))
+	assert.Equal(t, reads+1, f.reads()) // only the invalid secret again
+	assert.Equal(t, 2, restarted.LastSyncStats().Reused)
+}
+
+func TestSyncFromVault_ChangeDetection(t *testing.T) {
+	f := seededVault(t)
+	b := syncedBackend(t, f)
+	ctx := context.Background()
+	assert.Equal(t, 3, b.LastSyncStats().Fetched)
+
+	f.put("ssh/db", map[string]any{"hostname": "db2.internal", "user": "root"})
+	require.NoError(t, b.SyncFromVault(ctx))
+
+	stats := b.LastSyncStats()
+	assert.Equal(t, 2, stats.Fetched) // ssh/db and the invalid ssh/broken
+	assert.Equal(t, 1, stats.Reused)
+	server, err := b.GetServer(ctx, "ssh/db")
+	require.NoError(t, err)
+	assert.Equal(t, "db2.internal", server.Host)
+	assert.Equal(t, "2", server.Revision)
+}
+
+func TestSyncFromVault_TokenRejected(t *testing.T) {
+	f := seededVault(t)
+	client, err := NewHTTPClient(Options{Address: f.server.URL, Token: "revoked"})
+	require.NoError(t, err)
+	b := New(client, "ssh")
+
+	require.Error(t, b.SyncFromVault(context.Background()))
+	assert.Equal(t, backendpkg.StatusTokenError, b.GetStatus())
+}
+
+func TestSyncFromVault_Unreachable(t *testing.T) {
+	f := seededVault(t)
+	client := newTestClient(t, f)
+	f.server.Close()
+	b := New(client, "ssh")
+
+	require.Error(t, b.SyncFromVault(context.Background()))
+	assert.Equal(t, backendpkg.StatusUnavailable, b.GetStatus())
+}
+
+func TestCreateServer(t *testing.T) {
+	f := seededVault(t)
+	b := syncedBackend(t, f)
+	ctx := context.Background()
+
+	server := &domain.Server{DisplayName: "api", Host: "api.example.com", User: "deploy", Port: 22, ProjectIDs: []string{"payments"}}
+	require.NoError(t, b.CreateServer(ctx, server))
+	assert.Equal(t, "ssh/payments/api", server.ID)
+
+	data, version := f.current("ssh/payments/api")
+	assert.Equal(t, 1, version)
+	assert.Equal(t, map[string]any{"hostname": "api.example.com", "user": "deploy"}, data)
+
+	got, err := b.GetServer(ctx, "ssh/payments/api")
+	require.NoError(t, err)
+	assert.Equal(t, "1", got.Revision)
+
+	err = b.CreateServer(ctx, &domain.Server{DisplayName: "api", Host: "h", User: "u", ProjectIDs: []string{"payments"}})
+	assert.ErrorIs(t, err, errors.ErrDuplicateID)
+
+	// A soft-deleted secret can be written over
+	require.NoError(t, b.CreateServer(ctx, &domain.Server{DisplayName: "gone", Host: "back", User: "root"}))
+	_, version = f.current("ssh/gone")
+	assert.Equal(t, 2, version)
+
+	err = b.CreateServer(ctx, &domain.Server{DisplayName: "nohost", User: "u"})
+	assert.ErrorContains(t, err, "hostname")
+}
+
+func TestUpdateServer_InPlace(t *testing.T) {
+	f := seededVault(t)
+	b := syncedBackend(t, f)
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	server.Port = 2222
+	require.NoError(t, b.UpdateServer(ctx, server))
+
+	data, version := f.current("ssh/payments/web")
+	assert.Equal(t, 2, version)
+	assert.Equal(t, "2222", data["port"])
+	assert.Equal(t, "s3cret", data["password"])
+
+	got, err := b.GetServer(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	assert.Equal(t, "2", got.Revision)
+	assert.Equal(t, 2222, got.Port)
+}
+
+func TestUpdateServer_ChangedSinceSync(t *testing.T) {
+	f := seededVault(t)
+	b := syncedBackend(t, f)
+	ctx := context.Background()
+
+	f.put("ssh/db", map[string]any{"hostname": "db.internal", "user": "admin"})
+
+	server, err := b.GetServer(ctx, "ssh/db")
+	require.NoError(t, err)
+	server.Port = 2222
+	err = b.UpdateServer(ctx, server)
+	assert.ErrorContains(t, err, "changed in Vault since the last sync")
+
+	data, version := f.current("ssh/db")
+	assert.Equal(t, 2, version)
+	assert.Equal(t, "admin", data["user"])
+}
+
+func TestUpdateServer_Moves(t *testing.T) {
+	f := seededVault(t)
+	b := syncedBackend(t, f)
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	server.DisplayName = "frontend"
+	server.ProjectIDs = []string{"shop"}
+	require.NoError(t, b.UpdateServer(ctx, server))
+	assert.Equal(t, "ssh/shop/frontend", server.ID)
+
+	data, _ := f.current("ssh/shop/frontend")
+	assert.Equal(t, "s3cret", data["password"])
+	assert.True(t, f.secrets["ssh/payments/web"][0].deleted)
+
+	_, err = b.GetServer(ctx, "ssh/payments/web")
+	assert.ErrorIs(t, err, errors.ErrServerNotFound)
+
+	// A fresh sync sees the same picture
+	require.NoError(t, b.SyncFromVault(ctx))
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, "ssh/shop/frontend", servers[1].ID)
+}
+
+func TestUpdateServer_MoveConflict(t *testing.T) {
+	f := seededVault(t)
+	b := syncedBackend(t, f)
+	ctx := context.Background()
+
+	server, err := b.GetServer(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	server.DisplayName = "db"
+	server.ProjectIDs = nil
+	assert.ErrorIs(t, b.UpdateServer(ctx, server), errors.ErrDuplicateID)
+	assert.False(t, f.secrets["ssh/payments/web"][0].deleted)
+}
+
+func TestDeleteServer(t *testing.T) {
+	f := seededVault(t)
+	b := syncedBackend(t, f)
+	ctx := context.Background()
+
+	require.NoError(t, b.DeleteServer(ctx, "ssh/db"))
+	assert.True(t, f.secrets["ssh/db"][0].deleted)
+	assert.ErrorIs(t, b.DeleteServer(ctx, "ssh/db"), errors.ErrServerNotFound)
+
+	require.NoError(t, b.SyncFromVault(ctx))
+	servers, err := b.ListServers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, servers, 1)
+}
+
+func TestReadOnlyEntities(t *testing.T) {
+	b := syncedBackend(t, seededVault(t))
+	ctx := context.Background()
+
+	assert.ErrorIs(t, b.CreateProject(ctx, &domain.Project{}), errors.ErrReadOnlyBackend)
+	assert.ErrorIs(t, b.UpdateCredential(ctx, &domain.Credential{}), errors.ErrReadOnlyBackend)
+
+	credentials, err := b.ListCredentials(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+}
+
+func TestClose(t *testing.T) {
+	b := syncedBackend(t, seededVault(t))
+	require.NoError(t, b.Close())
+
+	_, err := b.ListServers(context.Background())
+	assert.ErrorIs(t, err, errors.ErrBackendUnavailable)
+}
@@ -0,0 +1,374 @@
+// Package vault implements a backend on a HashiCorp Vault KV version 2
+// secrets engine: every secret under a base path such as "ssh/" is a server.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables read by NewHTTPClient when the address, token or
+// namespace is not configured. The names match the vault CLI's.
+const (
+	AddressEnv   = "VAULT_ADDR"
+	TokenEnv     = "VAULT_TOKEN"
+	NamespaceEnv = "VAULT_NAMESPACE"
+)
+
+// requestTimeout bounds a single Vault API request.
+const requestTimeout = 30 * time.Second
+
+// Client abstracts the KV v2 operations the backend needs, for testability.
+// Paths are relative to the mount and never start or end with a slash.
+type Client interface {
+	// List returns the keys directly under path; sub-directories end in "/".
+	// A missing path yields no keys.
+	List(ctx context.Context, path string) ([]string, error)
+	// ReadMetadata returns the version information of a secret.
+	ReadMetadata(ctx context.Context, path string) (*Metadata, error)
+	// Read returns the current version of a secret.
+	Read(ctx context.Context, path string) (*Secret, error)
+	// Write stores data as a new version of the secret. cas is the version
+	// the write expects to replace (0 = the secret must not exist yet).
+	Write(ctx context.Context, path string, data map[string]any, cas int) (int, error)
+	// Delete soft-deletes the current version; "vault kv undelete" restores it.
+	Delete(ctx context.Context, path string) error
+}
+
+// Metadata is the KV metadata of a secret.
+type Metadata struct {
+	CurrentVersion int
+	UpdatedTime    time.Time
+	Deleted        bool // current version is deleted or destroyed
+}
+
+// Secret is one version of a secret.
+type Secret struct {
+	Data    map[string]any
+	Version int
+}
+
+// Options configures an HTTPClient. Empty Address, Token and Namespace fall
+// back to VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE. With a RoleID the
+// client logs in through AppRole instead of using a token.
+type Options struct {
+	Address      string // e.g. "https://vault.example.com:8200"
+	Namespace    string // Vault Enterprise namespace
+	Mount        string // KV v2 mount (default "secret")
+	Token        string // Token auth
+	RoleID       string // AppRole role_id
+	SecretID     string // AppRole secret_id
+	AppRoleMount string // AppRole auth mount (default "approle")
+}
+
+// HTTPClient implements the Client interface against Vault's HTTP API.
+type HTTPClient struct {
+	baseURL      string
+	namespace    string
+	mount        string
+	roleID       string
+	secretID     string
+	appRoleMount string
+	httpClient   *http.Client
+
+	mu    sync.Mutex // Protects token
+	token string
+}
+
+// NewHTTPClient creates a client for the KV v2 mount described by opts.
+func NewHTTPClient(opts Options) (*HTTPClient, error) {
+	if opts.Address == "" {
+		opts.Address = os.Getenv(AddressEnv)
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = os.Getenv(NamespaceEnv)
+	}
+	if opts.Token == "" && opts.RoleID == "" {
+		opts.Token = os.Getenv(TokenEnv)
+	}
+	if opts.Mount == "" {
+		opts.Mount = "secret"
+	}
+	if opts.AppRoleMount == "" {
+		opts.AppRoleMount = "approle"
+	}
+
+	if opts.Address == "" {
+		return nil, fmt.Errorf("vault address not set (address or %s)", AddressEnv)
+	}
+	u, err := url.Parse(opts.Address)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid vault address %q", opts.Address)
+	}
+	if opts.RoleID != "" && opts.SecretID == "" {
+		return nil, fmt.Errorf("vault AppRole auth needs a secret_id")
+	}
+	if opts.Token == "" && opts.RoleID == "" {
+		return nil, fmt.Errorf("vault token not set (token, role_id or %s)", TokenEnv)
+	}
+
+	return &HTTPClient{
+		baseURL:      strings.TrimRight(opts.Address, "/"),
+		namespace:    opts.Namespace,
+		mount:        strings.Trim(opts.Mount, "/"),
+		roleID:       opts.RoleID,
+		secretID:     opts.SecretID,
+		appRoleMount: strings.Trim(opts.AppRoleMount, "/"),
+		httpClient:   &http.Client{Timeout: requestTimeout},
+		token:        opts.Token,
+	}, nil
+}
+
+// APIError is a non-2xx response from Vault.
+// 401 and 403 mean the token is invalid, expired or lacks a policy for the path.
+type APIError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *APIError) Error() string {
+	msg := strings.Join(e.Errors, "; ")
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("vault: %s (status %d)", msg, e.StatusCode)
+}
+
+// isStatus reports whether err is an APIError with the given status code.
+func isStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+// IsCASMismatch reports whether a write failed because the secret's current
+// version is not the one the write expected.
+func IsCASMismatch(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	for _, msg := range apiErr.Errors {
+		if strings.Contains(msg, "check-and-set") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAuthError reports whether Vault rejected the token or AppRole login.
+func IsAuthError(err error) bool {
+	return isStatus(err, http.StatusUnauthorized) || isStatus(err, http.StatusForbidden)
+}
+
+// do sends a request to the Vault API and decodes a JSON response into out
+// (if non-nil). With AppRole auth the client logs in on first use and once
+// more when Vault rejects an expired token.
+func (c *HTTPClient) do(ctx context.Context, method, path string, body, out any) error {
+	token, err := c.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.send(ctx, method, path, token, body, out)
+	if c.roleID != "" && isStatus(err, http.StatusForbidden) {
+		if token, err = c.login(ctx); err != nil {
+			return err
+		}
+		err = c.send(ctx, method, path, token, body, out)
+	}
+	return err
+}
+
+// send performs one HTTP request with the given token ("" = none).
+func (c *HTTPClient) send(ctx context.Context, method, path, token string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return &APIError{StatusCode: resp.StatusCode, Errors: apiErr.Errors}
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// currentToken returns the token, logging in through AppRole if there is none yet.
+func (c *HTTPClient) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	if token != "" || c.roleID == "" {
+		return token, nil
+	}
+	return c.login(ctx)
+}
+
+// login exchanges the AppRole role_id and secret_id for a client token.
+func (c *HTTPClient) login(ctx context.Context) (string, error) {
+	var resp struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	body := map[string]string{"role_id": c.roleID, "secret_id": c.secretID}
+	if err := c.send(ctx, http.MethodPost, "/v1/auth/"+escapePath(c.appRoleMount)+"/login", "", body, &resp); err != nil {
+		return "", fmt.Errorf("vault AppRole login failed: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault AppRole login returned no token")
+	}
+
+	c.mu.Lock()
+	c.token = resp.Auth.ClientToken
+	c.mu.Unlock()
+	return resp.Auth.ClientToken, nil
+}
+
+// kvPath returns the KV v2 endpoint ("data" or "metadata") for a secret path.
+func (c *HTTPClient) kvPath(endpoint, path string) string {
+	return "/v1/" + escapePath(c.mount) + "/" + endpoint + "/" + escapePath(path)
+}
+
+// escapePath escapes each element of a slash-separated path.
+func escapePath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// List returns the keys directly under path.
+func (c *HTTPClient) List(ctx context.Context, path string) ([]string, error) {
+	var resp struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	err := c.do(ctx, http.MethodGet, c.kvPath("metadata", path)+"/?list=true", nil, &resp)
+	if isStatus(err, http.StatusNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", path, err)
+	}
+	return resp.Data.Keys, nil
+}
+
+// kvVersion is the per-version metadata in KV v2 responses.
+type kvVersion struct {
+	Version      int    `json:"version"`
+	DeletionTime string `json:"deletion_time"`
+	Destroyed    bool   `json:"destroyed"`
+}
+
+// ReadMetadata returns the version information of a secret.
+func (c *HTTPClient) ReadMetadata(ctx context.Context, path string) (*Metadata, error) {
+	var resp struct {
+		Data struct {
+			CurrentVersion int                  `json:"current_version"`
+			UpdatedTime    time.Time            `json:"updated_time"`
+			Versions       map[string]kvVersion `json:"versions"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, c.kvPath("metadata", path), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to read metadata of %s: %w", path, err)
+	}
+
+	current := resp.Data.Versions[fmt.Sprint(resp.Data.CurrentVersion)]
+	return &Metadata{
+		CurrentVersion: resp.Data.CurrentVersion,
+		UpdatedTime:    resp.Data.UpdatedTime,
+		Deleted:        current.DeletionTime != "" || current.Destroyed,
+	}, nil
+}
+
+// Read returns the current version of a secret.
+func (c *HTTPClient) Read(ctx context.Context, path string) (*Secret, error) {
+	var resp struct {
+		Data struct {
+			Data     map[string]any `json:"data"`
+			Metadata kvVersion      `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, c.kvPath("data", path), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if resp.Data.Data == nil {
+		resp.Data.Data = make(map[string]any)
+	}
+	return &Secret{Data: resp.Data.Data, Version: resp.Data.Metadata.Version}, nil
+}
+
+// Write stores data as a new version of the secret and returns that version.
+func (c *HTTPClient) Write(ctx context.Context, path string, data map[string]any, cas int) (int, error) {
+	body := map[string]any{
+		"data":    data,
+		"options": map[string]int{"cas": cas},
+	}
+	var resp struct {
+		Data kvVersion `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, c.kvPath("data", path), body, &resp); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return resp.Data.Version, nil
+}
+
+// Delete soft-deletes the current version of a secret.
+func (c *HTTPClient) Delete(ctx context.Context, path string) error {
+	if err := c.do(ctx, http.MethodDelete, c.kvPath("data", path), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+	return nil
+}
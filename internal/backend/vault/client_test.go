@@ -0,0 +1,319 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault is an httptest stand-in for a Vault server with a KV v2 engine
+// mounted at "secret/" and AppRole auth at "approle/".
+type fakeVault struct {
+	mu        sync.Mutex
+	server    *httptest.Server
+	tokens    map[string]bool        // valid tokens
+	roleID    string                 // AppRole credentials
+	secretID  string                 //
+	logins    int                    // successful AppRole logins
+	secrets   map[string][]fakeEntry // path -> versions (index 0 = version 1)
+	dataReads int                    // GETs of secret data
+	namespace string                 // last X-Vault-Namespace seen
+}
+
+type fakeEntry struct {
+	data    map[string]any
+	deleted bool
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	t.Helper()
+	f := &fakeVault{
+		tokens:  map[string]bool{"root": true},
+		secrets: make(map[string][]fakeEntry),
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// newTestClient returns a token-auth client for the fake server.
+func newTestClient(t *testing.T, f *fakeVault) *HTTPClient {
+	t.Helper()
+	client, err := NewHTTPClient(Options{Address: f.server.URL, Token: "root"})
+	require.NoError(t, err)
+	return client
+}
+
+// put stores a new version of a secret, as "vault kv put" would.
+func (f *fakeVault) put(path string, data map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secrets[path] = append(f.secrets[path], fakeEntry{data: data})
+}
+
+// current returns the data of a secret's current version.
+func (f *fakeVault) current(path string) (map[string]any, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	versions := f.secrets[path]
+	if len(versions) == 0 {
+		return nil, 0
+	}
+	return versions[len(versions)-1].data, len(versions)
+}
+
+func (f *fakeVault) reads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dataReads
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeErrors(w http.ResponseWriter, status int, msgs ...string) {
+	writeJSON(w, status, map[string]any{"errors": msgs})
+}
+
+func (f *fakeVault) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.namespace = r.Header.Get("X-Vault-Namespace")
+
+	if r.URL.Path == "/v1/auth/approle/login" && r.Method == http.MethodPost {
+		var body struct {
+			RoleID   string `json:"role_id"`
+			SecretID string `json:"secret_id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if f.roleID == "" || body.RoleID != f.roleID || body.SecretID != f.secretID {
+			writeErrors(w, http.StatusBadRequest, "invalid role or secret ID")
+			return
+		}
+		f.logins++
+		token := fmt.Sprintf("approle-%d", f.logins)
+		f.tokens[token] = true
+		writeJSON(w, http.StatusOK, map[string]any{"auth": map[string]any{"client_token": token}})
+		return
+	}
+
+	if !f.tokens[r.Header.Get("X-Vault-Token")] {
+		writeErrors(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/") && r.URL.Query().Get("list") == "true":
+		f.list(w, strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/"), "/"))
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/"):
+		f.metadata(w, strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/"))
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		f.data(w, r, strings.TrimPrefix(r.URL.Path, "/v1/secret/data/"))
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+func (f *fakeVault) list(w http.ResponseWriter, dir string) {
+	seen := make(map[string]bool)
+	for path := range f.secrets {
+		rest, ok := strings.CutPrefix(path, dir+"/")
+		if !ok {
+			continue
+		}
+		if child, _, isDir := strings.Cut(rest, "/"); isDir {
+			seen[child+"/"] = true
+		} else {
+			seen[rest] = true
+		}
+	}
+	if len(seen) == 0 {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"keys": keys}})
+}
+
+func (f *fakeVault) metadata(w http.ResponseWriter, path string) {
+	versions, ok := f.secrets[path]
+	if !ok {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	meta := make(map[string]any, len(versions))
+	for i, v := range versions {
+		deletion := ""
+		if v.deleted {
+			deletion = "2024-01-01T00:00:00Z"
+		}
+		meta[fmt.Sprint(i+1)] = map[string]any{"deletion_time": deletion, "destroyed": false}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{
+		"current_version": len(versions),
+		"updated_time":    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"versions":        meta,
+	}})
+}
+
+func (f *fakeVault) data(w http.ResponseWriter, r *http.Request, path string) {
+	versions := f.secrets[path]
+	switch r.Method {
+	case http.MethodGet:
+		f.dataReads++
+		if len(versions) == 0 || versions[len(versions)-1].deleted {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{
+			"data":     versions[len(versions)-1].data,
+			"metadata": map[string]any{"version": len(versions)},
+		}})
+
+	case http.MethodPost:
+		var body struct {
+			Data    map[string]any `json:"data"`
+			Options struct {
+				CAS *int `json:"cas"`
+			} `json:"options"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if body.Options.CAS != nil && *body.Options.CAS != len(versions) {
+			writeErrors(w, http.StatusBadRequest, "check-and-set parameter did not match the current version")
+			return
+		}
+		f.secrets[path] = append(versions, fakeEntry{data: body.Data})
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"version": len(versions) + 1}})
+
+	case http.MethodDelete:
+		if len(versions) > 0 {
+			versions[len(versions)-1].deleted = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestNewHTTPClient_Config(t *testing.T) {
+	t.Setenv(AddressEnv, "")
+	t.Setenv(TokenEnv, "")
+	t.Setenv(NamespaceEnv, "")
+
+	_, err := NewHTTPClient(Options{Token: "t"})
+	assert.ErrorContains(t, err, AddressEnv)
+
+	_, err = NewHTTPClient(Options{Address: "vault.example.com", Token: "t"})
+	assert.ErrorContains(t, err, "invalid vault address")
+
+	_, err = NewHTTPClient(Options{Address: "https://vault.example.com"})
+	assert.ErrorContains(t, err, TokenEnv)
+
+	_, err = NewHTTPClient(Options{Address: "https://vault.example.com", RoleID: "role"})
+	assert.ErrorContains(t, err, "secret_id")
+
+	t.Setenv(AddressEnv, "https://vault.example.com/")
+	t.Setenv(TokenEnv, "env-token")
+	client, err := NewHTTPClient(Options{Mount: "/kv/"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://vault.example.com", client.baseURL)
+	assert.Equal(t, "env-token", client.token)
+	assert.Equal(t, "kv", client.mount)
+}
+
+func TestHTTPClient_RoundTrip(t *testing.T) {
+	f := newFakeVault(t)
+	client := newTestClient(t, f)
+	ctx := context.Background()
+
+	keys, err := client.List(ctx, "ssh")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	version, err := client.Write(ctx, "ssh/payments/web", map[string]any{"hostname": "web.example.com"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	_, err = client.Write(ctx, "ssh/db", map[string]any{"hostname": "db"}, 0)
+	require.NoError(t, err)
+
+	keys, err = client.List(ctx, "ssh")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "payments/"}, keys)
+
+	secret, err := client.Read(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	assert.Equal(t, 1, secret.Version)
+	assert.Equal(t, "web.example.com", secret.Data["hostname"])
+
+	// Check-and-set: 0 means "must not exist", N means "current version is N"
+	_, err = client.Write(ctx, "ssh/payments/web", map[string]any{"hostname": "x"}, 0)
+	assert.True(t, IsCASMismatch(err))
+	version, err = client.Write(ctx, "ssh/payments/web", map[string]any{"hostname": "x"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	require.NoError(t, client.Delete(ctx, "ssh/payments/web"))
+	meta, err := client.ReadMetadata(ctx, "ssh/payments/web")
+	require.NoError(t, err)
+	assert.Equal(t, 2, meta.CurrentVersion)
+	assert.True(t, meta.Deleted)
+
+	_, err = client.Read(ctx, "ssh/payments/web")
+	assert.True(t, isStatus(err, http.StatusNotFound))
+}
+
+func TestHTTPClient_TokenRejected(t *testing.T) {
+	f := newFakeVault(t)
+	client, err := NewHTTPClient(Options{Address: f.server.URL, Token: "expired", Namespace: "team-a"})
+	require.NoError(t, err)
+
+	_, err = client.List(context.Background(), "ssh")
+	require.Error(t, err)
+	assert.True(t, IsAuthError(err))
+	assert.Contains(t, err.Error(), "permission denied")
+	assert.Equal(t, "team-a", f.namespace)
+}
+
+func TestHTTPClient_AppRole(t *testing.T) {
+	f := newFakeVault(t)
+	f.roleID, f.secretID = "role", "secret"
+	f.put("ssh/web", map[string]any{"hostname": "web"})
+	client, err := NewHTTPClient(Options{Address: f.server.URL, RoleID: "role", SecretID: "secret"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.Read(ctx, "ssh/web")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.logins)
+
+	// The token expires: the client logs in again once
+	f.mu.Lock()
+	delete(f.tokens, "approle-1")
+	f.mu.Unlock()
+	_, err = client.Read(ctx, "ssh/web")
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.logins)
+
+	bad, err := NewHTTPClient(Options{Address: f.server.URL, RoleID: "role", SecretID: "wrong"})
+	require.NoError(t, err)
+	_, err = bad.List(ctx, "ssh")
+	assert.ErrorContains(t, err, "AppRole login failed")
+}
@@ -0,0 +1,248 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+// managedKeys are the secret keys ssherpa reads and writes. They match the
+// 1Password field names; other keys in a secret are left alone.
+var managedKeys = []string{
+	"hostname", "user", "port", "identity_file", "remote_project_path", "project_tags",
+	"tags", "proxy_jump", "vpn_required", "favorite", "ssh_key", "forward_agent", "extra_config",
+}
+
+// SecretToServer converts a secret to a domain.Server.
+// id is the secret's path under the mount; its last element is the display
+// name and the directories between basePath and it name the server's
+// project, so "ssh/payments/web" is the "web" server of project "payments".
+// The secret version becomes the server's Revision.
+// Returns error if required fields (hostname, user) are missing.
+func SecretToServer(id, basePath string, secret *Secret) (*domain.Server, error) {
+	server := &domain.Server{
+		ID:          id,
+		DisplayName: path.Base(id),
+		Port:        22, // default port
+		Source:      "vault",
+		Tags:        []string{},
+		Revision:    strconv.Itoa(secret.Version),
+	}
+	if project := pathProject(id, basePath); project != "" {
+		server.ProjectIDs = []string{project}
+	}
+
+	for _, key := range managedKeys {
+		raw, ok := secret.Data[key]
+		if !ok {
+			continue
+		}
+		value := fieldString(raw)
+
+		switch key {
+		case "hostname":
+			server.Host = value
+		case "user":
+			server.User = value
+		case "port":
+			if port, err := strconv.Atoi(value); err == nil {
+				server.Port = port
+			}
+		case "identity_file":
+			server.IdentityFile = value
+		case "remote_project_path":
+			server.RemoteProjectPath = value
+		case "project_tags":
+			for _, id := range fieldList(raw) {
+				if !slices.Contains(server.ProjectIDs, id) {
+					server.ProjectIDs = append(server.ProjectIDs, id)
+				}
+			}
+		case "tags":
+			server.Tags = append(server.Tags, fieldList(raw)...)
+		case "proxy_jump":
+			server.Proxy = value
+		case "vpn_required":
+			server.VPNRequired = parseBoolField(value)
+		case "favorite":
+			server.Favorite = parseBoolField(value)
+		case "ssh_key":
+			server.CredentialID = value
+		case "forward_agent":
+			if value != "" {
+				setSSHOption(server, "ForwardAgent", formatBoolField(parseBoolField(value)))
+			}
+		case "extra_config":
+			for _, line := range strings.Split(value, "\n") {
+				if option, optionValue, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+					setSSHOption(server, option, strings.TrimSpace(optionValue))
+				}
+			}
+		}
+	}
+
+	// Validate required fields
+	if server.Host == "" {
+		return nil, fmt.Errorf("secret %q missing required field: hostname", id)
+	}
+	if server.User == "" {
+		return nil, fmt.Errorf("secret %q missing required field: user", id)
+	}
+
+	return server, nil
+}
+
+// ServerToData renders a server as secret data for id. existing is the
+// secret's current data (nil for a new secret); keys ssherpa doesn't manage
+// are kept. Values are strings, as "vault kv put" writes them. The project
+// given by the path is not repeated in project_tags.
+func ServerToData(server *domain.Server, id, basePath string, existing map[string]any) map[string]any {
+	data := make(map[string]any, len(existing)+len(managedKeys))
+	for key, value := range existing {
+		if !slices.Contains(managedKeys, key) {
+			data[key] = value
+		}
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			data[key] = value
+		}
+	}
+
+	set("hostname", server.Host)
+	set("user", server.User)
+	if server.Port != 22 && server.Port != 0 {
+		set("port", strconv.Itoa(server.Port))
+	}
+	set("identity_file", server.IdentityFile)
+	set("remote_project_path", server.RemoteProjectPath)
+	project := pathProject(id, basePath)
+	var projectTags []string
+	for _, projectID := range server.ProjectIDs {
+		if projectID != project {
+			projectTags = append(projectTags, projectID)
+		}
+	}
+	set("project_tags", strings.Join(projectTags, ", "))
+	set("tags", strings.Join(server.Tags, ", "))
+	set("proxy_jump", server.Proxy)
+	if server.VPNRequired {
+		set("vpn_required", "true")
+	}
+	if server.Favorite {
+		set("favorite", "true")
+	}
+	set("ssh_key", server.CredentialID)
+
+	// ForwardAgent has its own key; every other option goes to extra_config
+	var extra []string
+	forwardAgent := false
+	for _, line := range server.SSHOptionLines() {
+		key, value, _ := strings.Cut(line, " ")
+		lower := strings.ToLower(value)
+		if !forwardAgent && strings.EqualFold(key, "ForwardAgent") && (lower == "yes" || lower == "no") {
+			forwardAgent = true
+			set("forward_agent", lower)
+			continue
+		}
+		extra = append(extra, line)
+	}
+	set("extra_config", strings.Join(extra, "\n"))
+
+	return data
+}
+
+// secretPath returns the secret path for a server: basePath, then project
+// (when given), then the display name with slashes replaced.
+func secretPath(basePath, project, displayName string) string {
+	base := strings.ReplaceAll(strings.TrimSpace(displayName), "/", "-")
+	return path.Join(basePath, project, base)
+}
+
+// pathProject returns the directories between basePath and the secret name,
+// or "" for secrets directly under basePath.
+func pathProject(id, basePath string) string {
+	rel := strings.TrimPrefix(id, basePath+"/")
+	dir := path.Dir(rel)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// fieldString renders a secret value as a string. "vault kv put" stores
+// strings, but secrets written as JSON may hold numbers or booleans.
+func fieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// fieldList parses a comma-separated string or a JSON array, dropping blanks.
+func fieldList(value any) []string {
+	var parts []string
+	if values, ok := value.([]any); ok {
+		for _, v := range values {
+			parts = append(parts, fieldString(v))
+		}
+	} else {
+		parts = strings.Split(fieldString(value), ",")
+	}
+
+	var list []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// setSSHOption appends value to the server's SSH options under key, reusing
+// the spelling of a keyword that is already present.
+func setSSHOption(server *domain.Server, key, value string) {
+	if server.SSHOptions == nil {
+		server.SSHOptions = make(map[string][]string)
+	}
+	for existing := range server.SSHOptions {
+		if strings.EqualFold(existing, key) {
+			key = existing
+			break
+		}
+	}
+	server.SSHOptions[key] = append(server.SSHOptions[key], value)
+}
+
+// parseBoolField interprets a yes/no style value.
+func parseBoolField(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// formatBoolField renders a boolean as the yes/no form ssh_config uses.
+func formatBoolField(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
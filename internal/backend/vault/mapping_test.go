@@ -0,0 +1,107 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+func TestSecretToServer(t *testing.T) {
+	secret := &Secret{
+		Version: 7,
+		Data: map[string]any{
+			"hostname":      "web.example.com",
+			"user":          "deploy",
+			"port":          "2222",
+			"identity_file": "~/.ssh/deploy",
+			"project_tags":  "shared, payments",
+			"tags":          "prod, eu",
+			"proxy_jump":    "bastion",
+			"vpn_required":  "yes",
+			"favorite":      true,
+			"forward_agent": "on",
+			"extra_config":  "LocalForward 8080 localhost:80\nLocalForward 9090 localhost:90",
+			"password":      "s3cret",
+		},
+	}
+
+	server, err := SecretToServer("ssh/payments/web", "ssh", secret)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ssh/payments/web", server.ID)
+	assert.Equal(t, "web", server.DisplayName)
+	assert.Equal(t, "web.example.com", server.Host)
+	assert.Equal(t, "deploy", server.User)
+	assert.Equal(t, 2222, server.Port)
+	assert.Equal(t, []string{"payments", "shared"}, server.ProjectIDs)
+	assert.Equal(t, []string{"prod", "eu"}, server.Tags)
+	assert.Equal(t, "bastion", server.Proxy)
+	assert.True(t, server.VPNRequired)
+	assert.True(t, server.Favorite)
+	assert.Equal(t, "vault", server.Source)
+	assert.Equal(t, "7", server.Revision)
+	assert.Equal(t, []string{"yes"}, server.SSHOptions["ForwardAgent"])
+	assert.Equal(t, []string{"8080 localhost:80", "9090 localhost:90"}, server.SSHOptions["LocalForward"])
+}
+
+func TestSecretToServer_JSONValues(t *testing.T) {
+	secret := &Secret{Data: map[string]any{
+		"hostname": "db.internal",
+		"user":     "root",
+		"port":     float64(5022),
+		"tags":     []any{"db", " ", "prod"},
+	}}
+
+	server, err := SecretToServer("ssh/db", "ssh", secret)
+	require.NoError(t, err)
+	assert.Equal(t, 5022, server.Port)
+	assert.Equal(t, []string{"db", "prod"}, server.Tags)
+	assert.Empty(t, server.ProjectIDs)
+}
+
+func TestSecretToServer_MissingFields(t *testing.T) {
+	_, err := SecretToServer("ssh/db", "ssh", &Secret{Data: map[string]any{"user": "root"}})
+	assert.ErrorContains(t, err, "hostname")
+
+	_, err = SecretToServer("ssh/db", "ssh", &Secret{Data: map[string]any{"hostname": "db"}})
+	assert.ErrorContains(t, err, "user")
+}
+
+func TestServerToData(t *testing.T) {
+	server := &domain.Server{
+		DisplayName: "web",
+		Host:        "web.example.com",
+		User:        "deploy",
+		Port:        2222,
+		ProjectIDs:  []string{"payments", "shared"},
+		Tags:        []string{"prod"},
+		Favorite:    true,
+		SSHOptions: map[string][]string{
+			"ForwardAgent": {"yes"},
+			"LocalForward": {"8080 localhost:80", "9090 localhost:90"},
+		},
+	}
+	existing := map[string]any{"hostname": "old", "proxy_jump": "old-bastion", "password": "s3cret"}
+
+	data := ServerToData(server, "ssh/payments/web", "ssh", existing)
+
+	assert.Equal(t, map[string]any{
+		"hostname":      "web.example.com",
+		"user":          "deploy",
+		"port":          "2222",
+		"project_tags":  "shared",
+		"tags":          "prod",
+		"favorite":      "true",
+		"forward_agent": "yes",
+		"extra_config":  "LocalForward 8080 localhost:80\nLocalForward 9090 localhost:90",
+		"password":      "s3cret",
+	}, data)
+
+	parsed, err := SecretToServer("ssh/payments/web", "ssh", &Secret{Data: data})
+	require.NoError(t, err)
+	assert.Equal(t, server.ProjectIDs, parsed.ProjectIDs)
+	assert.Equal(t, server.SSHOptions, parsed.SSHOptions)
+}
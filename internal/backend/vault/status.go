@@ -0,0 +1,257 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	backendpkg "github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// pollIntervalEnv overrides the default poll interval (e.g. "30s").
+const pollIntervalEnv = "SSHJESUS_VAULT_POLL_INTERVAL"
+
+// SyncStats describes the work done by one sync.
+type SyncStats struct {
+	Secrets  int // secrets found under the base path
+	Fetched  int // secrets read because their version changed
+	Reused   int // secrets whose cached server was kept
+	Duration time.Duration
+}
+
+// GetStatus returns the current backend status (thread-safe).
+func (b *Backend) GetStatus() backendpkg.BackendStatus {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.status
+}
+
+// setStatus updates the backend status (thread-safe).
+func (b *Backend) setStatus(s backendpkg.BackendStatus) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.status = s
+}
+
+// LastSyncStats returns the counters of the last successful sync (thread-safe).
+func (b *Backend) LastSyncStats() SyncStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastStats
+}
+
+// SyncFromBackend implements backend.Syncer.
+// It delegates to SyncFromVault for the actual sync logic.
+func (b *Backend) SyncFromBackend(ctx context.Context) error {
+	return b.SyncFromVault(ctx)
+}
+
+// SyncFromVault lists the secrets under the base path recursively and
+// loads them as servers. The KV metadata version is the change marker: a
+// secret is only read when its current version differs from the Revision
+// of the cached server, and soft-deleted secrets are left out.
+// On success: sets status to Available, populates cache, writes to TOML cache.
+// On error: sets status to TokenError or Unavailable.
+func (b *Backend) SyncFromVault(ctx context.Context) error {
+	return b.syncSecrets(ctx)
+}
+
+// syncSecrets does the work of SyncFromVault.
+func (b *Backend) syncSecrets(ctx context.Context) error {
+	start := time.Now()
+
+	ids, err := b.walk(ctx, b.basePath)
+	if err != nil {
+		return b.syncFailed(err)
+	}
+
+	// Servers from the last sync (or the TOML cache) by path
+	b.mu.RLock()
+	cached := make(map[string]*domain.Server, len(b.servers))
+	for _, server := range b.servers {
+		cached[server.ID] = server
+	}
+	b.mu.RUnlock()
+
+	stats := SyncStats{Secrets: len(ids)}
+	servers := make([]*domain.Server, 0, len(ids))
+	var skippedItems []SkippedItem // Track skipped secrets for debugging
+	for _, id := range ids {
+		meta, err := b.client.ReadMetadata(ctx, id)
+		if isStatus(err, http.StatusNotFound) {
+			continue // deleted since the listing
+		}
+		if err != nil {
+			return b.syncFailed(err)
+		}
+		if meta.Deleted {
+			continue
+		}
+
+		if server, ok := cached[id]; ok && server.Revision == strconv.Itoa(meta.CurrentVersion) {
+			serverCopy := *server
+			servers = append(servers, &serverCopy)
+			stats.Reused++
+			continue
+		}
+
+		secret, err := b.client.Read(ctx, id)
+		if isStatus(err, http.StatusNotFound) {
+			continue
+		}
+		if err != nil {
+			return b.syncFailed(err)
+		}
+		stats.Fetched++
+		server, err := SecretToServer(id, b.basePath, secret)
+		if err != nil {
+			skippedItems = append(skippedItems, SkippedItem{Name: id, Reason: err.Error()})
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	// Report skipped secrets to help debug missing entries
+	if len(skippedItems) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d Vault secrets under %s/ skipped due to validation errors:\n", len(skippedItems), b.basePath)
+		for _, skipped := range skippedItems {
+			fmt.Fprintf(os.Stderr, "  - %s: %s\n", skipped.Name, skipped.Reason)
+		}
+	}
+
+	stats.Duration = time.Since(start)
+
+	// Update cache
+	b.mu.Lock()
+	b.servers = servers
+	b.skipped = skippedItems
+	b.lastStats = stats
+	b.status = backendpkg.StatusAvailable
+	b.mu.Unlock()
+
+	// Write to TOML cache for offline fallback (keeps versions across restarts)
+	if b.cachePath != "" {
+		_ = sync.WriteTOMLCache(servers, b.cachePath)
+	}
+
+	return nil
+}
+
+// syncFailed records the status for a failed sync and wraps err.
+func (b *Backend) syncFailed(err error) error {
+	b.setStatus(classifyError(err))
+	return &errors.BackendError{
+		Op:      "SyncFromVault",
+		Backend: "vault",
+		Err:     err,
+	}
+}
+
+// walk returns the secret paths under path, depth first and sorted.
+func (b *Backend) walk(ctx context.Context, path string) ([]string, error) {
+	keys, err := b.client.List(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	var ids []string
+	for _, key := range keys {
+		if dir, ok := strings.CutSuffix(key, "/"); ok {
+			sub, err := b.walk(ctx, path+"/"+dir)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, sub...)
+			continue
+		}
+		ids = append(ids, path+"/"+key)
+	}
+	return ids, nil
+}
+
+// classifyError maps a failed Vault call to a backend status.
+func classifyError(err error) backendpkg.BackendStatus {
+	if IsAuthError(err) {
+		return backendpkg.StatusTokenError
+	}
+	return backendpkg.StatusUnavailable
+}
+
+// SkippedItem is a secret the last sync could not turn into a server.
+type SkippedItem struct {
+	Name   string // secret path
+	Reason string // validation error
+}
+
+// SkippedItems returns the secrets skipped by the last successful sync (thread-safe).
+func (b *Backend) SkippedItems() []SkippedItem {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]SkippedItem(nil), b.skipped...)
+}
+
+// LoadFromCache loads servers from the TOML cache file.
+// This is called when Vault is unreachable on startup; the cached versions
+// let the next sync skip unchanged secrets.
+func (b *Backend) LoadFromCache() error {
+	if b.cachePath == "" {
+		return &errors.BackendError{
+			Op:      "LoadFromCache",
+			Backend: "vault",
+			Err:     errors.New("cache path not set"),
+		}
+	}
+
+	servers, err := sync.ReadTOMLCache(b.cachePath)
+	if err != nil {
+		return &errors.BackendError{
+			Op:      "LoadFromCache",
+			Backend: "vault",
+			Err:     err,
+		}
+	}
+
+	b.mu.Lock()
+	b.servers = servers
+	b.mu.Unlock()
+
+	return nil
+}
+
+// StartPolling starts a background poller for this backend.
+// interval: how often to poll (use 0 to read from SSHJESUS_VAULT_POLL_INTERVAL env var, defaults to 5m)
+// onChange: optional callback invoked when status changes (nil = no callback)
+func (b *Backend) StartPolling(interval time.Duration, onChange func(backendpkg.BackendStatus)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Stop existing poller if any
+	if b.poller != nil {
+		b.poller.Stop()
+	}
+
+	b.poller = backendpkg.NewPoller(
+		backendpkg.PollInterval(interval, pollIntervalEnv),
+		b.syncSecrets,
+		b.GetStatus,
+		b.lastWriteTime,
+		onChange,
+	)
+	b.poller.Start()
+}
+
+// lastWriteTime returns the last write timestamp (thread-safe).
+func (b *Backend) lastWriteTime() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastWrite
+}
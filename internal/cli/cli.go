@@ -0,0 +1,263 @@
+// Package cli implements ssherpa's non-interactive subcommands.
+// Commands run against the same backend stack as the TUI (sshconfig, 1Password,
+// MultiBackend) but never touch the alternate screen, so they can be scripted.
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/history"
+	"github.com/florianriquelme/ssherpa/internal/ssh"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// Exit codes returned by App.Run.
+// Scripts can rely on these to distinguish failure modes without parsing stderr.
+const (
+	ExitOK         = 0 // command succeeded
+	ExitError      = 1 // unexpected failure (backend error, I/O, ssh failure)
+	ExitUsage      = 2 // invalid flags or arguments
+	ExitNotFound   = 3 // server, project, or credential does not exist
+	ExitReadOnly   = 4 // backend (or the server's source) does not support writes
+	ExitValidation = 5 // input failed domain validation
+	ExitVPN        = 6 // server requires a VPN and the pre-connect check failed
+	ExitAuth       = 7 // 1Password rejected the session or token (locked, signed out, expired, no vault access)
+)
+
+// errUsage marks errors caused by invalid command-line usage.
+var errUsage = errors.New("usage error")
+
+// command describes a single subcommand.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(a *App, ctx context.Context, args []string) error
+}
+
+// commands lists all subcommands in the order they appear in help output.
+var commands = []command{
+	{name: "list", usage: "list [servers|projects|credentials] [--format F] [--favorites] [--tags T] [--project ID] [--query Q]", summary: "List inventory (table, json, jsonl, toml, csv)", run: (*App).runList},
+	{name: "export", usage: "export [--format ini|yaml] [--output FILE] [--tags T] [--project ID]", summary: "Write servers as an Ansible inventory (projects become groups)", run: (*App).runExport},
+	{name: "show", usage: "show <alias>", summary: "Show a server's details", run: (*App).runShow},
+	{name: "resolve", usage: "resolve <alias>", summary: "Show the effective SSH options for an alias and where each comes from", run: (*App).runResolve},
+	{name: "sync", usage: "sync [--quiet] [--timeout D]", summary: "Refresh the 1Password cache and SSH include file (for cron)", run: (*App).runSync},
+	{name: "migrate", usage: "migrate", summary: "Tag existing 1Password Server and Login items for ssherpa (interactive)", run: (*App).runMigrate},
+	{name: "push", usage: "push [<alias>...|--all] [--vault V] [--comment-out] [--dry-run]", summary: "Create 1Password items for SSH config hosts (interactive without aliases)", run: (*App).runPush},
+	{name: "doctor", usage: "doctor [--fix] [--format table|json]", summary: "Check the SSH config, keys and 1Password sync for problems", run: (*App).runDoctor},
+	{name: "connect", usage: "connect <alias> [--path DIR] [--force]", summary: "Connect to a server via ssh", run: (*App).runConnect},
+	{name: "add", usage: "add <alias> --host HOST --user USER [flags]", summary: "Add a server", run: (*App).runAdd},
+	{name: "edit", usage: "edit <alias> [flags]", summary: "Change a server's fields", run: (*App).runEdit},
+	{name: "rm", usage: "rm <alias>", summary: "Remove a server", run: (*App).runRemove},
+}
+
+// IsCommand reports whether name is a known subcommand.
+// Used by main to decide between the CLI and the TUI.
+func IsCommand(name string) bool {
+	if name == "help" {
+		return true
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// App holds the dependencies shared by all subcommands.
+type App struct {
+	Backend     backend.Backend // Backend stack built from the user's config
+	HistoryPath string          // Connection history file (empty = don't record)
+	Config      *config.Config  // App config for VPN rules (optional)
+	SSHConfig   string          // Path to the user's SSH config, used by resolve and doctor
+	SSHInclude  string          // Generated 1Password include file (empty when 1Password isn't configured)
+	Stdout      io.Writer
+	Stderr      io.Writer
+
+	// Connect hands the terminal to ssh for the given alias (defaults to ssh.Run).
+	Connect func(alias string, opts ssh.Options) error
+
+	// SkippedItems returns the 1Password items the last sync skipped (optional).
+	// doctor reports them; main syncs first so the list is current.
+	SkippedItems func(ctx context.Context) []onepassword.SkippedItem
+
+	// Sync refreshes servers from 1Password and rewrites the TOML cache and
+	// generated SSH include file (nil when 1Password isn't configured).
+	// Authentication failures wrap errors.ErrAuthentication.
+	Sync func(ctx context.Context) (onepassword.SyncStats, error)
+
+	// Migrate runs the interactive 1Password migration wizard and returns the
+	// outcome per migrated item (nil when 1Password isn't configured).
+	Migrate func(ctx context.Context) ([]onepassword.MigrationResult, error)
+
+	// OnePassword is the backend push creates servers in (nil when 1Password
+	// isn't configured). Its servers are checked for alias conflicts.
+	OnePassword backend.Backend
+
+	// PushWizard runs the interactive push wizard and returns the outcome per
+	// pushed host (nil when 1Password isn't configured).
+	PushWizard func(ctx context.Context) ([]sync.PushResult, error)
+
+	// AfterWrite is called after a successful add, edit or rm (optional).
+	// main uses it to refresh the 1Password cache and generated SSH include file.
+	AfterWrite func(ctx context.Context) error
+}
+
+// Run dispatches args[0] to its subcommand and returns the process exit code.
+func (a *App) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 || args[0] == "help" {
+		a.printUsage()
+		return ExitOK
+	}
+
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		if err := c.run(a, ctx, args[1:]); err != nil {
+			// ssh already reported its own failure; just pass its status through
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return exitErr.ExitCode()
+			}
+			if err != flag.ErrHelp {
+				_, _ = fmt.Fprintf(a.Stderr, "Error: %v\n", err)
+			}
+			return ExitCode(err)
+		}
+		return ExitOK
+	}
+
+	_, _ = fmt.Fprintf(a.Stderr, "Error: unknown command %q\n", args[0])
+	a.printUsage()
+	return ExitUsage
+}
+
+// ExitCode maps an error to the exit code documented on the Exit* constants.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case err == flag.ErrHelp:
+		return ExitOK
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, errors.ErrServerNotFound),
+		errors.Is(err, errors.ErrProjectNotFound),
+		errors.Is(err, errors.ErrCredentialNotFound):
+		return ExitNotFound
+	case errors.Is(err, errors.ErrReadOnlyBackend):
+		return ExitReadOnly
+	case errors.Is(err, errors.ErrValidation), errors.Is(err, errors.ErrDuplicateID):
+		return ExitValidation
+	case errors.Is(err, errors.ErrVPNNotConnected):
+		return ExitVPN
+	case errors.Is(err, errors.ErrAuthentication):
+		return ExitAuth
+	default:
+		return ExitError
+	}
+}
+
+// printUsage writes the subcommand overview to stderr.
+func (a *App) printUsage() {
+	_, _ = fmt.Fprintln(a.Stderr, "Usage: ssherpa [flags] [command] [args]")
+	_, _ = fmt.Fprintln(a.Stderr, "")
+	_, _ = fmt.Fprintln(a.Stderr, "Without a command, ssherpa starts the interactive TUI.")
+	_, _ = fmt.Fprintln(a.Stderr, "")
+	_, _ = fmt.Fprintln(a.Stderr, "Commands:")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(a.Stderr, "  %-50s %s\n", c.usage, c.summary)
+	}
+}
+
+// newFlagSet creates a FlagSet that reports errors instead of exiting.
+func (a *App) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("ssherpa "+name, flag.ContinueOnError)
+	fs.SetOutput(a.Stderr)
+	return fs
+}
+
+// parseArgs parses flags that may appear before or after positional arguments
+// (e.g. "ssherpa add web --host x" as well as "ssherpa add --host x web").
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// requireAlias extracts exactly one positional alias argument.
+func requireAlias(positional []string, usage string) (string, error) {
+	if len(positional) != 1 {
+		return "", fmt.Errorf("%w: expected exactly one alias (usage: ssherpa %s)", errUsage, usage)
+	}
+	return positional[0], nil
+}
+
+// findServer resolves an alias to a server.
+// Matches DisplayName (the SSH alias) case-insensitively first, then falls back to ID.
+func (a *App) findServer(ctx context.Context, alias string) (*domain.Server, error) {
+	servers, err := a.Backend.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, srv := range servers {
+		if strings.EqualFold(srv.DisplayName, alias) {
+			return srv, nil
+		}
+	}
+	for _, srv := range servers {
+		if srv.ID == alias {
+			return srv, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", errors.ErrServerNotFound, alias)
+}
+
+// writer returns the backend's Writer, or ErrReadOnlyBackend if writes are unsupported.
+func (a *App) writer() (backend.Writer, error) {
+	w, ok := a.Backend.(backend.Writer)
+	if !ok {
+		return nil, errors.ErrReadOnlyBackend
+	}
+	return w, nil
+}
+
+// connect runs ssh for the alias and records the connection in history.
+func (a *App) connect(srv *domain.Server, opts ssh.Options) error {
+	if a.HistoryPath != "" {
+		// Ignore error — don't block connection for history failure
+		_ = history.RecordConnection(a.HistoryPath, srv.DisplayName, srv.Host, srv.User)
+	}
+
+	connectFn := a.Connect
+	if connectFn == nil {
+		connectFn = ssh.Run
+	}
+	return connectFn(srv.DisplayName, opts)
+}
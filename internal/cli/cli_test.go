@@ -0,0 +1,698 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/mock"
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/doctor"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/ssh"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readOnlyBackend hides the mock's Writer methods to simulate the sshconfig backend.
+type readOnlyBackend struct {
+	backend.Backend
+}
+
+// newTestApp creates an App backed by a seeded mock backend with captured output.
+func newTestApp(t *testing.T) (*App, *mock.Backend, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	b := mock.New()
+	b.Seed([]*domain.Server{
+		{ID: "web", DisplayName: "web", Host: "web.example.com", User: "deploy", Port: 22, Source: "1password"},
+		{ID: "db", DisplayName: "db", Host: "10.0.0.5", User: "postgres", Port: 5432, Proxy: "bastion", Source: "1password"},
+		{ID: "legacy", DisplayName: "legacy", Host: "legacy.example.com", User: "root", Port: 22, Source: "ssh-config"},
+	}, nil, nil)
+
+	var stdout, stderr bytes.Buffer
+	app := &App{
+		Backend: b,
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+	}
+	return app, b, &stdout, &stderr
+}
+
+func TestIsCommand(t *testing.T) {
+	for _, name := range []string{"list", "export", "show", "sync", "connect", "add", "edit", "rm", "help"} {
+		assert.True(t, IsCommand(name), name)
+	}
+	assert.False(t, IsCommand("deploy"))
+	assert.False(t, IsCommand(""))
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"usage", errUsage, ExitUsage},
+		{"server not found", errors.ErrServerNotFound, ExitNotFound},
+		{"wrapped not found", &errors.BackendError{Op: "GetServer", Backend: "mock", Err: errors.ErrServerNotFound}, ExitNotFound},
+		{"read-only", errors.ErrReadOnlyBackend, ExitReadOnly},
+		{"validation", errors.ErrValidation, ExitValidation},
+		{"duplicate", errors.ErrDuplicateID, ExitValidation},
+		{"vpn", fmt.Errorf("db: %w", errors.ErrVPNNotConnected), ExitVPN},
+		{"auth", fmt.Errorf("%w (TokenError): expired", errors.ErrAuthentication), ExitAuth},
+		{"other", errors.New("boom"), ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"deploy"})
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr.String(), "unknown command")
+}
+
+func TestList(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"list"})
+	require.Equal(t, ExitOK, code)
+
+	out := stdout.String()
+	assert.Contains(t, out, "NAME")
+	assert.Contains(t, out, "web.example.com")
+	assert.Contains(t, out, "5432")
+
+	// Sorted alphabetically: db before legacy before web
+	assert.Less(t, bytes.Index(stdout.Bytes(), []byte("db ")), bytes.Index(stdout.Bytes(), []byte("legacy ")))
+	assert.Less(t, bytes.Index(stdout.Bytes(), []byte("legacy ")), bytes.Index(stdout.Bytes(), []byte("web ")))
+}
+
+func TestShow(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"show", "DB"})
+	require.Equal(t, ExitOK, code)
+
+	out := stdout.String()
+	assert.Contains(t, out, "10.0.0.5")
+	assert.Contains(t, out, "postgres")
+	assert.Contains(t, out, "bastion")
+}
+
+func TestShow_NotFound(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"show", "nope"})
+	assert.Equal(t, ExitNotFound, code)
+	assert.Contains(t, stderr.String(), "nope")
+}
+
+func TestShow_MissingAlias(t *testing.T) {
+	app, _, _, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"show"})
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestConnect(t *testing.T) {
+	app, _, _, _ := newTestApp(t)
+	app.HistoryPath = filepath.Join(t.TempDir(), "history.json")
+
+	var connected string
+	app.Connect = func(alias string, opts ssh.Options) error {
+		connected = alias
+		return nil
+	}
+
+	code := app.Run(context.Background(), []string{"connect", "web"})
+	require.Equal(t, ExitOK, code)
+	assert.Equal(t, "web", connected)
+	assert.FileExists(t, app.HistoryPath)
+}
+
+func TestConnect_RemotePath(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"server default", []string{"connect", "web"}, "/var/www/app"},
+		{"override", []string{"connect", "web", "--path", "/tmp"}, "/tmp"},
+		{"skip", []string{"connect", "--path", "", "web"}, ""},
+		{"no remote path", []string{"connect", "db"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, b, _, _ := newTestApp(t)
+			require.NoError(t, b.UpdateServer(context.Background(), &domain.Server{
+				ID: "web", DisplayName: "web", Host: "web.example.com", User: "deploy",
+				RemoteProjectPath: "/var/www/app", Source: "1password",
+			}))
+
+			var got ssh.Options
+			app.Connect = func(alias string, opts ssh.Options) error {
+				got = opts
+				return nil
+			}
+
+			require.Equal(t, ExitOK, app.Run(context.Background(), tt.args))
+			assert.Equal(t, tt.want, got.RemotePath)
+		})
+	}
+}
+
+func TestConnect_VPNCheck(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		checkCommand  string
+		wantCode      int
+		wantConnected bool
+		wantStderr    string
+	}{
+		{"vpn up", []string{"connect", "legacy"}, "true", ExitOK, true, ""},
+		{"vpn down", []string{"connect", "legacy"}, "false", ExitVPN, false, "wg-quick up work"},
+		{"forced", []string{"connect", "legacy", "--force"}, "false", ExitOK, true, "connecting anyway"},
+		{"not required", []string{"connect", "web"}, "false", ExitOK, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, _, stderr := newTestApp(t)
+			// legacy is an SSH config host flagged via the ssherpa config
+			app.Config = &config.Config{
+				VPN:   config.VPNConfig{CheckCommand: tt.checkCommand, ConnectCommand: "wg-quick up work"},
+				Hosts: []config.HostConfig{{Alias: "legacy", VPNRequired: true}},
+			}
+
+			connected := false
+			app.Connect = func(alias string, opts ssh.Options) error {
+				connected = true
+				return nil
+			}
+
+			assert.Equal(t, tt.wantCode, app.Run(context.Background(), tt.args))
+			assert.Equal(t, tt.wantConnected, connected)
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestConnect_PropagatesSSHExitCode(t *testing.T) {
+	app, _, _, stderr := newTestApp(t)
+	app.Connect = func(alias string, opts ssh.Options) error {
+		return exec.Command("sh", "-c", "exit 255").Run()
+	}
+
+	code := app.Run(context.Background(), []string{"connect", "web"})
+	assert.Equal(t, 255, code)
+	assert.Empty(t, stderr.String())
+}
+
+func TestAdd(t *testing.T) {
+	app, b, stdout, _ := newTestApp(t)
+
+	var afterWriteCalled bool
+	app.AfterWrite = func(ctx context.Context) error {
+		afterWriteCalled = true
+		return nil
+	}
+
+	code := app.Run(context.Background(), []string{
+		"add", "cache", "--host", "cache.example.com", "--user", "redis", "--port", "2222", "--proxy", "bastion",
+	})
+	require.Equal(t, ExitOK, code)
+	assert.True(t, afterWriteCalled)
+	assert.Contains(t, stdout.String(), "Added cache")
+
+	srv, err := b.GetServer(context.Background(), "cache")
+	require.NoError(t, err)
+	assert.Equal(t, "cache.example.com", srv.Host)
+	assert.Equal(t, "redis", srv.User)
+	assert.Equal(t, 2222, srv.Port)
+	assert.Equal(t, "bastion", srv.Proxy)
+}
+
+func TestAdd_FlagsBeforeAlias(t *testing.T) {
+	app, b, _, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"add", "--host", "x.example.com", "--user", "me", "x"})
+	require.Equal(t, ExitOK, code)
+
+	_, err := b.GetServer(context.Background(), "x")
+	assert.NoError(t, err)
+}
+
+func TestAdd_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"missing host", []string{"add", "cache", "--user", "redis"}, ExitValidation},
+		{"missing user", []string{"add", "cache", "--host", "cache.example.com"}, ExitValidation},
+		{"invalid port", []string{"add", "cache", "--host", "h", "--user", "u", "--port", "70000"}, ExitValidation},
+		{"duplicate alias", []string{"add", "web", "--host", "h", "--user", "u"}, ExitValidation},
+		{"unknown flag", []string{"add", "cache", "--color", "red"}, ExitUsage},
+		{"no alias", []string{"add", "--host", "h", "--user", "u"}, ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, _, _ := newTestApp(t)
+			assert.Equal(t, tt.want, app.Run(context.Background(), tt.args))
+		})
+	}
+}
+
+func TestAdd_ReadOnlyBackend(t *testing.T) {
+	app, b, _, _ := newTestApp(t)
+	app.Backend = readOnlyBackend{b}
+
+	code := app.Run(context.Background(), []string{"add", "cache", "--host", "h", "--user", "u"})
+	assert.Equal(t, ExitReadOnly, code)
+}
+
+func TestEdit_ChangesOnlyGivenFields(t *testing.T) {
+	app, b, _, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"edit", "db", "--port", "6432"})
+	require.Equal(t, ExitOK, code)
+
+	srv, err := b.GetServer(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Equal(t, 6432, srv.Port)
+	assert.Equal(t, "10.0.0.5", srv.Host)
+	assert.Equal(t, "postgres", srv.User)
+	assert.Equal(t, "bastion", srv.Proxy)
+}
+
+func TestEdit_ClearField(t *testing.T) {
+	app, b, _, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"edit", "db", "--proxy", ""})
+	require.Equal(t, ExitOK, code)
+
+	srv, err := b.GetServer(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Empty(t, srv.Proxy)
+}
+
+func TestEdit_Tags(t *testing.T) {
+	app, b, _, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"edit", "db", "--tags", "prod, db,,Prod"})
+	require.Equal(t, ExitOK, code)
+
+	srv, err := b.GetServer(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod", "db"}, srv.Tags)
+
+	code = app.Run(context.Background(), []string{"edit", "db", "--tags", ""})
+	require.Equal(t, ExitOK, code)
+
+	srv, err = b.GetServer(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Empty(t, srv.Tags)
+}
+
+func TestEdit_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"not found", []string{"edit", "nope", "--port", "22"}, ExitNotFound},
+		{"nothing to change", []string{"edit", "web"}, ExitUsage},
+		{"clears required field", []string{"edit", "web", "--host", ""}, ExitValidation},
+		{"ssh-config host", []string{"edit", "legacy", "--port", "2222"}, ExitReadOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, _, _ := newTestApp(t)
+			assert.Equal(t, tt.want, app.Run(context.Background(), tt.args))
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	app, b, stdout, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"rm", "web"})
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout.String(), "Removed web")
+
+	_, err := b.GetServer(context.Background(), "web")
+	assert.True(t, errors.Is(err, errors.ErrServerNotFound))
+}
+
+func TestRemove_Errors(t *testing.T) {
+	app, b, _, _ := newTestApp(t)
+
+	assert.Equal(t, ExitNotFound, app.Run(context.Background(), []string{"rm", "nope"}))
+	assert.Equal(t, ExitReadOnly, app.Run(context.Background(), []string{"rm", "legacy"}))
+
+	app.Backend = readOnlyBackend{b}
+	assert.Equal(t, ExitReadOnly, app.Run(context.Background(), []string{"rm", "web"}))
+}
+
+func TestList_Format(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+
+	code := app.Run(context.Background(), []string{"list", "--format", "jsonl"})
+	require.Equal(t, ExitOK, code)
+
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Contains(t, string(lines[0]), `"display_name":"db"`)
+}
+
+func TestList_Kinds(t *testing.T) {
+	app, b, stdout, _ := newTestApp(t)
+	b.Seed(nil, []*domain.Project{{ID: "payments", Name: "Payments"}}, nil)
+
+	code := app.Run(context.Background(), []string{"list", "projects", "--format", "csv"})
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout.String(), "payments,Payments")
+
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"list", "widgets"}))
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"list", "--format", "yaml"}))
+}
+
+func TestList_Favorites(t *testing.T) {
+	app, b, stdout, _ := newTestApp(t)
+	require.NoError(t, b.UpdateServer(context.Background(), &domain.Server{
+		ID: "db", DisplayName: "db", Host: "10.0.0.5", User: "postgres", Port: 5432, Source: "1password", Favorite: true,
+	}))
+
+	code := app.Run(context.Background(), []string{"list", "--favorites", "--format", "jsonl"})
+	require.Equal(t, ExitOK, code)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"display_name":"db"`)
+
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"list", "projects", "--favorites"}))
+}
+
+func TestList_Filters(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		readOnly bool
+		want     []string
+	}{
+		{"tags flag", []string{"--tags", "prod"}, false, []string{"db", "web"}},
+		{"tags flag all required", []string{"--tags", "prod,db"}, false, []string{"db"}},
+		{"project", []string{"--project", "payments"}, false, []string{"web"}},
+		{"query", []string{"--query", "legacy"}, false, []string{"legacy"}},
+		{"tag in query", []string{"--query", "tag:PROD postgres"}, false, []string{"db"}},
+		{"no matches", []string{"--tags", "staging"}, false, []string{}},
+		{"without filterer", []string{"--tags", "prod", "--project", "payments"}, true, []string{"web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, b, stdout, _ := newTestApp(t)
+			b.Seed([]*domain.Server{
+				{ID: "web", DisplayName: "web", Host: "web.example.com", User: "deploy", Port: 22, Source: "1password", Tags: []string{"prod"}, ProjectIDs: []string{"payments"}},
+				{ID: "db", DisplayName: "db", Host: "10.0.0.5", User: "postgres", Port: 5432, Source: "1password", Tags: []string{"prod", "db"}},
+			}, nil, nil)
+			if tt.readOnly {
+				app.Backend = readOnlyBackend{b}
+			}
+
+			code := app.Run(context.Background(), append([]string{"list", "--format", "csv"}, tt.args...))
+			require.Equal(t, ExitOK, code)
+
+			rows := strings.Split(strings.TrimSpace(stdout.String()), "\n")[1:]
+			got := make([]string, len(rows))
+			for i, row := range rows {
+				got[i] = strings.SplitN(row, ",", 3)[1]
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	app, _, _, _ := newTestApp(t)
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"list", "projects", "--tags", "prod"}))
+}
+
+func TestExport(t *testing.T) {
+	app, b, stdout, _ := newTestApp(t)
+	b.Seed([]*domain.Server{
+		{ID: "api", DisplayName: "api", Host: "api.internal", User: "deploy", Port: 22, ProjectIDs: []string{"acme/api"}, Tags: []string{"prod"}, Source: "1password"},
+	}, []*domain.Project{{ID: "acme/api", Name: "API"}, {ID: "unused", Name: "Unused"}}, nil)
+
+	code := app.Run(context.Background(), []string{"export"})
+	require.Equal(t, ExitOK, code)
+	out := stdout.String()
+	assert.Contains(t, out, "db ansible_host=10.0.0.5 ansible_user=postgres ansible_port=5432 ansible_ssh_common_args=\"-o ProxyJump=bastion\"\n")
+	assert.Contains(t, out, "[acme_api]\napi ansible_host=api.internal ansible_user=deploy ssherpa_tags=prod\n")
+	assert.Contains(t, out, "[unused]\n")
+
+	// Filters narrow the hosts and groups
+	stdout.Reset()
+	code = app.Run(context.Background(), []string{"export", "--project", "acme/api", "--format", "yaml"})
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout.String(), "    acme_api:\n      hosts:\n        api:\n")
+	assert.NotContains(t, stdout.String(), "unused")
+	assert.NotContains(t, stdout.String(), "legacy")
+
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"export", "--format", "json"}))
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"export", "web"}))
+}
+
+func TestExport_Output(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	path := filepath.Join(t.TempDir(), "inventory.yml")
+
+	code := app.Run(context.Background(), []string{"export", "--output", path})
+	require.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "Exported 3 servers to "+path)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# Ansible inventory exported by ssherpa\n\nall:\n"), string(content))
+}
+
+func TestEdit_AnsibleHostReadOnly(t *testing.T) {
+	app, b, _, stderr := newTestApp(t)
+	b.Seed([]*domain.Server{{ID: "app1", DisplayName: "app1", Host: "app1", Port: 22, Source: "ansible"}}, nil, nil)
+
+	assert.Equal(t, ExitReadOnly, app.Run(context.Background(), []string{"edit", "app1", "--port", "2222"}))
+	assert.Contains(t, stderr.String(), "Ansible inventory")
+	assert.Equal(t, ExitReadOnly, app.Run(context.Background(), []string{"rm", "app1"}))
+}
+
+func TestResolve(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+	app.SSHConfig = filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(app.SSHConfig, []byte("Host web\n    HostName web.example.com\n\nHost *\n    User deploy\n    HostName ignored\n\nMatch exec \"true\"\n    Port 2222\n"), 0600))
+
+	code := app.Run(context.Background(), []string{"resolve", "web"})
+	require.Equal(t, ExitOK, code)
+
+	out := stdout.String()
+	assert.Contains(t, out, "web.example.com")
+	assert.NotContains(t, out, "ignored")
+	assert.Contains(t, out, "Host * ("+app.SSHConfig+":4)")
+	assert.Contains(t, out, `not evaluated: Match exec "true"`)
+}
+
+func TestResolve_Errors(t *testing.T) {
+	app, _, _, _ := newTestApp(t)
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"resolve"}))
+	assert.Equal(t, ExitError, app.Run(context.Background(), []string{"resolve", "web"}), "no SSH config path")
+
+	app.SSHConfig = filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(app.SSHConfig, []byte("Host web\n    HostName web.example.com\n"), 0600))
+	assert.Equal(t, ExitNotFound, app.Run(context.Background(), []string{"resolve", "db"}))
+}
+
+func TestSync(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	assert.Equal(t, ExitError, app.Run(context.Background(), []string{"sync"}))
+	assert.Contains(t, stderr.String(), "1Password is not configured")
+
+	app.Sync = func(ctx context.Context) (onepassword.SyncStats, error) {
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline, "sync runs with a timeout")
+		return onepassword.SyncStats{Items: 5, Fetched: 2, Reused: 2, Skipped: 1, DeniedVaults: []string{"Finance"}}, nil
+	}
+	stderr.Reset()
+	require.Equal(t, ExitOK, app.Run(context.Background(), []string{"sync"}), stderr.String())
+	assert.Contains(t, stdout.String(), "Synced 4 servers from 1Password")
+	assert.Contains(t, stdout.String(), "(2 fetched, 2 unchanged, 1 skipped)")
+	assert.Contains(t, stderr.String(), `no access to vault "Finance"`)
+
+	stdout.Reset()
+	require.Equal(t, ExitOK, app.Run(context.Background(), []string{"sync", "--quiet"}))
+	assert.Empty(t, stdout.String())
+
+	app.Sync = func(ctx context.Context) (onepassword.SyncStats, error) {
+		return onepassword.SyncStats{}, fmt.Errorf("%w (TokenError): token expired", errors.ErrAuthentication)
+	}
+	assert.Equal(t, ExitAuth, app.Run(context.Background(), []string{"sync"}))
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"sync", "extra"}))
+}
+
+func TestMigrate(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+
+	assert.Equal(t, ExitError, app.Run(context.Background(), []string{"migrate"}))
+	assert.Contains(t, stderr.String(), "1Password is not configured")
+
+	app.Migrate = func(ctx context.Context) ([]onepassword.MigrationResult, error) {
+		return []onepassword.MigrationResult{
+			{Title: "Web", VaultName: "Infra"},
+			{Title: "Bastion", VaultName: "Infra"},
+		}, nil
+	}
+	stderr.Reset()
+	require.Equal(t, ExitOK, app.Run(context.Background(), []string{"migrate"}), stderr.String())
+	assert.Contains(t, stdout.String(), "Migrated 2 1Password items")
+
+	app.Migrate = func(ctx context.Context) ([]onepassword.MigrationResult, error) {
+		return []onepassword.MigrationResult{
+			{Title: "Web", VaultName: "Infra"},
+			{Title: "Mail", VaultName: "Personal", Err: fmt.Errorf("no hostname found")},
+		}, nil
+	}
+	stdout.Reset()
+	assert.Equal(t, ExitError, app.Run(context.Background(), []string{"migrate"}))
+	assert.Contains(t, stdout.String(), "Migrated 1 1Password items")
+	assert.Contains(t, stderr.String(), `Failed to migrate "Mail" (Personal): no hostname found`)
+
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"migrate", "extra"}))
+}
+
+func TestPush(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	app.SSHConfig = filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(app.SSHConfig, []byte("Host web\n    HostName web.example.com\n    User deploy\n\nHost api\n    HostName api.example.com\n    User svc\n    ProxyJump bastion\n\nHost nouser\n    HostName nouser.example.com\n"), 0600))
+
+	assert.Equal(t, ExitError, app.Run(context.Background(), []string{"push", "api"}))
+	assert.Contains(t, stderr.String(), "1Password is not configured")
+
+	target := mock.New()
+	target.Seed([]*domain.Server{{ID: "web", DisplayName: "web", Host: "web.example.com", User: "deploy"}}, nil, nil)
+	app.OnePassword = target
+	writes := 0
+	app.AfterWrite = func(ctx context.Context) error {
+		writes++
+		return nil
+	}
+
+	stderr.Reset()
+	require.Equal(t, ExitOK, app.Run(context.Background(), []string{"push", "api", "--dry-run"}), stderr.String())
+	assert.Contains(t, stdout.String(), "Would push api (svc@api.example.com)")
+
+	stdout.Reset()
+	require.Equal(t, ExitOK, app.Run(context.Background(), []string{"push", "--all", "--vault", "Infra", "--comment-out"}), stderr.String())
+	assert.Contains(t, stdout.String(), "Pushed api and commented out its Host block")
+	assert.Contains(t, stdout.String(), "Pushed 1 hosts to 1Password")
+	assert.Contains(t, stderr.String(), "Skipping web")
+	assert.Contains(t, stderr.String(), "Skipping nouser")
+	assert.Equal(t, 1, writes)
+
+	api, err := target.GetServer(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Equal(t, "bastion", api.Proxy)
+	assert.Equal(t, "Infra", api.VaultID)
+
+	content, err := os.ReadFile(app.SSHConfig)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Host api\n")
+	assert.FileExists(t, app.SSHConfig+".bak")
+
+	stderr.Reset()
+	assert.Equal(t, ExitError, app.Run(context.Background(), []string{"push", "web"}))
+	assert.Contains(t, stderr.String(), "Failed to push web")
+	assert.Equal(t, ExitNotFound, app.Run(context.Background(), []string{"push", "missing"}))
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"push", "api", "--all"}))
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"push", "--vault", "Infra"}))
+
+	app.PushWizard = func(ctx context.Context) ([]sync.PushResult, error) {
+		return []sync.PushResult{{Alias: "web", Pushed: true}}, nil
+	}
+	stdout.Reset()
+	require.Equal(t, ExitOK, app.Run(context.Background(), []string{"push"}), stderr.String())
+	assert.Contains(t, stdout.String(), "Pushed 1 hosts to 1Password")
+}
+
+func TestDoctor(t *testing.T) {
+	app, _, stdout, stderr := newTestApp(t)
+	dir := t.TempDir()
+	app.SSHConfig = filepath.Join(dir, "config")
+	app.SSHInclude = filepath.Join(dir, "ssherpa_config")
+	require.NoError(t, os.WriteFile(app.SSHConfig, []byte("Host web\n    HostName web.example.com\n    Protocol 2\n"), 0600))
+	app.SkippedItems = func(ctx context.Context) []onepassword.SkippedItem {
+		return []onepassword.SkippedItem{{Title: "broken", VaultID: "v1", Reason: "hostname is required"}}
+	}
+
+	code := app.Run(context.Background(), []string{"doctor"})
+	require.Equal(t, ExitOK, code, stderr.String())
+	out := stdout.String()
+	assert.Contains(t, out, "deprecated-option")
+	assert.Contains(t, out, "ssherpa-include")
+	assert.Contains(t, out, "1password-skipped")
+	assert.Contains(t, out, "ssherpa doctor --fix")
+
+	stdout.Reset()
+	code = app.Run(context.Background(), []string{"doctor", "--fix", "--format", "json"})
+	require.Equal(t, ExitOK, code, stderr.String())
+	var findings []doctor.Finding
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &findings))
+	fixed := 0
+	for _, f := range findings {
+		if f.Fixed {
+			fixed++
+			assert.Equal(t, doctor.CheckInclude, f.Check)
+		}
+	}
+	assert.Equal(t, 1, fixed)
+
+	content, err := os.ReadFile(app.SSHConfig)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# ssherpa 1Password integration\nInclude "+app.SSHInclude))
+}
+
+func TestDoctor_ErrorsExitNonZero(t *testing.T) {
+	app, _, stdout, _ := newTestApp(t)
+	app.SSHConfig = filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(app.SSHConfig, []byte("Include config\n"), 0600))
+
+	assert.Equal(t, ExitError, app.Run(context.Background(), []string{"doctor"}))
+	assert.Contains(t, stdout.String(), "parse-error")
+	assert.Equal(t, ExitUsage, app.Run(context.Background(), []string{"doctor", "--format", "xml"}))
+}
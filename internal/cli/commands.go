@@ -0,0 +1,903 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/renameio/v2/maybe"
+
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/ansible"
+	"github.com/florianriquelme/ssherpa/internal/doctor"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/output"
+	"github.com/florianriquelme/ssherpa/internal/ssh"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+	"github.com/florianriquelme/ssherpa/internal/vpn"
+)
+
+// runList prints servers (default), projects or credentials in the requested format.
+func (a *App) runList(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("list")
+	formatFlag := fs.String("format", string(output.FormatTable), "Output format: "+formatNames())
+	favorites := fs.Bool("favorites", false, "Only list favorite servers")
+	tags := fs.String("tags", "", "Only list servers with all of these comma-separated tags")
+	projectID := fs.String("project", "", "Only list servers in this project ID")
+	query := fs.String("query", "", `Fuzzy search across name, host, user and tags ("tag:<name>" filters by tag)`)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	format, err := output.ParseFormat(*formatFlag)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	kind := "servers"
+	if len(positional) > 1 {
+		return fmt.Errorf("%w: list takes at most one argument (servers, projects, credentials)", errUsage)
+	}
+	if len(positional) == 1 {
+		kind = positional[0]
+	}
+	if kind != "servers" {
+		for _, name := range []string{"favorites", "tags", "project", "query"} {
+			if isFlagSet(fs, name) {
+				return fmt.Errorf("%w: --%s only applies to servers", errUsage, name)
+			}
+		}
+	}
+
+	switch kind {
+	case "servers":
+		filter := backend.ParseQuery(*query)
+		filter.ProjectID = *projectID
+		if *tags != "" {
+			filter.Tags = append(filter.Tags, splitTags(*tags)...)
+		}
+		if *favorites {
+			filter.Favorite = favorites
+		}
+		servers, err := a.filterServers(ctx, filter)
+		if err != nil {
+			return err
+		}
+		sortServers(servers)
+		return output.WriteServers(a.Stdout, format, servers)
+
+	case "projects":
+		projects, err := a.Backend.ListProjects(ctx)
+		if err != nil {
+			return err
+		}
+		sort.Slice(projects, func(i, j int) bool {
+			return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
+		})
+		return output.WriteProjects(a.Stdout, format, projects)
+
+	case "credentials":
+		creds, err := a.Backend.ListCredentials(ctx)
+		if err != nil {
+			return err
+		}
+		sort.Slice(creds, func(i, j int) bool {
+			return strings.ToLower(creds[i].Name) < strings.ToLower(creds[j].Name)
+		})
+		return output.WriteCredentials(a.Stdout, format, creds)
+
+	default:
+		return fmt.Errorf("%w: unknown list kind %q (servers, projects, credentials)", errUsage, kind)
+	}
+}
+
+// runExport writes the servers of all backends as an Ansible inventory, to
+// stdout or atomically to --output. Projects become groups.
+func (a *App) runExport(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("export")
+	formatFlag := fs.String("format", "", "Inventory format: ini, yaml (default: from the --output extension, else ini)")
+	outputPath := fs.String("output", "", "Write to this file instead of stdout")
+	tags := fs.String("tags", "", "Only export servers with all of these comma-separated tags")
+	projectID := fs.String("project", "", "Only export servers in this project ID")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("%w: export takes no arguments", errUsage)
+	}
+
+	name := *formatFlag
+	if name == "" {
+		name = string(ansible.FormatINI)
+		switch strings.ToLower(filepath.Ext(*outputPath)) {
+		case ".yml", ".yaml":
+			name = string(ansible.FormatYAML)
+		}
+	}
+	format, err := ansible.ParseFormat(name)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	filter := backend.ServerFilter{ProjectID: *projectID}
+	if *tags != "" {
+		filter.Tags = splitTags(*tags)
+	}
+	servers, err := a.filterServers(ctx, filter)
+	if err != nil {
+		return err
+	}
+	sortServers(servers)
+
+	projects, err := a.Backend.ListProjects(ctx)
+	if err != nil {
+		return err
+	}
+	if *projectID != "" || *tags != "" {
+		// Only the groups of the exported servers
+		used := make(map[string]bool)
+		for _, srv := range servers {
+			for _, id := range srv.ProjectIDs {
+				used[id] = true
+			}
+		}
+		projects = slices.DeleteFunc(projects, func(p *domain.Project) bool { return !used[p.ID] })
+	}
+
+	if *outputPath == "" {
+		return ansible.Export(a.Stdout, format, servers, projects)
+	}
+	var buf bytes.Buffer
+	if err := ansible.Export(&buf, format, servers, projects); err != nil {
+		return err
+	}
+	if err := maybe.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write inventory: %w", err)
+	}
+	_, _ = fmt.Fprintf(a.Stderr, "Exported %d servers to %s\n", len(servers), *outputPath)
+	return nil
+}
+
+// filterServers asks the backend to filter when it implements backend.Filterer
+// and applies the same semantics in memory otherwise.
+func (a *App) filterServers(ctx context.Context, filter backend.ServerFilter) ([]*domain.Server, error) {
+	if filterer, ok := a.Backend.(backend.Filterer); ok {
+		return filterer.FilterServers(ctx, filter)
+	}
+
+	servers, err := a.Backend.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backend.ApplyFilter(servers, filter), nil
+}
+
+// formatNames returns the supported --format values for help output.
+func formatNames() string {
+	names := make([]string, len(output.Formats))
+	for i, f := range output.Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// runShow prints every populated field of a single server.
+func (a *App) runShow(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("show")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	alias, err := requireAlias(positional, "show <alias>")
+	if err != nil {
+		return err
+	}
+
+	srv, err := a.findServer(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.Stdout, 0, 4, 1, ' ', 0)
+	field := func(label, value string) {
+		if value != "" {
+			_, _ = fmt.Fprintf(tw, "%s:\t%s\n", label, value)
+		}
+	}
+	field("Name", srv.DisplayName)
+	field("ID", srv.ID)
+	field("Host", srv.Host)
+	field("User", srv.User)
+	field("Port", portString(srv.Port))
+	field("IdentityFile", srv.IdentityFile)
+	field("ProxyJump", srv.Proxy)
+	field("RemotePath", srv.RemoteProjectPath)
+	field("Tags", strings.Join(srv.Tags, ", "))
+	field("Projects", strings.Join(srv.ProjectIDs, ", "))
+	if srv.VPNRequired {
+		field("VPNRequired", "yes")
+	}
+	if srv.Favorite {
+		field("Favorite", "yes")
+	}
+	field("Vault", srv.VaultID)
+	field("Source", srv.Source)
+	field("Notes", srv.Notes)
+	return tw.Flush()
+}
+
+// runResolve prints the options ssh would use for an alias after applying
+// wildcards, Match blocks and Includes, with the block and file each came from.
+func (a *App) runResolve(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("resolve")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	alias, err := requireAlias(positional, "resolve <alias>")
+	if err != nil {
+		return err
+	}
+	if a.SSHConfig == "" {
+		return fmt.Errorf("resolve: no SSH config path configured")
+	}
+
+	res, err := sshconfig.ResolveHost(a.SSHConfig, alias)
+	if err != nil {
+		return err
+	}
+	if len(res.Options) == 0 && len(res.Unevaluated) == 0 {
+		return fmt.Errorf("%w: no SSH config options apply to %q", errors.ErrServerNotFound, alias)
+	}
+
+	tw := tabwriter.NewWriter(a.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "KEY\tVALUE\tFROM")
+	for _, opt := range res.Options {
+		for _, val := range opt.Values {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", opt.Key, val.Value, val.Origin())
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Match blocks with criteria like exec or user are decided by ssh at connect time
+	for _, match := range res.Unevaluated {
+		_, _ = fmt.Fprintf(a.Stdout, "not evaluated: %s (%s:%d)\n", match.Name, match.SourceFile, match.SourceLine)
+	}
+	return nil
+}
+
+// runSync refreshes servers from 1Password and rewrites the TOML cache and the
+// generated SSH include file. Meant for cron and CI: it never prompts, and a
+// rejected session or token exits with ExitAuth.
+func (a *App) runSync(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("sync")
+	quiet := fs.Bool("quiet", false, "Only print warnings and errors")
+	timeout := fs.Duration("timeout", 2*time.Minute, "Give up after this long")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("%w: sync takes no arguments", errUsage)
+	}
+	if a.Sync == nil {
+		return fmt.Errorf("sync: 1Password is not configured (backend must be onepassword or both)")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	stats, err := a.Sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, vault := range stats.DeniedVaults {
+		_, _ = fmt.Fprintf(a.Stderr, "Warning: no access to vault %q; its servers were not synced\n", vault)
+	}
+	if !*quiet {
+		_, _ = fmt.Fprintf(a.Stdout, "Synced %d servers from 1Password in %s (%d fetched, %d unchanged, %d skipped)\n",
+			stats.Items-stats.Skipped, stats.Duration.Round(time.Millisecond), stats.Fetched, stats.Reused, stats.Skipped)
+	}
+	return nil
+}
+
+// runMigrate runs the migration wizard and prints a summary, since the
+// wizard's own results screen disappears with the alternate screen.
+// Returns an error when any item failed to migrate.
+func (a *App) runMigrate(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("migrate")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("%w: migrate takes no arguments", errUsage)
+	}
+	if a.Migrate == nil {
+		return fmt.Errorf("migrate: 1Password is not configured (backend must be onepassword or both)")
+	}
+
+	results, err := a.Migrate(ctx)
+	if err != nil {
+		return err
+	}
+
+	migrated, failed := 0, 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			_, _ = fmt.Fprintf(a.Stderr, "Failed to migrate %q (%s): %v\n", r.Title, r.VaultName, r.Err)
+			continue
+		}
+		migrated++
+	}
+	_, _ = fmt.Fprintf(a.Stdout, "Migrated %d 1Password items\n", migrated)
+	if failed > 0 {
+		return fmt.Errorf("%d items could not be migrated", failed)
+	}
+	return nil
+}
+
+// runPush creates 1Password items for hosts in the user's SSH config, either
+// the given aliases or, with --all, every host that isn't in 1Password yet.
+// Without either it runs the interactive push wizard.
+// Returns an error when any host failed to push.
+func (a *App) runPush(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("push")
+	all := fs.Bool("all", false, "Push every host that isn't in 1Password yet")
+	vault := fs.String("vault", "", "1Password vault (name or ID) (default: the configured default vault)")
+	commentOut := fs.Bool("comment-out", false, "Comment out each pushed Host block (the file is backed up first)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be pushed without changing anything")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *all && len(positional) > 0 {
+		return fmt.Errorf("%w: give aliases or --all, not both", errUsage)
+	}
+
+	if !*all && len(positional) == 0 {
+		if fs.NFlag() > 0 {
+			return fmt.Errorf("%w: flags need aliases or --all (usage: ssherpa push [<alias>...|--all])", errUsage)
+		}
+		if a.PushWizard == nil {
+			return fmt.Errorf("push: 1Password is not configured (backend must be onepassword or both)")
+		}
+		results, err := a.PushWizard(ctx)
+		if err != nil {
+			return err
+		}
+		return a.reportPush(results)
+	}
+
+	if a.OnePassword == nil {
+		return fmt.Errorf("push: 1Password is not configured (backend must be onepassword or both)")
+	}
+	w, ok := a.OnePassword.(backend.Writer)
+	if !ok {
+		return errors.ErrReadOnlyBackend
+	}
+	if a.SSHConfig == "" {
+		return fmt.Errorf("push: no SSH config path configured")
+	}
+
+	servers, err := a.OnePassword.ListServers(ctx)
+	if err != nil {
+		return err
+	}
+	candidates, err := sync.FindPushCandidates(servers, a.SSHConfig)
+	if err != nil {
+		return err
+	}
+
+	var selected []sync.PushCandidate
+	if *all {
+		for _, c := range candidates {
+			if err := c.Check(); err != nil {
+				_, _ = fmt.Fprintf(a.Stderr, "Skipping %s: %v\n", c.Alias, err)
+				continue
+			}
+			selected = append(selected, c)
+		}
+	} else {
+		for _, alias := range positional {
+			c, ok := findPushCandidate(candidates, alias)
+			if !ok {
+				return fmt.Errorf("%w: no SSH config host %q", errors.ErrServerNotFound, alias)
+			}
+			selected = append(selected, c)
+		}
+	}
+
+	if *dryRun {
+		for _, c := range selected {
+			if err := c.Check(); err != nil {
+				_, _ = fmt.Fprintf(a.Stderr, "Cannot push %s: %v\n", c.Alias, err)
+				continue
+			}
+			_, _ = fmt.Fprintf(a.Stdout, "Would push %s (%s@%s) from %s\n", c.Alias, c.Server.User, c.Server.Host, c.SourceFile)
+		}
+		return nil
+	}
+
+	results := sync.PushHosts(ctx, w, selected, sync.PushOptions{
+		Vault:         *vault,
+		CommentOut:    *commentOut,
+		SSHConfigPath: a.SSHConfig,
+	})
+	for _, r := range results {
+		if r.Pushed {
+			if err := a.afterWrite(ctx); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return a.reportPush(results)
+}
+
+// findPushCandidate looks up a push candidate by alias (case-insensitive).
+func findPushCandidate(candidates []sync.PushCandidate, alias string) (sync.PushCandidate, bool) {
+	for _, c := range candidates {
+		if strings.EqualFold(c.Alias, alias) {
+			return c, true
+		}
+	}
+	return sync.PushCandidate{}, false
+}
+
+// reportPush prints the outcome of a push, failures to stderr.
+// Returns an error when any host failed.
+func (a *App) reportPush(results []sync.PushResult) error {
+	pushed, failed := 0, 0
+	for _, r := range results {
+		if r.Pushed {
+			pushed++
+			if r.CommentedOut {
+				_, _ = fmt.Fprintf(a.Stdout, "Pushed %s and commented out its Host block\n", r.Alias)
+			} else {
+				_, _ = fmt.Fprintf(a.Stdout, "Pushed %s\n", r.Alias)
+			}
+		}
+		if r.Err != nil {
+			failed++
+			_, _ = fmt.Fprintf(a.Stderr, "Failed to push %s: %v\n", r.Alias, r.Err)
+		}
+	}
+	_, _ = fmt.Fprintf(a.Stdout, "Pushed %d hosts to 1Password\n", pushed)
+	if failed > 0 {
+		return fmt.Errorf("%d hosts could not be pushed", failed)
+	}
+	return nil
+}
+
+// runDoctor checks the SSH config and its Includes, keys and the 1Password sync.
+// --fix applies the safe fixes (permissions, ssherpa Include placement).
+// Returns an error, and so a non-zero exit code, when errors remain.
+func (a *App) runDoctor(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("doctor")
+	fix := fs.Bool("fix", false, "Apply safe fixes (file permissions, ssherpa Include placement)")
+	formatFlag := fs.String("format", "table", "Output format: table, json")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("%w: doctor takes no arguments", errUsage)
+	}
+	format := strings.ToLower(*formatFlag)
+	if format != "table" && format != "json" {
+		return fmt.Errorf("%w: unknown format %q (valid: table, json)", errUsage, *formatFlag)
+	}
+	if a.SSHConfig == "" {
+		return fmt.Errorf("doctor: no SSH config path configured")
+	}
+
+	opts := doctor.Options{SSHConfigPath: a.SSHConfig, IncludePath: a.SSHInclude}
+	if a.SkippedItems != nil {
+		opts.SkippedItems = a.SkippedItems(ctx)
+	}
+	findings, err := doctor.Run(opts)
+	if err != nil {
+		return err
+	}
+
+	if *fix {
+		for i := range findings {
+			if !findings[i].Fixable {
+				continue
+			}
+			if err := findings[i].Fix(); err != nil {
+				_, _ = fmt.Fprintf(a.Stderr, "Could not fix %s: %v\n", findings[i].Location(), err)
+				continue
+			}
+			findings[i].Fixed = true
+		}
+	}
+
+	var errorCount, warningCount, fixedCount int
+	for _, f := range findings {
+		switch {
+		case f.Fixed:
+			fixedCount++
+		case f.Severity == doctor.SeverityError:
+			errorCount++
+		case f.Severity == doctor.SeverityWarning:
+			warningCount++
+		}
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(a.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	} else {
+		if err := a.writeFindings(findings); err != nil {
+			return err
+		}
+		switch {
+		case len(findings) == 0:
+			_, _ = fmt.Fprintln(a.Stdout, "No problems found.")
+		default:
+			_, _ = fmt.Fprintf(a.Stdout, "\n%d error(s), %d warning(s), %d fixed\n", errorCount, warningCount, fixedCount)
+			if !*fix && hasFixable(findings) {
+				_, _ = fmt.Fprintln(a.Stdout, "Run 'ssherpa doctor --fix' to apply the fixable ones.")
+			}
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("doctor found %d error(s)", errorCount)
+	}
+	return nil
+}
+
+// writeFindings prints findings as a table; fixed ones are marked instead of
+// showing their severity.
+func (a *App) writeFindings(findings []doctor.Finding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(a.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SEVERITY\tCHECK\tLOCATION\tMESSAGE")
+	for _, f := range findings {
+		severity := f.Severity.String()
+		switch {
+		case f.Fixed:
+			severity = "fixed"
+		case f.Fixable:
+			severity += " (fixable)"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", severity, f.Check, f.Location(), f.Message)
+	}
+	return tw.Flush()
+}
+
+// hasFixable reports whether any finding can still be fixed with --fix.
+func hasFixable(findings []doctor.Finding) bool {
+	for _, f := range findings {
+		if f.Fixable && !f.Fixed {
+			return true
+		}
+	}
+	return false
+}
+
+// runConnect hands the terminal to ssh for the server's alias.
+// If the server has a RemoteProjectPath, the session starts in that directory;
+// --path overrides it and --path "" connects without changing directory.
+// VPN-only servers are checked first; --force connects even if the check fails.
+// ssh's own exit status is propagated by main via the returned error.
+func (a *App) runConnect(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("connect")
+	pathFlag := fs.String("path", "", `Remote directory to start in (overrides the server's remote path; "" to skip)`)
+	force := fs.Bool("force", false, "Connect even if the VPN check fails")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	alias, err := requireAlias(positional, "connect <alias>")
+	if err != nil {
+		return err
+	}
+
+	srv, err := a.findServer(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	opts := ssh.Options{RemotePath: srv.RemoteProjectPath}
+	if isFlagSet(fs, "path") {
+		opts.RemotePath = *pathFlag
+	}
+
+	if err := vpn.Check(ctx, srv, a.Config); err != nil {
+		if !*force {
+			hint := "use --force to connect anyway"
+			if cmd := vpn.Settings(srv, a.Config).ConnectCommand; cmd != "" {
+				hint = fmt.Sprintf("run %q or use --force to connect anyway", cmd)
+			}
+			return fmt.Errorf("%s: %w (%s)", srv.DisplayName, err, hint)
+		}
+		_, _ = fmt.Fprintf(a.Stderr, "Warning: %s: %v, connecting anyway\n", srv.DisplayName, err)
+	}
+
+	return a.connect(srv, opts)
+}
+
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// serverFlags holds the editable fields shared by add and edit.
+type serverFlags struct {
+	name         string
+	host         string
+	user         string
+	port         int
+	identityFile string
+	proxy        string
+	vault        string
+	tags         string
+}
+
+// register binds the server flags to fs.
+// withName adds --name, which only makes sense when renaming an existing server.
+func (f *serverFlags) register(fs *flag.FlagSet, withName bool) {
+	if withName {
+		fs.StringVar(&f.name, "name", "", "New alias for the server")
+	}
+	fs.StringVar(&f.host, "host", "", "Hostname or IP address")
+	fs.StringVar(&f.user, "user", "", "SSH username")
+	fs.IntVar(&f.port, "port", 0, "SSH port (default 22)")
+	fs.StringVar(&f.identityFile, "identity-file", "", "Path to SSH private key")
+	fs.StringVar(&f.proxy, "proxy", "", "ProxyJump host")
+	fs.StringVar(&f.vault, "vault", "", "1Password vault (name or ID) for new servers (default: the configured default vault)")
+	fs.StringVar(&f.tags, "tags", "", `Comma-separated tags, e.g. "prod,web" ("" to clear)`)
+}
+
+// apply copies the flags that were explicitly set on the command line onto srv.
+// Unset flags leave the existing value untouched, so "edit --port 2222" changes only the port.
+func (f *serverFlags) apply(fs *flag.FlagSet, srv *domain.Server) {
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "name":
+			srv.DisplayName = f.name
+		case "host":
+			srv.Host = f.host
+		case "user":
+			srv.User = f.user
+		case "port":
+			srv.Port = f.port
+		case "identity-file":
+			srv.IdentityFile = f.identityFile
+		case "proxy":
+			srv.Proxy = f.proxy
+		case "vault":
+			srv.VaultID = f.vault
+		case "tags":
+			srv.Tags = splitTags(f.tags)
+		}
+	})
+}
+
+// splitTags parses a comma-separated tag list, dropping blanks and duplicates.
+func splitTags(s string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// runAdd creates a new server through the backend's Writer.
+func (a *App) runAdd(ctx context.Context, args []string) error {
+	var sf serverFlags
+	fs := a.newFlagSet("add")
+	sf.register(fs, false)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	alias, err := requireAlias(positional, "add <alias> --host HOST --user USER")
+	if err != nil {
+		return err
+	}
+
+	w, err := a.writer()
+	if err != nil {
+		return err
+	}
+
+	if existing, err := a.findServer(ctx, alias); err == nil {
+		return fmt.Errorf("%w: server %q already exists (source: %s)", errors.ErrDuplicateID, alias, existing.Source)
+	} else if !errors.Is(err, errors.ErrServerNotFound) {
+		return err
+	}
+
+	srv := &domain.Server{
+		ID:          alias,
+		DisplayName: alias,
+		Port:        22,
+	}
+	sf.apply(fs, srv)
+
+	if err := validateServer(srv); err != nil {
+		return err
+	}
+
+	if err := w.CreateServer(ctx, srv); err != nil {
+		return err
+	}
+	if err := a.afterWrite(ctx); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(a.Stdout, "Added %s\n", alias)
+	return nil
+}
+
+// runEdit updates the fields given on the command line and leaves the rest untouched.
+func (a *App) runEdit(ctx context.Context, args []string) error {
+	var sf serverFlags
+	fs := a.newFlagSet("edit")
+	sf.register(fs, true)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	alias, err := requireAlias(positional, "edit <alias> [flags]")
+	if err != nil {
+		return err
+	}
+	if fs.NFlag() == 0 {
+		return fmt.Errorf("%w: nothing to change (see ssherpa edit -h)", errUsage)
+	}
+
+	w, err := a.writer()
+	if err != nil {
+		return err
+	}
+
+	srv, err := a.findServer(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(srv); err != nil {
+		return err
+	}
+
+	updated := *srv
+	sf.apply(fs, &updated)
+
+	if err := validateServer(&updated); err != nil {
+		return err
+	}
+
+	if err := w.UpdateServer(ctx, &updated); err != nil {
+		return err
+	}
+	if err := a.afterWrite(ctx); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(a.Stdout, "Updated %s\n", updated.DisplayName)
+	return nil
+}
+
+// runRemove deletes a server. There is no confirmation prompt: the CLI is meant for scripts.
+func (a *App) runRemove(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("rm")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	alias, err := requireAlias(positional, "rm <alias>")
+	if err != nil {
+		return err
+	}
+
+	w, err := a.writer()
+	if err != nil {
+		return err
+	}
+
+	srv, err := a.findServer(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(srv); err != nil {
+		return err
+	}
+
+	if err := w.DeleteServer(ctx, srv.ID); err != nil {
+		return err
+	}
+	if err := a.afterWrite(ctx); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(a.Stdout, "Removed %s\n", srv.DisplayName)
+	return nil
+}
+
+// afterWrite runs the optional post-write hook.
+func (a *App) afterWrite(ctx context.Context) error {
+	if a.AfterWrite == nil {
+		return nil
+	}
+	return a.AfterWrite(ctx)
+}
+
+// checkWritable rejects servers that come from ~/.ssh/config or an Ansible
+// inventory. Both backends are read-only; SSH config hosts are edited in the
+// TUI or by hand, inventory hosts in the inventory.
+func checkWritable(srv *domain.Server) error {
+	switch srv.Source {
+	case "ssh-config":
+		return fmt.Errorf("%w: %q is defined in your SSH config", errors.ErrReadOnlyBackend, srv.DisplayName)
+	case "ansible":
+		return fmt.Errorf("%w: %q is defined in your Ansible inventory", errors.ErrReadOnlyBackend, srv.DisplayName)
+	}
+	return nil
+}
+
+// validateServer checks the fields every writable backend requires.
+func validateServer(srv *domain.Server) error {
+	if err := srv.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrValidation, err)
+	}
+	if srv.User == "" {
+		return fmt.Errorf("%w: server user is required", errors.ErrValidation)
+	}
+	if strings.ContainsAny(srv.DisplayName, " \t") {
+		return fmt.Errorf("%w: alias must not contain whitespace", errors.ErrValidation)
+	}
+	return nil
+}
+
+// sortServers orders servers alphabetically by alias (case-insensitive).
+func sortServers(servers []*domain.Server) {
+	sort.Slice(servers, func(i, j int) bool {
+		return strings.ToLower(servers[i].DisplayName) < strings.ToLower(servers[j].DisplayName)
+	})
+}
+
+// portString formats a port, leaving it blank when unset.
+func portString(port int) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.Itoa(port)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/adrg/xdg"
@@ -12,27 +13,159 @@ import (
 // ProjectConfig represents a project in the config file.
 // Projects are stored as TOML array-of-tables: [[project]]
 type ProjectConfig struct {
-	ID            string   `toml:"id"`                     // Project identifier (typically org/repo)
-	Name          string   `toml:"name"`                   // Human-readable project name
-	GitRemoteURLs []string `toml:"git_remote_urls"`        // Git remote URLs for this project
-	Color         string   `toml:"color,omitempty"`        // User-overridden color hex (empty = auto-generate)
-	ServerNames   []string `toml:"server_names,omitempty"` // SSH config host aliases in this project
+	ID            string     `toml:"id"`                     // Project identifier (typically org/repo)
+	Name          string     `toml:"name"`                   // Human-readable project name
+	GitRemoteURLs []string   `toml:"git_remote_urls"`        // Git remote URLs for this project
+	Color         string     `toml:"color,omitempty"`        // User-overridden color hex (empty = auto-generate)
+	ServerNames   []string   `toml:"server_names,omitempty"` // SSH config host aliases in this project
+	VPNRequired   bool       `toml:"vpn_required,omitempty"` // Every server in this project needs the VPN
+	VPN           *VPNConfig `toml:"vpn,omitempty"`          // Per-project VPN check (overrides the global [vpn] section)
+	Vault         string     `toml:"vault,omitempty"`        // 1Password vault (name or ID) for new servers in this project
+}
+
+// VPNConfig describes how ssherpa verifies VPN connectivity before connecting
+// to a server that requires it. Every configured check must pass; with no checks
+// configured, ssherpa dials the server's own host and port instead.
+type VPNConfig struct {
+	Canary         string `toml:"canary,omitempty"`          // host:port that must accept a TCP connection
+	Interface      string `toml:"interface,omitempty"`       // Network interface that must be up (e.g. "wg0", "utun4")
+	Route          string `toml:"route,omitempty"`           // IP or CIDR that must be on a local interface's network
+	CheckCommand   string `toml:"check_command,omitempty"`   // Shell command that exits 0 when the VPN is up
+	ConnectCommand string `toml:"connect_command,omitempty"` // Shell command offered to bring the VPN up
+	TimeoutSeconds int    `toml:"timeout_seconds,omitempty"` // Per-check timeout (default 3)
+}
+
+// HostConfig stores ssherpa metadata for hosts defined in ~/.ssh/config,
+// which has no place for fields like VPNRequired, Favorite or Tags.
+// Hosts are stored as TOML array-of-tables: [[host]]
+type HostConfig struct {
+	Alias       string   `toml:"alias"`                  // SSH config host alias
+	VPNRequired bool     `toml:"vpn_required,omitempty"` // Host is only reachable over VPN
+	Favorite    bool     `toml:"favorite,omitempty"`     // Pinned to the top of the list
+	Tags        []string `toml:"tags,omitempty"`         // User-defined tags for filtering (e.g. "prod")
 }
 
 // OnePasswordConfig represents 1Password-specific settings.
+// Vaults are referenced by name or ID; names match case-insensitively.
 type OnePasswordConfig struct {
-	AccountName string `toml:"account_name,omitempty"` // Account name for desktop app integration
-	CachePath   string `toml:"cache_path,omitempty"`   // Override TOML cache path
+	Client        string   `toml:"client,omitempty"`         // "cli" (op CLI, default) or "connect" (1Password Connect server)
+	AccountName   string   `toml:"account_name,omitempty"`   // Account name for desktop app integration
+	ConnectURL    string   `toml:"connect_url,omitempty"`    // Connect server URL (default: $OP_CONNECT_HOST)
+	ConnectToken  string   `toml:"connect_token,omitempty"`  // Connect access token (default: $OP_CONNECT_TOKEN)
+	CachePath     string   `toml:"cache_path,omitempty"`     // Override TOML cache path
+	IncludeVaults []string `toml:"include_vaults,omitempty"` // Only sync these vaults (empty = every vault)
+	ExcludeVaults []string `toml:"exclude_vaults,omitempty"` // Never sync these vaults, even if included
+	DefaultVault  string   `toml:"default_vault,omitempty"`  // Vault for new servers (overridden per project)
+}
+
+// BitwardenConfig represents Bitwarden-specific settings.
+// Servers are items with an "ssherpa" custom field; folder and collection
+// are referenced by name or ID and limit which items are synced.
+type BitwardenConfig struct {
+	Folder     string `toml:"folder,omitempty"`     // Personal folder to sync and create servers in
+	Collection string `toml:"collection,omitempty"` // Organization collection to use instead of a folder
+	CachePath  string `toml:"cache_path,omitempty"` // Override TOML cache path
+}
+
+// PassConfig represents password-store (pass/gopass) settings.
+// Every entry under Prefix is a server: the password on the first line,
+// "key: value" fields after it.
+type PassConfig struct {
+	Command  string `toml:"command,omitempty"`   // CLI to run: "pass" (default) or "gopass"
+	Prefix   string `toml:"prefix,omitempty"`    // Store subtree holding servers (default "ssh")
+	StoreDir string `toml:"store_dir,omitempty"` // Override PASSWORD_STORE_DIR (pass only)
+}
+
+// VaultConfig represents HashiCorp Vault settings.
+// Every secret under Path in the KV v2 Mount is a server. Authentication is
+// a token, or AppRole when RoleID is set.
+type VaultConfig struct {
+	Address      string `toml:"address,omitempty"`       // Vault address (default: $VAULT_ADDR)
+	Namespace    string `toml:"namespace,omitempty"`     // Enterprise namespace (default: $VAULT_NAMESPACE)
+	Mount        string `toml:"mount,omitempty"`         // KV v2 mount (default "secret")
+	Path         string `toml:"path,omitempty"`          // Path under the mount holding servers (default "ssh")
+	Token        string `toml:"token,omitempty"`         // Token auth (default: $VAULT_TOKEN)
+	RoleID       string `toml:"role_id,omitempty"`       // AppRole role_id
+	SecretID     string `toml:"secret_id,omitempty"`     // AppRole secret_id
+	AppRoleMount string `toml:"approle_mount,omitempty"` // AppRole auth mount (default "approle")
+	CachePath    string `toml:"cache_path,omitempty"`    // Override TOML cache path
+}
+
+// GitConfig points at a git clone holding a team inventory: servers,
+// projects and credential references in TOML files. When Repo is set, the
+// inventory is shown alongside the configured backend.
+type GitConfig struct {
+	Repo        string `toml:"repo,omitempty"`         // Path to the local clone ("~/" is expanded)
+	Files       string `toml:"files,omitempty"`        // Inventory file glob relative to the clone (default "ssherpa/*.toml")
+	DefaultFile string `toml:"default_file,omitempty"` // File new entries go to (default: first inventory file)
+	AutoCommit  bool   `toml:"auto_commit,omitempty"`  // Commit each change made through ssherpa (never pushed)
+}
+
+// AnsibleConfig points at an Ansible inventory (INI or YAML). When Inventory
+// is set, its hosts are shown alongside the configured backend, read-only,
+// with groups as projects.
+type AnsibleConfig struct {
+	Inventory string `toml:"inventory,omitempty"` // Inventory file ("~/" is expanded; .yml/.yaml/.json are YAML, others INI)
 }
 
 // Config represents the application configuration.
 type Config struct {
 	Version       int               `toml:"version"`                        // Config schema version for future migrations
-	Backend       string            `toml:"backend"`                        // Backend identifier: "sshconfig", "onepassword", "both"
+	Backend       string            `toml:"backend"`                        // Backend identifier: "sshconfig", "onepassword", "both", "bitwarden", "pass", "vault"
 	ReturnToTUI   bool              `toml:"return_to_tui_after_disconnect"` // Return to TUI after SSH session ends (default: false = exit to shell)
 	MigrationDone bool              `toml:"migration_done,omitempty"`       // Whether migration wizard has been completed or skipped
 	OnePassword   OnePasswordConfig `toml:"onepassword"`                    // 1Password backend settings
+	Bitwarden     BitwardenConfig   `toml:"bitwarden,omitempty"`            // Bitwarden backend settings
+	Pass          PassConfig        `toml:"pass,omitempty"`                 // pass/gopass backend settings
+	Vault         VaultConfig       `toml:"vault,omitempty"`                // HashiCorp Vault backend settings
+	Git           GitConfig         `toml:"git,omitempty"`                  // Git team inventory merged into the backend
+	Ansible       AnsibleConfig     `toml:"ansible,omitempty"`              // Ansible inventory merged into the backend (read-only)
+	VPN           VPNConfig         `toml:"vpn"`                            // Default VPN check
 	Projects      []ProjectConfig   `toml:"project"`                        // Projects (TOML array-of-tables: [[project]])
+	Hosts         []HostConfig      `toml:"host,omitempty"`                 // SSH config host metadata (TOML array-of-tables: [[host]])
+}
+
+// Host returns the metadata stored for an SSH config alias (case-insensitive).
+// Returns nil if the host has no entry.
+func (c *Config) Host(alias string) *HostConfig {
+	for i := range c.Hosts {
+		if strings.EqualFold(c.Hosts[i].Alias, alias) {
+			return &c.Hosts[i]
+		}
+	}
+	return nil
+}
+
+// SetHost stores metadata for an SSH config alias, replacing any existing entry.
+// Entries that carry no metadata are removed to keep the file tidy.
+func (c *Config) SetHost(host HostConfig) {
+	kept := make([]HostConfig, 0, len(c.Hosts)+1)
+	for _, h := range c.Hosts {
+		if !strings.EqualFold(h.Alias, host.Alias) {
+			kept = append(kept, h)
+		}
+	}
+	c.Hosts = kept
+	if !host.IsEmpty() {
+		c.Hosts = append(c.Hosts, host)
+	}
+}
+
+// IsEmpty reports whether the entry carries no metadata besides its alias.
+func (h HostConfig) IsEmpty() bool {
+	return !h.VPNRequired && !h.Favorite && len(h.Tags) == 0
+}
+
+// ProjectVaults maps project IDs to the 1Password vault set for them.
+// Projects without a vault are left out.
+func (c *Config) ProjectVaults() map[string]string {
+	vaults := make(map[string]string)
+	for _, p := range c.Projects {
+		if p.Vault != "" {
+			vaults[p.ID] = p.Vault
+		}
+	}
+	return vaults
 }
 
 // DefaultConfig returns a config with sensible defaults.
@@ -51,14 +184,23 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("config validation failed: backend must be non-empty")
 	}
 
-	// Valid backend values: "sshconfig", "onepassword", "both"
+	// Valid backend values: "sshconfig", "onepassword", "both", "bitwarden", "pass", "vault"
 	validBackends := map[string]bool{
 		"sshconfig":   true,
 		"onepassword": true,
 		"both":        true,
+		"bitwarden":   true,
+		"pass":        true,
+		"vault":       true,
 	}
 	if !validBackends[c.Backend] {
-		return fmt.Errorf("config validation failed: invalid backend '%s' (valid: sshconfig, onepassword, both)", c.Backend)
+		return fmt.Errorf("config validation failed: invalid backend '%s' (valid: sshconfig, onepassword, both, bitwarden, pass, vault)", c.Backend)
+	}
+
+	switch c.OnePassword.Client {
+	case "", "cli", "connect":
+	default:
+		return fmt.Errorf("config validation failed: invalid onepassword client '%s' (valid: cli, connect)", c.OnePassword.Client)
 	}
 
 	return nil
@@ -131,6 +131,52 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "backend",
 		},
+		{
+			name: "connect client passes",
+			config: &Config{
+				Version:     1,
+				Backend:     "onepassword",
+				OnePassword: OnePasswordConfig{Client: "connect"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bitwarden backend passes",
+			config: &Config{
+				Version:   1,
+				Backend:   "bitwarden",
+				Bitwarden: BitwardenConfig{Folder: "Servers"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pass backend passes",
+			config: &Config{
+				Version: 1,
+				Backend: "pass",
+				Pass:    PassConfig{Command: "gopass", Prefix: "infra/ssh"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "vault backend passes",
+			config: &Config{
+				Version: 1,
+				Backend: "vault",
+				Vault:   VaultConfig{Address: "https://vault.example.com:8200", RoleID: "role", SecretID: "secret"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown 1Password client fails",
+			config: &Config{
+				Version:     1,
+				Backend:     "onepassword",
+				OnePassword: OnePasswordConfig{Client: "sdk"},
+			},
+			wantErr: true,
+			errMsg:  "onepassword client",
+		},
 	}
 
 	for _, tt := range tests {
@@ -298,8 +344,14 @@ func TestSaveAndReload_OnePasswordConfig(t *testing.T) {
 		Version: 1,
 		Backend: "onepassword",
 		OnePassword: OnePasswordConfig{
-			AccountName: "my-team.1password.com",
-			CachePath:   "/tmp/op-cache",
+			Client:        "connect",
+			AccountName:   "my-team.1password.com",
+			ConnectURL:    "http://connect.internal:8080",
+			ConnectToken:  "token",
+			CachePath:     "/tmp/op-cache",
+			IncludeVaults: []string{"Team Infra", "vault-abc"},
+			ExcludeVaults: []string{"Private"},
+			DefaultVault:  "Team Infra",
 		},
 	}
 
@@ -311,6 +363,19 @@ func TestSaveAndReload_OnePasswordConfig(t *testing.T) {
 
 	assert.Equal(t, "my-team.1password.com", reloaded.OnePassword.AccountName)
 	assert.Equal(t, "/tmp/op-cache", reloaded.OnePassword.CachePath)
+	assert.Equal(t, original.OnePassword, reloaded.OnePassword)
+}
+
+func TestConfigProjectVaults(t *testing.T) {
+	cfg := &Config{
+		Projects: []ProjectConfig{
+			{ID: "acme/payments", Name: "Payments", Vault: "Payments Team"},
+			{ID: "acme/infra", Name: "Infra"},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"acme/payments": "Payments Team"}, cfg.ProjectVaults())
+	assert.Empty(t, (&Config{}).ProjectVaults())
 }
 
 func TestSaveAndReload_ReturnToTUI(t *testing.T) {
@@ -436,3 +501,62 @@ func TestConfigWithProjects_ServerNames(t *testing.T) {
 	assert.Equal(t, 4, len(reloaded.Projects[0].ServerNames))
 	assert.Equal(t, original.Projects[0].ServerNames, reloaded.Projects[0].ServerNames)
 }
+
+func TestConfigVPNRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.toml")
+
+	original := &Config{
+		Version: 1,
+		Backend: "sshconfig",
+		VPN: VPNConfig{
+			Canary:         "10.0.0.1:443",
+			ConnectCommand: "wg-quick up work",
+		},
+		Projects: []ProjectConfig{
+			{
+				ID:          "acme/backend",
+				Name:        "Backend",
+				VPNRequired: true,
+				VPN:         &VPNConfig{Interface: "utun4", TimeoutSeconds: 5},
+			},
+			{ID: "acme/web", Name: "Web"},
+		},
+		Hosts: []HostConfig{{Alias: "db-prod", VPNRequired: true}, {Alias: "web", Favorite: true, Tags: []string{"prod", "web"}}},
+	}
+
+	require.NoError(t, Save(original, configPath))
+
+	reloaded, err := Load(configPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.VPN, reloaded.VPN)
+	require.Len(t, reloaded.Projects, 2)
+	assert.True(t, reloaded.Projects[0].VPNRequired)
+	require.NotNil(t, reloaded.Projects[0].VPN)
+	assert.Equal(t, "utun4", reloaded.Projects[0].VPN.Interface)
+	assert.Nil(t, reloaded.Projects[1].VPN)
+	assert.Equal(t, original.Hosts, reloaded.Hosts)
+}
+
+func TestConfigSetHost(t *testing.T) {
+	cfg := &Config{}
+
+	cfg.SetHost(HostConfig{Alias: "db-prod", VPNRequired: true})
+	require.NotNil(t, cfg.Host("DB-PROD"))
+	assert.True(t, cfg.Host("db-prod").VPNRequired)
+
+	// Replacing keeps a single entry
+	cfg.SetHost(HostConfig{Alias: "db-prod", VPNRequired: true})
+	assert.Len(t, cfg.Hosts, 1)
+
+	// Any metadata keeps the entry
+	cfg.SetHost(HostConfig{Alias: "db-prod", Favorite: true})
+	require.NotNil(t, cfg.Host("db-prod"))
+	assert.False(t, cfg.Host("db-prod").VPNRequired)
+
+	// An entry without metadata is dropped
+	cfg.SetHost(HostConfig{Alias: "db-prod"})
+	assert.Nil(t, cfg.Host("db-prod"))
+	assert.Empty(t, cfg.Hosts)
+}
@@ -0,0 +1,468 @@
+// Package doctor checks the user's SSH setup for problems ssh won't report
+// clearly on its own: duplicate or shadowed hosts, missing or world-readable
+// keys, deprecated options, a misplaced ssherpa Include and 1Password items
+// that failed to sync. Some findings carry a safe fix.
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+	"github.com/florianriquelme/ssherpa/internal/sshkey"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// Severity ranks findings. ssh refuses to work with Error findings; Warning
+// findings are likely mistakes; Info findings are for reference.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the lowercase severity name used in output.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the severity by name in JSON output.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name, so JSON output can be read back.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, candidate := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		if candidate.String() == string(text) {
+			*s = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// Check names identify which rule produced a finding.
+const (
+	CheckParse          = "parse-error"
+	CheckDuplicateAlias = "duplicate-alias"
+	CheckShadowedHost   = "shadowed-host"
+	CheckMissingKey     = "missing-identity-file"
+	CheckPermissions    = "permissions"
+	CheckDeprecated     = "deprecated-option"
+	CheckInclude        = "ssherpa-include"
+	CheckOnePassword    = "1password-skipped"
+)
+
+// Finding is one problem found by Run.
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Host     string   `json:"host,omitempty"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+	Fixable  bool     `json:"fixable"` // Fix can repair it safely
+	Fixed    bool     `json:"fixed"`   // set by the caller after a successful Fix
+
+	fix func() error
+}
+
+// Location formats File and Line as "file:line", "file" or "".
+func (f Finding) Location() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Fix applies the finding's safe fix.
+func (f Finding) Fix() error {
+	if f.fix == nil {
+		return fmt.Errorf("%s: no automatic fix", f.Check)
+	}
+	return f.fix()
+}
+
+// Options configures Run.
+type Options struct {
+	SSHConfigPath string                    // main SSH config (~/.ssh/config)
+	IncludePath   string                    // ssherpa include file; empty skips the Include check
+	SkippedItems  []onepassword.SkippedItem // 1Password items the last sync skipped
+}
+
+// deprecatedOptions maps lowercased keywords OpenSSH no longer honours to advice.
+var deprecatedOptions = map[string]string{
+	"challengeresponseauthentication": "is a deprecated alias; use KbdInteractiveAuthentication",
+	"cipher":                          "only applied to SSH protocol 1; use Ciphers",
+	"compressionlevel":                "only applied to SSH protocol 1; remove it",
+	"hostbasedkeytypes":               "was renamed to HostbasedAcceptedAlgorithms in OpenSSH 8.5",
+	"protocol":                        "is ignored since OpenSSH 7.6 removed SSH protocol 1; remove it",
+	"pubkeyacceptedkeytypes":          "was renamed to PubkeyAcceptedAlgorithms in OpenSSH 8.5",
+	"rhostsrsaauthentication":         "only applied to SSH protocol 1; remove it",
+	"rsaauthentication":               "only applied to SSH protocol 1; remove it",
+	"useprivilegedport":               "is no longer supported; remove it",
+	"useroaming":                      "was removed in OpenSSH 7.2; remove it",
+}
+
+// Run checks the SSH config at opts.SSHConfigPath (following Includes) and
+// returns the findings, most severe first.
+func Run(opts Options) ([]Finding, error) {
+	findings := make([]Finding, 0)
+
+	if _, err := os.Stat(opts.SSHConfigPath); os.IsNotExist(err) {
+		findings = append(findings, Finding{
+			Check:    CheckParse,
+			Severity: SeverityInfo,
+			File:     opts.SSHConfigPath,
+			Message:  "SSH config does not exist",
+		})
+		findings = append(findings, checkInclude(opts)...)
+		return findings, nil
+	}
+
+	hosts, err := sshconfig.ParseSSHConfig(opts.SSHConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	findings = append(findings, checkParseErrors(hosts)...)
+	findings = append(findings, checkDuplicates(hosts)...)
+	findings = append(findings, checkShadowed(hosts)...)
+	findings = append(findings, checkIdentityFiles(hosts)...)
+	findings = append(findings, checkConfigPermissions(opts.SSHConfigPath, hosts)...)
+	findings = append(findings, checkDeprecated(hosts)...)
+	findings = append(findings, checkInclude(opts)...)
+	findings = append(findings, checkSkippedItems(opts.SkippedItems)...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+	return findings, nil
+}
+
+// checkParseErrors reports Include cycles and unreadable files.
+func checkParseErrors(hosts []sshconfig.SSHHost) []Finding {
+	var findings []Finding
+	for _, host := range hosts {
+		if host.ParseError != nil {
+			findings = append(findings, Finding{
+				Check:    CheckParse,
+				Severity: SeverityError,
+				File:     host.SourceFile,
+				Line:     host.SourceLine,
+				Message:  host.ParseError.Error(),
+			})
+		}
+	}
+	return findings
+}
+
+// checkDuplicates reports concrete aliases defined by more than one Host block.
+// ssh merges such blocks, so the later one's options only fill gaps.
+func checkDuplicates(hosts []sshconfig.SSHHost) []Finding {
+	var findings []Finding
+	first := make(map[string]sshconfig.SSHHost)
+
+	for _, host := range hosts {
+		if !host.Connectable() || host.SourceLine == 0 {
+			continue
+		}
+		for _, pattern := range strings.Fields(host.Name) {
+			if isPattern(pattern) {
+				continue
+			}
+			key := strings.ToLower(pattern)
+			prev, seen := first[key]
+			if !seen {
+				first[key] = host
+				continue
+			}
+			findings = append(findings, Finding{
+				Check:    CheckDuplicateAlias,
+				Severity: SeverityWarning,
+				Host:     pattern,
+				File:     host.SourceFile,
+				Line:     host.SourceLine,
+				Message:  fmt.Sprintf("%s is already defined at %s:%d; options set there win", pattern, prev.SourceFile, prev.SourceLine),
+			})
+		}
+	}
+	return findings
+}
+
+// checkShadowed reports options of a concrete host that never take effect
+// because an earlier wildcard, Match or global block already set them.
+func checkShadowed(hosts []sshconfig.SSHHost) []Finding {
+	var findings []Finding
+
+	for _, host := range hosts {
+		if !host.Connectable() || host.SourceLine == 0 {
+			continue
+		}
+		alias := strings.Fields(host.Name)[0]
+		if isPattern(alias) {
+			continue
+		}
+
+		res := sshconfig.Resolve(hosts, alias)
+		keys := sortedKeys(host.AllOptions)
+		for _, key := range keys {
+			opt, ok := res.Option(key)
+			if !ok || setBy(opt, host) {
+				continue
+			}
+			winner := opt.Values[0]
+			if !strings.ContainsAny(winner.Block, "*?") && winner.Block != "global" && !strings.HasPrefix(winner.Block, "Match ") {
+				continue // another block for the same alias: reported as a duplicate
+			}
+			if len(host.AllOptions[key]) > 0 && host.AllOptions[key][0] == winner.Value {
+				continue // same value, nothing lost
+			}
+			findings = append(findings, Finding{
+				Check:    CheckShadowedHost,
+				Severity: SeverityWarning,
+				Host:     alias,
+				File:     host.SourceFile,
+				Line:     host.SourceLine,
+				Message: fmt.Sprintf("%s %s is ignored: %s sets %s %s first",
+					key, host.AllOptions[key][0], winner.Origin(), opt.Key, winner.Value),
+			})
+		}
+	}
+	return findings
+}
+
+// setBy reports whether any of opt's values came from host's block.
+func setBy(opt sshconfig.ResolvedOption, host sshconfig.SSHHost) bool {
+	for _, v := range opt.Values {
+		if v.SourceFile == host.SourceFile && v.SourceLine == host.SourceLine {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIdentityFiles reports IdentityFile paths that don't exist and keys
+// other users can read (ssh refuses to load those).
+func checkIdentityFiles(hosts []sshconfig.SSHHost) []Finding {
+	var findings []Finding
+	checked := make(map[string]bool)
+
+	for _, host := range hosts {
+		if host.ParseError != nil {
+			continue
+		}
+		for _, key := range sortedKeys(host.AllOptions) {
+			if !strings.EqualFold(key, "IdentityFile") {
+				continue
+			}
+			for _, value := range host.AllOptions[key] {
+				path := expandHome(strings.Trim(value, `"`))
+				if strings.ContainsAny(path, "%$") || strings.EqualFold(path, "none") {
+					continue // ssh expands tokens at connect time
+				}
+
+				info, err := os.Stat(path)
+				if os.IsNotExist(err) {
+					missing := sshkey.CreateMissingKeyEntry(path)
+					findings = append(findings, Finding{
+						Check:    CheckMissingKey,
+						Severity: SeverityWarning,
+						Host:     host.Name,
+						File:     host.SourceFile,
+						Line:     host.SourceLine,
+						Message:  fmt.Sprintf("IdentityFile %s does not exist (%s)", missing.MissingPath, missing.Filename),
+					})
+					continue
+				}
+				if err != nil || checked[path] || runtime.GOOS == "windows" {
+					continue
+				}
+				checked[path] = true
+
+				if mode := info.Mode().Perm(); mode&0077 != 0 && !isPublicKey(path) {
+					findings = append(findings, permissionFinding(path, mode, 0600, host,
+						"private key %s has mode %04o; ssh ignores keys readable by others"))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// isPublicKey reports whether path holds a public key rather than a private
+// one, e.g. the .pub files ssherpa writes for 1Password SSH Key items. ssh
+// reads those to pick an agent key and doesn't care about their mode.
+func isPublicKey(path string) bool {
+	if strings.HasSuffix(path, ".pub") {
+		return true
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	_, _, _, _, err = ssh.ParseAuthorizedKey(data)
+	return err == nil
+}
+
+// checkConfigPermissions reports config files writable by group or others,
+// which ssh rejects with "Bad owner or permissions".
+func checkConfigPermissions(rootPath string, hosts []sshconfig.SSHHost) []Finding {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	files := []string{rootPath}
+	seen := map[string]bool{rootPath: true}
+	for _, host := range hosts {
+		if host.SourceFile != "" && !seen[host.SourceFile] {
+			seen[host.SourceFile] = true
+			files = append(files, host.SourceFile)
+		}
+	}
+
+	var findings []Finding
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if mode := info.Mode().Perm(); mode&0022 != 0 {
+			findings = append(findings, permissionFinding(file, mode, mode&^0022, sshconfig.SSHHost{},
+				"SSH config %s has mode %04o; ssh refuses configs writable by others"))
+		}
+	}
+	return findings
+}
+
+// permissionFinding builds a fixable permissions finding that chmods path to want.
+func permissionFinding(path string, mode, want os.FileMode, host sshconfig.SSHHost, format string) Finding {
+	return Finding{
+		Check:    CheckPermissions,
+		Severity: SeverityError,
+		Host:     host.Name,
+		File:     path,
+		Message:  fmt.Sprintf(format, path, mode),
+		Fixable:  true,
+		fix: func() error {
+			return os.Chmod(path, want)
+		},
+	}
+}
+
+// checkDeprecated reports options OpenSSH ignores or has renamed.
+func checkDeprecated(hosts []sshconfig.SSHHost) []Finding {
+	var findings []Finding
+	for _, host := range hosts {
+		if host.ParseError != nil {
+			continue
+		}
+		for _, key := range sortedKeys(host.AllOptions) {
+			advice, ok := deprecatedOptions[strings.ToLower(key)]
+			if !ok {
+				continue
+			}
+			findings = append(findings, Finding{
+				Check:    CheckDeprecated,
+				Severity: SeverityWarning,
+				Host:     host.Name,
+				File:     host.SourceFile,
+				Line:     host.SourceLine,
+				Message:  fmt.Sprintf("%s %s", key, advice),
+			})
+		}
+	}
+	return findings
+}
+
+// checkInclude verifies the ssherpa include file is referenced where
+// sync.EnsureIncludeDirective puts it, before every Host and Match block.
+func checkInclude(opts Options) []Finding {
+	if opts.IncludePath == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(opts.SSHConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil
+	}
+
+	var message string
+	switch sync.CheckIncludeDirective(string(content), opts.IncludePath) {
+	case sync.IncludeOK:
+		return nil
+	case sync.IncludeMissing:
+		message = fmt.Sprintf("Include %s is missing; 1Password servers can't be reached with plain ssh", opts.IncludePath)
+	case sync.IncludeMisplaced:
+		message = fmt.Sprintf("Include %s comes after a Host or Match line, so ssh only applies it inside that block", opts.IncludePath)
+	}
+
+	return []Finding{{
+		Check:    CheckInclude,
+		Severity: SeverityWarning,
+		File:     opts.SSHConfigPath,
+		Message:  message,
+		Fixable:  true,
+		fix: func() error {
+			return sync.FixIncludeDirective(opts.SSHConfigPath, opts.IncludePath)
+		},
+	}}
+}
+
+// checkSkippedItems reports ssherpa-tagged 1Password items that failed to sync.
+func checkSkippedItems(items []onepassword.SkippedItem) []Finding {
+	var findings []Finding
+	for _, item := range items {
+		findings = append(findings, Finding{
+			Check:    CheckOnePassword,
+			Severity: SeverityWarning,
+			Host:     item.Title,
+			Message:  fmt.Sprintf("1Password item %q (vault %s) was skipped: %s", item.Title, item.VaultID, item.Reason),
+		})
+	}
+	return findings
+}
+
+// isPattern reports whether a Host pattern is a wildcard or negation.
+func isPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?") || strings.HasPrefix(pattern, "!")
+}
+
+// sortedKeys returns map keys in a stable order.
+func sortedKeys(options map[string][]string) []string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,288 @@
+package doctor
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// writeConfig writes an SSH config into a temp dir and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// findingsFor returns the findings produced by one check.
+func findingsFor(findings []Finding, check string) []Finding {
+	var result []Finding
+	for _, f := range findings {
+		if f.Check == check {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func TestRun_CleanConfig(t *testing.T) {
+	path := writeConfig(t, "Host web\n    HostName web.example.com\n    User deploy\n")
+
+	findings, err := Run(Options{SSHConfigPath: path})
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+	assert.NotNil(t, findings)
+}
+
+func TestRun_Checks(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		check    string
+		severity Severity
+		count    int
+		host     string
+	}{
+		{
+			name:     "duplicate alias",
+			content:  "Host web\n    HostName a\n\nHost db web\n    HostName b\n",
+			check:    CheckDuplicateAlias,
+			severity: SeverityWarning,
+			count:    1,
+			host:     "web",
+		},
+		{
+			name:     "shadowed by earlier wildcard",
+			content:  "Host *\n    User root\n\nHost web\n    HostName web\n    User deploy\n",
+			check:    CheckShadowedHost,
+			severity: SeverityWarning,
+			count:    1,
+			host:     "web",
+		},
+		{
+			name:    "same value under wildcard is not shadowing",
+			content: "Host *\n    User deploy\n\nHost web\n    User deploy\n",
+			check:   CheckShadowedHost,
+		},
+		{
+			name:    "later wildcard is not shadowing",
+			content: "Host web\n    User deploy\n\nHost *\n    User root\n",
+			check:   CheckShadowedHost,
+		},
+		{
+			name:     "missing identity file",
+			content:  "Host web\n    IdentityFile /nonexistent/id_ed25519\n",
+			check:    CheckMissingKey,
+			severity: SeverityWarning,
+			count:    1,
+			host:     "web",
+		},
+		{
+			name:    "identity file with tokens is skipped",
+			content: "Host web\n    IdentityFile ~/.ssh/%h_key\n",
+			check:   CheckMissingKey,
+		},
+		{
+			name:     "deprecated options",
+			content:  "Host web\n    Protocol 2\n    PubkeyAcceptedKeyTypes +ssh-rsa\n",
+			check:    CheckDeprecated,
+			severity: SeverityWarning,
+			count:    2,
+			host:     "web",
+		},
+		{
+			name:     "include cycle",
+			content:  "Include config\n\nHost web\n",
+			check:    CheckParse,
+			severity: SeverityError,
+			count:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+
+			findings, err := Run(Options{SSHConfigPath: path})
+			require.NoError(t, err)
+
+			got := findingsFor(findings, tt.check)
+			require.Len(t, got, tt.count, "%+v", findings)
+			for _, f := range got {
+				assert.Equal(t, tt.severity, f.Severity)
+				assert.Equal(t, tt.host, f.Host)
+				assert.NotEmpty(t, f.Message)
+				assert.False(t, f.Fixable)
+			}
+		})
+	}
+}
+
+func TestRun_ShadowedMessage(t *testing.T) {
+	path := writeConfig(t, "Host *\n    User root\n\nHost web\n    User deploy\n")
+
+	findings, err := Run(Options{SSHConfigPath: path})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "User deploy is ignored: Host * ("+path+":1) sets User root first", findings[0].Message)
+	assert.Equal(t, path+":4", findings[0].Location())
+}
+
+func TestRun_Permissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, os.WriteFile(keyPath, []byte("key"), 0644))
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(path, []byte("Host web\n    IdentityFile "+keyPath+"\n"), 0600))
+	require.NoError(t, os.Chmod(path, 0666))
+
+	findings, err := Run(Options{SSHConfigPath: path})
+	require.NoError(t, err)
+
+	perms := findingsFor(findings, CheckPermissions)
+	require.Len(t, perms, 2)
+	for _, f := range perms {
+		assert.Equal(t, SeverityError, f.Severity)
+		assert.True(t, f.Fixable)
+		require.NoError(t, f.Fix())
+	}
+
+	keyInfo, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), keyInfo.Mode().Perm())
+	configInfo, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), configInfo.Mode().Perm())
+
+	findings, err = Run(Options{SSHConfigPath: path})
+	require.NoError(t, err)
+	assert.Empty(t, findingsFor(findings, CheckPermissions))
+}
+
+func TestRun_VaultPublicKeys(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	pubLine := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	dir := t.TempDir()
+	creds := []*domain.Credential{{ID: "key-1", Name: "Deploy key", Type: domain.CredentialSSHAgent, PublicKey: pubLine}}
+	servers := []*domain.Server{{ID: "a", DisplayName: "web", Host: "web.example.com", User: "deploy", CredentialID: "key-1"}}
+	servers, err = sync.ApplyVaultKeys(servers, creds, filepath.Join(dir, "ssherpa_keys"))
+	require.NoError(t, err)
+
+	includePath := filepath.Join(dir, "ssherpa_config")
+	require.NoError(t, sync.WriteSSHIncludeFile(servers, includePath))
+
+	// A public key without the .pub suffix is recognised by its content.
+	copyPath := filepath.Join(dir, "deploy_key")
+	require.NoError(t, os.WriteFile(copyPath, []byte(pubLine+"\n"), 0644))
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(path, []byte("Include "+includePath+"\n\nHost db\n    IdentityFile "+copyPath+"\n"), 0600))
+
+	findings, err := Run(Options{SSHConfigPath: path})
+	require.NoError(t, err)
+	assert.Empty(t, findingsFor(findings, CheckPermissions))
+	assert.Empty(t, findingsFor(findings, CheckMissingKey))
+}
+
+func TestRun_Include(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"missing", "Host web\n    HostName web\n", 1},
+		{"misplaced", "Host web\n    HostName web\n\nInclude INCLUDE\n", 1},
+		{"at top", "Include INCLUDE\n\nHost web\n    HostName web\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			includePath := filepath.Join(dir, "ssherpa_config")
+			path := filepath.Join(dir, "config")
+			content := strings.ReplaceAll(tt.content, "INCLUDE", includePath)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+			opts := Options{SSHConfigPath: path, IncludePath: includePath}
+			findings, err := Run(opts)
+			require.NoError(t, err)
+
+			got := findingsFor(findings, CheckInclude)
+			require.Len(t, got, tt.want)
+			if tt.want == 0 {
+				return
+			}
+
+			assert.True(t, got[0].Fixable)
+			require.NoError(t, got[0].Fix())
+			fixed, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, sync.IncludeOK, sync.CheckIncludeDirective(string(fixed), includePath))
+
+			findings, err = Run(opts)
+			require.NoError(t, err)
+			assert.Empty(t, findingsFor(findings, CheckInclude))
+		})
+	}
+}
+
+func TestRun_SkippedItems(t *testing.T) {
+	path := writeConfig(t, "Host web\n    HostName web\n")
+
+	findings, err := Run(Options{
+		SSHConfigPath: path,
+		SkippedItems:  []onepassword.SkippedItem{{Title: "broken", VaultID: "v1", Reason: "hostname is required"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, CheckOnePassword, findings[0].Check)
+	assert.Contains(t, findings[0].Message, "hostname is required")
+	assert.Error(t, findings[0].Fix(), "skipped items have no automatic fix")
+}
+
+func TestRun_MissingConfig(t *testing.T) {
+	findings, err := Run(Options{SSHConfigPath: filepath.Join(t.TempDir(), "config")})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityInfo, findings[0].Severity)
+}
+
+func TestRun_SortsBySeverity(t *testing.T) {
+	path := writeConfig(t, "Host web\n    Protocol 2\n\nInclude config\n")
+
+	findings, err := Run(Options{SSHConfigPath: path})
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+	assert.Equal(t, SeverityWarning, findings[1].Severity)
+}
+
+func TestFinding_JSON(t *testing.T) {
+	data, err := json.Marshal(Finding{Check: CheckDeprecated, Severity: SeverityWarning, File: "/c", Line: 3, Message: "m"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"check":"deprecated-option","severity":"warning","file":"/c","line":3,"message":"m","fixable":false,"fixed":false}`, string(data))
+}
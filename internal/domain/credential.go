@@ -30,5 +30,7 @@ type Credential struct {
 	Name        string // human label, e.g., "Work SSH Key"
 	Type        CredentialType
 	KeyFilePath string // populated when Type == CredentialKeyFile
+	PublicKey   string // OpenSSH public key line, for keys held by an agent (e.g. 1Password SSH Key items)
+	Fingerprint string // SHA256 fingerprint of PublicKey
 	Notes       string
 }
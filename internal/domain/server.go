@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
 // Server represents an SSH server configuration with metadata.
 // Domain model is storage-agnostic — no struct tags for serialization.
@@ -17,9 +21,35 @@ type Server struct {
 	Favorite          bool
 	DisplayName       string   // human-friendly name
 	VPNRequired       bool     // flag to warn before connecting
-	CredentialID      string   // references a Credential by ID
+	CredentialID      string   // references a Credential by ID (1Password: an SSH Key item)
 	ProjectIDs        []string // server belongs to multiple projects
 	RemoteProjectPath string   // remote path on server for 'ssh user@host -t "cd /path && $SHELL"'
 	VaultID           string   // 1Password vault ID for write operations (empty for non-1P servers)
 	Source            string   // backend that provided this server (e.g., "ssh-config", "1password")
+	Revision          string   // backend change marker (1Password item version); empty if unknown
+
+	// SSHOptions holds further ssh_config directives (ForwardAgent, LocalForward,
+	// SetEnv, ...) keyed by keyword. Keywords that ssh accepts more than once
+	// carry several values, in order.
+	SSHOptions map[string][]string
+}
+
+// SSHOptionLines returns SSHOptions as "Keyword value" lines, sorted by
+// keyword, with repeated keywords keeping their order.
+func (s *Server) SSHOptionLines() []string {
+	keys := make([]string, 0, len(s.SSHOptions))
+	for key := range s.SSHOptions {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(keys[i]) < strings.ToLower(keys[j])
+	})
+
+	var lines []string
+	for _, key := range keys {
+		for _, value := range s.SSHOptions[key] {
+			lines = append(lines, key+" "+value)
+		}
+	}
+	return lines
 }
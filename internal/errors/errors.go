@@ -15,6 +15,8 @@ var (
 	ErrReadOnlyBackend    = errors.New("backend does not support write operations")
 	ErrDuplicateID        = errors.New("duplicate ID")
 	ErrValidation         = errors.New("validation error")
+	ErrVPNNotConnected    = errors.New("VPN not connected")
+	ErrAuthentication     = errors.New("authentication failed")
 )
 
 // BackendError wraps errors with operation and backend context.
@@ -0,0 +1,338 @@
+// Package output serializes servers, projects and credentials for scripting.
+//
+// All machine-readable formats share one schema per entity. Servers use
+// sync.CachedServer, so a TOML export is a valid 1Password cache file and
+// can be read back with sync.ReadTOMLCache.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// Format selects how a collection is rendered.
+type Format string
+
+const (
+	FormatTable Format = "table" // aligned columns for humans (default)
+	FormatJSON  Format = "json"  // single JSON array
+	FormatJSONL Format = "jsonl" // one JSON object per line
+	FormatTOML  Format = "toml"  // array of tables ([[server]], [[project]], [[credential]])
+	FormatCSV   Format = "csv"   // header row plus one row per entity
+)
+
+// Formats lists all supported formats in the order shown in help output.
+var Formats = []Format{FormatTable, FormatJSON, FormatJSONL, FormatTOML, FormatCSV}
+
+// ParseFormat validates a --format value (case-insensitive).
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("%w: unknown format %q (valid: %s)", errors.ErrValidation, s, strings.Join(names, ", "))
+}
+
+// listSeparator joins multi-value fields (tags, project IDs) in table and CSV output.
+const listSeparator = ";"
+
+// Project is the stable export schema for domain.Project.
+type Project struct {
+	ID            string    `toml:"id" json:"id"`
+	Name          string    `toml:"name" json:"name"`
+	Description   string    `toml:"description,omitempty" json:"description"`
+	GitRemoteURLs []string  `toml:"git_remote_urls,omitempty" json:"git_remote_urls"`
+	CreatedAt     time.Time `toml:"created_at,omitempty" json:"created_at,omitzero"`
+	UpdatedAt     time.Time `toml:"updated_at,omitempty" json:"updated_at,omitzero"`
+}
+
+// Credential is the stable export schema for domain.Credential.
+// Type uses snake_case identifiers ("key_file", "ssh_agent", "password")
+// rather than the display strings from CredentialType.String.
+type Credential struct {
+	ID          string `toml:"id" json:"id"`
+	Name        string `toml:"name" json:"name"`
+	Type        string `toml:"type" json:"type"`
+	KeyFilePath string `toml:"key_file_path,omitempty" json:"key_file_path"`
+	PublicKey   string `toml:"public_key,omitempty" json:"public_key"`
+	Fingerprint string `toml:"fingerprint,omitempty" json:"fingerprint"`
+	Notes       string `toml:"notes,omitempty" json:"notes"`
+}
+
+// NewProject converts a domain.Project to its export schema.
+func NewProject(p *domain.Project) Project {
+	return Project{
+		ID:            p.ID,
+		Name:          p.Name,
+		Description:   p.Description,
+		GitRemoteURLs: nonNil(p.GitRemoteURLs),
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// NewCredential converts a domain.Credential to its export schema.
+func NewCredential(c *domain.Credential) Credential {
+	return Credential{
+		ID:          c.ID,
+		Name:        c.Name,
+		Type:        credentialTypeID(c.Type),
+		KeyFilePath: c.KeyFilePath,
+		PublicKey:   c.PublicKey,
+		Fingerprint: c.Fingerprint,
+		Notes:       c.Notes,
+	}
+}
+
+// ToProject converts the export schema back to a domain.Project.
+func (p Project) ToProject() *domain.Project {
+	return &domain.Project{
+		ID:            p.ID,
+		Name:          p.Name,
+		Description:   p.Description,
+		GitRemoteURLs: p.GitRemoteURLs,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// ToCredential converts the export schema back to a domain.Credential.
+// Unknown type identifiers read as a key file.
+func (c Credential) ToCredential() *domain.Credential {
+	return &domain.Credential{
+		ID:          c.ID,
+		Name:        c.Name,
+		Type:        parseCredentialType(c.Type),
+		KeyFilePath: c.KeyFilePath,
+		PublicKey:   c.PublicKey,
+		Fingerprint: c.Fingerprint,
+		Notes:       c.Notes,
+	}
+}
+
+// credentialTypeID returns the stable identifier for a credential type.
+func credentialTypeID(t domain.CredentialType) string {
+	switch t {
+	case domain.CredentialKeyFile:
+		return "key_file"
+	case domain.CredentialSSHAgent:
+		return "ssh_agent"
+	case domain.CredentialPassword:
+		return "password"
+	default:
+		return "unknown"
+	}
+}
+
+// parseCredentialType is the inverse of credentialTypeID.
+func parseCredentialType(id string) domain.CredentialType {
+	switch id {
+	case "ssh_agent":
+		return domain.CredentialSSHAgent
+	case "password":
+		return domain.CredentialPassword
+	default:
+		return domain.CredentialKeyFile
+	}
+}
+
+// newServer converts a domain.Server to the shared cache schema.
+// Nil slices and maps become empty so JSON consumers always see arrays and
+// objects, never null.
+func newServer(s *domain.Server) sync.CachedServer {
+	cached := sync.NewCachedServer(s)
+	cached.ProjectIDs = nonNil(cached.ProjectIDs)
+	cached.Tags = nonNil(cached.Tags)
+	if cached.SSHOptions == nil {
+		cached.SSHOptions = map[string][]string{}
+	}
+	return cached
+}
+
+// nonNil returns an empty slice in place of nil.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// WriteServers renders servers in the given format.
+func WriteServers(w io.Writer, format Format, servers []*domain.Server) error {
+	records := make([]sync.CachedServer, 0, len(servers))
+	for _, s := range servers {
+		records = append(records, newServer(s))
+	}
+
+	switch format {
+	case FormatTable:
+		return writeTable(w, []string{"NAME", "HOST", "USER", "PORT", "SOURCE"}, len(records), func(i int) []string {
+			r := records[i]
+			return []string{r.DisplayName, r.Host, r.User, portString(r.Port), r.Source}
+		})
+	case FormatCSV:
+		header := []string{
+			"id", "display_name", "host", "user", "port", "identity_file", "proxy",
+			"remote_project_path", "project_ids", "vault_id", "tags", "notes", "source",
+			"favorite", "vpn_required", "credential_id", "ssh_options",
+		}
+		return writeCSV(w, header, len(records), func(i int) []string {
+			r := records[i]
+			options := (&domain.Server{SSHOptions: r.SSHOptions}).SSHOptionLines()
+			return []string{
+				r.ID, r.DisplayName, r.Host, r.User, portString(r.Port), r.IdentityFile, r.Proxy,
+				r.RemoteProjectPath, strings.Join(r.ProjectIDs, listSeparator), r.VaultID,
+				strings.Join(r.Tags, listSeparator), r.Notes, r.Source,
+				strconv.FormatBool(r.Favorite), strconv.FormatBool(r.VPNRequired), r.CredentialID,
+				strings.Join(options, listSeparator),
+			}
+		})
+	case FormatTOML:
+		return writeTOML(w, struct {
+			Servers []sync.CachedServer `toml:"server"`
+		}{records})
+	default:
+		return writeJSON(w, format, records)
+	}
+}
+
+// WriteProjects renders projects in the given format.
+func WriteProjects(w io.Writer, format Format, projects []*domain.Project) error {
+	records := make([]Project, 0, len(projects))
+	for _, p := range projects {
+		records = append(records, NewProject(p))
+	}
+
+	switch format {
+	case FormatTable:
+		return writeTable(w, []string{"ID", "NAME", "GIT REMOTES"}, len(records), func(i int) []string {
+			r := records[i]
+			return []string{r.ID, r.Name, strings.Join(r.GitRemoteURLs, listSeparator)}
+		})
+	case FormatCSV:
+		header := []string{"id", "name", "description", "git_remote_urls", "created_at", "updated_at"}
+		return writeCSV(w, header, len(records), func(i int) []string {
+			r := records[i]
+			return []string{
+				r.ID, r.Name, r.Description, strings.Join(r.GitRemoteURLs, listSeparator),
+				timeString(r.CreatedAt), timeString(r.UpdatedAt),
+			}
+		})
+	case FormatTOML:
+		return writeTOML(w, struct {
+			Projects []Project `toml:"project"`
+		}{records})
+	default:
+		return writeJSON(w, format, records)
+	}
+}
+
+// WriteCredentials renders credentials in the given format.
+func WriteCredentials(w io.Writer, format Format, creds []*domain.Credential) error {
+	records := make([]Credential, 0, len(creds))
+	for _, c := range creds {
+		records = append(records, NewCredential(c))
+	}
+
+	switch format {
+	case FormatTable:
+		return writeTable(w, []string{"ID", "NAME", "TYPE", "KEY FILE"}, len(records), func(i int) []string {
+			r := records[i]
+			return []string{r.ID, r.Name, r.Type, r.KeyFilePath}
+		})
+	case FormatCSV:
+		header := []string{"id", "name", "type", "key_file_path", "public_key", "fingerprint", "notes"}
+		return writeCSV(w, header, len(records), func(i int) []string {
+			r := records[i]
+			return []string{r.ID, r.Name, r.Type, r.KeyFilePath, r.PublicKey, r.Fingerprint, r.Notes}
+		})
+	case FormatTOML:
+		return writeTOML(w, struct {
+			Credentials []Credential `toml:"credential"`
+		}{records})
+	default:
+		return writeJSON(w, format, records)
+	}
+}
+
+// writeJSON renders records as a JSON array or as JSON Lines.
+func writeJSON[T any](w io.Writer, format Format, records []T) error {
+	enc := json.NewEncoder(w)
+	switch format {
+	case FormatJSON:
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatJSONL:
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown format %q", errors.ErrValidation, format)
+	}
+}
+
+// writeTOML encodes a document whose single key holds the record array.
+func writeTOML(w io.Writer, doc any) error {
+	return toml.NewEncoder(w).Encode(doc)
+}
+
+// writeCSV writes a header row followed by one row per record.
+func writeCSV(w io.Writer, header []string, n int, row func(i int) []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := cw.Write(row(i)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeTable writes tab-aligned columns for terminal output.
+func writeTable(w io.Writer, header []string, n int, row func(i int) []string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for i := 0; i < n; i++ {
+		_, _ = fmt.Fprintln(tw, strings.Join(row(i), "\t"))
+	}
+	return tw.Flush()
+}
+
+// portString formats a port, leaving it blank when unset.
+func portString(port int) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.Itoa(port)
+}
+
+// timeString formats a timestamp as RFC 3339, leaving it blank when unset.
+func timeString(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
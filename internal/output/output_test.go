@@ -0,0 +1,287 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+func testServers() []*domain.Server {
+	return []*domain.Server{
+		{
+			ID:                "srv-001",
+			DisplayName:       "prod-web",
+			Host:              "prod.example.com",
+			User:              "deploy",
+			Port:              2222,
+			IdentityFile:      "~/.ssh/id_ed25519",
+			Proxy:             "bastion",
+			RemoteProjectPath: "/var/www/app",
+			ProjectIDs:        []string{"payments"},
+			VaultID:           "vault-1",
+			Tags:              []string{"prod", "web"},
+			Source:            "1password",
+			Favorite:          true,
+			SSHOptions:        map[string][]string{"ForwardAgent": {"yes"}, "LocalForward": {"8080 localhost:80"}},
+		},
+		{
+			ID:          "staging",
+			DisplayName: "staging",
+			Host:        "staging.example.com",
+			User:        "ubuntu",
+			Port:        22,
+			Notes:       "Source: /home/u/.ssh/config:12",
+			Source:      "ssh-config",
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"table", FormatTable, false},
+		{"json", FormatJSON, false},
+		{"JSONL", FormatJSONL, false},
+		{"toml", FormatTOML, false},
+		{"csv", FormatCSV, false},
+		{"yaml", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, errors.ErrValidation))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriteServers_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteServers(&buf, FormatJSON, testServers()))
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded, 2)
+
+	// Schema is stable: every key is present even when empty
+	expectedKeys := []string{
+		"id", "display_name", "host", "user", "port", "identity_file", "proxy",
+		"remote_project_path", "project_ids", "vault_id", "tags", "notes", "source",
+		"favorite", "vpn_required", "credential_id", "ssh_options",
+	}
+	for _, record := range decoded {
+		assert.Len(t, record, len(expectedKeys))
+		for _, key := range expectedKeys {
+			assert.Contains(t, record, key)
+		}
+	}
+
+	assert.Equal(t, "prod-web", decoded[0]["display_name"])
+	assert.Equal(t, float64(2222), decoded[0]["port"])
+	assert.Equal(t, true, decoded[0]["favorite"])
+
+	// Empty slices are arrays, not null
+	assert.Equal(t, []any{}, decoded[1]["tags"])
+	assert.Equal(t, []any{}, decoded[1]["project_ids"])
+	assert.Equal(t, map[string]any{}, decoded[1]["ssh_options"])
+}
+
+func TestWriteServers_JSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteServers(&buf, FormatJSON, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteServers_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteServers(&buf, FormatJSONL, testServers()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first sync.CachedServer
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "srv-001", first.ID)
+	assert.Equal(t, []string{"prod", "web"}, first.Tags)
+}
+
+func TestWriteServers_TOMLRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteServers(&buf, FormatTOML, testServers()))
+	assert.Contains(t, buf.String(), "[[server]]")
+
+	// The export must be readable as a 1Password cache file
+	path := filepath.Join(t.TempDir(), "export.toml")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+
+	servers, err := sync.ReadTOMLCache(path)
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+
+	original := testServers()
+	assert.Equal(t, original[0].ID, servers[0].ID)
+	assert.Equal(t, original[0].Host, servers[0].Host)
+	assert.Equal(t, original[0].Port, servers[0].Port)
+	assert.Equal(t, original[0].RemoteProjectPath, servers[0].RemoteProjectPath)
+	assert.Equal(t, original[0].ProjectIDs, servers[0].ProjectIDs)
+	assert.Equal(t, original[0].Tags, servers[0].Tags)
+	assert.Equal(t, original[0].Favorite, servers[0].Favorite)
+	assert.Equal(t, original[1].Notes, servers[1].Notes)
+	assert.Equal(t, original[1].Source, servers[1].Source)
+}
+
+func TestWriteServers_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteServers(&buf, FormatCSV, testServers()))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	header := rows[0]
+	assert.Equal(t, "id", header[0])
+	assert.Equal(t, "display_name", header[1])
+
+	col := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("column %q missing", name)
+		return -1
+	}
+
+	assert.Equal(t, "prod-web", rows[1][col("display_name")])
+	assert.Equal(t, "2222", rows[1][col("port")])
+	assert.Equal(t, "prod;web", rows[1][col("tags")])
+	assert.Equal(t, "true", rows[1][col("favorite")])
+	assert.Equal(t, "", rows[2][col("tags")])
+	assert.Equal(t, "ForwardAgent yes;LocalForward 8080 localhost:80", rows[1][col("ssh_options")])
+}
+
+func TestWriteServers_Table(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteServers(&buf, FormatTable, testServers()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "NAME"))
+	assert.Contains(t, lines[1], "prod.example.com")
+	assert.Contains(t, lines[2], "ssh-config")
+}
+
+func TestWriteProjects(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	projects := []*domain.Project{
+		{ID: "payments", Name: "Payments API", GitRemoteURLs: []string{"git@github.com:acme/payments.git"}, CreatedAt: created},
+		{ID: "infra", Name: "Infra"},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteProjects(&buf, FormatJSON, projects))
+
+		var decoded []map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded, 2)
+		assert.Equal(t, "2026-01-02T03:04:05Z", decoded[0]["created_at"])
+		assert.NotContains(t, decoded[1], "created_at")
+		assert.Equal(t, []any{}, decoded[1]["git_remote_urls"])
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteProjects(&buf, FormatTOML, projects))
+		assert.Contains(t, buf.String(), "[[project]]")
+		assert.Contains(t, buf.String(), `name = "Payments API"`)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteProjects(&buf, FormatCSV, projects))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, []string{"id", "name", "description", "git_remote_urls", "created_at", "updated_at"}, rows[0])
+		assert.Equal(t, "2026-01-02T03:04:05Z", rows[1][4])
+		assert.Equal(t, "", rows[2][4])
+	})
+}
+
+func TestWriteCredentials(t *testing.T) {
+	creds := []*domain.Credential{
+		{ID: "cred-1", Name: "Work key", Type: domain.CredentialKeyFile, KeyFilePath: "~/.ssh/work"},
+		{ID: "cred-2", Name: "Agent", Type: domain.CredentialSSHAgent, PublicKey: "ssh-ed25519 AAAA deploy", Fingerprint: "SHA256:abc"},
+	}
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteCredentials(&buf, FormatJSONL, creds))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		var first Credential
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		assert.Equal(t, "key_file", first.Type)
+		assert.Equal(t, "~/.ssh/work", first.KeyFilePath)
+
+		var second Credential
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+		assert.Equal(t, "ssh-ed25519 AAAA deploy", second.PublicKey)
+		assert.Equal(t, "SHA256:abc", second.Fingerprint)
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteCredentials(&buf, FormatTOML, creds))
+		assert.Contains(t, buf.String(), "[[credential]]")
+		assert.Contains(t, buf.String(), `type = "ssh_agent"`)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteCredentials(&buf, FormatCSV, creds))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, []string{"id", "name", "type", "key_file_path", "public_key", "fingerprint", "notes"}, rows[0])
+		assert.Equal(t, []string{"cred-1", "Work key", "key_file", "~/.ssh/work", "", "", ""}, rows[1])
+		assert.Equal(t, []string{"cred-2", "Agent", "ssh_agent", "", "ssh-ed25519 AAAA deploy", "SHA256:abc", ""}, rows[2])
+	})
+}
+
+func TestProjectAndCredentialRoundTrip(t *testing.T) {
+	project := &domain.Project{ID: "payments", Name: "Payments API", GitRemoteURLs: []string{"git@github.com:acme/payments.git"}}
+	assert.Equal(t, project, NewProject(project).ToProject())
+
+	for _, typ := range []domain.CredentialType{domain.CredentialKeyFile, domain.CredentialSSHAgent, domain.CredentialPassword} {
+		cred := &domain.Credential{ID: "c", Name: "key", Type: typ, KeyFilePath: "~/.ssh/k"}
+		assert.Equal(t, cred, NewCredential(cred).ToCredential())
+	}
+}
@@ -3,6 +3,7 @@ package ssh
 import (
 	"os"
 	"os/exec"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -13,21 +14,80 @@ type SSHFinishedMsg struct {
 	HostName string
 }
 
-// ConnectSSH creates a Bubbletea command that hands off terminal control to SSH
-// using the host alias from SSH config. This leverages the user's existing
-// ~/.ssh/config settings (ProxyJump, IdentityFile, Port, etc.) automatically.
-func ConnectSSH(hostName string) tea.Cmd {
-	cmd := exec.Command("ssh", hostName)
+// Options controls how a connection is opened.
+type Options struct {
+	// RemotePath is a directory to cd into on the remote host before starting
+	// a login shell. Empty means a plain "ssh <alias>".
+	RemotePath string
+}
+
+// Args returns the ssh arguments for a host alias.
+// With a RemotePath the session is forced to allocate a TTY and runs
+// "cd <path> && exec $SHELL -l" so the user lands in the project directory.
+func Args(hostName string, opts Options) []string {
+	if opts.RemotePath == "" {
+		return []string{hostName}
+	}
+	return []string{"-t", hostName, RemoteCommand(opts.RemotePath)}
+}
+
+// RemoteCommand builds the remote shell command that changes into path and
+// replaces itself with the user's login shell.
+func RemoteCommand(path string) string {
+	return "cd " + QuotePath(path) + " && exec $SHELL -l"
+}
+
+// QuotePath quotes a remote path for a POSIX shell.
+// A leading "~" or "~/" is left unquoted so the remote shell still expands it
+// to the remote user's home directory.
+func QuotePath(path string) string {
+	switch {
+	case path == "~":
+		return "~"
+	case strings.HasPrefix(path, "~/"):
+		rest := path[2:]
+		if rest == "" {
+			return "~/"
+		}
+		return "~/" + ShellQuote(rest)
+	default:
+		return ShellQuote(path)
+	}
+}
+
+// ShellQuote wraps s in single quotes for a POSIX shell.
+// Embedded single quotes are closed, backslash-escaped and reopened.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Command builds the ssh command for a host alias with the terminal I/O attached.
+// Using the alias leverages the user's existing ~/.ssh/config settings
+// (ProxyJump, IdentityFile, Port, etc.) automatically.
+func Command(hostName string, opts Options) *exec.Cmd {
+	cmd := exec.Command("ssh", Args(hostName, opts)...)
 
 	// Critical: Connect terminal I/O for silent handoff
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+	return cmd
+}
+
+// ConnectSSH creates a Bubbletea command that hands off terminal control to SSH
+// using the host alias from SSH config.
+func ConnectSSH(hostName string, opts Options) tea.Cmd {
+	return tea.ExecProcess(Command(hostName, opts), func(err error) tea.Msg {
 		return SSHFinishedMsg{
 			Err:      err,
 			HostName: hostName,
 		}
 	})
 }
+
+// Run connects to the host in the foreground and blocks until ssh exits.
+// Used by the non-interactive CLI where no Bubbletea program owns the terminal.
+func Run(hostName string, opts Options) error {
+	return Command(hostName, opts).Run()
+}
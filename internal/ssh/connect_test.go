@@ -0,0 +1,72 @@
+package ssh
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/var/www/app", `'/var/www/app'`},
+		{"", `''`},
+		{"/srv/my app", `'/srv/my app'`},
+		{"/srv/it's", `'/srv/it'\''s'`},
+		{"/tmp/$(rm -rf ~)", `'/tmp/$(rm -rf ~)'`},
+		{"/a;b&&c|d`e`", "'/a;b&&c|d`e`'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShellQuote(tt.input))
+		})
+	}
+}
+
+func TestQuotePath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/opt/app", `'/opt/app'`},
+		{"~", `~`},
+		{"~/", `~/`},
+		{"~/projects/app", `~/'projects/app'`},
+		{"~other/app", `'~other/app'`},
+		{"~/it's here", `~/'it'\''s here'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, QuotePath(tt.input))
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	assert.Equal(t, []string{"web"}, Args("web", Options{}))
+	assert.Equal(t,
+		[]string{"-t", "web", `cd '/var/www/app' && exec $SHELL -l`},
+		Args("web", Options{RemotePath: "/var/www/app"}))
+}
+
+func TestRemoteCommand_ShellEvaluation(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	// A hostile path must reach cd as a single literal argument
+	dir := t.TempDir() + "/it's $(echo pwned); ls"
+	require.NoError(t, exec.Command("mkdir", "-p", dir).Run())
+
+	cmd := "cd " + QuotePath(dir) + " && pwd"
+	out, err := exec.Command(sh, "-c", cmd).Output()
+	require.NoError(t, err)
+	assert.Equal(t, dir+"\n", string(out))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/florianriquelme/ssherpa/internal/backend"
@@ -11,17 +12,28 @@ import (
 	"github.com/florianriquelme/ssherpa/internal/errors"
 )
 
-// Backend implements backend.Backend interface for SSH config files.
+// Backend implements backend.Backend and backend.Filterer for SSH config files.
 // Read-only backend that parses ~/.ssh/config and exposes hosts as domain.Server.
 // Does NOT implement backend.Writer interface.
 type Backend struct {
-	hosts  []SSHHost
-	closed bool
-	mu     sync.RWMutex
+	hosts    []SSHHost
+	metadata map[string]HostMetadata // Lowercased alias -> ssherpa metadata
+	closed   bool
+	mu       sync.RWMutex
+}
+
+// HostMetadata carries ssherpa fields for a host that SSH config can't express.
+// It is stored in the ssherpa config file and applied when hosts become servers.
+type HostMetadata struct {
+	Favorite    bool
+	VPNRequired bool
+	Tags        []string
+	ProjectIDs  []string // Projects listing the alias in server_names
 }
 
 // Compile-time interface verification
 var _ backend.Backend = (*Backend)(nil)
+var _ backend.Filterer = (*Backend)(nil)
 
 // New creates a new sshconfig backend by parsing the SSH config file at configPath.
 func New(configPath string) (*Backend, error) {
@@ -39,6 +51,18 @@ func New(configPath string) (*Backend, error) {
 	}, nil
 }
 
+// SetMetadata replaces the ssherpa metadata applied to hosts, keyed by alias
+// (case-insensitive).
+func (b *Backend) SetMetadata(metadata map[string]HostMetadata) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.metadata = make(map[string]HostMetadata, len(metadata))
+	for alias, meta := range metadata {
+		b.metadata[strings.ToLower(alias)] = meta
+	}
+}
+
 // GetServer retrieves a server by ID (Host pattern name).
 func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, error) {
 	b.mu.RLock()
@@ -54,7 +78,7 @@ func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, err
 
 	// Linear search through hosts (config files are small)
 	for _, host := range b.hosts {
-		if host.Name == id {
+		if host.Name == id && !host.IsMatch {
 			// Return a copy (copy-on-read pattern)
 			server := b.toServer(host)
 			return &server, nil
@@ -70,6 +94,7 @@ func (b *Backend) GetServer(ctx context.Context, id string) (*domain.Server, err
 
 // ListServers returns all hosts as domain.Server entries.
 // Includes ALL hosts, even those with ParseError (shown with warning indicator in TUI).
+// Match blocks are skipped: they are rules, not servers.
 func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -85,6 +110,9 @@ func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
 	// Convert all hosts to domain.Server
 	servers := make([]*domain.Server, 0, len(b.hosts))
 	for _, host := range b.hosts {
+		if host.IsMatch {
+			continue
+		}
 		server := b.toServer(host)
 		servers = append(servers, &server)
 	}
@@ -92,6 +120,16 @@ func (b *Backend) ListServers(ctx context.Context) ([]*domain.Server, error) {
 	return servers, nil
 }
 
+// FilterServers returns the hosts matching the filter.
+// Favorites, tags and projects come from the metadata set with SetMetadata.
+func (b *Backend) FilterServers(ctx context.Context, filters backend.ServerFilter) ([]*domain.Server, error) {
+	servers, err := b.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backend.ApplyFilter(servers, filters), nil
+}
+
 // GetProject always returns ErrProjectNotFound (SSH config has no projects).
 func (b *Backend) GetProject(ctx context.Context, id string) (*domain.Project, error) {
 	b.mu.RLock()
@@ -182,7 +220,7 @@ func (b *Backend) toServer(host SSHHost) domain.Server {
 		Host:        host.Hostname,
 		User:        host.User,
 		Port:        parsePort(host.Port),
-		Tags:        []string{}, // SSH config has no tag concept
+		Tags:        []string{}, // SSH config has no tag concept (see HostMetadata)
 		Source:      "ssh-config",
 	}
 
@@ -201,6 +239,18 @@ func (b *Backend) toServer(host SSHHost) domain.Server {
 		server.Proxy = proxyJump[0]
 	}
 
+	// Apply ssherpa metadata stored outside the SSH config
+	if meta, ok := b.metadata[strings.ToLower(host.Name)]; ok {
+		server.Favorite = meta.Favorite
+		server.VPNRequired = meta.VPNRequired
+		if len(meta.Tags) > 0 {
+			server.Tags = append([]string{}, meta.Tags...)
+		}
+		if len(meta.ProjectIDs) > 0 {
+			server.ProjectIDs = append([]string{}, meta.ProjectIDs...)
+		}
+	}
+
 	// Set Notes with source file information
 	if host.ParseError != nil {
 		server.Notes = fmt.Sprintf("Parse error: %v (Source: %s:%d)",
@@ -119,6 +119,90 @@ Host server1
 	assert.Equal(t, "alice", server.User)
 }
 
+func TestBackendSetMetadata(t *testing.T) {
+	content := `
+Host web
+    HostName web.example.com
+
+Host db
+    HostName 10.0.0.5
+`
+
+	tmpFile := createTempConfig(t, content)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	b, err := New(tmpFile)
+	require.NoError(t, err)
+
+	b.SetMetadata(map[string]HostMetadata{
+		"WEB": {Favorite: true, Tags: []string{"prod"}},
+		"db":  {VPNRequired: true},
+	})
+
+	web, err := b.GetServer(context.Background(), "web")
+	require.NoError(t, err)
+	assert.True(t, web.Favorite)
+	assert.False(t, web.VPNRequired)
+	assert.Equal(t, []string{"prod"}, web.Tags)
+
+	db, err := b.GetServer(context.Background(), "db")
+	require.NoError(t, err)
+	assert.False(t, db.Favorite)
+	assert.True(t, db.VPNRequired)
+	assert.Equal(t, []string{}, db.Tags)
+}
+
+func TestBackendFilterServers(t *testing.T) {
+	content := `
+Host web
+    HostName web.example.com
+    User deploy
+
+Host db
+    HostName 10.0.0.5
+    User postgres
+
+Host staging
+    HostName staging.example.com
+`
+
+	tmpFile := createTempConfig(t, content)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	b, err := New(tmpFile)
+	require.NoError(t, err)
+	b.SetMetadata(map[string]HostMetadata{
+		"web": {Favorite: true, Tags: []string{"prod"}, ProjectIDs: []string{"payments"}},
+		"db":  {Tags: []string{"prod", "db"}},
+	})
+
+	yes := true
+	tests := []struct {
+		name   string
+		filter backend.ServerFilter
+		want   []string
+	}{
+		{"empty filter", backend.ServerFilter{}, []string{"web", "db", "staging"}},
+		{"tags from metadata", backend.ServerFilter{Tags: []string{"PROD"}}, []string{"web", "db"}},
+		{"project from metadata", backend.ServerFilter{ProjectID: "payments"}, []string{"web"}},
+		{"favorite from metadata", backend.ServerFilter{Favorite: &yes}, []string{"web"}},
+		{"query", backend.ServerFilter{Query: "postgres"}, []string{"db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, err := b.FilterServers(context.Background(), tt.filter)
+			require.NoError(t, err)
+
+			ids := make([]string, len(servers))
+			for i, s := range servers {
+				ids[i] = s.ID
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
 func TestBackendGetServer_NotFound(t *testing.T) {
 	content := `
 Host server1
@@ -341,8 +425,7 @@ Host myserver.com
 func TestBackendParseErrorHandling(t *testing.T) {
 	// Test that hosts with ParseError are included in listings
 	content := `
-Match host prod-*
-    User admin
+Include /
 `
 
 	tmpFile := createTempConfig(t, content)
@@ -358,7 +441,31 @@ Match host prod-*
 
 	// Should have a server with parse error in Notes
 	assert.Contains(t, servers[0].Notes, "Parse error")
-	assert.Contains(t, servers[0].Notes, "Match")
+	assert.Contains(t, servers[0].Notes, "Include")
+}
+
+func TestBackendSkipsMatchBlocks(t *testing.T) {
+	content := `
+Host web
+    HostName web.example.com
+
+Match host prod-* exec "test -f ~/.vpn"
+    User admin
+`
+
+	tmpFile := createTempConfig(t, content)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	b, err := New(tmpFile)
+	require.NoError(t, err)
+
+	servers, err := b.ListServers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "web", servers[0].ID)
+
+	_, err = b.GetServer(context.Background(), `Match host prod-* exec "test -f ~/.vpn"`)
+	assert.True(t, errors.Is(err, errors.ErrServerNotFound))
 }
 
 func TestBackendCopyOnRead(t *testing.T) {
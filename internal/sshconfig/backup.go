@@ -3,6 +3,7 @@ package sshconfig
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/google/renameio/v2/maybe"
 )
@@ -12,20 +13,35 @@ import (
 // Uses the same file permissions as the original file.
 // Returns an error if the source file doesn't exist.
 func CreateBackup(configPath string) error {
+	return copyBackup(configPath, configPath+".bak")
+}
+
+// backupHostFile backs up a file that defines a host before it is edited.
+// The main config gets the usual configPath + ".bak". Included files are backed
+// up next to the main config instead, so the backup can't be picked up by a
+// glob such as "Include conf.d/*" and duplicate every host in it.
+func backupHostFile(rootPath, file string) error {
+	if rootAbs, err := filepath.Abs(rootPath); err == nil && rootAbs == file {
+		return CreateBackup(file)
+	}
+	return copyBackup(file, filepath.Join(filepath.Dir(rootPath), filepath.Base(file)+".bak"))
+}
+
+// copyBackup copies src to backupPath with the same file permissions.
+func copyBackup(src, backupPath string) error {
 	// Check if source file exists and get its permissions
-	info, err := os.Stat(configPath)
+	info, err := os.Stat(src)
 	if err != nil {
 		return fmt.Errorf("stat source file: %w", err)
 	}
 
 	// Read the source file contents
-	data, err := os.ReadFile(configPath)
+	data, err := os.ReadFile(src)
 	if err != nil {
 		return fmt.Errorf("read source file: %w", err)
 	}
 
 	// Write backup file with same permissions
-	backupPath := configPath + ".bak"
 	if err := os.WriteFile(backupPath, data, info.Mode()); err != nil {
 		return fmt.Errorf("write backup file: %w", err)
 	}
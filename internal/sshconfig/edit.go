@@ -0,0 +1,326 @@
+package sshconfig
+
+import (
+	"sort"
+	"strings"
+)
+
+// managedKeys are the directives HostEntry holds in dedicated fields.
+// Everything else travels in ExtraConfig.
+var managedKeys = []string{"HostName", "User", "Port", "IdentityFile"}
+
+// EntryFromHost builds the HostEntry for an existing host, so that passing it
+// unchanged to EditHost leaves the file byte-for-byte identical.
+// ExtraConfig lists the non-managed options one per line, sorted by keyword.
+func EntryFromHost(host SSHHost) HostEntry {
+	entry := HostEntry{Alias: firstPattern(host.Name)}
+
+	var extra []string
+	keys := make([]string, 0, len(host.AllOptions))
+	for key := range host.AllOptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		values := host.AllOptions[key]
+		if len(values) == 0 {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "hostname":
+			entry.Hostname = values[0]
+		case "user":
+			entry.User = values[0]
+		case "port":
+			entry.Port = values[0]
+		case "identityfile":
+			entry.IdentityFile = values[0]
+		default:
+			for _, val := range values {
+				extra = append(extra, key+" "+val)
+			}
+		}
+	}
+	entry.ExtraConfig = strings.Join(extra, "\n")
+	return entry
+}
+
+// ParseDirectives parses "Keyword value" lines (ExtraConfig text) into a map
+// from keyword to values in written order. Repeated keywords are merged
+// case-insensitively under their first spelling; comments, blank lines and
+// keywords without a value are skipped.
+func ParseDirectives(text string) map[string][]string {
+	options := make(map[string][]string)
+	spelling := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		l, ok := parseConfigLine(line)
+		if !ok || l.value == "" {
+			continue
+		}
+		key := strings.ToLower(l.keyword)
+		if _, seen := spelling[key]; !seen {
+			spelling[key] = l.keyword
+		}
+		options[spelling[key]] = append(options[spelling[key]], l.value)
+	}
+	return options
+}
+
+// configLine is one directive line split into the parts an edit must preserve.
+// Reassembling indent+keyword+sep+value+trailer gives back the original line.
+type configLine struct {
+	indent  string // leading whitespace
+	keyword string // keyword as written (casing kept)
+	sep     string // separator as written: spaces, tabs and/or "="
+	value   string // value without trailing comment or whitespace
+	trailer string // trailing whitespace and inline comment
+}
+
+// parseConfigLine splits a directive line. Returns false for blank lines and comments.
+func parseConfigLine(line string) (configLine, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return configLine{}, false
+	}
+	l := configLine{indent: line[:len(line)-len(trimmed)]}
+
+	end := strings.IndexAny(trimmed, " \t=")
+	if end == -1 {
+		l.keyword = trimmed
+		return l, true
+	}
+	l.keyword = trimmed[:end]
+	rest := trimmed[end:]
+
+	// Separator: whitespace with at most one "="
+	i := 0
+	seenEquals := false
+	for i < len(rest) && (rest[i] == ' ' || rest[i] == '\t' || (rest[i] == '=' && !seenEquals)) {
+		seenEquals = seenEquals || rest[i] == '='
+		i++
+	}
+	l.sep = rest[:i]
+	rest = rest[i:]
+
+	// Inline comments start at a "#" preceded by whitespace
+	valueEnd := len(rest)
+	for j := 1; j < len(rest); j++ {
+		if rest[j] == '#' && (rest[j-1] == ' ' || rest[j-1] == '\t') {
+			valueEnd = j
+			break
+		}
+	}
+	l.value = strings.TrimRight(rest[:valueEnd], " \t")
+	l.trailer = rest[len(l.value):]
+	return l, true
+}
+
+// String reassembles the line.
+func (l configLine) String() string {
+	return l.indent + l.keyword + l.sep + l.value + l.trailer
+}
+
+// directiveStyle is the indentation and separator new lines in a block copy.
+type directiveStyle struct {
+	indent string
+	sep    string
+}
+
+// blockStyle infers how a block writes its directives from its first option line,
+// falling back to the Host line's separator and four-space indentation.
+func blockStyle(block []string) directiveStyle {
+	style := directiveStyle{indent: "    ", sep: " "}
+	if host, ok := parseConfigLine(block[0]); ok && host.sep != "" {
+		style.sep = host.sep
+	}
+	for _, line := range block[1:] {
+		if l, ok := parseConfigLine(line); ok {
+			style.indent = l.indent
+			if l.sep != "" {
+				style.sep = l.sep
+			}
+			break
+		}
+	}
+	return style
+}
+
+// desiredOption is one keyword and the values an edit should leave in the block.
+type desiredOption struct {
+	keyword string
+	values  []string
+}
+
+// editHostBlock applies entry to an existing Host block (block[0] is the Host
+// line) and returns the new lines. Only directives whose values differ are
+// touched: changed values are rewritten in place keeping indentation, keyword
+// casing, separator and inline comments; removed directives are dropped; new
+// ones are appended after the block's last directive in the block's own style.
+// Comments and blank lines inside the block are kept.
+func editHostBlock(block []string, entry HostEntry) []string {
+	existing := make(map[string][]int) // lowercased keyword -> line indexes
+	lastDirective := 0
+	for i := 1; i < len(block); i++ {
+		if l, ok := parseConfigLine(block[i]); ok {
+			key := strings.ToLower(l.keyword)
+			existing[key] = append(existing[key], i)
+			lastDirective = i
+		}
+	}
+
+	currentValues := func(key string) []string {
+		var values []string
+		for _, idx := range existing[key] {
+			l, _ := parseConfigLine(block[idx])
+			values = append(values, l.value)
+		}
+		return values
+	}
+	desired := desiredOptions(entry, currentValues)
+
+	replace := make(map[int]string)
+	drop := make(map[int]bool)
+	after := make(map[int][]string)
+	style := blockStyle(block)
+
+	// Host line: an alias swaps the first pattern, a pattern list replaces them all
+	if host, ok := parseConfigLine(block[0]); ok {
+		patterns := strings.Fields(host.value)
+		switch {
+		case len(strings.Fields(entry.Alias)) > 1:
+			if strings.Join(patterns, " ") != entry.Alias {
+				host.value = entry.Alias
+				replace[0] = host.String()
+			}
+		case len(patterns) > 0 && patterns[0] != entry.Alias:
+			host.value = entry.Alias + strings.TrimPrefix(host.value, patterns[0])
+			replace[0] = host.String()
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, opt := range desired {
+		key := strings.ToLower(opt.keyword)
+		wanted[key] = true
+		lines := existing[key]
+
+		for i, idx := range lines {
+			if i >= len(opt.values) {
+				drop[idx] = true
+				continue
+			}
+			l, _ := parseConfigLine(block[idx])
+			if l.value != opt.values[i] {
+				if l.sep == "" {
+					l.sep = style.sep
+				}
+				l.value = opt.values[i]
+				replace[idx] = l.String()
+			}
+		}
+
+		// Extra values go after the keyword's last line, or at the end of the block
+		anchor := lastDirective
+		if len(lines) > 0 {
+			anchor = lines[len(lines)-1]
+		}
+		for _, val := range opt.values[min(len(lines), len(opt.values)):] {
+			after[anchor] = append(after[anchor], style.indent+opt.keyword+style.sep+val)
+		}
+	}
+
+	// Directives no longer present
+	for key, lines := range existing {
+		if !wanted[key] {
+			for _, idx := range lines {
+				drop[idx] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(block))
+	for i, line := range block {
+		if !drop[i] {
+			if newLine, ok := replace[i]; ok {
+				line = newLine
+			}
+			result = append(result, line)
+		}
+		result = append(result, after[i]...)
+	}
+	return result
+}
+
+// desiredOptions lists the directives entry asks for, managed fields first and
+// then ExtraConfig in its written order. current returns a keyword's values as
+// they are in the block today.
+func desiredOptions(entry HostEntry, current func(key string) []string) []desiredOption {
+	var desired []desiredOption
+	add := func(keyword string, values ...string) {
+		for i, opt := range desired {
+			if strings.EqualFold(opt.keyword, keyword) {
+				desired[i].values = append(desired[i].values, values...)
+				return
+			}
+		}
+		desired = append(desired, desiredOption{keyword: keyword, values: values})
+	}
+
+	// HostEntry carries a managed keyword's first value; repeats are kept
+	managed := func(keyword, value string) {
+		values := current(strings.ToLower(keyword))
+		if len(values) > 0 {
+			values[0] = value
+		} else {
+			values = []string{value}
+		}
+		add(keyword, values...)
+	}
+
+	if entry.Hostname != "" {
+		managed("HostName", entry.Hostname)
+	}
+	if entry.User != "" {
+		managed("User", entry.User)
+	}
+
+	// An empty Port means ssh's default, so an explicit "Port 22" stays
+	if port := current("port"); entry.Port != "" {
+		managed("Port", entry.Port)
+	} else if len(port) > 0 && port[0] == "22" {
+		add("Port", port...)
+	}
+
+	if entry.IdentityFile != "" {
+		managed("IdentityFile", entry.IdentityFile)
+	}
+
+	for _, line := range strings.Split(entry.ExtraConfig, "\n") {
+		keyword, value := directive(line)
+		if keyword == "" || isManagedKey(keyword) {
+			continue
+		}
+		add(keyword, value)
+	}
+
+	return desired
+}
+
+// isManagedKey reports whether keyword is one of HostEntry's dedicated fields.
+func isManagedKey(keyword string) bool {
+	for _, key := range managedKeys {
+		if strings.EqualFold(key, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstPattern returns the first pattern of a Host value.
+func firstPattern(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return name
+}
@@ -0,0 +1,233 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripConfig exercises the formatting EditHost must leave alone.
+const roundTripConfig = `# Global defaults
+ServerAliveInterval 60
+
+Host web web.internal # primary web server
+	HostName=web.example.com
+	user deploy   # service account
+	Port 22
+	# keys, most specific first
+	IdentityFile ~/.ssh/web
+	IdentityFile ~/.ssh/fallback
+
+	ForwardAgent yes
+	LocalForward 8080 localhost:80
+	LocalForward 9090 localhost:90
+
+Host db
+  hostname   db.example.com
+  User = postgres
+  ProxyJump bastion
+  SendEnv "LANG LC_*"
+
+Match host *.internal
+    User internal
+
+Host *
+    Compression yes
+`
+
+func TestParseConfigLine_RoundTrip(t *testing.T) {
+	lines := []string{
+		"Host web",
+		"    HostName web.example.com",
+		"\tHostName=web.example.com",
+		"  User = postgres",
+		"  user deploy   # service account",
+		"  SendEnv \"LANG LC_*\"",
+		"  ProxyCommand ssh -W %h:%p bastion#1",
+		"Compression",
+	}
+
+	for _, line := range lines {
+		t.Run(line, func(t *testing.T) {
+			l, ok := parseConfigLine(line)
+			require.True(t, ok)
+			assert.Equal(t, line, l.String())
+		})
+	}
+
+	l, _ := parseConfigLine("  user deploy   # service account")
+	assert.Equal(t, "user", l.keyword)
+	assert.Equal(t, "deploy", l.value)
+	assert.Equal(t, "   # service account", l.trailer)
+
+	_, ok := parseConfigLine("   # comment")
+	assert.False(t, ok)
+	_, ok = parseConfigLine("   ")
+	assert.False(t, ok)
+}
+
+func TestEditHost_UnchangedIsByteIdentical(t *testing.T) {
+	for _, alias := range []string{"web", "db"} {
+		t.Run(alias, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(configPath, []byte(roundTripConfig), 0600))
+
+			hosts, err := ParseSSHConfig(configPath)
+			require.NoError(t, err)
+			host := findParsedHost(t, hosts, alias)
+
+			require.NoError(t, EditHost(configPath, alias, EntryFromHost(host)))
+
+			content, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, roundTripConfig, string(content))
+		})
+	}
+}
+
+func TestEditHost_ChangesOnlyDifferingDirectives(t *testing.T) {
+	tests := []struct {
+		name   string
+		alias  string
+		modify func(e *HostEntry)
+		want   map[string]string // original line -> replacement ("" = removed)
+		added  []string          // lines expected to appear
+	}{
+		{
+			name:   "value change keeps style and comment",
+			alias:  "web",
+			modify: func(e *HostEntry) { e.User = "admin" },
+			want:   map[string]string{"\tuser deploy   # service account": "\tuser admin   # service account"},
+		},
+		{
+			name:   "equals style kept",
+			alias:  "db",
+			modify: func(e *HostEntry) { e.User = "admin" },
+			want:   map[string]string{"  User = postgres": "  User = admin"},
+		},
+		{
+			name:   "first identity file replaced, others kept",
+			alias:  "web",
+			modify: func(e *HostEntry) { e.IdentityFile = "~/.ssh/new" },
+			want:   map[string]string{"\tIdentityFile ~/.ssh/web": "\tIdentityFile ~/.ssh/new"},
+		},
+		{
+			name:   "cleared identity file removes all",
+			alias:  "web",
+			modify: func(e *HostEntry) { e.IdentityFile = "" },
+			want: map[string]string{
+				"\tIdentityFile ~/.ssh/web\n":      "",
+				"\tIdentityFile ~/.ssh/fallback\n": "",
+			},
+		},
+		{
+			name:   "new directive copies the first directive's indentation and separator",
+			alias:  "db",
+			modify: func(e *HostEntry) { e.Port = "5432" },
+			added:  []string{"  SendEnv \"LANG LC_*\"\n  Port   5432\n\nMatch"},
+		},
+		{
+			name:   "removed extra directive",
+			alias:  "db",
+			modify: func(e *HostEntry) { e.ExtraConfig = "SendEnv \"LANG LC_*\"" },
+			want:   map[string]string{"  ProxyJump bastion\n": ""},
+		},
+		{
+			name:  "rename keeps other patterns and comment",
+			alias: "web",
+			modify: func(e *HostEntry) {
+				e.Alias = "www"
+			},
+			want: map[string]string{"Host web web.internal # primary web server": "Host www web.internal # primary web server"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(configPath, []byte(roundTripConfig), 0600))
+
+			hosts, err := ParseSSHConfig(configPath)
+			require.NoError(t, err)
+			entry := EntryFromHost(findParsedHost(t, hosts, tt.alias))
+			tt.modify(&entry)
+
+			require.NoError(t, EditHost(configPath, tt.alias, entry))
+			content, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+
+			want := roundTripConfig
+			for from, to := range tt.want {
+				require.Contains(t, want, from)
+				want = strings.Replace(want, from, to, 1)
+			}
+			if tt.added == nil {
+				assert.Equal(t, want, string(content))
+			}
+			for _, fragment := range tt.added {
+				assert.Contains(t, string(content), fragment)
+			}
+		})
+	}
+}
+
+func TestEditHost_PortDefaultKept(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(roundTripConfig), 0600))
+
+	hosts, err := ParseSSHConfig(configPath)
+	require.NoError(t, err)
+	entry := EntryFromHost(findParsedHost(t, hosts, "web"))
+	entry.Port = "" // the form leaves the default port blank
+
+	require.NoError(t, EditHost(configPath, "web", entry))
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, roundTripConfig, string(content))
+}
+
+func TestEntryFromHost(t *testing.T) {
+	host := SSHHost{
+		Name: "web other",
+		AllOptions: map[string][]string{
+			"hostname":     {"web.example.com"},
+			"User":         {"deploy"},
+			"IdentityFile": {"~/.ssh/a", "~/.ssh/b"},
+			"ProxyJump":    {"bastion"},
+			"LocalForward": {"8080 localhost:80", "9090 localhost:90"},
+		},
+	}
+
+	entry := EntryFromHost(host)
+	assert.Equal(t, "web", entry.Alias)
+	assert.Equal(t, "web.example.com", entry.Hostname)
+	assert.Equal(t, "deploy", entry.User)
+	assert.Equal(t, "~/.ssh/a", entry.IdentityFile)
+	assert.Equal(t, "LocalForward 8080 localhost:80\nLocalForward 9090 localhost:90\nProxyJump bastion", entry.ExtraConfig)
+}
+
+// findParsedHost returns the parsed host whose first pattern is alias.
+func findParsedHost(t *testing.T, hosts []SSHHost, alias string) SSHHost {
+	t.Helper()
+	for _, h := range hosts {
+		if !h.IsMatch && firstPattern(h.Name) == alias {
+			return h
+		}
+	}
+	t.Fatalf("host %q not found", alias)
+	return SSHHost{}
+}
+
+func TestParseDirectives(t *testing.T) {
+	text := "# tunnels\nLocalForward 8080 localhost:80\n  localforward=9090 localhost:90\nForwardAgent yes # team default\n\nCompression\n"
+
+	assert.Equal(t, map[string][]string{
+		"LocalForward": {"8080 localhost:80", "9090 localhost:90"},
+		"ForwardAgent": {"yes"},
+	}, ParseDirectives(text))
+	assert.Empty(t, ParseDirectives(""))
+}
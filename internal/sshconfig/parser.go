@@ -22,16 +22,28 @@ type SSHHost struct {
 	SourceFile   string              // absolute path to the config file that defined this host
 	SourceLine   int                 // line number in SourceFile where Host directive appears
 	IsWildcard   bool                // true if any pattern contains `*` or `?`
+	IsMatch      bool                // true for Match blocks (kept as opaque, non-connectable entries)
 	ParseError   error               // non-nil if this entry had issues (malformed, unreadable, etc.)
 }
 
+// maxIncludeDepth limits Include nesting, matching OpenSSH.
+const maxIncludeDepth = 16
+
 // ParseSSHConfig parses an SSH config file and returns structured host data.
-// Handles Include directives (via library's automatic recursion), malformed files,
-// and wildcard detection.
+// Handles Include directives, malformed files, wildcard detection and Match blocks.
+//
+// Include directives are expanded in place, so hosts keep OpenSSH's evaluation
+// order and every entry records the file and line that defines it. Relative
+// Include paths resolve against the directory of the top-level config (~/.ssh
+// for the user config); globs are expanded in sorted order.
+//
+// Match blocks are returned in file order as opaque entries (IsMatch set) that
+// cannot be connected to; every Host block is still listed. kevinburke/ssh_config
+// rejects Match directives, so they are blanked out before decoding.
 //
 // If the file doesn't exist, returns an error.
-// If the file is malformed (e.g., contains Match blocks), returns a single SSHHost
-// with ParseError set, not a fatal error.
+// If a file is otherwise malformed or an included file can't be read, that file
+// becomes a single SSHHost with ParseError set, not a fatal error.
 func ParseSSHConfig(path string) ([]SSHHost, error) {
 	// Convert to absolute path for consistent SourceFile tracking
 	absPath, err := filepath.Abs(path)
@@ -39,94 +51,286 @@ func ParseSSHConfig(path string) ([]SSHHost, error) {
 		return nil, fmt.Errorf("resolve config path: %w", err)
 	}
 
-	// Open the config file
-	f, err := os.Open(absPath)
+	// Read the config file
+	content, err := os.ReadFile(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("open SSH config: %w", err)
 	}
-	defer func() { _ = f.Close() }()
+
+	p := &includeParser{
+		baseDir: filepath.Dir(absPath),
+		active:  map[string]bool{absPath: true},
+	}
+	return p.parse(absPath, string(content), 0), nil
+}
+
+// includeParser parses a config file and, recursively, the files it includes.
+type includeParser struct {
+	baseDir string          // directory relative Include paths resolve against
+	active  map[string]bool // files on the current Include chain (cycle detection)
+}
+
+// parse returns the entries of one file with its includes expanded in place.
+func (p *includeParser) parse(file, content string, depth int) []SSHHost {
+	// Locate Host, Match and Include lines, and hide Match/Include from the library
+	blocks, hostOnly := scanBlocks(content)
 
 	// Parse the config using kevinburke/ssh_config
-	cfg, err := ssh_config.Decode(f)
+	cfg, err := ssh_config.Decode(strings.NewReader(hostOnly))
 	if err != nil {
-		// Decode failure (e.g., Match blocks) — don't fail fatally
+		// Decode failure — don't fail fatally
 		// Return a single SSHHost with ParseError set
-		parseErr := err
-		if strings.Contains(err.Error(), "Match") {
-			parseErr = fmt.Errorf("SSH config contains unsupported Match directives: %w", err)
+		return []SSHHost{fileError(file, 0, err)}
+	}
+
+	// Extract hosts from parsed config. cfg.Hosts[0] is the library's implicit
+	// "Host *" holding global options; the rest line up with the Host blocks we scanned.
+	var hosts []SSHHost
+	nextHost := 0
+	for _, block := range blocks {
+		switch block.kind {
+		case blockMatch:
+			hosts = append(hosts, SSHHost{
+				Name:       "Match " + block.value,
+				AllOptions: block.options,
+				SourceFile: file,
+				SourceLine: block.line,
+				IsMatch:    true,
+			})
+
+		case blockInclude:
+			hosts = append(hosts, p.include(file, block, depth)...)
+
+		case blockHost:
+			if nextHost >= len(cfg.Hosts) {
+				continue
+			}
+			host := cfg.Hosts[nextHost]
+			nextHost++
+
+			sshHost, ok := toSSHHost(host, file)
+			if !ok {
+				continue
+			}
+			sshHost.SourceLine = block.line
+			if patterns := hostPatterns(block.value); patterns != "" {
+				// The library drops "!" from negated patterns; keep them as written
+				sshHost.Name = patterns
+			}
+			hosts = append(hosts, sshHost)
 		}
+	}
+
+	return hosts
+}
 
-		return []SSHHost{
-			{
-				Name:       filepath.Base(absPath),
-				SourceFile: absPath,
-				ParseError: parseErr,
-			},
-		}, nil
+// include parses every file matched by an Include directive.
+func (p *includeParser) include(file string, block configBlock, depth int) []SSHHost {
+	if depth+1 > maxIncludeDepth {
+		return []SSHHost{fileError(file, block.line, fmt.Errorf("Include nested deeper than %d levels", maxIncludeDepth))}
 	}
 
-	// Extract hosts from parsed config
 	var hosts []SSHHost
-	for _, host := range cfg.Hosts {
-		// Skip empty pattern lists (comments-only blocks)
-		if len(host.Patterns) == 0 {
+	for _, pattern := range strings.Fields(block.value) {
+		matches, err := filepath.Glob(p.resolve(pattern))
+		if err != nil {
+			hosts = append(hosts, fileError(file, block.line, fmt.Errorf("Include %s: %w", pattern, err)))
 			continue
 		}
 
-		// Build SSHHost entry
-		sshHost := SSHHost{
-			Name:       joinPatterns(host.Patterns),
-			SourceFile: absPath,
-			AllOptions: make(map[string][]string),
+		// Missing files are ignored, like OpenSSH does
+		for _, match := range matches {
+			if p.active[match] {
+				hosts = append(hosts, fileError(file, block.line, fmt.Errorf("Include %s: include cycle", match)))
+				continue
+			}
+
+			content, err := os.ReadFile(match)
+			if err != nil {
+				hosts = append(hosts, fileError(match, 0, fmt.Errorf("Include %s: %w", match, err)))
+				continue
+			}
+
+			p.active[match] = true
+			hosts = append(hosts, p.parse(match, string(content), depth+1)...)
+			delete(p.active, match)
 		}
+	}
+
+	return hosts
+}
+
+// resolve turns an Include argument into an absolute path pattern.
+// "~" expands to the home directory; relative paths resolve against baseDir.
+func (p *includeParser) resolve(pattern string) string {
+	if pattern == "~" || strings.HasPrefix(pattern, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			pattern = filepath.Join(home, pattern[1:])
+		}
+	}
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(p.baseDir, pattern)
+	}
+	return pattern
+}
+
+// fileError builds the placeholder entry for a file that couldn't be parsed.
+func fileError(file string, line int, err error) SSHHost {
+	return SSHHost{
+		Name:       filepath.Base(file),
+		SourceFile: file,
+		SourceLine: line,
+		ParseError: err,
+	}
+}
+
+// toSSHHost converts a library host block into an SSHHost.
+// Returns false for blocks that should not be listed.
+func toSSHHost(host *ssh_config.Host, sourceFile string) (SSHHost, bool) {
+	// Skip empty pattern lists (comments-only blocks)
+	if len(host.Patterns) == 0 {
+		return SSHHost{}, false
+	}
+
+	// Build SSHHost entry
+	sshHost := SSHHost{
+		Name:       joinPatterns(host.Patterns),
+		SourceFile: sourceFile,
+		AllOptions: make(map[string][]string),
+	}
 
-		// Check for wildcard patterns
-		sshHost.IsWildcard = containsWildcard(host.Patterns)
-
-		// Note: kevinburke/ssh_config does not expose per-host position information.
-		// We store the top-level config path as SourceFile for all hosts.
-		// SourceLine is left at 0 as a limitation of the library.
-		// For included files, the library handles Include recursion automatically,
-		// but we cannot determine which file each host came from.
-
-		// Extract key-value options
-		for _, node := range host.Nodes {
-			if kv, ok := node.(*ssh_config.KV); ok {
-				key := kv.Key
-				value := kv.Value
-
-				// Populate AllOptions
-				sshHost.AllOptions[key] = append(sshHost.AllOptions[key], value)
-
-				// Extract named fields for common options
-				switch key {
-				case "HostName":
-					if sshHost.Hostname == "" {
-						sshHost.Hostname = value
-					}
-				case "User":
-					if sshHost.User == "" {
-						sshHost.User = value
-					}
-				case "Port":
-					if sshHost.Port == "" {
-						sshHost.Port = value
-					}
-				case "IdentityFile":
-					sshHost.IdentityFile = append(sshHost.IdentityFile, value)
+	// Check for wildcard patterns
+	sshHost.IsWildcard = containsWildcard(host.Patterns)
+
+	// Extract key-value options
+	for _, node := range host.Nodes {
+		if kv, ok := node.(*ssh_config.KV); ok {
+			key := kv.Key
+			value := kv.Value
+
+			// Populate AllOptions
+			sshHost.AllOptions[key] = append(sshHost.AllOptions[key], value)
+
+			// Extract named fields for common options (keywords are case-insensitive)
+			switch strings.ToLower(key) {
+			case "hostname":
+				if sshHost.Hostname == "" {
+					sshHost.Hostname = value
+				}
+			case "user":
+				if sshHost.User == "" {
+					sshHost.User = value
 				}
+			case "port":
+				if sshHost.Port == "" {
+					sshHost.Port = value
+				}
+			case "identityfile":
+				sshHost.IdentityFile = append(sshHost.IdentityFile, value)
 			}
 		}
+	}
 
-		// Skip implicit "Host *" entries with no options (default catch-all from library)
-		if len(host.Patterns) == 1 && host.Patterns[0].String() == "*" && len(sshHost.AllOptions) == 0 {
-			continue
+	// Skip implicit "Host *" entries with no options (default catch-all from library)
+	if len(host.Patterns) == 1 && host.Patterns[0].String() == "*" && len(sshHost.AllOptions) == 0 {
+		return SSHHost{}, false
+	}
+
+	return sshHost, true
+}
+
+// blockKind identifies the entries scanBlocks finds.
+type blockKind int
+
+const (
+	blockHost    blockKind = iota // Host block (or the global section before the first Host)
+	blockMatch                    // Match block, kept opaque
+	blockInclude                  // Include directive, expanded by the parser
+)
+
+// configBlock is a Host block, Match block or Include directive found by scanBlocks.
+type configBlock struct {
+	kind    blockKind
+	value   string              // Host patterns, Match criteria or Include arguments
+	line    int                 // 1-based line of the directive (0 for the global section)
+	options map[string][]string // Match block options (Host options come from the library)
+}
+
+// scanBlocks splits config content into entries in file order, starting with the
+// global section. It also returns the content with every Match block and Include
+// line replaced by blank lines, which keeps line numbers intact for the library
+// parser while stopping it from resolving includes on its own.
+func scanBlocks(content string) ([]configBlock, string) {
+	lines := strings.Split(content, "\n")
+	blocks := []configBlock{{kind: blockHost}}
+	inMatch := false
+
+	for i, line := range lines {
+		keyword, value := directive(line)
+		switch {
+		case strings.EqualFold(keyword, "Host"):
+			inMatch = false
+			blocks = append(blocks, configBlock{kind: blockHost, value: value, line: i + 1})
+		case strings.EqualFold(keyword, "Match"):
+			inMatch = true
+			blocks = append(blocks, configBlock{
+				kind:    blockMatch,
+				value:   value,
+				line:    i + 1,
+				options: make(map[string][]string),
+			})
+		case inMatch && keyword != "":
+			// Options of the current Match block (an Include here stays opaque too)
+			opts := blocks[len(blocks)-1].options
+			opts[keyword] = append(opts[keyword], value)
+		case strings.EqualFold(keyword, "Include"):
+			blocks = append(blocks, configBlock{kind: blockInclude, value: value, line: i + 1})
+			lines[i] = ""
 		}
 
-		hosts = append(hosts, sshHost)
+		if inMatch {
+			lines[i] = ""
+		}
+	}
+
+	return blocks, strings.Join(lines, "\n")
+}
+
+// directive splits an SSH config line into its keyword and value.
+// Keywords and values may be separated by whitespace or "=". Blank lines and
+// comments return an empty keyword.
+func directive(line string) (keyword, value string) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", ""
 	}
 
-	return hosts, nil
+	end := strings.IndexAny(trimmed, " \t=")
+	if end == -1 {
+		return trimmed, ""
+	}
+
+	value = strings.TrimSpace(trimmed[end:])
+	value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+	return trimmed[:end], value
+}
+
+// Connectable reports whether ssh can be pointed at this entry's Name.
+// Match blocks and unparseable files are listed for reference only.
+func (h SSHHost) Connectable() bool {
+	return !h.IsMatch && h.ParseError == nil
+}
+
+// hostPatterns returns the patterns of a Host line value, without a trailing comment.
+func hostPatterns(value string) string {
+	var patterns []string
+	for _, field := range strings.Fields(value) {
+		if strings.HasPrefix(field, "#") {
+			break
+		}
+		patterns = append(patterns, field)
+	}
+	return strings.Join(patterns, " ")
 }
 
 // joinPatterns combines SSH config patterns into a single string.
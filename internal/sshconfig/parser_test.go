@@ -125,11 +125,181 @@ func TestParseSSHConfig_EmptyFile(t *testing.T) {
 	assert.Empty(t, hosts)
 }
 
+func TestParseSSHConfig_MatchBlocks(t *testing.T) {
+	content := `Host *
+    ServerAliveInterval 30
+
+Host web
+    HostName web.example.com
+    User deploy
+
+# Use the jump host outside the office
+Match host db-* !exec "ip route | grep -q 10.0.0.0"
+    ProxyJump bastion
+    User=admin
+
+Host db-1
+    HostName 10.0.0.5
+
+match all
+    ForwardAgent no
+`
+
+	tmpFile := createTempConfig(t, content)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	hosts, err := ParseSSHConfig(tmpFile)
+	require.NoError(t, err)
+	require.Len(t, hosts, 5)
+
+	// Entries stay in file order with their directive lines
+	names := make([]string, len(hosts))
+	for i, h := range hosts {
+		names[i] = h.Name
+		assert.Nil(t, h.ParseError)
+	}
+	assert.Equal(t, []string{
+		"*",
+		"web",
+		`Match host db-* !exec "ip route | grep -q 10.0.0.0"`,
+		"db-1",
+		"Match all",
+	}, names)
+	assert.Equal(t, 1, hosts[0].SourceLine)
+	assert.Equal(t, 4, hosts[1].SourceLine)
+	assert.Equal(t, 9, hosts[2].SourceLine)
+	assert.Equal(t, 13, hosts[3].SourceLine)
+	assert.Equal(t, 16, hosts[4].SourceLine)
+
+	// Host blocks parse normally; options after a Match block don't leak into them
+	assert.Equal(t, "web.example.com", hosts[1].Hostname)
+	assert.Equal(t, "deploy", hosts[1].User)
+	assert.True(t, hosts[1].Connectable())
+	assert.Equal(t, "10.0.0.5", hosts[3].Hostname)
+	assert.Empty(t, hosts[3].User)
+	assert.NotContains(t, hosts[3].AllOptions, "ProxyJump")
+
+	// Match blocks are opaque and not connectable
+	match := hosts[2]
+	assert.True(t, match.IsMatch)
+	assert.False(t, match.IsWildcard)
+	assert.False(t, match.Connectable())
+	assert.Empty(t, match.Hostname)
+	assert.Equal(t, []string{"bastion"}, match.AllOptions["ProxyJump"])
+	assert.Equal(t, []string{"admin"}, match.AllOptions["User"])
+	assert.True(t, hosts[4].IsMatch)
+}
+
+func TestParseSSHConfig_Includes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	mainPath := writeFile("config", `Include conf.d/*.conf
+
+Host main
+    HostName main.example.com
+
+Host jump
+    HostName jump.example.com
+    Include extra
+`)
+	bPath := writeFile("conf.d/b.conf", `# team b
+
+Host b1
+    HostName b1.example.com
+`)
+	aPath := writeFile("conf.d/a.conf", `Host a1
+    HostName a1.example.com
+    User alice
+Include nested/deep
+`)
+	deepPath := writeFile("nested/deep", `
+Host deep
+    HostName deep.example.com
+`)
+	extraPath := writeFile("extra", `Host extra
+    HostName extra.example.com
+`)
+	writeFile("conf.d/ignored.txt", "Host ignored\n")
+
+	hosts, err := ParseSSHConfig(mainPath)
+	require.NoError(t, err)
+
+	type location struct {
+		name string
+		file string
+		line int
+	}
+	var got []location
+	for _, h := range hosts {
+		require.Nil(t, h.ParseError, h.Name)
+		got = append(got, location{h.Name, h.SourceFile, h.SourceLine})
+	}
+
+	// Includes expand in place; globs expand in sorted order
+	assert.Equal(t, []location{
+		{"a1", aPath, 1},
+		{"deep", deepPath, 2},
+		{"b1", bPath, 3},
+		{"main", mainPath, 3},
+		{"jump", mainPath, 6},
+		{"extra", extraPath, 1},
+	}, got)
+
+	assert.Equal(t, "alice", hosts[0].User)
+	assert.Equal(t, "jump.example.com", hosts[4].Hostname)
+}
+
+func TestParseSSHConfig_IncludeHomeAndMissing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	confDir := filepath.Join(home, "ssh-extra")
+	require.NoError(t, os.MkdirAll(confDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(confDir, "work"), []byte("Host work\n    HostName work.example.com\n"), 0600))
+
+	tmpFile := createTempConfig(t, `Include ~/ssh-extra/* /nonexistent/config
+Host local
+    HostName localhost
+`)
+
+	hosts, err := ParseSSHConfig(tmpFile)
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+	assert.Equal(t, "work", hosts[0].Name)
+	assert.Equal(t, filepath.Join(confDir, "work"), hosts[0].SourceFile)
+	assert.Equal(t, "local", hosts[1].Name)
+}
+
+func TestParseSSHConfig_IncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "config")
+	otherPath := filepath.Join(dir, "other")
+	require.NoError(t, os.WriteFile(mainPath, []byte("Include other\nHost main\n    HostName m\n"), 0600))
+	require.NoError(t, os.WriteFile(otherPath, []byte("Host other\n    HostName o\nInclude config\n"), 0600))
+
+	hosts, err := ParseSSHConfig(mainPath)
+	require.NoError(t, err)
+	require.Len(t, hosts, 3)
+
+	assert.Equal(t, "other", hosts[0].Name)
+	require.NotNil(t, hosts[1].ParseError)
+	assert.Contains(t, hosts[1].ParseError.Error(), "cycle")
+	assert.Equal(t, otherPath, hosts[1].SourceFile)
+	assert.Equal(t, 3, hosts[1].SourceLine)
+	assert.Equal(t, "main", hosts[2].Name)
+}
+
 func TestParseSSHConfig_MalformedFile(t *testing.T) {
-	// Match blocks are not supported by kevinburke/ssh_config
+	// Including a directory makes kevinburke/ssh_config fail to decode
 	content := `
-Match host prod-*
-    User admin
+Include /
 `
 
 	tmpFile := createTempConfig(t, content)
@@ -141,8 +311,9 @@ Match host prod-*
 
 	// Should return a single SSHHost with ParseError set
 	assert.NotNil(t, hosts[0].ParseError)
-	assert.Contains(t, hosts[0].ParseError.Error(), "Match")
+	assert.Contains(t, hosts[0].ParseError.Error(), "Include")
 	assert.NotEmpty(t, hosts[0].SourceFile)
+	assert.False(t, hosts[0].Connectable())
 }
 
 func TestParseSSHConfig_MultiValueKeys(t *testing.T) {
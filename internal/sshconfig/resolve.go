@@ -0,0 +1,250 @@
+package sshconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolvedValue is one effective option value and the block that set it.
+type ResolvedValue struct {
+	Value      string // option value as written in the config
+	Block      string // block that set it, e.g. "Host *.prod" or "Match all"
+	SourceFile string // file that defines the block
+	SourceLine int    // line of the block's Host/Match directive (0 for global options)
+}
+
+// Origin formats where a value came from, e.g. "Host *.prod (/home/u/.ssh/config:12)".
+func (v ResolvedValue) Origin() string {
+	if v.SourceLine > 0 {
+		return fmt.Sprintf("%s (%s:%d)", v.Block, v.SourceFile, v.SourceLine)
+	}
+	return fmt.Sprintf("%s (%s)", v.Block, v.SourceFile)
+}
+
+// ResolvedOption is an option's effective value(s) for a host.
+// Most options hold a single value; options ssh accumulates (IdentityFile,
+// LocalForward, ...) hold one value per block that set them, in order.
+type ResolvedOption struct {
+	Key    string // keyword as written in the block that set it first
+	Values []ResolvedValue
+}
+
+// Resolution is the effective SSH config for an alias.
+type Resolution struct {
+	Alias   string
+	Options []ResolvedOption // in the order ssh first obtains them
+	// Unevaluated lists Match blocks whose criteria ssherpa can't evaluate
+	// (exec, user, localnetwork, ...). Their options are not applied.
+	Unevaluated []SSHHost
+}
+
+// Option returns the resolved option for key (case-insensitive).
+func (r Resolution) Option(key string) (ResolvedOption, bool) {
+	for _, opt := range r.Options {
+		if strings.EqualFold(opt.Key, key) {
+			return opt, true
+		}
+	}
+	return ResolvedOption{}, false
+}
+
+// multiValueKeys are options for which ssh keeps every value instead of the first.
+var multiValueKeys = map[string]bool{
+	"certificatefile": true,
+	"dynamicforward":  true,
+	"identityfile":    true,
+	"localforward":    true,
+	"remoteforward":   true,
+	"sendenv":         true,
+}
+
+// ResolveHost parses the SSH config at configPath (following Includes) and
+// returns the effective options for alias.
+func ResolveHost(configPath, alias string) (Resolution, error) {
+	hosts, err := ParseSSHConfig(configPath)
+	if err != nil {
+		return Resolution{}, err
+	}
+	return Resolve(hosts, alias), nil
+}
+
+// Resolve computes the effective options for alias from parsed entries, using
+// ssh's rules: blocks are evaluated in file order (Includes expanded in place),
+// and for each option the first value obtained wins.
+//
+// Host patterns support "*", "?" and "!" negation. Match blocks are evaluated
+// when they only use "all", "host" or "originalhost"; any other criterion puts
+// the block in Unevaluated.
+func Resolve(hosts []SSHHost, alias string) Resolution {
+	res := Resolution{Alias: alias}
+	index := make(map[string]int)
+
+	for _, host := range hosts {
+		if host.ParseError != nil {
+			continue
+		}
+
+		var block string
+		if host.IsMatch {
+			matched, ok := matchCriteria(strings.TrimPrefix(host.Name, "Match "), alias, res)
+			if !ok {
+				res.Unevaluated = append(res.Unevaluated, host)
+				continue
+			}
+			if !matched {
+				continue
+			}
+			block = host.Name
+		} else {
+			if !matchHostPatterns(strings.Fields(host.Name), alias) {
+				continue
+			}
+			block = "Host " + host.Name
+			if host.SourceLine == 0 {
+				block = "global"
+			}
+		}
+
+		for _, key := range orderedKeys(host) {
+			lower := strings.ToLower(key)
+			values := host.AllOptions[key]
+			if i, seen := index[lower]; seen {
+				if !multiValueKeys[lower] {
+					continue
+				}
+				for _, v := range values {
+					res.Options[i].Values = append(res.Options[i].Values, resolvedValue(v, block, host))
+				}
+				continue
+			}
+
+			opt := ResolvedOption{Key: key}
+			if !multiValueKeys[lower] {
+				values = values[:1]
+			}
+			for _, v := range values {
+				opt.Values = append(opt.Values, resolvedValue(v, block, host))
+			}
+			index[lower] = len(res.Options)
+			res.Options = append(res.Options, opt)
+		}
+	}
+
+	return res
+}
+
+// resolvedValue records value as coming from host's block.
+func resolvedValue(value, block string, host SSHHost) ResolvedValue {
+	return ResolvedValue{
+		Value:      value,
+		Block:      block,
+		SourceFile: host.SourceFile,
+		SourceLine: host.SourceLine,
+	}
+}
+
+// orderedKeys returns a host's option keys in a stable order.
+// AllOptions is a map, so the written order is lost; sorting keeps output deterministic.
+func orderedKeys(host SSHHost) []string {
+	keys := make([]string, 0, len(host.AllOptions))
+	for key := range host.AllOptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matchHostPatterns applies ssh's Host matching: the alias must match at least
+// one pattern and no negated ("!") pattern.
+func matchHostPatterns(patterns []string, alias string) bool {
+	matched := false
+	for _, p := range patterns {
+		if negated := strings.HasPrefix(p, "!"); negated {
+			if matchPattern(p[1:], alias) {
+				return false
+			}
+			continue
+		}
+		if matchPattern(p, alias) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matchCriteria evaluates a Match line. ok is false when it uses a criterion
+// ssherpa can't evaluate without running ssh.
+func matchCriteria(criteria, alias string, res Resolution) (matched, ok bool) {
+	fields := strings.Fields(criteria)
+	if len(fields) == 1 && strings.EqualFold(fields[0], "all") {
+		return true, true
+	}
+
+	// "host" matches the target hostname once HostName has been resolved
+	hostname := alias
+	if opt, found := res.Option("HostName"); found {
+		hostname = opt.Values[0].Value
+	}
+
+	matched = true
+	for i := 0; i < len(fields); i++ {
+		name := strings.ToLower(fields[i])
+		negate := strings.HasPrefix(name, "!")
+		name = strings.TrimPrefix(name, "!")
+
+		var target string
+		switch name {
+		case "host":
+			target = hostname
+		case "originalhost":
+			target = alias
+		default:
+			return false, false
+		}
+
+		if i+1 >= len(fields) {
+			return false, false
+		}
+		i++
+		result := matchHostPatterns(strings.Split(fields[i], ","), target)
+		if result == negate {
+			matched = false
+		}
+	}
+	return matched, true
+}
+
+// matchPattern reports whether s matches an ssh pattern with "*" and "?"
+// wildcards. Matching is case-insensitive, like ssh's host matching.
+func matchPattern(pattern, s string) bool {
+	pattern = strings.ToLower(pattern)
+	s = strings.ToLower(s)
+
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			pattern = pattern[1:]
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchPattern(pattern, s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+		default:
+			if s == "" || pattern[0] != s[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		s = s[1:]
+	}
+	return s == ""
+}
@@ -0,0 +1,218 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"web", "web", true},
+		{"web", "WEB", true},
+		{"web", "web1", false},
+		{"*", "anything", true},
+		{"*.prod", "db.prod", true},
+		{"*.prod", "db.staging", false},
+		{"web?", "web1", true},
+		{"web?", "web", false},
+		{"w*b*", "webserver-b1", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.s, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchPattern(tt.pattern, tt.s))
+		})
+	}
+}
+
+func TestMatchHostPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		alias    string
+		want     bool
+	}{
+		{"single", []string{"web"}, "web", true},
+		{"any of several", []string{"db", "web"}, "web", true},
+		{"negation excludes", []string{"*", "!bastion"}, "bastion", false},
+		{"negation passes others", []string{"*", "!bastion"}, "web", true},
+		{"negation alone never matches", []string{"!bastion"}, "web", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchHostPatterns(tt.patterns, tt.alias))
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	content := `User global-user
+
+Host web
+    HostName web.example.com
+    IdentityFile ~/.ssh/web
+
+Host *.prod web
+    User deploy
+    Port 2222
+    IdentityFile ~/.ssh/prod
+
+Host * !bastion
+    User fallback
+    ForwardAgent no
+
+Host bastion
+    HostName bastion.example.com
+`
+	path := createTempConfig(t, content)
+	hosts, err := ParseSSHConfig(path)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		alias string
+		want  map[string][]string // key -> values
+		block map[string]string   // key -> block of first value
+	}{
+		{
+			name:  "first match wins and identity files accumulate",
+			alias: "web",
+			want: map[string][]string{
+				"HostName":     {"web.example.com"},
+				"User":         {"global-user"},
+				"Port":         {"2222"},
+				"IdentityFile": {"~/.ssh/web", "~/.ssh/prod"},
+				"ForwardAgent": {"no"},
+			},
+			block: map[string]string{
+				"HostName": "Host web",
+				"User":     "global",
+				"Port":     "Host *.prod web",
+			},
+		},
+		{
+			name:  "wildcard host",
+			alias: "db.prod",
+			want: map[string][]string{
+				"User":         {"global-user"},
+				"Port":         {"2222"},
+				"IdentityFile": {"~/.ssh/prod"},
+				"ForwardAgent": {"no"},
+			},
+		},
+		{
+			name:  "negated pattern skips block",
+			alias: "bastion",
+			want: map[string][]string{
+				"HostName": {"bastion.example.com"},
+				"User":     {"global-user"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Resolve(hosts, tt.alias)
+			assert.Equal(t, tt.alias, res.Alias)
+			assert.Len(t, res.Options, len(tt.want))
+
+			for key, values := range tt.want {
+				opt, ok := res.Option(key)
+				require.True(t, ok, "missing option %s", key)
+				var got []string
+				for _, v := range opt.Values {
+					got = append(got, v.Value)
+					assert.Equal(t, path, v.SourceFile)
+				}
+				assert.Equal(t, values, got, key)
+			}
+			for key, block := range tt.block {
+				opt, ok := res.Option(key)
+				require.True(t, ok)
+				assert.Equal(t, block, opt.Values[0].Block, key)
+			}
+		})
+	}
+}
+
+func TestResolve_MatchBlocks(t *testing.T) {
+	content := `Host web
+    HostName web.internal
+
+Match host *.internal
+    User internal
+
+Match originalhost db
+    User db-admin
+
+Match exec "test -f /tmp/vpn"
+    ProxyJump vpn
+
+Match all
+    ServerAliveInterval 30
+`
+	path := createTempConfig(t, content)
+
+	res, err := ResolveHost(path, "web")
+	require.NoError(t, err)
+
+	user, ok := res.Option("User")
+	require.True(t, ok)
+	assert.Equal(t, "internal", user.Values[0].Value)
+	assert.Equal(t, "Match host *.internal", user.Values[0].Block)
+	assert.Equal(t, 4, user.Values[0].SourceLine)
+
+	_, ok = res.Option("ProxyJump")
+	assert.False(t, ok, "options of unevaluated Match blocks are not applied")
+	require.Len(t, res.Unevaluated, 1)
+	assert.Equal(t, `Match exec "test -f /tmp/vpn"`, res.Unevaluated[0].Name)
+
+	alive, ok := res.Option("ServerAliveInterval")
+	require.True(t, ok)
+	assert.Equal(t, "Match all", alive.Values[0].Block)
+
+	res, err = ResolveHost(path, "db")
+	require.NoError(t, err)
+	user, ok = res.Option("User")
+	require.True(t, ok)
+	assert.Equal(t, "db-admin", user.Values[0].Value)
+}
+
+func TestResolve_Includes(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "config")
+	workPath := filepath.Join(dir, "conf.d", "work")
+	require.NoError(t, os.MkdirAll(filepath.Dir(workPath), 0700))
+	require.NoError(t, os.WriteFile(mainPath, []byte("Include conf.d/*\n\nHost *\n    User me\n    Port 22\n"), 0600))
+	require.NoError(t, os.WriteFile(workPath, []byte("Host office\n    HostName office.example.com\n    User alice\n"), 0600))
+
+	res, err := ResolveHost(mainPath, "office")
+	require.NoError(t, err)
+
+	user, ok := res.Option("User")
+	require.True(t, ok)
+	assert.Equal(t, "alice", user.Values[0].Value, "included block comes first")
+	assert.Equal(t, workPath, user.Values[0].SourceFile)
+	assert.Equal(t, 1, user.Values[0].SourceLine)
+	assert.Equal(t, "Host office ("+workPath+":1)", user.Values[0].Origin())
+
+	port, ok := res.Option("Port")
+	require.True(t, ok)
+	assert.Equal(t, mainPath, port.Values[0].SourceFile)
+	assert.Equal(t, "Host *", port.Values[0].Block)
+}
+
+func TestResolveHost_MissingFile(t *testing.T) {
+	_, err := ResolveHost(filepath.Join(t.TempDir(), "missing"), "web")
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -18,8 +19,13 @@ type HostEntry struct {
 }
 
 // AddHost adds a new Host block to the SSH config file.
-// Creates a backup before writing. Returns an error if the alias already exists.
+// Creates a backup before writing. Returns an error if the alias already exists
+// in the config or any file it includes.
 func AddHost(configPath string, entry HostEntry) error {
+	if file, ok := findHostFile(configPath, entry.Alias); ok {
+		return fmt.Errorf("host alias %q already exists in %s", entry.Alias, file)
+	}
+
 	// Create backup first
 	if err := CreateBackup(configPath); err != nil {
 		return fmt.Errorf("create backup: %w", err)
@@ -58,11 +64,25 @@ func AddHost(configPath string, entry HostEntry) error {
 }
 
 // EditHost modifies an existing Host block in the SSH config file.
-// Creates a backup before writing. Returns an error if the host is not found
-// or if renaming the alias would create a conflict.
+// The block is edited in the file that defines it, which may be a file pulled
+// in by Include. Only directives that differ from entry are changed; comments,
+// blank lines, keyword casing, indentation and "Key=Value" style are kept, so
+// an entry from EntryFromHost leaves the file unchanged. Creates a backup of
+// that file before writing. Returns an error if the host is not found or if
+// renaming the alias would create a conflict.
 func EditHost(configPath string, originalAlias string, entry HostEntry) error {
+	rootPath := configPath
+	configPath = HostFile(rootPath, originalAlias)
+
+	// Renaming onto a host defined in another file is a conflict too
+	if !strings.EqualFold(originalAlias, entry.Alias) {
+		if file, ok := findHostFile(rootPath, entry.Alias); ok && file != configPath {
+			return fmt.Errorf("host alias %q already exists in %s", entry.Alias, file)
+		}
+	}
+
 	// Create backup first
-	if err := CreateBackup(configPath); err != nil {
+	if err := backupHostFile(rootPath, configPath); err != nil {
 		return fmt.Errorf("create backup: %w", err)
 	}
 
@@ -93,9 +113,8 @@ func EditHost(configPath string, originalAlias string, entry HostEntry) error {
 		}
 	}
 
-	// Build new block
-	newBlock := buildHostBlock(entry)
-	newBlockLines := strings.Split(newBlock, "\n")
+	// Edit the block in place
+	newBlockLines := editHostBlock(lines[startIdx:endIdx], entry)
 
 	// Replace the block
 	var newLines []string
@@ -113,10 +132,14 @@ func EditHost(configPath string, originalAlias string, entry HostEntry) error {
 }
 
 // RemoveHost deletes a Host block from the SSH config file.
-// Creates a backup before writing. Returns the removed block lines for undo.
+// The block is removed from the file that defines it (see HostFile).
+// Creates a backup of that file before writing. Returns the removed block lines for undo.
 func RemoveHost(configPath string, alias string) ([]string, error) {
+	rootPath := configPath
+	configPath = HostFile(rootPath, alias)
+
 	// Create backup first
-	if err := CreateBackup(configPath); err != nil {
+	if err := backupHostFile(rootPath, configPath); err != nil {
 		return nil, fmt.Errorf("create backup: %w", err)
 	}
 
@@ -158,6 +181,92 @@ func RemoveHost(configPath string, alias string) ([]string, error) {
 	return removedLines, nil
 }
 
+// CommentOutHost disables a Host block by prefixing each of its lines with
+// "# ", in the file that defines it (see HostFile). A non-empty note is added
+// as a comment line above the block. Creates a backup of that file before
+// writing. Returns an error if the host is not found.
+func CommentOutHost(configPath string, alias string, note string) error {
+	rootPath := configPath
+	configPath = HostFile(rootPath, alias)
+
+	// Create backup first
+	if err := backupHostFile(rootPath, configPath); err != nil {
+		return fmt.Errorf("create backup: %w", err)
+	}
+
+	// Read existing file
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	// Find the target block
+	startIdx, endIdx, found := findHostBlock(lines, alias)
+	if !found {
+		return fmt.Errorf("host %q not found", alias)
+	}
+
+	var newLines []string
+	newLines = append(newLines, lines[:startIdx]...)
+	if note != "" {
+		newLines = append(newLines, "# "+note)
+	}
+	for _, line := range lines[startIdx:endIdx] {
+		if strings.TrimSpace(line) == "" {
+			newLines = append(newLines, "#")
+			continue
+		}
+		newLines = append(newLines, "# "+line)
+	}
+	newLines = append(newLines, lines[endIdx:]...)
+
+	// Write atomically
+	newContent := strings.Join(newLines, "\n")
+	if err := AtomicWrite(configPath, []byte(newContent), 0600); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+
+	return nil
+}
+
+// generatedFileName is the include file ssherpa generates from its backends
+// (~/.ssh/ssherpa_config). Every sync rewrites it, so its hosts are edited
+// through their backend, never in place.
+const generatedFileName = "ssherpa_config"
+
+// HostFile returns the file that defines alias: configPath itself or a file it
+// includes, other than the generated ssherpa_config. Falls back to configPath
+// when the alias isn't found, so callers report the usual "not found" error
+// against the main config.
+func HostFile(configPath string, alias string) string {
+	if file, ok := findHostFile(configPath, alias); ok {
+		return file
+	}
+	return configPath
+}
+
+// findHostFile looks up the first Host block whose first pattern is alias
+// (case-insensitive), following Include directives. Hosts in the generated
+// ssherpa_config are skipped.
+func findHostFile(configPath string, alias string) (string, bool) {
+	hosts, err := ParseSSHConfig(configPath)
+	if err != nil {
+		return "", false
+	}
+
+	for _, host := range hosts {
+		if host.IsMatch || host.ParseError != nil || filepath.Base(host.SourceFile) == generatedFileName {
+			continue
+		}
+		if patterns := strings.Fields(host.Name); len(patterns) > 0 && strings.EqualFold(patterns[0], alias) {
+			return host.SourceFile, true
+		}
+	}
+	return "", false
+}
+
 // buildHostBlock creates a formatted Host block from a HostEntry.
 func buildHostBlock(entry HostEntry) string {
 	var lines []string
@@ -198,17 +307,9 @@ func hostExists(content string, alias string) bool {
 	aliasLower := strings.ToLower(alias)
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
 		// Match "Host <alias>" lines (case-insensitive)
-		if strings.HasPrefix(strings.ToLower(line), "host ") {
-			// Extract the alias part
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				existingAlias := strings.ToLower(parts[1])
-				if existingAlias == aliasLower {
-					return true
-				}
-			}
+		if existingAlias, ok := hostAlias(scanner.Text()); ok && strings.ToLower(existingAlias) == aliasLower {
+			return true
 		}
 	}
 
@@ -223,13 +324,9 @@ func findHostBlock(lines []string, alias string) (int, int, bool) {
 
 	// Find the start of the block
 	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(strings.ToLower(trimmed), "host ") {
-			parts := strings.Fields(trimmed)
-			if len(parts) >= 2 && strings.ToLower(parts[1]) == aliasLower {
-				startIdx = i
-				break
-			}
+		if existingAlias, ok := hostAlias(line); ok && strings.ToLower(existingAlias) == aliasLower {
+			startIdx = i
+			break
 		}
 	}
 
@@ -237,12 +334,12 @@ func findHostBlock(lines []string, alias string) (int, int, bool) {
 		return 0, 0, false
 	}
 
-	// Find the end of the block (next Host line or EOF)
+	// Find the end of the block (next Host or Match line, or EOF).
+	// Stopping at Match keeps a following Match block intact byte-for-byte.
 	endIdx := len(lines)
 	for i := startIdx + 1; i < len(lines); i++ {
-		trimmed := strings.TrimSpace(lines[i])
-		// End block at next Host line
-		if strings.HasPrefix(strings.ToLower(trimmed), "host ") {
+		keyword, _ := directive(lines[i])
+		if strings.EqualFold(keyword, "Host") || strings.EqualFold(keyword, "Match") {
 			endIdx = i
 			break
 		}
@@ -264,3 +361,17 @@ func findHostBlock(lines []string, alias string) (int, int, bool) {
 
 	return startIdx, endIdx, true
 }
+
+// hostAlias returns the first pattern of a "Host" line.
+// Accepts "Host alias", "Host\talias" and "Host=alias" forms.
+func hostAlias(line string) (string, bool) {
+	keyword, value := directive(line)
+	if !strings.EqualFold(keyword, "Host") {
+		return "", false
+	}
+	parts := strings.Fields(value)
+	if len(parts) == 0 {
+		return "", false
+	}
+	return parts[0], true
+}
@@ -585,3 +585,220 @@ Host third
 	// Verify target is gone
 	assert.NotContains(t, string(content), "Host target")
 }
+
+// matchConfig has Match blocks directly after Host blocks, with no blank line
+// in between, to check that block boundaries stop at Match.
+const matchConfig = `Host web
+    HostName web.example.com
+    User deploy
+Match host web exec "test -f ~/.ssh/vpn-up"
+    ProxyJump bastion
+  # indented comment kept as-is
+	ForwardAgent=yes
+
+Host db
+    HostName 10.0.0.5
+    User postgres
+match all
+    ServerAliveInterval 30
+`
+
+func TestEditHost_PreservesMatchBlocks(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(matchConfig), 0600))
+
+	err := EditHost(configPath, "web", HostEntry{Alias: "web", Hostname: "web2.example.com", User: "deploy"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	want := strings.Replace(matchConfig, "web.example.com", "web2.example.com", 1)
+	assert.Equal(t, want, string(content))
+}
+
+func TestRemoveHost_PreservesMatchBlocks(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(matchConfig), 0600))
+
+	removed, err := RemoveHost(configPath, "db")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Host db", "    HostName 10.0.0.5", "    User postgres"}, removed)
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	want := strings.Replace(matchConfig, "Host db\n    HostName 10.0.0.5\n    User postgres\n", "", 1)
+	assert.Equal(t, want, string(content))
+}
+
+func TestAddHost_AfterMatchBlock(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(matchConfig), 0600))
+
+	require.NoError(t, AddHost(configPath, HostEntry{Alias: "cache", Hostname: "cache.example.com", User: "redis"}))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), matchConfig), "existing content must be untouched")
+
+	// The new Host line ends the trailing Match block
+	hosts, err := ParseSSHConfig(configPath)
+	require.NoError(t, err)
+	last := hosts[len(hosts)-1]
+	assert.Equal(t, "cache", last.Name)
+	assert.Equal(t, "redis", last.User)
+	assert.NotContains(t, last.AllOptions, "ServerAliveInterval")
+}
+
+func TestHostAlias(t *testing.T) {
+	tests := []struct {
+		line  string
+		alias string
+		ok    bool
+	}{
+		{"Host web", "web", true},
+		{"  host\tweb db", "web", true},
+		{"Host=web", "web", true},
+		{"HostName web.example.com", "", false},
+		{"Match host web", "", false},
+		{"# Host web", "", false},
+		{"Host", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			alias, ok := hostAlias(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.alias, alias)
+		})
+	}
+}
+
+// includeConfig sets up ~/.ssh/config with a conf.d include and returns both paths.
+func includeConfig(t *testing.T) (mainPath, workPath string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	mainPath = filepath.Join(dir, "config")
+	workPath = filepath.Join(dir, "conf.d", "work")
+	require.NoError(t, os.MkdirAll(filepath.Dir(workPath), 0700))
+	require.NoError(t, os.WriteFile(mainPath, []byte("Include conf.d/*\n\nHost home\n    HostName home.lan\n    User me\n"), 0600))
+	require.NoError(t, os.WriteFile(workPath, []byte("Host office\n    HostName office.example.com\n    User alice\n\nHost ci\n    HostName ci.example.com\n    User build\n"), 0600))
+	return mainPath, workPath
+}
+
+func TestEditHost_IncludedFile(t *testing.T) {
+	mainPath, workPath := includeConfig(t)
+	mainBefore, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+
+	err = EditHost(mainPath, "office", HostEntry{Alias: "office", Hostname: "office2.example.com", User: "alice"})
+	require.NoError(t, err)
+
+	// The included file is edited and backed up; the main config is untouched
+	work, err := os.ReadFile(workPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Host office\n    HostName office2.example.com\n    User alice\n\nHost ci\n    HostName ci.example.com\n    User build\n", string(work))
+	assert.FileExists(t, filepath.Join(filepath.Dir(mainPath), "work.bak"))
+	assert.NoFileExists(t, workPath+".bak", "a backup inside conf.d would be included by the glob")
+
+	mainAfter, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(mainBefore), string(mainAfter))
+	assert.NoFileExists(t, mainPath+".bak")
+}
+
+func TestEditHost_RenameConflictAcrossFiles(t *testing.T) {
+	mainPath, _ := includeConfig(t)
+
+	err := EditHost(mainPath, "home", HostEntry{Alias: "CI", Hostname: "home.lan", User: "me"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestAddHost_DuplicateInIncludedFile(t *testing.T) {
+	mainPath, _ := includeConfig(t)
+
+	err := AddHost(mainPath, HostEntry{Alias: "office", Hostname: "x", User: "y"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestRemoveHost_IncludedFile(t *testing.T) {
+	mainPath, workPath := includeConfig(t)
+
+	assert.Equal(t, workPath, HostFile(mainPath, "CI"))
+	assert.Equal(t, mainPath, HostFile(mainPath, "nope"))
+
+	removed, err := RemoveHost(mainPath, "ci")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Host ci", "    HostName ci.example.com", "    User build"}, removed)
+
+	work, err := os.ReadFile(workPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Host office\n    HostName office.example.com\n    User alice\n", string(work))
+
+	hosts, err := ParseSSHConfig(mainPath)
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+}
+
+func TestCommentOutHost(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	original := "Host first\n    HostName first.com\n\nHost target\n    HostName target.com\n\n    User bob\n\nHost third\n    HostName third.com\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(original), 0600))
+
+	require.NoError(t, CommentOutHost(configPath, "TARGET", "Moved to 1Password"))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Host first\n    HostName first.com\n\n# Moved to 1Password\n# Host target\n#     HostName target.com\n#\n#     User bob\n\nHost third\n    HostName third.com\n", string(content))
+
+	backup, err := os.ReadFile(configPath + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, original, string(backup))
+
+	hosts, err := ParseSSHConfig(configPath)
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+	assert.Equal(t, "first", hosts[0].Name)
+	assert.Equal(t, "third", hosts[1].Name)
+
+	err = CommentOutHost(configPath, "target", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestCommentOutHost_IncludedFile(t *testing.T) {
+	mainPath, workPath := includeConfig(t)
+
+	require.NoError(t, CommentOutHost(mainPath, "office", ""))
+
+	work, err := os.ReadFile(workPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Host office\n#     HostName office.example.com\n#     User alice\n\nHost ci\n    HostName ci.example.com\n    User build\n", string(work))
+	assert.FileExists(t, filepath.Join(filepath.Dir(mainPath), "work.bak"))
+}
+
+func TestHostFile_SkipsGeneratedInclude(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "config")
+	generatedPath := filepath.Join(dir, "ssherpa_config")
+	generated := "# Generated by ssherpa - DO NOT EDIT MANUALLY\n\nHost api\n    HostName api.example.com\n    User deploy\n"
+	require.NoError(t, os.WriteFile(generatedPath, []byte(generated), 0600))
+	require.NoError(t, os.WriteFile(mainPath, []byte("Include "+generatedPath+"\n\nHost home\n    HostName home.lan\n    User me\n"), 0600))
+
+	assert.Equal(t, mainPath, HostFile(mainPath, "api"))
+
+	err := EditHost(mainPath, "api", HostEntry{Alias: "api", Hostname: "api2.example.com", User: "deploy"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	_, err = RemoveHost(mainPath, "api")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	content, err := os.ReadFile(generatedPath)
+	require.NoError(t, err)
+	assert.Equal(t, generated, string(content))
+	assert.NoFileExists(t, filepath.Join(dir, "ssherpa_config.bak"))
+}
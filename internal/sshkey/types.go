@@ -34,6 +34,10 @@ type SSHKey struct {
 	Missing bool
 	// MissingPath is the path that was referenced but not found (only set when Missing=true)
 	MissingPath string
+	// PublicKey is the authorized_keys line of a vault key (empty for other keys)
+	PublicKey string
+	// CredentialID is the vault credential the key comes from (1Password SSH Key item ID)
+	CredentialID string
 }
 
 // DisplayName returns a human-friendly display name for the key.
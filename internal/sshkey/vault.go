@@ -0,0 +1,98 @@
+package sshkey
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/renameio/v2/maybe"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+// PublicKeyPath returns where the public key of a vault credential is written
+// inside keyDir (e.g. ~/.ssh/ssherpa_keys/<id>.pub).
+func PublicKeyPath(keyDir, credentialID string) string {
+	return filepath.Join(keyDir, credentialID+".pub")
+}
+
+// KeysFromCredentials converts credentials that carry a public key (1Password
+// SSH Key items) to picker entries. Path is where WritePublicKey puts the
+// public key; the private key stays in the vault and is served by its agent.
+// Credentials without a parseable public key are skipped.
+func KeysFromCredentials(creds []*domain.Credential, keyDir string) []SSHKey {
+	var keys []SSHKey
+	for _, cred := range creds {
+		pubKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(cred.PublicKey))
+		if err != nil {
+			continue
+		}
+
+		fingerprint := cred.Fingerprint
+		if fingerprint == "" {
+			fingerprint = ssh.FingerprintSHA256(pubKey)
+		}
+
+		keys = append(keys, SSHKey{
+			Path:         PublicKeyPath(keyDir, cred.ID),
+			Filename:     cred.Name,
+			Type:         extractKeyType(pubKey.Type()),
+			Fingerprint:  fingerprint,
+			Comment:      comment,
+			Source:       Source1Password,
+			Bits:         extractKeyBits(pubKey),
+			PublicKey:    strings.TrimSpace(cred.PublicKey),
+			CredentialID: cred.ID,
+		})
+	}
+	return keys
+}
+
+// MergeVaultKeys adds vault keys to a DiscoverKeys result. A vault key
+// replaces any discovered key with the same fingerprint, and missing entries
+// for its public key path are dropped once the vault provides the key.
+// The result keeps DiscoverKeys' ordering.
+func MergeVaultKeys(keys, vaultKeys []SSHKey) []SSHKey {
+	fingerprints := make(map[string]bool, len(vaultKeys))
+	paths := make(map[string]bool, len(vaultKeys))
+	for _, key := range vaultKeys {
+		fingerprints[key.Fingerprint] = true
+		paths[key.Path] = true
+	}
+
+	result := make([]SSHKey, 0, len(keys)+len(vaultKeys))
+	for _, key := range keys {
+		if key.Fingerprint != "" && fingerprints[key.Fingerprint] {
+			continue
+		}
+		if key.Missing && paths[key.MissingPath] {
+			continue
+		}
+		result = append(result, key)
+	}
+	result = append(result, vaultKeys...)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Source < result[j].Source
+	})
+	return result
+}
+
+// WritePublicKey writes a vault key's public key to its Path, creating the
+// directory if needed. ssh uses the file with IdentitiesOnly to pick the
+// matching key from the agent.
+func WritePublicKey(key SSHKey) error {
+	if key.PublicKey == "" || key.Path == "" {
+		return fmt.Errorf("key %q has no public key to write", key.Filename)
+	}
+	if err := os.MkdirAll(filepath.Dir(key.Path), 0700); err != nil {
+		return fmt.Errorf("create key directory: %w", err)
+	}
+	if err := maybe.WriteFile(key.Path, []byte(key.PublicKey+"\n"), 0644); err != nil {
+		return fmt.Errorf("write public key: %w", err)
+	}
+	return nil
+}
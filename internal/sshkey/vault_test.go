@@ -0,0 +1,88 @@
+package sshkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+// newPublicKey returns a fresh ed25519 public key line and its fingerprint.
+func newPublicKey(t *testing.T, comment string) (string, string) {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if comment != "" {
+		line += " " + comment
+	}
+	return line, ssh.FingerprintSHA256(sshPub)
+}
+
+func TestKeysFromCredentials(t *testing.T) {
+	pub, fingerprint := newPublicKey(t, "deploy@ci")
+	keyDir := filepath.Join(t.TempDir(), "ssherpa_keys")
+
+	keys := KeysFromCredentials([]*domain.Credential{
+		{ID: "key-1", Name: "Deploy key", Type: domain.CredentialSSHAgent, PublicKey: pub},
+		{ID: "key-2", Name: "No public key", Type: domain.CredentialSSHAgent},
+	}, keyDir)
+
+	require.Len(t, keys, 1)
+	key := keys[0]
+	assert.Equal(t, filepath.Join(keyDir, "key-1.pub"), key.Path)
+	assert.Equal(t, "Deploy key", key.Filename)
+	assert.Equal(t, "ed25519", key.Type)
+	assert.Equal(t, fingerprint, key.Fingerprint)
+	assert.Equal(t, "deploy@ci", key.Comment)
+	assert.Equal(t, Source1Password, key.Source)
+	assert.Equal(t, "key-1", key.CredentialID)
+	assert.Equal(t, "1p:Deploy key", key.DisplayName())
+}
+
+func TestMergeVaultKeys(t *testing.T) {
+	pub, fingerprint := newPublicKey(t, "")
+	keyDir := t.TempDir()
+	vaultKeys := KeysFromCredentials([]*domain.Credential{{ID: "key-1", Name: "Deploy key", PublicKey: pub}}, keyDir)
+
+	discovered := []SSHKey{
+		{Filename: "id_rsa", Fingerprint: "SHA256:file", Source: SourceFile},
+		{Comment: "Deploy key", Fingerprint: fingerprint, Source: Source1Password},                      // same key via the agent socket
+		{Filename: "key-1.pub", Missing: true, MissingPath: vaultKeys[0].Path, Source: Source1Password}, // referenced before the file was written
+		{Comment: "laptop", Fingerprint: "SHA256:agent", Source: SourceAgent},
+	}
+
+	merged := MergeVaultKeys(discovered, vaultKeys)
+	require.Len(t, merged, 3)
+	assert.Equal(t, "id_rsa", merged[0].Filename)
+	assert.Equal(t, "laptop", merged[1].Comment)
+	assert.Equal(t, "key-1", merged[2].CredentialID)
+}
+
+func TestWritePublicKey(t *testing.T) {
+	pub, _ := newPublicKey(t, "")
+	keyDir := filepath.Join(t.TempDir(), "ssherpa_keys")
+	keys := KeysFromCredentials([]*domain.Credential{{ID: "key-1", Name: "Deploy key", PublicKey: pub}}, keyDir)
+
+	require.NoError(t, WritePublicKey(keys[0]))
+
+	content, err := os.ReadFile(keys[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, pub+"\n", string(content))
+
+	info, err := os.Stat(keyDir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	assert.Error(t, WritePublicKey(SSHKey{Filename: "id_rsa", Path: "/tmp/id_rsa"}))
+}
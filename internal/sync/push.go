@@ -0,0 +1,167 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+)
+
+// PushCandidate is a host from the user's SSH config that can be pushed to
+// 1Password, together with the server it becomes.
+type PushCandidate struct {
+	Alias      string         // first pattern of the Host line
+	SourceFile string         // file that defines the host
+	Server     *domain.Server // server to create, mapped from the Host block
+	Exists     bool           // alias already exists in 1Password (see DetectConflicts)
+}
+
+// Check reports why the candidate can't be pushed: its alias already exists
+// in 1Password (ErrDuplicateID) or it has no User, which 1Password servers
+// require (ErrValidation). Returns nil if it can be pushed.
+func (c PushCandidate) Check() error {
+	switch {
+	case c.Exists:
+		return fmt.Errorf("%w: %q already exists in 1Password", errors.ErrDuplicateID, c.Alias)
+	case c.Server.User == "":
+		return fmt.Errorf("%w: %q has no User directive", errors.ErrValidation, c.Alias)
+	}
+	return nil
+}
+
+// PushOptions controls PushHosts.
+type PushOptions struct {
+	Vault         string // target vault by ID or name ("" = the backend's default vault)
+	CommentOut    bool   // comment out the original Host block after a successful push
+	SSHConfigPath string // SSH config the hosts come from (required for CommentOut)
+}
+
+// PushResult is the outcome of pushing one host.
+type PushResult struct {
+	Alias        string
+	Pushed       bool  // item created in 1Password
+	CommentedOut bool  // original Host block commented out
+	Err          error // why the push or the comment-out failed (nil on success)
+}
+
+// FindPushCandidates lists the hosts in the SSH config at sshConfigPath that
+// can be pushed to 1Password, in file order. Wildcard hosts, Match blocks,
+// unparseable files and the generated ssherpa_config are left out; a repeated
+// alias keeps its first block, as ssh does. Aliases that already exist among
+// onePasswordServers are marked Exists.
+func FindPushCandidates(onePasswordServers []*domain.Server, sshConfigPath string) ([]PushCandidate, error) {
+	hosts, err := sshconfig.ParseSSHConfig(sshConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse SSH config: %w", err)
+	}
+
+	conflicts, err := DetectConflicts(onePasswordServers, sshConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(conflicts))
+	for _, c := range conflicts {
+		existing[strings.ToLower(c.Alias)] = true
+	}
+
+	var candidates []PushCandidate
+	seen := make(map[string]bool)
+	for _, host := range hosts {
+		if !host.Connectable() || host.IsWildcard || isSshjesusGenerated(host) {
+			continue
+		}
+		srv := pushServer(host)
+		key := strings.ToLower(srv.DisplayName)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		candidates = append(candidates, PushCandidate{
+			Alias:      srv.DisplayName,
+			SourceFile: host.SourceFile,
+			Server:     srv,
+			Exists:     existing[key],
+		})
+	}
+	return candidates, nil
+}
+
+// pushServer maps a Host block to the server pushed to 1Password. HostName
+// defaults to the alias, as with ssh. ProxyJump, Port and the first
+// IdentityFile get their own fields; every other directive is carried in
+// SSHOptions. Further IdentityFiles are dropped, since the include file only
+// writes one.
+func pushServer(host sshconfig.SSHHost) *domain.Server {
+	srv := sshHostToDomainServer(host)
+	if fields := strings.Fields(host.Name); len(fields) > 0 {
+		srv.DisplayName = fields[0]
+	}
+	srv.ID = srv.DisplayName
+	if srv.Host == "" {
+		srv.Host = srv.DisplayName
+	}
+
+	for key, values := range host.AllOptions {
+		if len(values) == 0 {
+			continue
+		}
+		lower := strings.ToLower(key)
+		switch {
+		case lower == "proxyjump":
+			srv.Proxy = values[0]
+		case dedicatedDirectives[lower]:
+			// Written from the server's own fields
+		default:
+			if srv.SSHOptions == nil {
+				srv.SSHOptions = make(map[string][]string)
+			}
+			srv.SSHOptions[key] = append(srv.SSHOptions[key], values...)
+		}
+	}
+	return srv
+}
+
+// PushHost creates the candidate's server through w (the 1Password backend)
+// in opts.Vault and, with opts.CommentOut, comments out the original block
+// after creating a backup of its file.
+func PushHost(ctx context.Context, w backend.Writer, c PushCandidate, opts PushOptions) PushResult {
+	result := PushResult{Alias: c.Alias}
+	if err := c.Check(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	srv := *c.Server
+	srv.VaultID = opts.Vault
+	if err := w.CreateServer(ctx, &srv); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Pushed = true
+
+	if opts.CommentOut {
+		note := fmt.Sprintf("Moved to 1Password by ssherpa on %s", time.Now().Format("2006-01-02"))
+		if err := sshconfig.CommentOutHost(opts.SSHConfigPath, c.Alias, note); err != nil {
+			result.Err = fmt.Errorf("pushed, but could not comment out the original block: %w", err)
+			return result
+		}
+		result.CommentedOut = true
+	}
+	return result
+}
+
+// PushHosts pushes each candidate and reports the outcome per host.
+// A failure doesn't stop the remaining hosts.
+func PushHosts(ctx context.Context, w backend.Writer, candidates []PushCandidate, opts PushOptions) []PushResult {
+	results := make([]PushResult, 0, len(candidates))
+	for _, c := range candidates {
+		results = append(results, PushHost(ctx, w, c, opts))
+	}
+	return results
+}
@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/backend/mock"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+const pushConfig = `Host web web.example
+    HostName web.example.com
+    User deploy
+    Port 2222
+    IdentityFile ~/.ssh/id_web
+    IdentityFile ~/.ssh/id_old
+    proxyjump bastion
+    ForwardAgent yes
+    LocalForward 8080 localhost:80
+    LocalForward 8443 localhost:443
+
+Host bastion
+    User jump
+
+Host nouser
+    HostName nouser.example.com
+
+Host *.internal
+    User admin
+
+Host web
+    HostName shadowed.example.com
+    User other
+`
+
+func writePushConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(pushConfig), 0600))
+	return path
+}
+
+func TestFindPushCandidates(t *testing.T) {
+	configPath := writePushConfig(t)
+	opServers := []*domain.Server{{DisplayName: "Bastion", Host: "bastion.example.com", User: "jump"}}
+
+	candidates, err := FindPushCandidates(opServers, configPath)
+	require.NoError(t, err)
+	require.Len(t, candidates, 3)
+
+	web := candidates[0]
+	assert.Equal(t, "web", web.Alias)
+	assert.Equal(t, configPath, web.SourceFile)
+	assert.False(t, web.Exists)
+	assert.NoError(t, web.Check())
+	assert.Equal(t, "web", web.Server.DisplayName)
+	assert.Equal(t, "web.example.com", web.Server.Host)
+	assert.Equal(t, "deploy", web.Server.User)
+	assert.Equal(t, 2222, web.Server.Port)
+	assert.Equal(t, "~/.ssh/id_web", web.Server.IdentityFile)
+	assert.Equal(t, "bastion", web.Server.Proxy)
+	assert.Equal(t, map[string][]string{
+		"ForwardAgent": {"yes"},
+		"LocalForward": {"8080 localhost:80", "8443 localhost:443"},
+	}, web.Server.SSHOptions)
+
+	bastion := candidates[1]
+	assert.Equal(t, "bastion", bastion.Server.Host, "HostName defaults to the alias")
+	assert.True(t, bastion.Exists)
+	assert.True(t, errors.Is(bastion.Check(), errors.ErrDuplicateID))
+
+	assert.Equal(t, "nouser", candidates[2].Alias)
+	assert.True(t, errors.Is(candidates[2].Check(), errors.ErrValidation))
+}
+
+func TestPushHosts(t *testing.T) {
+	configPath := writePushConfig(t)
+	candidates, err := FindPushCandidates([]*domain.Server{{DisplayName: "bastion"}}, configPath)
+	require.NoError(t, err)
+
+	target := mock.New()
+	results := PushHosts(context.Background(), target, candidates, PushOptions{Vault: "Infra"})
+	require.Len(t, results, 3)
+	assert.Equal(t, PushResult{Alias: "web", Pushed: true}, results[0])
+	assert.True(t, errors.Is(results[1].Err, errors.ErrDuplicateID))
+	assert.True(t, errors.Is(results[2].Err, errors.ErrValidation))
+
+	servers, err := target.ListServers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "web", servers[0].DisplayName)
+	assert.Equal(t, "Infra", servers[0].VaultID)
+
+	// Without CommentOut the SSH config is left alone
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, pushConfig, string(content))
+}
+
+func TestPushHost_CommentOut(t *testing.T) {
+	configPath := writePushConfig(t)
+	candidates, err := FindPushCandidates(nil, configPath)
+	require.NoError(t, err)
+
+	opts := PushOptions{CommentOut: true, SSHConfigPath: configPath}
+	result := PushHost(context.Background(), mock.New(), candidates[0], opts)
+	require.NoError(t, result.Err)
+	assert.True(t, result.Pushed)
+	assert.True(t, result.CommentedOut)
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Moved to 1Password by ssherpa on ")
+	assert.Contains(t, string(content), "# Host web web.example\n#     HostName web.example.com\n")
+	assert.True(t, strings.HasSuffix(string(content), "Host web\n    HostName shadowed.example.com\n    User other\n"))
+	assert.FileExists(t, configPath+".bak")
+
+	// A failed push leaves the block in place
+	target := mock.New()
+	require.NoError(t, target.CreateServer(context.Background(), &domain.Server{ID: "bastion"}))
+	result = PushHost(context.Background(), target, candidates[1], opts)
+	assert.True(t, errors.Is(result.Err, errors.ErrDuplicateID))
+	assert.False(t, result.Pushed)
+	assert.False(t, result.CommentedOut)
+}
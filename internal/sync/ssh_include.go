@@ -57,10 +57,11 @@ func WriteSSHIncludeFile(servers []*domain.Server, includePath string) error {
 			fmt.Fprintf(&content, "    ProxyJump %s\n", server.Proxy)
 		}
 
-		// ForwardAgent based on tags or notes (convention)
-		// Check if server has "forwardagent" tag or mentions it in notes
-		if hasForwardAgent(server) {
-			content.WriteString("    ForwardAgent yes\n")
+		// Further options shared through 1Password (ForwardAgent, LocalForward, ...)
+		for _, line := range server.SSHOptionLines() {
+			if !isDedicatedDirective(line) {
+				fmt.Fprintf(&content, "    %s\n", line)
+			}
 		}
 	}
 
@@ -72,22 +73,23 @@ func WriteSSHIncludeFile(servers []*domain.Server, includePath string) error {
 	return nil
 }
 
-// hasForwardAgent checks if a server should have ForwardAgent enabled.
-// This checks for a "forwardagent" tag (case-insensitive) or mentions in notes.
-func hasForwardAgent(server *domain.Server) bool {
-	// Check tags
-	for _, tag := range server.Tags {
-		if strings.EqualFold(tag, "forwardagent") {
-			return true
-		}
-	}
-
-	// Check notes (case-insensitive)
-	if strings.Contains(strings.ToLower(server.Notes), "forwardagent") {
-		return true
-	}
+// dedicatedDirectives are written from their own Server fields, so copies in
+// SSHOptions are skipped rather than emitted twice.
+var dedicatedDirectives = map[string]bool{
+	"host":         true,
+	"match":        true,
+	"hostname":     true,
+	"user":         true,
+	"port":         true,
+	"identityfile": true,
+	"proxyjump":    true,
+}
 
-	return false
+// isDedicatedDirective reports whether an SSH option line sets one of the
+// dedicatedDirectives.
+func isDedicatedDirective(line string) bool {
+	keyword, _, _ := strings.Cut(line, " ")
+	return dedicatedDirectives[strings.ToLower(keyword)]
 }
 
 // EnsureIncludeDirective ensures that the SSH config file contains an Include
@@ -125,7 +127,7 @@ func EnsureIncludeDirective(sshConfigPath, includePath string) error {
 	var newContent strings.Builder
 
 	// Add Include directive at the top
-	newContent.WriteString("# ssherpa 1Password integration\n")
+	newContent.WriteString(includeHeader + "\n")
 	newContent.WriteString(includeDirective)
 	newContent.WriteString("\n")
 
@@ -144,27 +146,99 @@ func EnsureIncludeDirective(sshConfigPath, includePath string) error {
 	return nil
 }
 
-// hasIncludeDirective checks if the SSH config content contains an Include
-// directive for the specified path (case-insensitive).
-func hasIncludeDirective(content, includePath string) bool {
-	lines := strings.Split(content, "\n")
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		trimmedLower := strings.ToLower(trimmed)
-
-		// Check for "Include <path>" (case-insensitive)
-		if strings.HasPrefix(trimmedLower, "include ") {
-			// Extract the path part
-			parts := strings.Fields(trimmed)
-			if len(parts) >= 2 {
-				pathPart := strings.ToLower(parts[1])
-				if pathPart == strings.ToLower(includePath) {
-					return true
+// includeHeader is the comment EnsureIncludeDirective writes above the Include.
+const includeHeader = "# ssherpa 1Password integration"
+
+// IncludeState describes how an SSH config references the ssherpa include file.
+type IncludeState int
+
+const (
+	IncludeMissing   IncludeState = iota // no Include for the file
+	IncludeMisplaced                     // Include follows a Host or Match line, so ssh only applies it inside that block
+	IncludeOK                            // Include precedes every Host and Match block
+)
+
+// String returns a human-readable label for the state.
+func (s IncludeState) String() string {
+	switch s {
+	case IncludeMissing:
+		return "missing"
+	case IncludeMisplaced:
+		return "misplaced"
+	case IncludeOK:
+		return "ok"
+	default:
+		return "unknown"
+	}
+}
+
+// CheckIncludeDirective reports whether content includes includePath where
+// EnsureIncludeDirective would put it: before the first Host or Match line.
+func CheckIncludeDirective(content, includePath string) IncludeState {
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		if isIncludeFor(line, includePath) {
+			if inBlock {
+				return IncludeMisplaced
+			}
+			return IncludeOK
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 && (strings.EqualFold(fields[0], "Host") || strings.EqualFold(fields[0], "Match")) {
+			inBlock = true
+		}
+	}
+	return IncludeMissing
+}
+
+// FixIncludeDirective moves (or adds) the Include for includePath to the top of
+// the SSH config. Misplaced Include lines, and the header comment directly
+// above them, are removed before EnsureIncludeDirective prepends a fresh one.
+func FixIncludeDirective(sshConfigPath, includePath string) error {
+	content, err := os.ReadFile(sshConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read SSH config: %w", err)
+	}
+
+	if CheckIncludeDirective(string(content), includePath) == IncludeMisplaced {
+		lines := strings.Split(string(content), "\n")
+		kept := make([]string, 0, len(lines))
+		for _, line := range lines {
+			if isIncludeFor(line, includePath) {
+				if n := len(kept); n > 0 && strings.TrimSpace(kept[n-1]) == includeHeader {
+					kept = kept[:n-1]
 				}
+				continue
 			}
+			kept = append(kept, line)
+		}
+		if err := maybe.WriteFile(sshConfigPath, []byte(strings.Join(kept, "\n")), 0600); err != nil {
+			return fmt.Errorf("write SSH config: %w", err)
 		}
 	}
 
+	return EnsureIncludeDirective(sshConfigPath, includePath)
+}
+
+// hasIncludeDirective checks if the SSH config content contains an Include
+// directive for the specified path (case-insensitive).
+func hasIncludeDirective(content, includePath string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if isIncludeFor(line, includePath) {
+			return true
+		}
+	}
 	return false
 }
+
+// isIncludeFor reports whether line is "Include <includePath>" (case-insensitive).
+func isIncludeFor(line, includePath string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	// Check for "Include <path>" (case-insensitive)
+	if !strings.HasPrefix(strings.ToLower(trimmed), "include ") {
+		return false
+	}
+	parts := strings.Fields(trimmed)
+	return len(parts) >= 2 && strings.EqualFold(parts[1], includePath)
+}
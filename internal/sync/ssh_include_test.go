@@ -348,17 +348,22 @@ func TestEnsureIncludeDirective_Idempotent(t *testing.T) {
 	assert.Equal(t, 1, includeCount, "Include directive should appear exactly once")
 }
 
-func TestWriteSSHIncludeFile_ForwardAgentFromTag(t *testing.T) {
+func TestWriteSSHIncludeFile_SSHOptions(t *testing.T) {
 	tmpDir := t.TempDir()
 	includePath := filepath.Join(tmpDir, "ssherpa_config")
 
 	server := &domain.Server{
-		ID:          "srv-fa-tag",
-		DisplayName: "forward-agent-tag",
-		Host:        "fa.example.com",
+		ID:          "srv-opts",
+		DisplayName: "with-options",
+		Host:        "opts.example.com",
 		User:        "admin",
 		Port:        22,
-		Tags:        []string{"production", "ForwardAgent"},
+		SSHOptions: map[string][]string{
+			"ForwardAgent": {"yes"},
+			"LocalForward": {"8080 localhost:80", "5432 db:5432"},
+			"SetEnv":       {"APP_ENV=production"},
+			"User":         {"root"}, // duplicates a dedicated field: skipped
+		},
 	}
 
 	err := WriteSSHIncludeFile([]*domain.Server{server}, includePath)
@@ -367,19 +372,27 @@ func TestWriteSSHIncludeFile_ForwardAgentFromTag(t *testing.T) {
 	content, err := os.ReadFile(includePath)
 	require.NoError(t, err)
 
-	assert.Contains(t, string(content), "ForwardAgent yes")
+	assert.Contains(t, string(content), "Host with-options\n"+
+		"    HostName opts.example.com\n"+
+		"    User admin\n"+
+		"    ForwardAgent yes\n"+
+		"    LocalForward 8080 localhost:80\n"+
+		"    LocalForward 5432 db:5432\n"+
+		"    SetEnv APP_ENV=production\n")
+	assert.NotContains(t, string(content), "User root")
 }
 
-func TestWriteSSHIncludeFile_ForwardAgentFromNotes(t *testing.T) {
+func TestWriteSSHIncludeFile_ForwardAgentNotGuessed(t *testing.T) {
 	tmpDir := t.TempDir()
 	includePath := filepath.Join(tmpDir, "ssherpa_config")
 
 	server := &domain.Server{
-		ID:          "srv-fa-notes",
-		DisplayName: "forward-agent-notes",
-		Host:        "fa2.example.com",
+		ID:          "srv-fa-tag",
+		DisplayName: "forward-agent-tag",
+		Host:        "fa.example.com",
 		User:        "admin",
 		Port:        22,
+		Tags:        []string{"production", "ForwardAgent"},
 		Notes:       "This server needs ForwardAgent enabled",
 	}
 
@@ -389,7 +402,8 @@ func TestWriteSSHIncludeFile_ForwardAgentFromNotes(t *testing.T) {
 	content, err := os.ReadFile(includePath)
 	require.NoError(t, err)
 
-	assert.Contains(t, string(content), "ForwardAgent yes")
+	// Only the forward_agent field enables forwarding, not tags or notes
+	assert.NotContains(t, string(content), "ForwardAgent")
 }
 
 func TestWriteSSHIncludeFile_NoForwardAgent(t *testing.T) {
@@ -465,3 +479,48 @@ Host myserver
 	includeCount := strings.Count(strings.ToLower(string(content)), "include "+strings.ToLower(includePath))
 	assert.Equal(t, 1, includeCount, "Include directive should appear exactly once (case-insensitive)")
 }
+
+func TestCheckIncludeDirective(t *testing.T) {
+	const includePath = "/home/u/.ssh/ssherpa_config"
+
+	tests := []struct {
+		name    string
+		content string
+		want    IncludeState
+	}{
+		{"empty", "", IncludeMissing},
+		{"other include only", "Include ~/.ssh/conf.d/*\n", IncludeMissing},
+		{"at top", "# ssherpa 1Password integration\nInclude " + includePath + "\n\nHost web\n    HostName web\n", IncludeOK},
+		{"after global options", "ServerAliveInterval 30\ninclude " + includePath + "\nHost web\n", IncludeOK},
+		{"inside host block", "Host web\n    HostName web\n\nInclude " + includePath + "\n", IncludeMisplaced},
+		{"inside match block", "Match all\n    Include " + includePath + "\n", IncludeMisplaced},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckIncludeDirective(tt.content, includePath))
+		})
+	}
+}
+
+func TestFixIncludeDirective_MovesMisplacedInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	sshConfigPath := filepath.Join(tmpDir, "config")
+	includePath := filepath.Join(tmpDir, "ssherpa_config")
+
+	existing := "Host web\n    HostName web.example.com\n\n# ssherpa 1Password integration\nInclude " + includePath + "\n"
+	require.NoError(t, os.WriteFile(sshConfigPath, []byte(existing), 0600))
+
+	require.NoError(t, FixIncludeDirective(sshConfigPath, includePath))
+
+	content, err := os.ReadFile(sshConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# ssherpa 1Password integration\nInclude "+includePath+"\n\nHost web\n    HostName web.example.com\n\n", string(content))
+	assert.Equal(t, IncludeOK, CheckIncludeDirective(string(content), includePath))
+
+	// Already correct: no change
+	require.NoError(t, FixIncludeDirective(sshConfigPath, includePath))
+	again, err := os.ReadFile(sshConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(again))
+}
@@ -13,20 +13,75 @@ import (
 
 // CachedServer represents a server in the TOML cache.
 // This includes ssherpa-specific fields that don't fit in SSH config.
+// The JSON tags mirror the TOML keys so exported inventories share one schema.
 type CachedServer struct {
-	ID                string   `toml:"id"`
-	DisplayName       string   `toml:"display_name"`
-	Host              string   `toml:"host"`
-	User              string   `toml:"user"`
-	Port              int      `toml:"port"`
-	IdentityFile      string   `toml:"identity_file,omitempty"`
-	Proxy             string   `toml:"proxy,omitempty"`
-	RemoteProjectPath string   `toml:"remote_project_path,omitempty"`
-	ProjectIDs        []string `toml:"project_ids,omitempty"`
-	VaultID           string   `toml:"vault_id"`
-	Tags              []string `toml:"tags,omitempty"`
-	Notes             string   `toml:"notes,omitempty"`
-	Source            string   `toml:"source,omitempty"`
+	ID                string   `toml:"id" json:"id"`
+	DisplayName       string   `toml:"display_name" json:"display_name"`
+	Host              string   `toml:"host" json:"host"`
+	User              string   `toml:"user" json:"user"`
+	Port              int      `toml:"port" json:"port"`
+	IdentityFile      string   `toml:"identity_file,omitempty" json:"identity_file"`
+	Proxy             string   `toml:"proxy,omitempty" json:"proxy"`
+	RemoteProjectPath string   `toml:"remote_project_path,omitempty" json:"remote_project_path"`
+	ProjectIDs        []string `toml:"project_ids,omitempty" json:"project_ids"`
+	VaultID           string   `toml:"vault_id" json:"vault_id"`
+	Tags              []string `toml:"tags,omitempty" json:"tags"`
+	Notes             string   `toml:"notes,omitempty" json:"notes"`
+	Source            string   `toml:"source,omitempty" json:"source"`
+	Favorite          bool     `toml:"favorite,omitempty" json:"favorite"`
+	VPNRequired       bool     `toml:"vpn_required,omitempty" json:"vpn_required"`
+	CredentialID      string   `toml:"credential_id,omitempty" json:"credential_id"`
+	Revision          string   `toml:"revision,omitempty" json:"-"` // sync bookkeeping, not exported
+
+	SSHOptions map[string][]string `toml:"ssh_options,omitempty" json:"ssh_options"`
+}
+
+// NewCachedServer converts a domain.Server to its cache representation.
+func NewCachedServer(srv *domain.Server) CachedServer {
+	return CachedServer{
+		ID:                srv.ID,
+		DisplayName:       srv.DisplayName,
+		Host:              srv.Host,
+		User:              srv.User,
+		Port:              srv.Port,
+		IdentityFile:      srv.IdentityFile,
+		Proxy:             srv.Proxy,
+		RemoteProjectPath: srv.RemoteProjectPath,
+		ProjectIDs:        srv.ProjectIDs,
+		VaultID:           srv.VaultID,
+		Tags:              srv.Tags,
+		Notes:             srv.Notes,
+		Source:            srv.Source,
+		Favorite:          srv.Favorite,
+		VPNRequired:       srv.VPNRequired,
+		CredentialID:      srv.CredentialID,
+		Revision:          srv.Revision,
+		SSHOptions:        srv.SSHOptions,
+	}
+}
+
+// ToServer converts the cache representation back to a domain.Server.
+func (c CachedServer) ToServer() *domain.Server {
+	return &domain.Server{
+		ID:                c.ID,
+		DisplayName:       c.DisplayName,
+		Host:              c.Host,
+		User:              c.User,
+		Port:              c.Port,
+		IdentityFile:      c.IdentityFile,
+		Proxy:             c.Proxy,
+		RemoteProjectPath: c.RemoteProjectPath,
+		ProjectIDs:        c.ProjectIDs,
+		VaultID:           c.VaultID,
+		Tags:              c.Tags,
+		Notes:             c.Notes,
+		Source:            c.Source,
+		Favorite:          c.Favorite,
+		VPNRequired:       c.VPNRequired,
+		CredentialID:      c.CredentialID,
+		Revision:          c.Revision,
+		SSHOptions:        c.SSHOptions,
+	}
 }
 
 // TOMLCache represents the entire TOML cache file structure.
@@ -46,22 +101,7 @@ func WriteTOMLCache(servers []*domain.Server, cachePath string) error {
 	}
 
 	for _, srv := range servers {
-		cached := CachedServer{
-			ID:                srv.ID,
-			DisplayName:       srv.DisplayName,
-			Host:              srv.Host,
-			User:              srv.User,
-			Port:              srv.Port,
-			IdentityFile:      srv.IdentityFile,
-			Proxy:             srv.Proxy,
-			RemoteProjectPath: srv.RemoteProjectPath,
-			ProjectIDs:        srv.ProjectIDs,
-			VaultID:           srv.VaultID,
-			Tags:              srv.Tags,
-			Notes:             srv.Notes,
-			Source:            srv.Source,
-		}
-		cache.Servers = append(cache.Servers, cached)
+		cache.Servers = append(cache.Servers, NewCachedServer(srv))
 	}
 
 	// Encode as TOML
@@ -114,22 +154,7 @@ func ReadTOMLCache(cachePath string) ([]*domain.Server, error) {
 	// Convert CachedServer list to domain.Server list
 	servers := make([]*domain.Server, 0, len(cache.Servers))
 	for _, cached := range cache.Servers {
-		srv := &domain.Server{
-			ID:                cached.ID,
-			DisplayName:       cached.DisplayName,
-			Host:              cached.Host,
-			User:              cached.User,
-			Port:              cached.Port,
-			IdentityFile:      cached.IdentityFile,
-			Proxy:             cached.Proxy,
-			RemoteProjectPath: cached.RemoteProjectPath,
-			ProjectIDs:        cached.ProjectIDs,
-			VaultID:           cached.VaultID,
-			Tags:              cached.Tags,
-			Notes:             cached.Notes,
-			Source:            cached.Source,
-		}
-		servers = append(servers, srv)
+		servers = append(servers, cached.ToServer())
 	}
 
 	return servers, nil
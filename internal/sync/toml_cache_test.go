@@ -33,6 +33,10 @@ func TestWriteReadTOMLCache_RoundTrip(t *testing.T) {
 			VaultID:           "vault-001",
 			Tags:              []string{"production", "web"},
 			LastConnected:     &now,
+			SSHOptions: map[string][]string{
+				"ForwardAgent": {"yes"},
+				"LocalForward": {"8080 localhost:80", "5432 db:5432"},
+			},
 		},
 		{
 			ID:          "srv-002",
@@ -68,6 +72,10 @@ func TestWriteReadTOMLCache_RoundTrip(t *testing.T) {
 	assert.Equal(t, []string{"proj-001", "proj-002"}, srv1.ProjectIDs)
 	assert.Equal(t, "vault-001", srv1.VaultID)
 	assert.Equal(t, []string{"production", "web"}, srv1.Tags)
+	assert.Equal(t, map[string][]string{
+		"ForwardAgent": {"yes"},
+		"LocalForward": {"8080 localhost:80", "5432 db:5432"},
+	}, srv1.SSHOptions)
 
 	// Verify second server fields
 	srv2 := readServers[1]
@@ -77,6 +85,7 @@ func TestWriteReadTOMLCache_RoundTrip(t *testing.T) {
 	assert.Equal(t, "ubuntu", srv2.User)
 	assert.Equal(t, 22, srv2.Port)
 	assert.Equal(t, "vault-002", srv2.VaultID)
+	assert.Empty(t, srv2.SSHOptions)
 }
 
 func TestReadTOMLCache_NotFound(t *testing.T) {
@@ -0,0 +1,58 @@
+package sync
+
+import (
+	"strings"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/sshkey"
+)
+
+// ApplyVaultKeys points servers that reference a vault SSH key (CredentialID)
+// at its public key, written to keyDir, so the generated Host block reads
+// "IdentityFile <keyDir>/<id>.pub" plus "IdentitiesOnly yes" and ssh offers
+// only that key from the 1Password agent. Servers are returned as copies;
+// servers whose credential is unknown are returned unchanged.
+func ApplyVaultKeys(servers []*domain.Server, creds []*domain.Credential, keyDir string) ([]*domain.Server, error) {
+	keys := make(map[string]sshkey.SSHKey)
+	for _, key := range sshkey.KeysFromCredentials(creds, keyDir) {
+		keys[key.CredentialID] = key
+	}
+
+	written := make(map[string]bool)
+	result := make([]*domain.Server, 0, len(servers))
+	for _, server := range servers {
+		key, ok := keys[server.CredentialID]
+		if server.CredentialID == "" || !ok {
+			result = append(result, server)
+			continue
+		}
+
+		if !written[key.CredentialID] {
+			if err := sshkey.WritePublicKey(key); err != nil {
+				return nil, err
+			}
+			written[key.CredentialID] = true
+		}
+
+		serverCopy := *server
+		serverCopy.IdentityFile = key.Path
+		serverCopy.SSHOptions = make(map[string][]string, len(server.SSHOptions)+1)
+		for keyword, values := range server.SSHOptions {
+			serverCopy.SSHOptions[keyword] = values
+		}
+		setIdentitiesOnly(serverCopy.SSHOptions)
+		result = append(result, &serverCopy)
+	}
+	return result, nil
+}
+
+// setIdentitiesOnly sets IdentitiesOnly to yes, replacing any spelling of the
+// keyword already present.
+func setIdentitiesOnly(options map[string][]string) {
+	for keyword := range options {
+		if strings.EqualFold(keyword, "IdentitiesOnly") {
+			delete(options, keyword)
+		}
+	}
+	options["IdentitiesOnly"] = []string{"yes"}
+}
@@ -0,0 +1,56 @@
+package sync
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
+)
+
+func TestApplyVaultKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	pubLine := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	keyDir := filepath.Join(t.TempDir(), "ssherpa_keys")
+	creds := []*domain.Credential{{ID: "key-1", Name: "Deploy key", Type: domain.CredentialSSHAgent, PublicKey: pubLine}}
+	servers := []*domain.Server{
+		{ID: "a", DisplayName: "web", Host: "web.example.com", User: "deploy", CredentialID: "key-1",
+			SSHOptions: map[string][]string{"identitiesonly": {"no"}, "ForwardAgent": {"yes"}}},
+		{ID: "b", DisplayName: "db", Host: "db.example.com", User: "deploy", IdentityFile: "~/.ssh/id_rsa"},
+		{ID: "c", DisplayName: "old", Host: "old.example.com", User: "deploy", CredentialID: "deleted-key"},
+	}
+
+	got, err := ApplyVaultKeys(servers, creds, keyDir)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	pubPath := filepath.Join(keyDir, "key-1.pub")
+	assert.Equal(t, pubPath, got[0].IdentityFile)
+	assert.Equal(t, map[string][]string{"IdentitiesOnly": {"yes"}, "ForwardAgent": {"yes"}}, got[0].SSHOptions)
+	assert.Equal(t, []string{"no"}, servers[0].SSHOptions["identitiesonly"], "input servers are not modified")
+	assert.Same(t, servers[1], got[1])
+	assert.Same(t, servers[2], got[2], "unknown credentials are left alone")
+
+	content, err := os.ReadFile(pubPath)
+	require.NoError(t, err)
+	assert.Equal(t, pubLine+"\n", string(content))
+
+	// The generated Host block uses the public key with the agent
+	includePath := filepath.Join(t.TempDir(), "ssherpa_config")
+	require.NoError(t, WriteSSHIncludeFile(got, includePath))
+	include, err := os.ReadFile(includePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(include), "    IdentityFile "+pubPath+"\n")
+	assert.Contains(t, string(include), "    IdentitiesOnly yes\n")
+}
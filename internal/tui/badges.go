@@ -16,3 +16,10 @@ func RenderProjectBadge(projectName string, color lipgloss.AdaptiveColor) string
 
 	return badgeStyle.Render(projectName)
 }
+
+// RenderTagBadge creates an inline badge for a user-defined tag.
+// Tags use a muted outline look so they don't compete with project badges.
+// Example output: #prod
+func RenderTagBadge(tag string) string {
+	return tagBadgeStyle.Render("#" + tag)
+}
@@ -9,6 +9,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
 	"github.com/florianriquelme/ssherpa/internal/sshconfig"
 )
 
@@ -21,6 +22,7 @@ type DeleteConfirm struct {
 	configPath    string          // SSH config path for RemoveHost
 	backendWriter backend.Writer  // Optional: if set, routes deletes through backend instead of sshconfig
 	serverID      string          // For backend delete mode: server ID
+	source        string          // For backend delete mode: backend the server comes from
 }
 
 // NewDeleteConfirm creates a delete confirmation view for the given server alias.
@@ -38,6 +40,14 @@ func NewDeleteConfirm(alias, configPath string) DeleteConfirm {
 	}
 }
 
+// SetBackendServer makes the confirmation delete srv through writer instead
+// of removing a Host block from the SSH config.
+func (d *DeleteConfirm) SetBackendServer(writer backend.Writer, srv *domain.Server) {
+	d.backendWriter = writer
+	d.serverID = srv.ID
+	d.source = srv.Source
+}
+
 // Update handles input and confirmation logic.
 func (d DeleteConfirm) Update(msg tea.Msg) (DeleteConfirm, tea.Cmd) {
 	var cmd tea.Cmd
@@ -53,7 +63,8 @@ func (d DeleteConfirm) Update(msg tea.Msg) (DeleteConfirm, tea.Cmd) {
 					return d, d.performBackendDelete()
 				}
 
-				// Perform deletion via SSH config
+				// Perform deletion via SSH config (in the file that defines the host)
+				sourceFile := sshconfig.HostFile(d.configPath, d.alias)
 				removedLines, err := sshconfig.RemoveHost(d.configPath, d.alias)
 				if err != nil {
 					return d, func() tea.Msg {
@@ -64,6 +75,7 @@ func (d DeleteConfirm) Update(msg tea.Msg) (DeleteConfirm, tea.Cmd) {
 				return d, func() tea.Msg {
 					return serverDeletedMsg{
 						alias:        d.alias,
+						sourceFile:   sourceFile,
 						removedLines: removedLines,
 					}
 				}
@@ -98,9 +110,9 @@ func (d *DeleteConfirm) performBackendDelete() tea.Cmd {
 		}
 	}
 
-	// Success - send BackendServersUpdatedMsg to trigger reload
+	// Success - the model closes the confirmation and reloads the servers
 	return func() tea.Msg {
-		return BackendServersUpdatedMsg{}
+		return serverDeletedMsg{alias: d.alias, backend: true}
 	}
 }
 
@@ -110,8 +122,12 @@ func (d DeleteConfirm) View() string {
 	title := deleteWarningStyle.Render("Delete SSH Connection")
 
 	// Warning message
+	target := "your SSH config"
+	if d.backendWriter != nil && d.source != "" {
+		target = "the " + d.source + " backend"
+	}
 	warningText := deleteInstructionStyle.Render(
-		"This will remove '" + d.alias + "' from your SSH config.",
+		"This will remove '" + d.alias + "' from " + target + ".",
 	)
 
 	// Instruction
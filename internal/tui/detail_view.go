@@ -5,12 +5,46 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/viewport"
+
+	"github.com/florianriquelme/ssherpa/internal/domain"
 	"github.com/florianriquelme/ssherpa/internal/sshconfig"
 )
 
+// showDetail opens the detail view for host, resolving its effective options.
+func (m *Model) showDetail(host sshconfig.SSHHost) {
+	m.detailHost = &host
+	m.detailSource = m.hostSources[host.Name]
+	m.detailResolution = m.resolveHost(host)
+	m.viewport = viewport.New(m.width, m.height)
+	m.viewport.SetContent(m.detailContent())
+}
+
+// detailContent renders the detail view for the current detail host.
+func (m Model) detailContent() string {
+	return renderDetailView(m.detailHost, m.detailSource, m.detailServer(*m.detailHost), m.detailResolution, m.width, m.height)
+}
+
+// resolveHost computes the effective SSH options for host from the SSH config.
+// Hosts with several patterns (e.g. "web web-alt") resolve as their first one.
+// Returns nil for entries ssh can't connect to or when the config can't be read.
+func (m Model) resolveHost(host sshconfig.SSHHost) *sshconfig.Resolution {
+	if !host.Connectable() || m.configPath == "" {
+		return nil
+	}
+	res, err := sshconfig.ResolveHost(m.configPath, strings.Fields(host.Name)[0])
+	if err != nil {
+		return nil
+	}
+	return &res
+}
+
 // renderDetailView renders the full-screen detail view for a selected host.
 // Shows all SSH config options, source backend, source file, and error info.
-func renderDetailView(host *sshconfig.SSHHost, source string, width, height int) string {
+// server carries backend-only fields (e.g. remote project path) and may be nil.
+// resolution, when non-nil, adds the effective options with the block and file
+// each value came from.
+func renderDetailView(host *sshconfig.SSHHost, source string, server *domain.Server, resolution *sshconfig.Resolution, width, height int) string {
 	if host == nil {
 		return emptyStateStyle.Render("No host selected")
 	}
@@ -28,6 +62,12 @@ func renderDetailView(host *sshconfig.SSHHost, source string, width, height int)
 		b.WriteString("\n")
 	}
 
+	// Match blocks are listed for reference only
+	if host.IsMatch {
+		b.WriteString(secondaryStyle.Render("Match block: the options below apply to hosts matching the criteria.\nssherpa lists it read-only and can't connect to it."))
+		b.WriteString("\n\n")
+	}
+
 	// Backend source
 	if source != "" {
 		b.WriteString(secondaryStyle.Render(fmt.Sprintf("Source: %s", source)))
@@ -73,6 +113,17 @@ func renderDetailView(host *sshconfig.SSHHost, source string, width, height int)
 		}
 	}
 
+	// ssherpa fields (backend metadata not present in SSH config)
+	if server != nil && (server.RemoteProjectPath != "" || server.VPNRequired) {
+		b.WriteString("\n")
+		b.WriteString(detailLabelStyle.Render("ssherpa Fields:"))
+		b.WriteString("\n")
+		writeField("Remote Path", server.RemoteProjectPath)
+		if server.VPNRequired {
+			writeField("VPN", "required (checked before connecting)")
+		}
+	}
+
 	// All options section (sorted alphabetically)
 	if len(host.AllOptions) > 0 {
 		b.WriteString("\n")
@@ -101,6 +152,34 @@ func renderDetailView(host *sshconfig.SSHHost, source string, width, height int)
 		}
 	}
 
+	// Effective options after wildcards, Match blocks and Includes
+	if resolution != nil && len(resolution.Options) > 0 {
+		b.WriteString("\n")
+		b.WriteString(detailLabelStyle.Render("Effective Config:"))
+		b.WriteString("\n")
+		for _, opt := range resolution.Options {
+			for i, val := range opt.Values {
+				label := opt.Key + ":"
+				if i > 0 {
+					label = strings.Repeat(" ", len(label))
+				}
+				fmt.Fprintf(&b, "  %s %s %s\n",
+					detailLabelStyle.Render(label),
+					detailValueStyle.Render(val.Value),
+					secondaryStyle.Render("← "+val.Origin()))
+			}
+		}
+	}
+	if resolution != nil && len(resolution.Unevaluated) > 0 {
+		b.WriteString("\n")
+		b.WriteString(secondaryStyle.Render("Not evaluated (ssh decides at connect time):"))
+		b.WriteString("\n")
+		for _, match := range resolution.Unevaluated {
+			b.WriteString(secondaryStyle.Render(fmt.Sprintf("  %s (%s:%d)", match.Name, match.SourceFile, match.SourceLine)))
+			b.WriteString("\n")
+		}
+	}
+
 	return b.String()
 }
 
@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+)
+
+func TestResolveHost_MultiPatternHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "Host web web-alt\n    HostName web.example.com\n    User deploy\n\nHost *\n    User root\n    Port 2222\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	hosts, err := sshconfig.ParseSSHConfig(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, hosts)
+	require.Equal(t, "web web-alt", hosts[0].Name)
+
+	m := Model{configPath: path}
+	res := m.resolveHost(hosts[0])
+	require.NotNil(t, res)
+
+	user, ok := res.Option("User")
+	require.True(t, ok)
+	assert.Equal(t, "deploy", user.Values[0].Value)
+	assert.Equal(t, "Host web web-alt", user.Values[0].Block)
+
+	port, ok := res.Option("Port")
+	require.True(t, ok)
+	assert.Equal(t, "2222", port.Values[0].Value)
+	assert.Equal(t, "Host *", port.Values[0].Block)
+}
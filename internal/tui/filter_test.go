@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/backend/mock"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+)
+
+func TestRankHosts_BestMatchFirst(t *testing.T) {
+	m := Model{allHosts: []sshconfig.SSHHost{
+		{Name: "westside-backup", Hostname: "backup.internal.example.org"},
+		{Name: "db", Hostname: "db.example.com"},
+		{Name: "web", Hostname: "web.example.com"},
+	}}
+	allowed := []bool{true, true, true}
+
+	assert.Equal(t, []int{2, 0}, m.rankHosts("web", allowed))
+	assert.Equal(t, []int{0}, m.rankHosts("web", []bool{true, true, false}))
+	assert.Equal(t, []int{0, 1, 2}, m.rankHosts("", allowed))
+}
+
+func TestStructuredMatches_UsesFilterer(t *testing.T) {
+	b := mock.New()
+	b.Seed([]*domain.Server{
+		{ID: "1", DisplayName: "api", Host: "api.example.com", Source: "1password", Tags: []string{"prod"}},
+		{ID: "2", DisplayName: "staging", Host: "staging.example.com", Source: "1password"},
+	}, nil, nil)
+
+	m := Model{
+		appBackend: b,
+		allHosts: []sshconfig.SSHHost{
+			{Name: "api", Hostname: "api.example.com"},
+			{Name: "staging", Hostname: "staging.example.com"},
+			{Name: "web", Hostname: "web.example.com"},
+			{Name: "db", Hostname: "db.example.com"},
+		},
+		hostServers: map[string]*domain.Server{
+			// Stale local copy: the backend no longer tags staging as prod
+			"staging": {ID: "2", DisplayName: "staging", Source: "1password", Tags: []string{"prod"}},
+			"api":     {ID: "1", DisplayName: "api", Source: "1password"},
+		},
+		hostConfigs: []config.HostConfig{{Alias: "web", Tags: []string{"prod"}}},
+	}
+
+	got := m.structuredMatches(backend.ServerFilter{Tags: []string{"prod"}})
+	assert.Equal(t, []bool{true, false, true, false}, got)
+
+	assert.Equal(t, []bool{true, true, true, true}, m.structuredMatches(backend.ServerFilter{}))
+}
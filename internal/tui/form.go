@@ -36,9 +36,12 @@ type ServerForm struct {
 	saveError     string // Error from save attempt
 	dnsError      string // Error from DNS check (non-blocking warning)
 	spinner       spinner.Model
-	backendWriter backend.Writer // Optional: if set, routes writes through backend instead of sshconfig
-	originalID    string         // For backend edit mode: original server ID
-	selectedKey   *sshkey.SSHKey // Currently selected SSH key (nil = None)
+	backendWriter backend.Writer  // Optional: if set, routes writes through backend instead of sshconfig
+	originalID    string          // For backend edit mode: original server ID
+	original      *domain.Server  // For backend edit mode: server being edited (keeps fields the form doesn't show)
+	selectedKey   *sshkey.SSHKey  // Currently selected SSH key (nil = None)
+	vaults        []backend.Vault // Vault picker targets (nil = no picker); choice 0 is the SSH config
+	vaultField    int             // Index of the Vault field when vaults is set
 }
 
 // formField represents a single field in the form.
@@ -47,6 +50,11 @@ type formField struct {
 	input      textinput.Model
 	textarea   textarea.Model
 	isTextarea bool
+	isToggle   bool // Checkbox toggled with space; uses checked instead of input
+	checked    bool
+	isChoice   bool     // Picker cycled with left/right; uses choices[choice] instead of input
+	choices    []string // Options shown by a picker
+	choice     int
 	required   bool
 	validator  func(string) string
 	errorMsg   string
@@ -54,7 +62,7 @@ type formField struct {
 
 // NewServerForm creates a new form in add mode with empty fields.
 func NewServerForm(configPath string) ServerForm {
-	fields := make([]formField, 6)
+	fields := make([]formField, 8)
 
 	// Alias field
 	aliasInput := textinput.New()
@@ -120,6 +128,22 @@ func NewServerForm(configPath string) ServerForm {
 		required:   false,
 	}
 
+	// Tags field (comma-separated)
+	tagsInput := textinput.New()
+	tagsInput.Placeholder = "e.g. prod, web (search with tag:prod)"
+	fields[6] = formField{
+		label:     "Tags",
+		input:     tagsInput,
+		required:  false,
+		validator: validateTags,
+	}
+
+	// VPN Required field (checkbox)
+	fields[7] = formField{
+		label:    "VPN Required",
+		isToggle: true,
+	}
+
 	// Initialize spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -160,36 +184,13 @@ func NewEditServerForm(configPath string, host sshconfig.SSHHost) ServerForm {
 	}
 
 	// Pre-fill ExtraConfig (all other options)
-	extraLines := buildExtraConfig(host)
-	if extraLines != "" {
-		form.fields[5].textarea.SetValue(extraLines)
+	if extra := sshconfig.EntryFromHost(host).ExtraConfig; extra != "" {
+		form.fields[5].textarea.SetValue(extra)
 	}
 
 	return form
 }
 
-// buildExtraConfig extracts non-standard SSH options from AllOptions.
-func buildExtraConfig(host sshconfig.SSHHost) string {
-	var lines []string
-	standardKeys := map[string]bool{
-		"HostName":     true,
-		"User":         true,
-		"Port":         true,
-		"IdentityFile": true,
-	}
-
-	for key, values := range host.AllOptions {
-		if standardKeys[key] {
-			continue
-		}
-		for _, val := range values {
-			lines = append(lines, fmt.Sprintf("%s %s", key, val))
-		}
-	}
-
-	return strings.Join(lines, "\n")
-}
-
 // Update handles form messages.
 func (f ServerForm) Update(msg tea.Msg) (ServerForm, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -246,7 +247,29 @@ func (f ServerForm) Update(msg tea.Msg) (ServerForm, tea.Cmd) {
 				return f, nil
 			}
 
+		case "left", "h", "right", "l":
+			// Arrows cycle a picker
+			if field := &f.fields[f.focusIndex]; field.isChoice {
+				step := 1
+				if msg.String() == "left" || msg.String() == "h" {
+					step = len(field.choices) - 1
+				}
+				field.choice = (field.choice + step) % len(field.choices)
+				return f, nil
+			}
+
 		case "enter", " ":
+			// Space on a checkbox toggles it, on a picker selects the next option
+			if msg.String() == " " && f.fields[f.focusIndex].isToggle {
+				f.fields[f.focusIndex].checked = !f.fields[f.focusIndex].checked
+				return f, nil
+			}
+			if msg.String() == " " && f.fields[f.focusIndex].isChoice {
+				field := &f.fields[f.focusIndex]
+				field.choice = (field.choice + 1) % len(field.choices)
+				return f, nil
+			}
+
 			// Special handling for IdentityFile field (index 4): open key picker
 			if f.focusIndex == 4 {
 				// Request model to open key picker
@@ -263,7 +286,7 @@ func (f ServerForm) Update(msg tea.Msg) (ServerForm, tea.Cmd) {
 		}
 
 		// Pass key to focused field (skip IdentityFile field - it's display-only)
-		if f.focusIndex == 4 {
+		if f.focusIndex == 4 || f.fields[f.focusIndex].isToggle || f.fields[f.focusIndex].isChoice {
 			// Don't update IdentityFile input - it's controlled by key selection
 			return f, nil
 		}
@@ -303,6 +326,9 @@ func (f ServerForm) Update(msg tea.Msg) (ServerForm, tea.Cmd) {
 
 // blurCurrentField removes focus from current field.
 func (f *ServerForm) blurCurrentField() {
+	if f.fields[f.focusIndex].isToggle || f.fields[f.focusIndex].isChoice {
+		return
+	}
 	if f.fields[f.focusIndex].isTextarea {
 		f.fields[f.focusIndex].textarea.Blur()
 	} else {
@@ -312,6 +338,9 @@ func (f *ServerForm) blurCurrentField() {
 
 // focusCurrentField gives focus to current field.
 func (f *ServerForm) focusCurrentField() {
+	if f.fields[f.focusIndex].isToggle || f.fields[f.focusIndex].isChoice {
+		return
+	}
 	if f.fields[f.focusIndex].isTextarea {
 		f.fields[f.focusIndex].textarea.Focus()
 	} else {
@@ -388,8 +417,8 @@ func (f *ServerForm) handleSave() tea.Cmd {
 
 // performSave writes the entry to SSH config or backend.
 func (f *ServerForm) performSave() tea.Cmd {
-	// If backend writer is set, route through it
-	if f.backendWriter != nil {
+	// If backend writer is set, route through it (unless the vault picker chose the SSH config)
+	if f.backendWriter != nil && (f.vaults == nil || f.selectedVaultID() != "") {
 		return f.performBackendSave()
 	}
 
@@ -403,6 +432,17 @@ func (f *ServerForm) performSave() tea.Cmd {
 		ExtraConfig:  strings.TrimSpace(f.fields[5].textarea.Value()),
 	}
 
+	// A 1Password SSH Key is used through the agent: point IdentityFile at
+	// its public key so ssh offers only that key
+	if key := f.vaultKey(); key != nil {
+		if err := sshkey.WritePublicKey(*key); err != nil {
+			f.saveError = err.Error()
+			f.saving = false
+			return nil
+		}
+		entry.ExtraConfig = withIdentitiesOnly(entry.ExtraConfig)
+	}
+
 	// Perform add or edit
 	var err error
 	if f.mode == FormAdd {
@@ -418,8 +458,15 @@ func (f *ServerForm) performSave() tea.Cmd {
 	}
 
 	// Success - send serverSavedMsg
+	// Tags and VPN Required have no SSH config directive; the model stores them in the ssherpa config
+	saved := serverSavedMsg{
+		alias:         entry.Alias,
+		originalAlias: f.originalAlias,
+		tags:          parseTags(f.fields[6].input.Value()),
+		vpnRequired:   f.fields[7].checked,
+	}
 	return func() tea.Msg {
-		return serverSavedMsg{alias: entry.Alias}
+		return saved
 	}
 }
 
@@ -440,14 +487,45 @@ func (f *ServerForm) performBackendSave() tea.Cmd {
 		}
 	}
 
-	server := &domain.Server{
-		ID:           alias, // For new servers, ID = DisplayName
-		DisplayName:  alias,
-		Host:         hostname,
-		User:         user,
-		Port:         port,
-		IdentityFile: identityFile,
-		Tags:         []string{},
+	server := &domain.Server{}
+	if f.mode == FormEdit && f.original != nil {
+		// Start from the stored server so vault, projects, favorite and
+		// notes are kept; the form fields below replace the rest
+		*server = *f.original
+		server.Proxy = ""
+		server.SSHOptions = nil
+		if identityFile != "" && identityFile != f.original.IdentityFile {
+			server.CredentialID = ""
+		}
+	}
+	server.ID = alias // For new servers, ID = DisplayName
+	server.DisplayName = alias
+	server.Host = hostname
+	server.User = user
+	server.Port = port
+	server.IdentityFile = identityFile
+	server.Tags = parseTags(f.fields[6].input.Value())
+	server.VPNRequired = f.fields[7].checked
+	if f.mode == FormAdd {
+		server.VaultID = f.selectedVaultID()
+	}
+
+	// Servers using a 1Password SSH Key reference the item; the public key
+	// file is written when the SSH include file is generated
+	if key := f.vaultKey(); key != nil {
+		server.CredentialID = key.CredentialID
+		server.IdentityFile = ""
+	}
+
+	// Extra Config becomes SSH options; ProxyJump has its own field
+	if options := sshconfig.ParseDirectives(f.fields[5].textarea.Value()); len(options) > 0 {
+		for key, values := range options {
+			if strings.EqualFold(key, "ProxyJump") {
+				server.Proxy = values[0]
+				delete(options, key)
+			}
+		}
+		server.SSHOptions = options
 	}
 
 	// Perform add or edit
@@ -469,12 +547,140 @@ func (f *ServerForm) performBackendSave() tea.Cmd {
 		return nil
 	}
 
-	// Success - send BackendServersUpdatedMsg to trigger reload
+	// Success - send serverSavedMsg so the model closes the form and reloads
+	saved := serverSavedMsg{alias: alias, originalAlias: f.originalAlias, backend: true}
 	return func() tea.Msg {
-		return BackendServersUpdatedMsg{}
+		return saved
 	}
 }
 
+// vaultKey returns the selected key if it is a 1Password SSH Key item still
+// shown in the IdentityFile field, nil otherwise.
+func (f *ServerForm) vaultKey() *sshkey.SSHKey {
+	if f.selectedKey == nil || f.selectedKey.CredentialID == "" {
+		return nil
+	}
+	if strings.TrimSpace(f.fields[4].input.Value()) != f.selectedKey.Path {
+		return nil
+	}
+	return f.selectedKey
+}
+
+// withIdentitiesOnly adds "IdentitiesOnly yes" to extra SSH directives unless
+// they already set IdentitiesOnly.
+func withIdentitiesOnly(extra string) string {
+	for key := range sshconfig.ParseDirectives(extra) {
+		if strings.EqualFold(key, "IdentitiesOnly") {
+			return extra
+		}
+	}
+	if extra == "" {
+		return "IdentitiesOnly yes"
+	}
+	return extra + "\nIdentitiesOnly yes"
+}
+
+// SetVaults adds a Vault picker (add mode) offering the SSH config and the
+// given vaults, with defaultVaultID preselected. Servers saved to a vault go
+// through writer.
+func (f *ServerForm) SetVaults(writer backend.Writer, vaults []backend.Vault, defaultVaultID string) {
+	choices := []string{"SSH config (~/.ssh/config)"}
+	selected := 0
+	for i, v := range vaults {
+		choices = append(choices, "1Password: "+v.Name)
+		if v.ID == defaultVaultID {
+			selected = i + 1
+		}
+	}
+
+	f.backendWriter = writer
+	f.vaults = vaults
+	f.vaultField = len(f.fields)
+	f.fields = append(f.fields, formField{
+		label:    "Vault",
+		isChoice: true,
+		choices:  choices,
+		choice:   selected,
+	})
+}
+
+// selectedVaultID returns the vault picked in the Vault field, or "" for the
+// SSH config and forms without a picker.
+func (f *ServerForm) selectedVaultID() string {
+	if f.vaults == nil {
+		return ""
+	}
+	choice := f.fields[f.vaultField].choice
+	if choice == 0 {
+		return ""
+	}
+	return f.vaults[choice-1].ID
+}
+
+// SetBackendServer makes an edit form save through writer instead of the SSH
+// config, updating srv. Tags, the VPN flag and the server's SSH options are
+// pre-filled from srv; fields the form doesn't show keep srv's values.
+func (f *ServerForm) SetBackendServer(writer backend.Writer, srv *domain.Server) {
+	f.backendWriter = writer
+	f.originalID = srv.ID
+	f.original = srv
+
+	var extra []string
+	if srv.Proxy != "" {
+		extra = append(extra, "ProxyJump "+srv.Proxy)
+	}
+	extra = append(extra, srv.SSHOptionLines()...)
+	f.fields[5].textarea.SetValue(strings.Join(extra, "\n"))
+	f.SetTags(srv.Tags)
+	f.SetVPNRequired(srv.VPNRequired)
+}
+
+// SetVPNRequired pre-sets the VPN Required checkbox (edit mode).
+func (f *ServerForm) SetVPNRequired(required bool) {
+	f.fields[7].checked = required
+}
+
+// SetTags pre-fills the Tags field (edit mode).
+func (f *ServerForm) SetTags(tags []string) {
+	f.fields[6].input.SetValue(strings.Join(tags, ", "))
+}
+
+// parseTags splits a comma-separated tag list, trimming blanks and dropping
+// duplicates (case-insensitive, first spelling wins).
+func parseTags(value string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// renderCheckbox renders a checkbox field with its description.
+func renderCheckbox(checked, focused bool, desc string) string {
+	box := "[ ]"
+	if checked {
+		box = "[x]"
+	}
+	if focused {
+		return lipgloss.NewStyle().Foreground(accentColor).Render("> "+box) + " " + desc + secondaryStyle.Render("  (space to toggle)")
+	}
+	return "  " + box + " " + desc
+}
+
+// renderChoice renders a picker field's current option.
+func renderChoice(option string, focused bool) string {
+	if focused {
+		return lipgloss.NewStyle().Foreground(accentColor).Render("> ‹ "+option+" ›") + secondaryStyle.Render("  (←/→ to change)")
+	}
+	return "  " + option
+}
+
 // View renders the form.
 func (f ServerForm) View() string {
 	var b strings.Builder
@@ -497,8 +703,12 @@ func (f ServerForm) View() string {
 		b.WriteString(formLabelStyle.Render(labelText))
 		b.WriteString("\n")
 
-		// Input or textarea
-		if field.isTextarea {
+		// Input, textarea, checkbox or picker
+		if field.isToggle {
+			b.WriteString(renderCheckbox(field.checked, i == f.focusIndex, "Check VPN connectivity before connecting"))
+		} else if field.isChoice {
+			b.WriteString(renderChoice(field.choices[field.choice], i == f.focusIndex))
+		} else if field.isTextarea {
 			b.WriteString(field.textarea.View())
 		} else {
 			b.WriteString(field.input.View())
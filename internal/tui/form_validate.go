@@ -77,6 +77,25 @@ func validatePort(value string) string {
 	return ""
 }
 
+// validateTags validates the comma-separated tags field.
+// Returns empty string if valid, error message otherwise.
+// Tags are optional; each one must work as a "tag:<name>" search token.
+func validateTags(value string) string {
+	for _, tag := range strings.Split(value, ",") {
+		trimmed := strings.TrimSpace(tag)
+		if strings.ContainsAny(trimmed, " \t") {
+			return "Tags cannot contain spaces (separate tags with commas)"
+		}
+		if strings.Contains(trimmed, ":") {
+			return "Tags cannot contain ':'"
+		}
+		if strings.EqualFold(trimmed, "ssherpa") {
+			return "'ssherpa' is reserved for 1Password items"
+		}
+	}
+	return ""
+}
+
 // checkDNS performs a DNS lookup on the hostname with a 2-second timeout.
 // Returns nil on success, error on failure.
 func checkDNS(hostname string) error {
@@ -118,8 +118,10 @@ func RenderFieldReferencePlain() string {
 	b.WriteString("  proxy_jump            no        -             Bastion/jump host for ProxyJump\n")
 	b.WriteString("  project_tags          no        -             Comma-separated project tags (e.g. \"web,api\")\n")
 	b.WriteString("  remote_project_path   no        -             Remote path to cd into on connect\n")
-	b.WriteString("  forward_agent         no        -             Enable SSH agent forwarding (noted, not yet mapped)\n")
-	b.WriteString("  extra_config          no        -             Additional SSH config directives (noted, not yet mapped)\n\n")
+	b.WriteString("  vpn_required          no        false         Check VPN before connecting (true/yes/1)\n")
+	b.WriteString("  favorite              no        false         Pin to the top of the list (true/yes/1)\n")
+	b.WriteString("  forward_agent         no        -             Enable SSH agent forwarding (yes/no)\n")
+	b.WriteString("  extra_config          no        -             Extra SSH directives, one per line\n\n")
 
 	b.WriteString("Example:\n")
 	b.WriteString("  Title: Production API Server\n")
@@ -159,8 +161,10 @@ func buildFieldTable() string {
 		buildFieldRow("proxy_jump", "no", "-", "Bastion/jump host for ProxyJump"),
 		buildFieldRow("project_tags", "no", "-", "Comma-separated project tags (e.g. \"web,api\")"),
 		buildFieldRow("remote_project_path", "no", "-", "Remote path to cd into on connect"),
-		buildFieldRow("forward_agent", "no", "-", "Enable SSH agent forwarding (noted, not yet mapped)"),
-		buildFieldRow("extra_config", "no", "-", "Additional SSH config directives (noted, not yet mapped)"),
+		buildFieldRow("vpn_required", "no", "false", "Check VPN before connecting (true/yes/1)"),
+		buildFieldRow("favorite", "no", "false", "Pin to the top of the list (true/yes/1)"),
+		buildFieldRow("forward_agent", "no", "-", "Enable SSH agent forwarding (yes/no)"),
+		buildFieldRow("extra_config", "no", "-", "Extra SSH directives, one per line"),
 	}
 
 	parts := []string{header, separator}
@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+)
+
+// serverForHost returns the backend server for a host, or one built from the
+// SSH config entry so ssherpa rules (VPN, favorites) can be evaluated for
+// plain SSH config hosts.
+func (m *Model) serverForHost(host sshconfig.SSHHost) *domain.Server {
+	if srv := m.hostServers[host.Name]; srv != nil {
+		if srv.Source != "ssh-config" {
+			return srv
+		}
+		// The backend's copy of the VPN flag dates from startup; the
+		// [[host]] entry is always current in the model.
+		current := *srv
+		current.VPNRequired = m.hostVPNRequired(host.Name)
+		return &current
+	}
+
+	srv := &domain.Server{
+		ID:          host.Name,
+		DisplayName: host.Name,
+		Host:        host.Hostname,
+		User:        host.User,
+		Source:      "ssh-config",
+	}
+	if srv.Host == "" {
+		srv.Host = host.Name
+	}
+	if port, err := strconv.Atoi(host.Port); err == nil {
+		srv.Port = port
+	}
+	for key, values := range host.AllOptions {
+		if strings.EqualFold(key, "ProxyJump") || strings.EqualFold(key, "ProxyCommand") {
+			if len(values) > 0 {
+				srv.Proxy = values[0]
+			}
+		}
+	}
+	return srv
+}
+
+// filterServer returns the server used to evaluate search filters for a host,
+// with the favorite flag and tags resolved the same way the list renders them.
+func (m *Model) filterServer(host sshconfig.SSHHost) *domain.Server {
+	srv := *m.serverForHost(host)
+	srv.Tags = m.hostTags(host.Name)
+	srv.Favorite = m.isFavorite(host.Name)
+	return &srv
+}
+
+// rejectMatchBlock shows a status message and returns true for Match blocks,
+// which ssherpa lists but never edits, deletes or pins.
+func (m *Model) rejectMatchBlock(host sshconfig.SSHHost) bool {
+	if !host.IsMatch {
+		return false
+	}
+	m.statusMsg = fmt.Sprintf("Match blocks are read-only in ssherpa; edit %s:%d directly", host.SourceFile, host.SourceLine)
+	return true
+}
+
+// rejectReadOnly shows a status message and returns true for backend servers
+// ssherpa can't change: the backend has no Writer, or the server comes from an
+// Ansible inventory. Their entries in the generated SSH include file are
+// rewritten on every sync, so they are never edited there either.
+func (m *Model) rejectReadOnly(host sshconfig.SSHHost) bool {
+	srv := m.backendServer(host.Name)
+	if srv == nil || m.serverWriter(srv) != nil {
+		return false
+	}
+	m.statusMsg = fmt.Sprintf("'%s' comes from %s, which is read-only in ssherpa", host.Name, srv.Source)
+	return true
+}
+
+// hostSettings returns the model's view of the ssherpa config (VPN settings,
+// projects and [[host]] metadata) for the helpers that take a *config.Config.
+func (m *Model) hostSettings() *config.Config {
+	return &config.Config{
+		VPN:      m.vpnConfig,
+		Projects: m.projects,
+		Hosts:    m.hostConfigs,
+	}
+}
+
+// hostConfig returns the [[host]] entry for an alias, or an empty entry.
+func (m *Model) hostConfig(alias string) config.HostConfig {
+	if h := m.hostSettings().Host(alias); h != nil {
+		return *h
+	}
+	return config.HostConfig{Alias: alias}
+}
+
+// updateHostConfig applies change to the [[host]] entry for alias and saves the
+// ssherpa config. When originalAlias differs (rename), its entry moves to alias.
+func (m *Model) updateHostConfig(alias, originalAlias string, change func(*config.HostConfig)) {
+	settings := m.hostSettings()
+
+	entry := m.hostConfig(alias)
+	if originalAlias != "" && !strings.EqualFold(originalAlias, alias) {
+		if old := settings.Host(originalAlias); old != nil {
+			entry = *old
+			entry.Alias = alias
+		}
+		settings.SetHost(config.HostConfig{Alias: originalAlias})
+	}
+
+	change(&entry)
+	settings.SetHost(entry)
+
+	m.hostConfigs = settings.Hosts
+	m.saveConfig()
+}
+
+// hostVPNRequired reports whether the host itself (not its projects) is flagged as VPN-only.
+// Backend servers carry the flag themselves; SSH config hosts use the [[host]] entry.
+func (m *Model) hostVPNRequired(alias string) bool {
+	if srv := m.hostServers[alias]; srv != nil && srv.Source != "ssh-config" {
+		return srv.VPNRequired
+	}
+	return m.hostConfig(alias).VPNRequired
+}
+
+// isFavorite reports whether a host is pinned.
+// Backend servers (1Password) carry the flag themselves; SSH config hosts
+// use the [[host]] entry, which is always current in the model.
+func (m *Model) isFavorite(alias string) bool {
+	if srv := m.hostServers[alias]; srv != nil && srv.Source != "ssh-config" {
+		return srv.Favorite
+	}
+	return m.hostConfig(alias).Favorite
+}
+
+// hostTags returns a host's tags.
+// Backend servers carry their own tags; SSH config hosts use the [[host]] entry.
+func (m *Model) hostTags(alias string) []string {
+	if srv := m.hostServers[alias]; srv != nil && srv.Source != "ssh-config" {
+		return srv.Tags
+	}
+	return m.hostConfig(alias).Tags
+}
+
+// backendServer returns the backend server behind alias, or nil for SSH
+// config hosts, which are edited in the SSH config files.
+func (m *Model) backendServer(alias string) *domain.Server {
+	if srv := m.hostServers[alias]; srv != nil && srv.Source != "ssh-config" {
+		return srv
+	}
+	return nil
+}
+
+// serverWriter returns the writer that edits a backend server, or nil when the
+// server is read-only (the backend has no Writer, or it's an Ansible host).
+func (m *Model) serverWriter(srv *domain.Server) backend.Writer {
+	writer, ok := m.appBackend.(backend.Writer)
+	if !ok || srv.Source == "ansible" {
+		return nil
+	}
+	return writer
+}
+
+// newEditForm opens the edit form for host. Backend servers are saved through
+// the backend writer, which stores their tags and VPN flag itself; SSH config
+// hosts take those fields from their [[host]] entry.
+func (m *Model) newEditForm(host sshconfig.SSHHost) ServerForm {
+	form := NewEditServerForm(m.configPath, host)
+	if srv := m.backendServer(host.Name); srv != nil {
+		if writer := m.serverWriter(srv); writer != nil {
+			form.SetBackendServer(writer, srv)
+			return form
+		}
+	}
+	form.SetTags(m.hostTags(host.Name))
+	form.SetVPNRequired(m.hostVPNRequired(host.Name))
+	return form
+}
+
+// newDeleteConfirm opens the delete confirmation for host. Backend servers are
+// deleted through the backend writer; SSH config hosts lose their Host block.
+func (m *Model) newDeleteConfirm(host sshconfig.SSHHost) DeleteConfirm {
+	confirm := NewDeleteConfirm(host.Name, m.configPath)
+	if srv := m.backendServer(host.Name); srv != nil {
+		if writer := m.serverWriter(srv); writer != nil {
+			confirm.SetBackendServer(writer, srv)
+		}
+	}
+	return confirm
+}
+
+// saveHostFields stores the form fields SSH config can't hold (tags, VPN flag)
+// in the ssherpa config. Nothing is written unless there is metadata to store
+// or clear. Backend servers keep these fields themselves, so a [[host]] entry
+// for them would never be read.
+func (m *Model) saveHostFields(msg serverSavedMsg) {
+	if m.backendServer(msg.alias) != nil || (msg.originalAlias != "" && m.backendServer(msg.originalAlias) != nil) {
+		return
+	}
+
+	renamed := msg.originalAlias != "" && !strings.EqualFold(msg.originalAlias, msg.alias)
+	hasEntry := !m.hostConfig(msg.alias).IsEmpty() || (renamed && !m.hostConfig(msg.originalAlias).IsEmpty())
+	if !hasEntry && !msg.vpnRequired && len(msg.tags) == 0 {
+		return
+	}
+
+	m.updateHostConfig(msg.alias, msg.originalAlias, func(h *config.HostConfig) {
+		h.VPNRequired = msg.vpnRequired
+		h.Tags = msg.tags
+		if len(h.Tags) == 0 {
+			h.Tags = nil
+		}
+	})
+}
+
+// toggleFavorite pins or unpins a host.
+// SSH config hosts are saved to the ssherpa config immediately; backend servers
+// are updated through the backend's Writer in the background.
+func (m *Model) toggleFavorite(alias string) tea.Cmd {
+	favorite := !m.isFavorite(alias)
+
+	srv := m.backendServer(alias)
+	if srv == nil {
+		m.updateHostConfig(alias, "", func(h *config.HostConfig) { h.Favorite = favorite })
+		m.rebuildListItems()
+		return func() tea.Msg { return favoriteToggledMsg{alias: alias, favorite: favorite} }
+	}
+
+	// Ansible inventory hosts are read-only; the flag lives in the inventory
+	writer := m.serverWriter(srv)
+	if writer == nil {
+		return func() tea.Msg {
+			return favoriteToggledMsg{alias: alias, favorite: favorite, err: errors.ErrReadOnlyBackend}
+		}
+	}
+
+	// Optimistic update so the list reorders immediately
+	updated := *srv
+	updated.Favorite = favorite
+	m.hostServers[alias] = &updated
+	m.rebuildListItems()
+
+	return func() tea.Msg {
+		err := writer.UpdateServer(context.Background(), &updated)
+		return favoriteToggledMsg{alias: alias, favorite: favorite, err: err, backend: true}
+	}
+}
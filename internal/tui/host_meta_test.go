@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/backend/mock"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+	"github.com/florianriquelme/ssherpa/internal/vpn"
+)
+
+func TestHostVPNRequired_SSHConfigHostUsesHostEntry(t *testing.T) {
+	host := sshconfig.SSHHost{Name: "web", Hostname: "web.example.com"}
+	m := Model{
+		hostServers: map[string]*domain.Server{
+			"web": {ID: "web", DisplayName: "web", Host: "web.example.com", Source: "ssh-config", VPNRequired: true},
+			"db":  {ID: "db", DisplayName: "db", Host: "db.example.com", Source: "1password", VPNRequired: true},
+		},
+		hostConfigs: []config.HostConfig{{Alias: "web", VPNRequired: true}},
+	}
+	assert.True(t, m.hostVPNRequired("web"))
+	assert.True(t, vpn.Required(m.serverForHost(host), m.hostSettings()))
+
+	// Clearing the flag in the form updates only the [[host]] entry
+	m.hostConfigs = nil
+	assert.False(t, m.hostVPNRequired("web"))
+	assert.False(t, vpn.Required(m.serverForHost(host), m.hostSettings()))
+
+	assert.True(t, m.hostVPNRequired("db"))
+}
+
+func TestEditForm_BackendServerSavesTagsThroughWriter(t *testing.T) {
+	stored := &domain.Server{
+		ID: "item-1", DisplayName: "api", Host: "api.example.com", User: "deploy", Port: 22,
+		VaultID: "v1", Source: "1password", Tags: []string{"old"}, Favorite: true,
+		ProjectIDs: []string{"payments"}, SSHOptions: map[string][]string{"ForwardAgent": {"yes"}},
+	}
+	b := mock.New()
+	b.Seed([]*domain.Server{stored}, nil, nil)
+
+	m := Model{
+		appBackend:     b,
+		hostServers:    map[string]*domain.Server{"api": stored},
+		configFilePath: filepath.Join(t.TempDir(), "config.toml"),
+	}
+	host, _, _ := serversToSSHHosts([]*domain.Server{stored})
+
+	form := m.newEditForm(host[0])
+	assert.Equal(t, "old", form.fields[6].input.Value())
+	assert.Equal(t, "ForwardAgent yes", form.fields[5].textarea.Value())
+
+	form.fields[6].input.SetValue("prod, web")
+	form.fields[7].checked = true
+	msg := form.performBackendSave()()
+	saved, ok := msg.(serverSavedMsg)
+	require.True(t, ok, "got %T", msg)
+	assert.True(t, saved.backend)
+
+	got, err := b.GetServer(context.Background(), "item-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod", "web"}, got.Tags)
+	assert.True(t, got.VPNRequired)
+	assert.Equal(t, "v1", got.VaultID)
+	assert.True(t, got.Favorite)
+	assert.Equal(t, []string{"payments"}, got.ProjectIDs)
+	assert.Equal(t, map[string][]string{"ForwardAgent": {"yes"}}, got.SSHOptions)
+
+	// A [[host]] entry for a backend server would never be read
+	m.saveHostFields(serverSavedMsg{alias: "api", tags: []string{"prod"}, vpnRequired: true})
+	assert.Empty(t, m.hostConfigs)
+}
+
+func TestDeleteConfirm_BackendServerDeletesThroughWriter(t *testing.T) {
+	stored := &domain.Server{ID: "item-1", DisplayName: "api", Host: "api.example.com", Source: "1password"}
+	inventory := &domain.Server{ID: "db1", DisplayName: "db1", Host: "10.0.0.5", Source: "ansible"}
+	b := mock.New()
+	b.Seed([]*domain.Server{stored, inventory}, nil, nil)
+
+	m := Model{
+		appBackend:  b,
+		hostServers: map[string]*domain.Server{"api": stored, "db1": inventory},
+		configPath:  filepath.Join(t.TempDir(), "config"),
+	}
+	hosts, _, _ := serversToSSHHosts([]*domain.Server{stored, inventory})
+
+	assert.False(t, m.rejectReadOnly(hosts[0]))
+	confirm := m.newDeleteConfirm(hosts[0])
+	msg := confirm.performBackendDelete()()
+	deleted, ok := msg.(serverDeletedMsg)
+	require.True(t, ok, "got %T", msg)
+	assert.True(t, deleted.backend)
+	assert.Equal(t, "api", deleted.alias)
+
+	_, err := b.GetServer(context.Background(), "item-1")
+	assert.Error(t, err)
+
+	// Inventory hosts are regenerated on every sync, so they can't be changed
+	assert.True(t, m.rejectReadOnly(hosts[1]))
+	assert.Contains(t, m.statusMsg, "read-only")
+}
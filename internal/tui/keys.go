@@ -15,20 +15,22 @@ type KeyMap struct {
 	GoToBottom   key.Binding
 
 	// Actions
-	Connect       key.Binding
-	Details       key.Binding
-	Search        key.Binding
-	AssignProject key.Binding
-	SelectKey     key.Binding
-	AddServer     key.Binding
-	EditServer    key.Binding
-	DeleteServer  key.Binding
-	Undo          key.Binding
-	SignIn        key.Binding
-	Help          key.Binding
-	Quit          key.Binding
-	ClearSearch   key.Binding
-	ForceQuit     key.Binding
+	Connect          key.Binding
+	ToggleRemotePath key.Binding
+	ToggleFavorite   key.Binding
+	Details          key.Binding
+	Search           key.Binding
+	AssignProject    key.Binding
+	SelectKey        key.Binding
+	AddServer        key.Binding
+	EditServer       key.Binding
+	DeleteServer     key.Binding
+	Undo             key.Binding
+	SignIn           key.Binding
+	Help             key.Binding
+	Quit             key.Binding
+	ClearSearch      key.Binding
+	ForceQuit        key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -73,6 +75,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "connect"),
 		),
+		ToggleRemotePath: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "toggle cd"),
+		),
+		ToggleFavorite: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "favorite"),
+		),
 		Details: key.NewBinding(
 			key.WithKeys("tab", "i"),
 			key.WithHelp("tab/i", "details"),
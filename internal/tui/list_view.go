@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -20,17 +21,25 @@ type hostItem struct {
 	host            sshconfig.SSHHost
 	lastConnectedAt *time.Time  // Timestamp of last connection (nil if never connected)
 	projectBadges   []badgeData // Project badges to render inline
+	favorite        bool        // Pinned to the Favorites section
+	tags            []string    // User-defined tags rendered as badges
 }
 
 // FilterValue returns the value used for filtering/searching.
-// Returns concatenated Name + Hostname + User for multi-field search.
+// Returns concatenated Name + Hostname + User + tags for multi-field search.
 func (h hostItem) FilterValue() string {
-	return h.host.Name + " " + h.host.Hostname + " " + h.host.User
+	return h.host.Name + " " + h.host.Hostname + " " + h.host.User + " " + strings.Join(h.tags, " ")
 }
 
 // Title returns the first line of the list item.
-// Format: Name (hostname) [badge1] [badge2] with warning indicator if ParseError is set.
+// Format: Name (hostname) [badge1] [badge2] #tag1 #tag2 with a star for favorites and a
+// warning indicator if ParseError is set.
 func (h hostItem) Title() string {
+	// Match blocks have no hostname of their own
+	if h.host.IsMatch {
+		return secondaryStyle.Render(h.host.Name)
+	}
+
 	title := fmt.Sprintf("%s (%s)",
 		hostnameStyle.Render(h.host.Name),
 		h.host.Hostname)
@@ -40,6 +49,15 @@ func (h hostItem) Title() string {
 		title += " " + RenderProjectBadge(badge.name, badge.color)
 	}
 
+	// Append tag badges
+	for _, tag := range h.tags {
+		title += " " + RenderTagBadge(tag)
+	}
+
+	if h.favorite {
+		title = favoriteStyle.Render("★ ") + title
+	}
+
 	// Add warning indicator if there's a parse error
 	if h.host.ParseError != nil {
 		title = warningStyle.Render("⚠ ") + title
@@ -56,6 +74,10 @@ func (h hostItem) Description() string {
 		return warningStyle.Render(fmt.Sprintf("Error: %v", h.host.ParseError))
 	}
 
+	if h.host.IsMatch {
+		return secondaryStyle.Render("Match block (not connectable)")
+	}
+
 	// Default values for empty fields
 	user := h.host.User
 	if user == "" {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/domain"
 	"github.com/florianriquelme/ssherpa/internal/sshconfig"
 	"github.com/florianriquelme/ssherpa/internal/sshkey"
 )
@@ -14,7 +15,8 @@ import (
 type configLoadedMsg struct {
 	hosts   []sshconfig.SSHHost
 	items   []list.Item
-	sources map[string]string // Maps host name to source (e.g., "ssh-config", "1password")
+	sources map[string]string         // Maps host name to source (e.g., "ssh-config", "1password")
+	servers map[string]*domain.Server // Maps host name to backend server (nil for ssh-config-only mode)
 	err     error
 }
 
@@ -30,13 +32,19 @@ type formCancelledMsg struct{}
 
 // serverSavedMsg is sent after a server is successfully added or edited.
 type serverSavedMsg struct {
-	alias string
+	alias         string
+	originalAlias string   // Alias before the edit (empty in add mode)
+	tags          []string // Tags field, persisted in the ssherpa config
+	vpnRequired   bool     // VPN Required checkbox, persisted in the ssherpa config
+	backend       bool     // Saved through the backend writer, which stores tags and VPN flag itself
 }
 
 // serverDeletedMsg is sent after a server is successfully deleted.
 type serverDeletedMsg struct {
 	alias        string
+	sourceFile   string // File the Host block was removed from (undo restores it there)
 	removedLines []string
+	backend      bool // Deleted through the backend writer; there is nothing to undo
 }
 
 // deleteErrorMsg is sent when deletion fails.
@@ -92,3 +100,36 @@ type hostKeyUpdatedMsg struct {
 type formRequestKeyPickerMsg struct {
 	currentKeyPath string
 }
+
+// vpnCheckResultMsg is sent when the pre-connect VPN check completes.
+type vpnCheckResultMsg struct {
+	host sshconfig.SSHHost
+	err  error // nil = VPN is up
+}
+
+// vpnConnectAnywayMsg is sent when the user connects despite a failed VPN check.
+type vpnConnectAnywayMsg struct {
+	host sshconfig.SSHHost
+}
+
+// vpnWarningCancelledMsg is sent when the user cancels from the VPN warning.
+type vpnWarningCancelledMsg struct{}
+
+// vpnRunCommandMsg is sent when the user asks to run the configured VPN command.
+type vpnRunCommandMsg struct {
+	host sshconfig.SSHHost
+}
+
+// vpnCommandFinishedMsg is sent when the VPN command exits.
+type vpnCommandFinishedMsg struct {
+	host sshconfig.SSHHost
+	err  error
+}
+
+// favoriteToggledMsg is sent after a host is pinned or unpinned.
+type favoriteToggledMsg struct {
+	alias    string
+	favorite bool
+	err      error
+	backend  bool // Saved through the backend Writer (list must be reloaded)
+}
@@ -8,37 +8,29 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
 )
 
 // MigrationWizard is a Bubbletea model for migrating existing 1Password items.
 type MigrationWizard struct {
-	client   interface{}          // SDK client (will be onepassword.Client when integrated)
-	items    []MigrationCandidate // Discovered unmanaged items
-	selected map[int]bool         // Selection state for each item
-	cursor   int                  // Cursor position in list
-	step     int                  // Current step: scanning, selecting, migrating, done
-	spinner  spinner.Model        // Loading spinner
-	results  MigrationResults     // Migration results
+	client   onepassword.Client               // 1Password client (op CLI or Connect)
+	items    []onepassword.MigrationCandidate // Discovered unmanaged items
+	selected map[int]bool                     // Selection state for each item
+	cursor   int                              // Cursor position in list
+	step     int                              // Current step: scanning, selecting, migrating, done
+	spinner  spinner.Model                    // Loading spinner
+	results  MigrationResults                 // Migration results
 	width    int
 	height   int
 	err      error // Error message if any
 }
 
-// MigrationCandidate represents a 1Password item that can be migrated.
-type MigrationCandidate struct {
-	ItemID      string // 1Password item ID
-	Title       string // Item title
-	VaultName   string // Vault the item belongs to
-	HasHostname bool   // Whether hostname field exists
-	HasUser     bool   // Whether user field exists
-	Complete    bool   // Has all required fields (hostname + user)
-}
-
 // MigrationResults tracks the outcome of the migration.
 type MigrationResults struct {
-	Migrated int      // Number of successfully migrated items
-	Skipped  int      // Number of items skipped by user
-	Errors   []string // Error messages for failed migrations
+	Migrated int                           // Number of successfully migrated items
+	Skipped  int                           // Number of items skipped by user
+	Errors   []string                      // Error messages for failed migrations
+	Items    []onepassword.MigrationResult // Outcome per migrated item, in list order
 }
 
 // Migration steps
@@ -49,8 +41,8 @@ const (
 	stepDone
 )
 
-// NewMigrationWizard creates a new migration wizard.
-func NewMigrationWizard(client interface{}) MigrationWizard {
+// NewMigrationWizard creates a new migration wizard using client.
+func NewMigrationWizard(client onepassword.Client) MigrationWizard {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(accentColor)
@@ -79,6 +71,11 @@ func (m MigrationWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 	case tea.KeyMsg:
+		// Quit at any step; items already migrated stay migrated
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
 		switch m.step {
 		case stepScanning:
 			// No input during scan
@@ -126,7 +123,7 @@ func (m MigrationWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Pre-select all complete items
 		for i, item := range m.items {
-			if item.Complete {
+			if item.Complete() {
 				m.selected[i] = true
 			}
 		}
@@ -195,7 +192,7 @@ func (m MigrationWizard) renderScanning() string {
 	title := titleStyle.Render("Migration Wizard")
 	b.WriteString(title + "\n\n")
 
-	fmt.Fprintf(&b, "  %s Scanning 1Password vaults for SSH items...\n", m.spinner.View())
+	fmt.Fprintf(&b, "  %s Scanning 1Password vaults for Server and Login items...\n", m.spinner.View())
 
 	return wizardBoxStyle.Render(b.String())
 }
@@ -230,20 +227,20 @@ func (m MigrationWizard) renderSelecting() string {
 		// Status indicator
 		status := "✓ Complete"
 		statusStyle := wizardSuccessStyle
-		if !item.Complete {
-			if !item.HasHostname {
+		if !item.Complete() {
+			if item.Hostname == "" {
 				status = "✗ Missing hostname"
 				statusStyle = wizardErrorStyle
-			} else if !item.HasUser {
-				status = "⚠ Missing user"
-				statusStyle = warningStyle
+			} else {
+				status = "✗ Missing user"
+				statusStyle = wizardErrorStyle
 			}
 		}
 
 		line := fmt.Sprintf("%s%s %-30s (%-20s) %s",
 			cursor,
 			checkbox,
-			item.Title,
+			item.Item.Title,
 			item.VaultName,
 			statusStyle.Render(status),
 		)
@@ -255,6 +252,10 @@ func (m MigrationWizard) renderSelecting() string {
 		b.WriteString(line + "\n")
 	}
 
+	// Field mapping preview for the item under the cursor
+	b.WriteString("\n")
+	b.WriteString(m.renderPreview(m.items[m.cursor]))
+
 	b.WriteString("\n")
 	b.WriteString(wizardDimStyle.Render("Space: toggle, a: select all, n: deselect all\n"))
 	b.WriteString(wizardDimStyle.Render("Enter: migrate selected, Esc: skip\n"))
@@ -266,6 +267,36 @@ func (m MigrationWizard) renderSelecting() string {
 	return wizardBoxStyle.Render(b.String())
 }
 
+// renderPreview shows how a candidate's fields map to ssherpa fields and
+// which fields the migration adds.
+func (m MigrationWizard) renderPreview(item onepassword.MigrationCandidate) string {
+	var b strings.Builder
+
+	row := func(field, value, from string) {
+		if value == "" {
+			fmt.Fprintf(&b, "  %-9s %s\n", field, wizardErrorStyle.Render("not found"))
+			return
+		}
+		fmt.Fprintf(&b, "  %-9s %s %s\n", field, value, wizardDimStyle.Render("← "+from))
+	}
+
+	fmt.Fprintf(&b, "%s\n", wizardDimStyle.Render(fmt.Sprintf("Mapping for %q:", item.Item.Title)))
+	row("hostname", item.Hostname, item.HostnameFrom)
+	row("user", item.User, item.UserFrom)
+	if item.Port != 0 {
+		fmt.Fprintf(&b, "  %-9s %d\n", "port", item.Port)
+	}
+
+	added := make([]string, 0, len(item.Fields)+1)
+	added = append(added, "tag ssherpa")
+	for _, f := range item.Fields {
+		added = append(added, f.Title)
+	}
+	fmt.Fprintf(&b, "  %-9s %s\n", "adds", strings.Join(added, ", "))
+
+	return b.String()
+}
+
 // renderMigrating renders the migration progress screen.
 func (m MigrationWizard) renderMigrating() string {
 	var b strings.Builder
@@ -295,7 +326,7 @@ func (m MigrationWizard) renderDone() string {
 	if m.err != nil {
 		b.WriteString(wizardErrorStyle.Render(fmt.Sprintf("Error: %v\n", m.err)))
 	} else if len(m.items) == 0 {
-		b.WriteString(wizardDimStyle.Render("No unmanaged SSH items found in 1Password\n"))
+		b.WriteString(wizardDimStyle.Render("No unmanaged Server or Login items found in 1Password\n"))
 	} else {
 		fmt.Fprintf(&b, "  Migrated:  %s items\n", wizardSuccessStyle.Render(fmt.Sprintf("%d", m.results.Migrated)))
 		fmt.Fprintf(&b, "  Skipped:   %d items\n", m.results.Skipped)
@@ -315,54 +346,43 @@ func (m MigrationWizard) renderDone() string {
 	return wizardBoxStyle.Render(b.String())
 }
 
-// scanForItems scans 1Password vaults for unmanaged SSH items.
+// Results returns the outcome of the migration once the wizard is done.
+func (m MigrationWizard) Results() MigrationResults {
+	return m.results
+}
+
+// scanForItems scans 1Password vaults for unmanaged Server and Login items.
 func (m MigrationWizard) scanForItems() tea.Cmd {
 	return func() tea.Msg {
-		// TODO: Implement actual 1Password scanning using the client
-		// For now, simulate no items found (client integration pending)
-
-		// In real implementation:
-		// 1. List all vaults
-		// 2. For each vault, list items with category "Server" or "SSH"
-		// 3. Filter items WITHOUT "ssherpa" tag
-		// 4. Parse fields to determine completeness (hostname + user)
-
-		return scanCompleteMsg{
-			items: []MigrationCandidate{}, // Empty for now (placeholder)
-			err:   nil,
-		}
+		ctx := context.Background()
+		items, err := onepassword.FindMigrationCandidates(ctx, m.client)
+		return scanCompleteMsg{items: items, err: err}
 	}
 }
 
-// migrateSelected performs migration on selected items.
+// migrateSelected tags the selected items and adds their missing fields.
 func (m MigrationWizard) migrateSelected() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
-		results := MigrationResults{}
 
-		// Count selected items
-		var selectedItems []MigrationCandidate
+		var selectedItems []onepassword.MigrationCandidate
 		for i, item := range m.items {
 			if m.selected[i] {
 				selectedItems = append(selectedItems, item)
 			}
 		}
 
-		// TODO: Implement actual migration using the client
-		// For now, simulate successful migration
-
-		// In real implementation:
-		// 1. For each selected item:
-		//    a. If incomplete, prompt for missing fields (inline form)
-		//    b. Add "ssherpa" tag to item
-		//    c. Normalize field labels (hostname, user, port, etc.)
-		//    d. Call client.UpdateItem()
-		// 2. Track results (success/failure per item)
-
-		_ = ctx // Suppress unused warning
-
-		results.Migrated = len(selectedItems)
-		results.Skipped = len(m.items) - len(selectedItems)
+		results := MigrationResults{
+			Skipped: len(m.items) - len(selectedItems),
+			Items:   onepassword.MigrateItems(ctx, m.client, selectedItems),
+		}
+		for _, r := range results.Items {
+			if r.Err != nil {
+				results.Errors = append(results.Errors, fmt.Sprintf("%s (%s): %v", r.Title, r.VaultName, r.Err))
+				continue
+			}
+			results.Migrated++
+		}
 
 		return migrationCompleteMsg{
 			results: results,
@@ -372,7 +392,7 @@ func (m MigrationWizard) migrateSelected() tea.Cmd {
 
 // scanCompleteMsg is sent when scanning completes.
 type scanCompleteMsg struct {
-	items []MigrationCandidate
+	items []onepassword.MigrationCandidate
 	err   error
 }
 
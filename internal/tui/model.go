@@ -23,6 +23,7 @@ import (
 	"github.com/florianriquelme/ssherpa/internal/ssh"
 	"github.com/florianriquelme/ssherpa/internal/sshconfig"
 	"github.com/florianriquelme/ssherpa/internal/sshkey"
+	"github.com/florianriquelme/ssherpa/internal/vpn"
 	"github.com/sahilm/fuzzy"
 )
 
@@ -35,6 +36,7 @@ const (
 	ViewAdd
 	ViewEdit
 	ViewDelete
+	ViewVPNWarning
 )
 
 // hostWithProject pairs a host with its project configurations
@@ -90,19 +92,29 @@ type Model struct {
 	appBackend  backend.Backend       // Backend interface (nil for sshconfig-only mode)
 
 	// Phase 7 additions:
-	discoveredKeys   []sshkey.SSHKey   // All discovered SSH keys (from file/agent/1Password)
-	keyPicker        *SSHKeyPicker     // SSH key picker overlay (nil when not showing)
-	showingKeyPicker bool              // Whether key picker is visible
-	hostSources      map[string]string // Maps host name to source (e.g., "ssh-config", "1password")
-	detailSource     string            // Source of the currently displayed detail host
+	discoveredKeys   []sshkey.SSHKey       // All discovered SSH keys (from file/agent/1Password)
+	keyPicker        *SSHKeyPicker         // SSH key picker overlay (nil when not showing)
+	showingKeyPicker bool                  // Whether key picker is visible
+	hostSources      map[string]string     // Maps host name to source (e.g., "ssh-config", "1password")
+	detailSource     string                // Source of the currently displayed detail host
+	detailResolution *sshconfig.Resolution // Effective options of the detail host (nil for Match blocks or unreadable config)
 
 	// Quick-1 additions:
 	showingHelp bool         // Whether help overlay is visible
 	helpOverlay *HelpOverlay // Help overlay (nil when not showing)
+
+	// Backend server metadata not carried by SSHHost:
+	hostServers    map[string]*domain.Server // Maps host name to backend server (remote path, etc.)
+	skipRemotePath bool                      // Skip cd into RemoteProjectPath for the next connection only
+
+	// VPN pre-connect checks:
+	vpnConfig   config.VPNConfig    // Global [vpn] section
+	hostConfigs []config.HostConfig // [[host]] metadata for SSH config aliases
+	vpnWarning  *VPNWarning         // Failed-check prompt (nil when not showing)
 }
 
 // New creates a new TUI model.
-func New(configPath, historyPath string, returnToTUI bool, currentProjectID string, projects []config.ProjectConfig, appConfigPath string, opStatus backend.BackendStatus, appBackend backend.Backend) Model {
+func New(configPath, historyPath string, returnToTUI bool, currentProjectID string, projects []config.ProjectConfig, hosts []config.HostConfig, vpnConfig config.VPNConfig, appConfigPath string, opStatus backend.BackendStatus, appBackend backend.Backend) Model {
 	// Initialize spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -161,6 +173,8 @@ func New(configPath, historyPath string, returnToTUI bool, currentProjectID stri
 		opStatus:         opStatus,   // Initial 1Password status
 		opStatusBar:      "",         // Will be rendered on first draw
 		appBackend:       appBackend, // Backend interface (may be nil)
+		hostConfigs:      hosts,
+		vpnConfig:        vpnConfig,
 	}
 }
 
@@ -169,7 +183,7 @@ func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
 		m.spinner.Tick,
 		loadHistoryCmd(m.historyPath),
-		discoverKeysCmd(nil), // Async SSH key discovery (re-runs after hosts load)
+		discoverKeysCmd(m.appBackend, nil), // Async SSH key discovery (re-runs after hosts load)
 	}
 
 	// Load from backend if available, otherwise fallback to SSH config
@@ -251,9 +265,10 @@ func loadHistoryCmd(historyPath string) tea.Cmd {
 }
 
 // discoverKeysCmd returns a command that discovers SSH keys asynchronously.
-// Accepts optional hosts to extract IdentityFile references.
+// Accepts optional hosts to extract IdentityFile references, and adds the
+// SSH Key items the backend lists as credentials (1Password).
 // Always parses ~/.ssh/config directly for IdentityAgent directives (e.g. 1Password agent).
-func discoverKeysCmd(hosts []sshconfig.SSHHost) tea.Cmd {
+func discoverKeysCmd(b backend.Backend, hosts []sshconfig.SSHHost) tea.Cmd {
 	return func() tea.Msg {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
@@ -303,6 +318,13 @@ func discoverKeysCmd(hosts []sshconfig.SSHHost) tea.Cmd {
 			return keysDiscoveredMsg{keys: nil, err: err}
 		}
 
+		if b != nil {
+			if creds, err := b.ListCredentials(context.Background()); err == nil {
+				vaultKeys := sshkey.KeysFromCredentials(creds, filepath.Join(sshDir, "ssherpa_keys"))
+				keys = sshkey.MergeVaultKeys(keys, vaultKeys)
+			}
+		}
+
 		return keysDiscoveredMsg{keys: keys, err: nil}
 	}
 }
@@ -344,11 +366,12 @@ func loadBackendServersCmd(backend backend.Backend) tea.Cmd {
 		}
 
 		// Convert domain.Server to SSHHost at TUI boundary
-		hosts, sources := serversToSSHHosts(servers)
+		hosts, sources, byName := serversToSSHHosts(servers)
 		return configLoadedMsg{
 			hosts:   hosts,
 			items:   nil,
 			sources: sources,
+			servers: byName,
 			err:     nil,
 		}
 	}
@@ -371,10 +394,12 @@ func syncBackendWithTimeoutCmd(b backend.Backend, timeout time.Duration) tea.Cmd
 
 // serversToSSHHosts converts domain.Server models to TUI-internal SSHHost representations.
 // This function defines the domain → TUI boundary, keeping TUI independent of domain models.
-// Returns hosts and a map of host name to source (e.g., "ssh-config", "1password").
-func serversToSSHHosts(servers []*domain.Server) ([]sshconfig.SSHHost, map[string]string) {
+// Returns hosts, a map of host name to source (e.g., "ssh-config", "1password"),
+// and a map of host name to the originating server for fields SSHHost cannot carry.
+func serversToSSHHosts(servers []*domain.Server) ([]sshconfig.SSHHost, map[string]string, map[string]*domain.Server) {
 	hosts := make([]sshconfig.SSHHost, 0, len(servers))
 	sources := make(map[string]string, len(servers))
+	byName := make(map[string]*domain.Server, len(servers))
 
 	for _, srv := range servers {
 		// Use DisplayName if available, otherwise fallback to Host
@@ -435,21 +460,10 @@ func serversToSSHHosts(servers []*domain.Server) ([]sshconfig.SSHHost, map[strin
 		if srv.Source != "" {
 			sources[name] = srv.Source
 		}
+		byName[name] = srv
 	}
 
-	return hosts, sources
-}
-
-// hostSource implements fuzzy.Source for SSHHost slices.
-type hostSource []sshconfig.SSHHost
-
-func (h hostSource) String(i int) string {
-	// Fuzzy match against Name, Hostname, and User
-	return h[i].Name + " " + h[i].Hostname + " " + h[i].User
-}
-
-func (h hostSource) Len() int {
-	return len(h)
+	return hosts, sources, byName
 }
 
 // filterHosts applies fuzzy search to hosts and updates filteredIdx.
@@ -462,24 +476,22 @@ func (m *Model) filterHosts() {
 			m.filteredIdx[i] = i
 		}
 	} else {
-		// Fuzzy search across Name + Hostname + User
-		source := hostSource(m.allHosts)
-		// Use FindFromNoSort to get unsorted matches (we'll sort by project)
-		matches := fuzzy.FindFromNoSort(query, source)
+		// "tag:<name>" words go through backend.Filterer; the rest is
+		// fuzzy-matched across name, hostname, user and tags
+		filter := backend.ParseQuery(query)
+		allowed := m.structuredMatches(backend.ServerFilter{Tags: filter.Tags})
+		matches := m.rankHosts(filter.Query, allowed)
 
 		if m.currentProjectID == "" || len(m.projects) == 0 {
-			// No project context - just sort by score
-			m.filteredIdx = make([]int, len(matches))
-			for i, match := range matches {
-				m.filteredIdx[i] = match.Index
-			}
+			// No project context - best match first
+			m.filteredIdx = matches
 		} else {
 			// Split matches into current project vs others
-			var currentMatches, otherMatches []fuzzy.Match
+			var currentMatches, otherMatches []int
 			hostProjectMap := m.buildHostProjectMap()
 
 			for _, match := range matches {
-				host := m.allHosts[match.Index]
+				host := m.allHosts[match]
 				projectConfigs := hostProjectMap[host.Name]
 
 				// Check if this host belongs to current project
@@ -500,16 +512,67 @@ func (m *Model) filterHosts() {
 
 			// Build filteredIdx: current project first, then others
 			m.filteredIdx = make([]int, 0, len(matches))
-			for _, match := range currentMatches {
-				m.filteredIdx = append(m.filteredIdx, match.Index)
+			m.filteredIdx = append(m.filteredIdx, currentMatches...)
+			m.filteredIdx = append(m.filteredIdx, otherMatches...)
+		}
+	}
+
+	m.rebuildListItems()
+}
+
+// structuredMatches reports, for each host in allHosts, whether it passes the
+// structured criteria of filter (tags). Backend servers are filtered by the
+// backend when it implements backend.Filterer; SSH config hosts are matched
+// against their [[host]] entry, which is always current in the model.
+func (m *Model) structuredMatches(filter backend.ServerFilter) []bool {
+	var filtered map[string]bool
+	if filterer, ok := m.appBackend.(backend.Filterer); ok && len(filter.Tags) > 0 {
+		if servers, err := filterer.FilterServers(context.Background(), filter); err == nil {
+			filtered = make(map[string]bool, len(servers))
+			for _, srv := range servers {
+				name := srv.DisplayName
+				if name == "" {
+					name = srv.Host
+				}
+				filtered[name] = true
 			}
-			for _, match := range otherMatches {
-				m.filteredIdx = append(m.filteredIdx, match.Index)
+		}
+	}
+
+	result := make([]bool, len(m.allHosts))
+	for i, host := range m.allHosts {
+		if srv := m.hostServers[host.Name]; filtered != nil && srv != nil && srv.Source != "ssh-config" {
+			result[i] = filtered[host.Name]
+			continue
+		}
+		result[i] = filter.Match(m.filterServer(host))
+	}
+	return result
+}
+
+// rankHosts returns the indices of allowed hosts that fuzzy-match query,
+// best match first. An empty query keeps config order.
+func (m *Model) rankHosts(query string, allowed []bool) []int {
+	var matches []int
+	if query == "" {
+		for i, ok := range allowed {
+			if ok {
+				matches = append(matches, i)
 			}
 		}
+		return matches
 	}
 
-	m.rebuildListItems()
+	source := make([]string, len(m.allHosts))
+	for i, host := range m.allHosts {
+		source[i] = backend.SearchText(m.filterServer(host))
+	}
+	for _, match := range fuzzy.Find(query, source) {
+		if allowed[match.Index] {
+			matches = append(matches, match.Index)
+		}
+	}
+	return matches
 }
 
 // buildHostProjectMap creates a map of host name -> []ProjectConfig
@@ -617,21 +680,27 @@ func (m *Model) rebuildListItems() {
 		return
 	}
 
-	// Organize hosts: recently used at top, rest alphabetically
-	var recentHosts []hostWithProject // Hosts with recent connections (top of list)
-	var otherHosts []hostWithProject  // All other hosts (sorted alphabetically)
+	// Organize hosts: favorites at top, then recently used, rest alphabetically
+	var favoriteHosts []hostWithProject // Pinned hosts (top of list)
+	var recentHosts []hostWithProject   // Hosts with recent connections
+	var otherHosts []hostWithProject    // All other hosts (sorted alphabetically)
 
 	for _, idx := range m.filteredIdx {
 		host := m.allHosts[idx]
 
-		// Wildcards always go to bottom
-		if host.IsWildcard {
+		// Wildcards and Match blocks always go to bottom
+		if host.IsWildcard || host.IsMatch {
 			continue
 		}
 
 		projectConfigs := hostProjectMap[host.Name]
 		hwp := hostWithProject{host: host, projects: projectConfigs}
 
+		if m.isFavorite(host.Name) {
+			favoriteHosts = append(favoriteHosts, hwp)
+			continue
+		}
+
 		// Check if this host has recent connection history
 		_, hasRecentConnection := m.recentHosts[host.Name]
 		if hasRecentConnection {
@@ -641,6 +710,9 @@ func (m *Model) rebuildListItems() {
 		}
 	}
 
+	// Sort favorites alphabetically by name
+	m.sortHostsWithProjectAlphabetically(favoriteHosts)
+
 	// Sort recently used hosts by most recent first
 	m.sortHostsWithProjectByRecency(recentHosts)
 
@@ -650,12 +722,17 @@ func (m *Model) rebuildListItems() {
 	// Build list items
 	items := make([]list.Item, 0, len(m.filteredIdx)+3)
 
-	// 1. Recently used hosts FIRST (sorted by most recent)
+	// 1. Favorites FIRST (sorted alphabetically)
+	for _, hwp := range favoriteHosts {
+		items = append(items, m.createHostItem(hwp.host, hwp.projects, hostProjectMap))
+	}
+
+	// 2. Recently used hosts (sorted by most recent)
 	for _, hwp := range recentHosts {
 		items = append(items, m.createHostItem(hwp.host, hwp.projects, hostProjectMap))
 	}
 
-	// 2. All other hosts (sorted alphabetically)
+	// 3. All other hosts (sorted alphabetically)
 	for _, hwp := range otherHosts {
 		items = append(items, m.createHostItem(hwp.host, hwp.projects, hostProjectMap))
 	}
@@ -666,12 +743,14 @@ func (m *Model) rebuildListItems() {
 
 // rebuildListItemsSimple handles list building when in search mode or no projects configured
 func (m *Model) rebuildListItemsSimple(hostProjectMap map[string][]config.ProjectConfig, hasSearch bool) {
-	// Organize filtered hosts into recent and non-recent
-	var recentHosts, otherHosts, wildcards []sshconfig.SSHHost
+	// Organize filtered hosts into favorites, recent and non-recent
+	var favoriteHosts, recentHosts, otherHosts, wildcards []sshconfig.SSHHost
 	for _, idx := range m.filteredIdx {
 		host := m.allHosts[idx]
-		if host.IsWildcard {
+		if host.IsWildcard || host.IsMatch {
 			wildcards = append(wildcards, host)
+		} else if m.isFavorite(host.Name) {
+			favoriteHosts = append(favoriteHosts, host)
 		} else {
 			// Check if this host has recent connection history
 			_, hasRecentConnection := m.recentHosts[host.Name]
@@ -683,6 +762,9 @@ func (m *Model) rebuildListItemsSimple(hostProjectMap map[string][]config.Projec
 		}
 	}
 
+	// Sort favorites alphabetically
+	m.sortHostsAlphabetically(favoriteHosts)
+
 	// Sort recent hosts by most recent first
 	m.sortHostsByRecency(recentHosts)
 
@@ -690,14 +772,19 @@ func (m *Model) rebuildListItemsSimple(hostProjectMap map[string][]config.Projec
 	m.sortHostsAlphabetically(otherHosts)
 
 	// Build list items
-	items := make([]list.Item, 0, len(recentHosts)+len(otherHosts)+len(wildcards))
+	items := make([]list.Item, 0, len(favoriteHosts)+len(recentHosts)+len(otherHosts)+len(wildcards))
 
-	// 1. Add recently used hosts first (sorted by most recent)
+	// 1. Add favorites first (sorted alphabetically)
+	for _, host := range favoriteHosts {
+		items = append(items, m.createHostItem(host, hostProjectMap[host.Name], hostProjectMap))
+	}
+
+	// 2. Add recently used hosts (sorted by most recent)
 	for _, host := range recentHosts {
 		items = append(items, m.createHostItem(host, hostProjectMap[host.Name], hostProjectMap))
 	}
 
-	// 2. Add all other hosts (sorted alphabetically)
+	// 3. Add all other hosts (sorted alphabetically)
 	for _, host := range otherHosts {
 		items = append(items, m.createHostItem(host, hostProjectMap[host.Name], hostProjectMap))
 	}
@@ -729,6 +816,8 @@ func (m *Model) createHostItem(host sshconfig.SSHHost, projectConfigs []config.P
 		host:            host,
 		lastConnectedAt: lastConnectedAt,
 		projectBadges:   badges,
+		favorite:        m.isFavorite(host.Name),
+		tags:            m.hostTags(host.Name),
 	}
 }
 
@@ -760,15 +849,34 @@ func (m *Model) preselectLastConnectedHost(items []list.Item) {
 	}
 }
 
+// selectHost moves the list cursor to the named host, if it is visible.
+// Used after reordering so the cursor follows the host the user acted on.
+func (m *Model) selectHost(name string) {
+	for i, item := range m.list.Items() {
+		if hi, ok := item.(hostItem); ok && hi.host.Name == name {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
 // connectToHost initiates SSH connection and records history.
-func (m Model) connectToHost(host sshconfig.SSHHost) tea.Cmd {
+// Lands in the server's RemoteProjectPath unless skipped for this connection.
+func (m *Model) connectToHost(host sshconfig.SSHHost) tea.Cmd {
 	// Record history BEFORE handoff (app may exit after SSH)
 	if m.historyPath != "" {
 		_ = history.RecordConnection(m.historyPath, host.Name, host.Hostname, host.User)
 		// Ignore error — don't block connection for history failure
 	}
 
-	return ssh.ConnectSSH(host.Name)
+	var opts ssh.Options
+	if srv := m.hostServers[host.Name]; srv != nil && !m.skipRemotePath {
+		opts.RemotePath = srv.RemoteProjectPath
+	}
+	// The toggle applies to a single connection only
+	m.skipRemotePath = false
+
+	return ssh.ConnectSSH(host.Name, opts)
 }
 
 // Update handles messages and updates the model.
@@ -796,8 +904,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update viewport dimensions if in detail mode
 		if m.viewMode == ViewDetail && m.detailHost != nil {
 			m.viewport = viewport.New(msg.Width, msg.Height)
-			content := renderDetailView(m.detailHost, m.detailSource, m.width, m.height)
-			m.viewport.SetContent(content)
+			m.viewport.SetContent(m.detailContent())
 		}
 
 		// Update help overlay viewport if showing
@@ -825,9 +932,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.sources != nil {
 				m.hostSources = msg.sources
 			}
+			if msg.servers != nil {
+				m.hostServers = msg.servers
+			}
 
 			// Re-discover keys now that hosts are loaded (includes IdentityFile references)
-			cmds = append(cmds, discoverKeysCmd(m.allHosts))
+			cmds = append(cmds, discoverKeysCmd(m.appBackend, m.allHosts))
 		}
 
 	case historyLoadedMsg:
@@ -946,7 +1056,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					if item, ok := selectedItem.(hostItem); ok {
-						return m, m.connectToHost(item.host)
+						cmd := m.requestConnect(item.host)
+						return m, cmd
 					}
 
 				// Arrow navigation in search mode (up/down only, no j/k)
@@ -962,9 +1073,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, nil
 					}
 					if item, ok := selectedItem.(hostItem); ok {
-						m.detailHost = &item.host
-						m.detailSource = m.hostSources[item.host.Name]
 						m.viewMode = ViewDetail
+						m.showDetail(item.host)
 					}
 
 				default:
@@ -1007,7 +1117,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					if item, ok := selectedItem.(hostItem); ok {
-						return m, m.connectToHost(item.host)
+						cmd := m.requestConnect(item.host)
+						return m, cmd
 					}
 
 				case key.Matches(msg, m.keys.Details):
@@ -1019,16 +1130,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 					if item, ok := selectedItem.(hostItem); ok {
 						m.viewMode = ViewDetail
-						m.detailHost = &item.host
+						m.showDetail(item.host)
+					}
 
-						// Look up source for this host
-						if m.hostSources != nil {
-							m.detailSource = m.hostSources[item.host.Name]
-						}
+				case key.Matches(msg, m.keys.ToggleRemotePath):
+					// 'c': skip (or restore) cd into the remote project path for the next connection
+					m.skipRemotePath = !m.skipRemotePath
 
-						m.viewport = viewport.New(m.width, m.height)
-						content := renderDetailView(m.detailHost, m.detailSource, m.width, m.height)
-						m.viewport.SetContent(content)
+				case key.Matches(msg, m.keys.ToggleFavorite):
+					// 'f': pin or unpin the selected server
+					selectedItem := m.list.SelectedItem()
+					if selectedItem == nil {
+						return m, nil
+					}
+
+					if item, ok := selectedItem.(hostItem); ok {
+						if m.rejectMatchBlock(item.host) {
+							return m, nil
+						}
+						cmd := m.toggleFavorite(item.host.Name)
+						m.selectHost(item.host.Name)
+						return m, cmd
 					}
 
 				case key.Matches(msg, m.keys.AssignProject):
@@ -1051,6 +1173,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if has1PasswordKeys(m.discoveredKeys) {
 						form.fields[4].input.Placeholder = "Default (1Password agent) - Press Enter to select key"
 					}
+					// Offer a vault picker when new servers can go to several vaults
+					if writer, ok := m.appBackend.(backend.Writer); ok {
+						if lister, ok := m.appBackend.(backend.VaultLister); ok {
+							if vaults := lister.WritableVaults(); len(vaults) > 1 {
+								form.SetVaults(writer, vaults, lister.DefaultVaultID(m.currentProjectID))
+							}
+						}
+					}
 					m.serverForm = &form
 					m.viewMode = ViewAdd
 
@@ -1062,7 +1192,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					if item, ok := selectedItem.(hostItem); ok {
-						form := NewEditServerForm(m.configPath, item.host)
+						if m.rejectMatchBlock(item.host) || m.rejectReadOnly(item.host) {
+							return m, nil
+						}
+						form := m.newEditForm(item.host)
 						m.serverForm = &form
 						m.viewMode = ViewEdit
 					}
@@ -1075,7 +1208,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					if item, ok := selectedItem.(hostItem); ok {
-						confirm := NewDeleteConfirm(item.host.Name, m.configPath)
+						if m.rejectMatchBlock(item.host) || m.rejectReadOnly(item.host) {
+							return m, nil
+						}
+						confirm := m.newDeleteConfirm(item.host)
 						m.deleteConfirm = &confirm
 						m.viewMode = ViewDelete
 					}
@@ -1204,6 +1340,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				*m.deleteConfirm, cmd = m.deleteConfirm.Update(msg)
 				cmds = append(cmds, cmd)
 			}
+
+		case ViewVPNWarning:
+			// Route all keys to the VPN warning
+			if m.vpnWarning != nil {
+				var cmd tea.Cmd
+				*m.vpnWarning, cmd = m.vpnWarning.Update(msg)
+				cmds = append(cmds, cmd)
+			}
 		}
 
 	case formCancelledMsg:
@@ -1212,16 +1356,93 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.serverForm = nil
 
 	case serverSavedMsg:
-		// Server saved successfully - reload config and return to list
+		// Server saved successfully - persist tags and VPN flag, reload config and return to list
 		m.viewMode = ViewList
 		m.serverForm = nil
+		if msg.backend && m.appBackend != nil {
+			m.statusMsg = fmt.Sprintf("Saved '%s' to 1Password", msg.alias)
+			return m, loadBackendServersCmd(m.appBackend)
+		}
+		m.saveHostFields(msg)
 		return m, loadConfigCmd(m.configPath)
 
+	case favoriteToggledMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Could not update favorite: %v", msg.err)
+		} else if msg.favorite {
+			m.statusMsg = fmt.Sprintf("Pinned '%s' to favorites", msg.alias)
+		} else {
+			m.statusMsg = fmt.Sprintf("Removed '%s' from favorites", msg.alias)
+		}
+		// Backend writes (and failed optimistic updates) need a fresh server list
+		if (msg.backend || msg.err != nil) && m.appBackend != nil {
+			return m, loadBackendServersCmd(m.appBackend)
+		}
+		return m, nil
+
+	case vpnCheckResultMsg:
+		m.statusMsg = ""
+		if msg.err == nil {
+			m.viewMode = ViewList
+			m.vpnWarning = nil
+			cmd := m.connectToHost(msg.host)
+			return m, cmd
+		}
+		srv := m.serverForHost(msg.host)
+		warning := NewVPNWarning(msg.host, msg.err, vpn.Settings(srv, m.hostSettings()).ConnectCommand)
+		m.vpnWarning = &warning
+		m.viewMode = ViewVPNWarning
+		return m, nil
+
+	case vpnConnectAnywayMsg:
+		m.viewMode = ViewList
+		m.vpnWarning = nil
+		cmd := m.connectToHost(msg.host)
+		return m, cmd
+
+	case vpnWarningCancelledMsg:
+		m.viewMode = ViewList
+		m.vpnWarning = nil
+		return m, nil
+
+	case vpnRunCommandMsg:
+		srv := m.serverForHost(msg.host)
+		return m, vpnUpCmd(msg.host, vpn.Settings(srv, m.hostSettings()))
+
+	case vpnCommandFinishedMsg:
+		if msg.err != nil {
+			// Keep the warning up with the command's failure as the reason
+			srv := m.serverForHost(msg.host)
+			warning := NewVPNWarning(msg.host, fmt.Errorf("VPN command failed: %w", msg.err), vpn.Settings(srv, m.hostSettings()).ConnectCommand)
+			m.vpnWarning = &warning
+			return m, nil
+		}
+		// Re-check; success connects, failure shows the warning again
+		srv := m.serverForHost(msg.host)
+		checker := vpn.CheckerFor(srv, m.hostSettings())
+		if checker == nil {
+			return m, func() tea.Msg { return vpnCheckResultMsg{host: msg.host} }
+		}
+		return m, vpnCheckCmd(msg.host, checker)
+
 	case serverDeletedMsg:
 		// Server deleted successfully - push to undo buffer and reload config
+		if msg.backend {
+			m.viewMode = ViewList
+			m.deleteConfirm = nil
+			m.statusMsg = fmt.Sprintf("Deleted '%s'", msg.alias)
+			if m.appBackend != nil {
+				return m, loadBackendServersCmd(m.appBackend)
+			}
+			return m, loadConfigCmd(m.configPath)
+		}
+		configPath := msg.sourceFile
+		if configPath == "" {
+			configPath = m.configPath
+		}
 		m.undoBuffer.Push(UndoEntry{
 			Alias:      msg.alias,
-			ConfigPath: m.configPath,
+			ConfigPath: configPath,
 			RawLines:   msg.removedLines,
 			DeletedAt:  time.Now(),
 		})
@@ -1283,7 +1504,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle based on current view mode
 		if m.viewMode == ViewDetail && m.detailHost != nil {
 			// Update SSH config for the host
-			return m, m.updateHostIdentityFile(m.detailHost.Name, msg.path, msg.cleared)
+			return m, m.updateHostIdentityFile(m.detailHost.Name, msg.key, msg.cleared)
 		} else if (m.viewMode == ViewAdd || m.viewMode == ViewEdit) && m.serverForm != nil {
 			// Update form's IdentityFile field
 			if msg.cleared {
@@ -1299,7 +1520,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case hostKeyUpdatedMsg:
 		// Update detail view with new host data
 		m.detailHost = &msg.host
-		m.viewport.SetContent(renderDetailView(&msg.host, m.detailSource, m.width, m.height))
+		m.detailResolution = m.resolveHost(msg.host)
+		m.viewport.SetContent(m.detailContent())
 
 		// Show status message
 		if msg.cleared {
@@ -1461,8 +1683,9 @@ func (m *Model) saveConfig() {
 		cfg.Backend = "sshconfig"
 	}
 
-	// Update projects in config
+	// Update projects and host metadata in config
 	cfg.Projects = m.projects
+	cfg.Hosts = m.hostConfigs
 
 	// Save back to disk (empty path triggers DefaultPath fallback)
 	_ = config.Save(cfg, m.configFilePath)
@@ -1470,7 +1693,7 @@ func (m *Model) saveConfig() {
 
 // updateHostIdentityFile updates the IdentityFile for a host in SSH config.
 // Returns a command that performs the update and refreshes the detail view.
-func (m *Model) updateHostIdentityFile(alias, keyPath string, cleared bool) tea.Cmd {
+func (m *Model) updateHostIdentityFile(alias string, selected *sshkey.SSHKey, cleared bool) tea.Cmd {
 	return func() tea.Msg {
 		// Build updated host entry
 		// First, parse the current host to get all fields
@@ -1479,13 +1702,21 @@ func (m *Model) updateHostIdentityFile(alias, keyPath string, cleared bool) tea.
 			return nil
 		}
 
-		entry := sshconfig.HostEntry{
-			Alias:        host.Name,
-			Hostname:     host.Hostname,
-			User:         host.User,
-			Port:         host.Port,
-			IdentityFile: keyPath,
-			ExtraConfig:  buildExtraConfigFromHost(*host),
+		keyPath := ""
+		if selected != nil {
+			keyPath = selected.Path
+		}
+
+		entry := sshconfig.EntryFromHost(*host)
+		entry.IdentityFile = keyPath
+
+		// 1Password SSH Key items are used through the agent via their public key
+		if selected != nil && selected.CredentialID != "" {
+			if err := sshkey.WritePublicKey(*selected); err != nil {
+				m.statusMsg = fmt.Sprintf("Failed to update key: %v", err)
+				return nil
+			}
+			entry.ExtraConfig = withIdentitiesOnly(entry.ExtraConfig)
 		}
 
 		// Update the host in SSH config
@@ -1514,28 +1745,6 @@ func (m *Model) updateHostIdentityFile(alias, keyPath string, cleared bool) tea.
 	}
 }
 
-// buildExtraConfigFromHost extracts non-standard SSH options from host.
-func buildExtraConfigFromHost(host sshconfig.SSHHost) string {
-	var lines []string
-	standardKeys := map[string]bool{
-		"HostName":     true,
-		"User":         true,
-		"Port":         true,
-		"IdentityFile": true,
-	}
-
-	for key, values := range host.AllOptions {
-		if standardKeys[key] {
-			continue
-		}
-		for _, val := range values {
-			lines = append(lines, fmt.Sprintf("%s %s", key, val))
-		}
-	}
-
-	return strings.Join(lines, "\n")
-}
-
 // View renders the current view.
 func (m Model) View() string {
 	if !m.ready {
@@ -1615,6 +1824,8 @@ Press 'q' to quit
 		var statusView string
 		if m.statusMsg != "" {
 			statusView = undoStatusStyle.Render(m.statusMsg)
+		} else if m.skipRemotePath {
+			statusView = undoStatusStyle.Render("Next connection skips the remote project path (press 'c' to restore)")
 		}
 
 		// Combine all parts (with optional status bar)
@@ -1728,6 +1939,18 @@ Press 'q' to quit
 
 		return baseView
 
+	case ViewVPNWarning:
+		if m.vpnWarning == nil {
+			return m.list.View()
+		}
+		return lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			m.vpnWarning.View(),
+		)
+
 	case ViewDelete:
 		if m.deleteConfirm == nil {
 			return m.list.View()
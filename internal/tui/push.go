@@ -0,0 +1,441 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/florianriquelme/ssherpa/internal/backend"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+	"github.com/florianriquelme/ssherpa/internal/sync"
+)
+
+// PushWizard is a Bubbletea model for pushing SSH config hosts to 1Password.
+type PushWizard struct {
+	target        backend.Backend      // 1Password backend the servers are created in
+	sshConfigPath string               // SSH config the hosts come from
+	items         []sync.PushCandidate // Hosts found in the SSH config
+	selected      map[int]bool         // Selection state for each host
+	cursor        int                  // Cursor position in the host list
+	vaults        []backend.Vault      // Writable vaults to choose from
+	vaultCursor   int                  // Cursor position in the vault list
+	commentOut    bool                 // Comment out pushed Host blocks
+	step          int                  // Current step: scanning, selecting, vault, pushing, done
+	spinner       spinner.Model        // Loading spinner
+	results       []sync.PushResult    // Outcome per pushed host
+	width         int
+	height        int
+	err           error // Error message if any
+}
+
+// Push steps
+const (
+	pushStepScanning = iota
+	pushStepSelecting
+	pushStepVault
+	pushStepPushing
+	pushStepDone
+)
+
+// NewPushWizard creates a push wizard for the hosts in sshConfigPath.
+// target is the 1Password backend; it must implement backend.Writer.
+func NewPushWizard(target backend.Backend, sshConfigPath string) PushWizard {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(accentColor)
+
+	return PushWizard{
+		target:        target,
+		sshConfigPath: sshConfigPath,
+		selected:      make(map[int]bool),
+		spinner:       s,
+		step:          pushStepScanning,
+	}
+}
+
+// Init starts scanning the SSH config.
+func (m PushWizard) Init() tea.Cmd {
+	return tea.Batch(
+		m.spinner.Tick,
+		m.scanHosts(),
+	)
+}
+
+// Update handles messages.
+func (m PushWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		// Quit at any step; hosts already pushed stay pushed
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case pushStepSelecting:
+			return m.updateSelecting(msg)
+		case pushStepVault:
+			return m.updateVault(msg)
+		case pushStepDone:
+			if msg.String() == "enter" {
+				return m, tea.Quit
+			}
+		}
+
+	case spinner.TickMsg:
+		if m.step == pushStepScanning || m.step == pushStepPushing {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+
+	case pushScanCompleteMsg:
+		m.items = msg.items
+		m.err = msg.err
+		if msg.err != nil || len(m.items) == 0 {
+			m.step = pushStepDone
+			return m, nil
+		}
+
+		m.step = pushStepSelecting
+		if lister, ok := m.target.(backend.VaultLister); ok {
+			m.vaults = lister.WritableVaults()
+			defaultID := lister.DefaultVaultID("")
+			for i, v := range m.vaults {
+				if v.ID == defaultID {
+					m.vaultCursor = i
+				}
+			}
+		}
+
+	case pushCompleteMsg:
+		m.results = msg.results
+		m.err = msg.err
+		m.step = pushStepDone
+	}
+
+	return m, nil
+}
+
+// updateSelecting handles input during host selection.
+func (m PushWizard) updateSelecting(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.cursor = (m.cursor + 1) % len(m.items)
+	case "k", "up":
+		m.cursor = (m.cursor - 1 + len(m.items)) % len(m.items)
+	case " ":
+		// Hosts that can't be pushed stay unselected
+		if m.items[m.cursor].Check() == nil {
+			m.selected[m.cursor] = !m.selected[m.cursor]
+		}
+	case "a":
+		for i, item := range m.items {
+			m.selected[i] = item.Check() == nil
+		}
+	case "n":
+		for i := range m.items {
+			m.selected[i] = false
+		}
+	case "c":
+		m.commentOut = !m.commentOut
+	case "enter":
+		if m.selectedCount() == 0 {
+			return m, nil
+		}
+		// Ask for the vault only when there is a choice
+		if len(m.vaults) > 1 {
+			m.step = pushStepVault
+			return m, nil
+		}
+		return m.startPush()
+	case "esc":
+		m.step = pushStepDone
+	}
+	return m, nil
+}
+
+// updateVault handles input during vault selection.
+func (m PushWizard) updateVault(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.vaultCursor = (m.vaultCursor + 1) % len(m.vaults)
+	case "k", "up":
+		m.vaultCursor = (m.vaultCursor - 1 + len(m.vaults)) % len(m.vaults)
+	case "enter":
+		return m.startPush()
+	case "esc":
+		m.step = pushStepSelecting
+	}
+	return m, nil
+}
+
+// startPush moves to the pushing step.
+func (m PushWizard) startPush() (tea.Model, tea.Cmd) {
+	m.step = pushStepPushing
+	return m, tea.Batch(m.spinner.Tick, m.pushSelected())
+}
+
+// selectedCount returns the number of selected hosts.
+func (m PushWizard) selectedCount() int {
+	count := 0
+	for _, selected := range m.selected {
+		if selected {
+			count++
+		}
+	}
+	return count
+}
+
+// vault returns the chosen vault ID ("" lets the backend pick its default).
+func (m PushWizard) vault() string {
+	if len(m.vaults) == 0 {
+		return ""
+	}
+	return m.vaults[m.vaultCursor].ID
+}
+
+// View renders the current step.
+func (m PushWizard) View() string {
+	switch m.step {
+	case pushStepScanning:
+		return m.renderScanning()
+	case pushStepSelecting:
+		return m.renderSelecting()
+	case pushStepVault:
+		return m.renderVault()
+	case pushStepPushing:
+		return m.renderPushing()
+	case pushStepDone:
+		return m.renderDone()
+	default:
+		return "Unknown step"
+	}
+}
+
+// renderScanning renders the scanning screen.
+func (m PushWizard) renderScanning() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Push to 1Password") + "\n\n")
+	fmt.Fprintf(&b, "  %s Reading %s...\n", m.spinner.View(), m.sshConfigPath)
+
+	return wizardBoxStyle.Render(b.String())
+}
+
+// renderSelecting renders the host selection screen.
+func (m PushWizard) renderSelecting() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Push to 1Password: %d hosts found", len(m.items))) + "\n\n")
+
+	for i, item := range m.items {
+		checkbox := "[ ]"
+		if m.selected[i] {
+			checkbox = "[x]"
+		}
+
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+
+		status := wizardSuccessStyle.Render("✓ Ready")
+		switch {
+		case item.Exists:
+			status = wizardDimStyle.Render("already in 1Password")
+		case item.Server.User == "":
+			status = wizardErrorStyle.Render("✗ Missing user")
+		}
+
+		line := fmt.Sprintf("%s%s %-25s %-35s %s",
+			cursor,
+			checkbox,
+			item.Alias,
+			item.Server.User+"@"+item.Server.Host,
+			status,
+		)
+		if i == m.cursor {
+			line = selectedStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.renderPreview(m.items[m.cursor]))
+
+	commentOut := "no"
+	if m.commentOut {
+		commentOut = "yes (a backup is written first)"
+	}
+	fmt.Fprintf(&b, "\n  Comment out pushed hosts: %s\n\n", commentOut)
+
+	b.WriteString(wizardDimStyle.Render("Space: toggle, a: select all, n: deselect all, c: toggle comment out\n"))
+	b.WriteString(wizardDimStyle.Render("Enter: push selected, Esc: cancel\n"))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%s/%d selected",
+		wizardSuccessStyle.Render(fmt.Sprintf("%d", m.selectedCount())),
+		len(m.items))
+
+	return wizardBoxStyle.Render(b.String())
+}
+
+// renderPreview shows the 1Password fields a host maps to.
+func (m PushWizard) renderPreview(item sync.PushCandidate) string {
+	var b strings.Builder
+	srv := item.Server
+
+	row := func(field, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "  %-13s %s\n", field, value)
+		}
+	}
+
+	fmt.Fprintf(&b, "%s\n", wizardDimStyle.Render(fmt.Sprintf("Fields for %q (%s):", item.Alias, item.SourceFile)))
+	row("hostname", srv.Host)
+	row("user", srv.User)
+	if srv.Port != 0 && srv.Port != 22 {
+		row("port", fmt.Sprintf("%d", srv.Port))
+	}
+	row("identity_file", srv.IdentityFile)
+	row("proxy_jump", srv.Proxy)
+	row("extra_config", strings.Join(srv.SSHOptionLines(), "; "))
+
+	return b.String()
+}
+
+// renderVault renders the vault selection screen.
+func (m PushWizard) renderVault() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Push %d hosts to which vault?", m.selectedCount())) + "\n\n")
+
+	for i, v := range m.vaults {
+		line := "  " + v.Name
+		if i == m.vaultCursor {
+			line = selectedStyle.Render("> " + v.Name)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(wizardDimStyle.Render("Enter: push, Esc: back\n"))
+
+	return wizardBoxStyle.Render(b.String())
+}
+
+// renderPushing renders the push progress screen.
+func (m PushWizard) renderPushing() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Pushing Hosts") + "\n\n")
+	fmt.Fprintf(&b, "  %s Pushing %d hosts to 1Password...\n", m.spinner.View(), m.selectedCount())
+
+	return wizardBoxStyle.Render(b.String())
+}
+
+// renderDone renders the results screen.
+func (m PushWizard) renderDone() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Push Complete") + "\n\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(wizardErrorStyle.Render(fmt.Sprintf("Error: %v\n", m.err)))
+	case len(m.items) == 0:
+		b.WriteString(wizardDimStyle.Render("No hosts found in your SSH config\n"))
+	case len(m.results) == 0:
+		b.WriteString(wizardDimStyle.Render("Nothing pushed\n"))
+	default:
+		var pushed, commented int
+		var failures []string
+		for _, r := range m.results {
+			if r.Pushed {
+				pushed++
+			}
+			if r.CommentedOut {
+				commented++
+			}
+			if r.Err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", r.Alias, r.Err))
+			}
+		}
+		fmt.Fprintf(&b, "  Pushed:        %s hosts\n", wizardSuccessStyle.Render(fmt.Sprintf("%d", pushed)))
+		if m.commentOut {
+			fmt.Fprintf(&b, "  Commented out: %d hosts\n", commented)
+		}
+		if len(failures) > 0 {
+			fmt.Fprintf(&b, "  Errors:        %s hosts\n", wizardErrorStyle.Render(fmt.Sprintf("%d", len(failures))))
+			b.WriteString("\n")
+			b.WriteString(wizardErrorStyle.Render("Errors:\n"))
+			for _, msg := range failures {
+				fmt.Fprintf(&b, "  - %s\n", msg)
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(wizardDimStyle.Render("Press Enter to continue"))
+
+	return wizardBoxStyle.Render(b.String())
+}
+
+// Results returns the outcome per pushed host once the wizard is done.
+func (m PushWizard) Results() []sync.PushResult {
+	return m.results
+}
+
+// scanHosts lists the SSH config hosts and marks those already in 1Password.
+func (m PushWizard) scanHosts() tea.Cmd {
+	return func() tea.Msg {
+		servers, err := m.target.ListServers(context.Background())
+		if err != nil {
+			return pushScanCompleteMsg{err: err}
+		}
+		items, err := sync.FindPushCandidates(servers, m.sshConfigPath)
+		return pushScanCompleteMsg{items: items, err: err}
+	}
+}
+
+// pushSelected creates the selected hosts in the chosen vault.
+func (m PushWizard) pushSelected() tea.Cmd {
+	return func() tea.Msg {
+		w, ok := m.target.(backend.Writer)
+		if !ok {
+			return pushCompleteMsg{err: errors.ErrReadOnlyBackend}
+		}
+
+		var selected []sync.PushCandidate
+		for i, item := range m.items {
+			if m.selected[i] {
+				selected = append(selected, item)
+			}
+		}
+
+		results := sync.PushHosts(context.Background(), w, selected, sync.PushOptions{
+			Vault:         m.vault(),
+			CommentOut:    m.commentOut,
+			SSHConfigPath: m.sshConfigPath,
+		})
+		return pushCompleteMsg{results: results}
+	}
+}
+
+// pushScanCompleteMsg is sent when the SSH config has been read.
+type pushScanCompleteMsg struct {
+	items []sync.PushCandidate
+	err   error
+}
+
+// pushCompleteMsg is sent when pushing completes.
+type pushCompleteMsg struct {
+	results []sync.PushResult
+	err     error
+}
@@ -45,6 +45,8 @@ func renderShortcutFooter(mode ViewMode, searchFocused bool, signInEnabled bool,
 		}
 		row2 := []shortcutHint{
 			{key: "p", desc: "project"},
+			{key: "f", desc: "favorite"},
+			{key: "c", desc: "toggle cd"},
 			{key: "?", desc: "1pass ref"},
 		}
 		if hasUndo {
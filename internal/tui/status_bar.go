@@ -24,6 +24,12 @@ func renderStatusBar(status backend.BackendStatus, width int) string {
 			Width(width).
 			Render("⚠️  1Password CLI not signed in. Press 's' to authenticate.")
 
+	case backend.StatusTokenError:
+		// Yellow warning bar: service account or Connect token rejected
+		return statusBarWarningStyle.
+			Width(width).
+			Render("⚠️  1Password token rejected (expired or no vault access). Using cached servers.")
+
 	case backend.StatusUnavailable:
 		// Orange warning bar: 1Password not available
 		return statusBarWarningStyle.
@@ -160,6 +160,13 @@ var (
 				Foreground(lipgloss.AdaptiveColor{Light: "#16A34A", Dark: "#4ADE80"}). // Green
 				BorderForeground(lipgloss.AdaptiveColor{Light: "#16A34A", Dark: "#4ADE80"})
 
+	tagBadgeStyle = lipgloss.NewStyle().
+			Foreground(secondaryColor).
+			Italic(true)
+
+	favoriteStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#D97706", Dark: "#FBBF24"}) // Amber
+
 	undoStatusStyle = lipgloss.NewStyle().
 			Foreground(accentColor).
 			Italic(true)
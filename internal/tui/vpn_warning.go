@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/sshconfig"
+	"github.com/florianriquelme/ssherpa/internal/vpn"
+)
+
+// VPNWarning is a full-screen prompt shown when a VPN-only server fails its
+// pre-connect check. The user can connect anyway, cancel, or run the
+// configured VPN command and re-check.
+type VPNWarning struct {
+	host           sshconfig.SSHHost // Host the user tried to connect to
+	err            error             // Why the check failed
+	connectCommand string            // Configured command to bring the VPN up (empty = option hidden)
+	checking       bool              // True while re-checking after the VPN command ran
+}
+
+// NewVPNWarning creates a warning for a failed VPN check.
+func NewVPNWarning(host sshconfig.SSHHost, err error, connectCommand string) VPNWarning {
+	return VPNWarning{
+		host:           host,
+		err:            err,
+		connectCommand: connectCommand,
+	}
+}
+
+// Update handles the warning's keys.
+func (w VPNWarning) Update(msg tea.Msg) (VPNWarning, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || w.checking {
+		return w, nil
+	}
+
+	host := w.host
+	switch keyMsg.String() {
+	case "enter", "y":
+		return w, func() tea.Msg { return vpnConnectAnywayMsg{host: host} }
+	case "esc", "n", "q":
+		return w, func() tea.Msg { return vpnWarningCancelledMsg{} }
+	case "r":
+		if w.connectCommand != "" {
+			w.checking = true
+			return w, func() tea.Msg { return vpnRunCommandMsg{host: host} }
+		}
+	}
+	return w, nil
+}
+
+// View renders the warning.
+func (w VPNWarning) View() string {
+	title := deleteWarningStyle.Render("VPN Not Connected")
+
+	intro := deleteInstructionStyle.Render("'" + w.host.Name + "' is only reachable over VPN, but the check failed:")
+	reason := lipgloss.NewStyle().Foreground(warningColor).Render(w.err.Error())
+
+	hints := []shortcutHint{
+		{key: "enter", desc: "connect anyway"},
+		{key: "esc", desc: "cancel"},
+	}
+	if w.connectCommand != "" {
+		hints = append(hints, shortcutHint{key: "r", desc: "run VPN command"})
+	}
+
+	parts := []string{"", title, "", intro, "", "  " + reason, ""}
+	if w.connectCommand != "" {
+		parts = append(parts, deleteInstructionStyle.Render("VPN command: "+w.connectCommand), "")
+	}
+	if w.checking {
+		parts = append(parts, formSavingStyle.Render("Checking VPN..."), "")
+	}
+	parts = append(parts, "", renderHintRow(hints))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.AdaptiveColor{Light: "#D97706", Dark: "#FBBF24"}). // Amber
+		Padding(2, 4).
+		Width(70).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+// vpnCheckCmd runs the VPN checker in the background.
+func vpnCheckCmd(host sshconfig.SSHHost, checker vpn.Checker) tea.Cmd {
+	return func() tea.Msg {
+		return vpnCheckResultMsg{host: host, err: checker.Check(context.Background())}
+	}
+}
+
+// vpnUpCmd hands the terminal to the configured VPN command (it may prompt for
+// a password) and reports back when it exits.
+func vpnUpCmd(host sshconfig.SSHHost, settings config.VPNConfig) tea.Cmd {
+	cmd := vpn.UpCommand(settings)
+	if cmd == nil {
+		return nil
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return vpnCommandFinishedMsg{host: host, err: err}
+	})
+}
+
+// requestConnect connects to host, first checking the VPN if the server requires it.
+func (m *Model) requestConnect(host sshconfig.SSHHost) tea.Cmd {
+	if !host.Connectable() {
+		if host.IsMatch {
+			m.statusMsg = "Match blocks apply to other hosts and can't be connected to"
+		} else {
+			m.statusMsg = "Fix the SSH config parse error before connecting"
+		}
+		return nil
+	}
+
+	srv := m.serverForHost(host)
+	policy := m.hostSettings()
+	if !vpn.Required(srv, policy) {
+		return m.connectToHost(host)
+	}
+
+	checker := vpn.CheckerFor(srv, policy)
+	if checker == nil {
+		return m.connectToHost(host)
+	}
+
+	m.statusMsg = "Checking VPN for '" + host.Name + "'..."
+	return vpnCheckCmd(host, checker)
+}
+
+// detailServer returns the server shown in the detail view, with VPNRequired
+// resolved from host and project settings as well as the server itself.
+func (m *Model) detailServer(host sshconfig.SSHHost) *domain.Server {
+	srv := *m.serverForHost(host)
+	srv.VPNRequired = vpn.Required(&srv, m.hostSettings())
+	return &srv
+}
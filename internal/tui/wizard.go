@@ -10,15 +10,18 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/florianriquelme/ssherpa/internal/backend/onepassword"
+	"github.com/florianriquelme/ssherpa/internal/backend/passstore"
 	"github.com/florianriquelme/ssherpa/internal/config"
 )
 
 // SetupWizard is a Bubbletea model for the first-launch setup flow.
 type SetupWizard struct {
 	step               int                    // Current step in the wizard
-	backendChoice      string                 // Selected backend: "sshconfig", "onepassword", "both"
+	backendChoice      string                 // Selected backend: "sshconfig", "onepassword", "both", "pass"
 	spinner            spinner.Model          // Loading spinner for async operations
 	checking           bool                   // Whether we're checking 1Password CLI
 	checkResult        onePasswordCheckResult // Result of 1Password CLI detection
@@ -33,6 +36,17 @@ type SetupWizard struct {
 	// Vault discovery fields
 	vaults       []vaultDiscovery // Available vaults from 1Password
 	totalServers int              // Total ssherpa-tagged servers across all vaults
+
+	// 1Password Connect fields
+	useConnect   bool            // Access 1Password through a Connect server instead of the op CLI
+	connectURL   textinput.Model // Connect server URL
+	connectToken textinput.Model // Connect access token
+
+	// Password store fields
+	passCommand textinput.Model // "pass" or "gopass"
+	passPrefix  textinput.Model // Store subtree holding servers
+	passEntries int             // Entries found under the prefix
+	passError   string          // Why the last store check failed
 }
 
 type vaultDiscovery struct {
@@ -51,9 +65,13 @@ type onePasswordCheckResult struct {
 // Wizard steps
 const (
 	stepWelcome = iota
+	stepOnePasswordClient
+	stepConnectSetup
 	stepCheckingOnePassword
 	stepOnePasswordSetup
 	stepMigrationOffer
+	stepPassSetup
+	stepCheckingPass
 	stepSummary
 )
 
@@ -64,11 +82,29 @@ func NewSetupWizard(configPath string) SetupWizard {
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(accentColor)
 
+	// Connect settings default to the environment used by the Connect SDKs
+	connectURL := textinput.New()
+	connectURL.Placeholder = "http://localhost:8080"
+	connectURL.SetValue(os.Getenv(onepassword.ConnectHostEnv))
+	connectToken := textinput.New()
+	connectToken.Placeholder = "Connect access token"
+	connectToken.EchoMode = textinput.EchoPassword
+	connectToken.SetValue(os.Getenv(onepassword.ConnectTokenEnv))
+
+	passCommand := textinput.New()
+	passCommand.Placeholder = "pass"
+	passPrefix := textinput.New()
+	passPrefix.Placeholder = passstore.DefaultPrefix
+
 	return SetupWizard{
-		step:       stepWelcome,
-		spinner:    s,
-		cursor:     0,
-		configPath: configPath,
+		step:         stepWelcome,
+		spinner:      s,
+		cursor:       0,
+		configPath:   configPath,
+		connectURL:   connectURL,
+		connectToken: connectToken,
+		passCommand:  passCommand,
+		passPrefix:   passPrefix,
 	}
 }
 
@@ -94,6 +130,12 @@ func (w SetupWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case stepWelcome:
 			return w.updateWelcome(msg)
 
+		case stepOnePasswordClient:
+			return w.updateOnePasswordClient(msg)
+
+		case stepConnectSetup:
+			return w.updateConnectSetup(msg)
+
 		case stepCheckingOnePassword:
 			// No input while checking
 			return w, nil
@@ -104,6 +146,13 @@ func (w SetupWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case stepMigrationOffer:
 			return w.updateMigrationOffer(msg)
 
+		case stepPassSetup:
+			return w.updatePassSetup(msg)
+
+		case stepCheckingPass:
+			// No input while checking
+			return w, nil
+
 		case stepSummary:
 			// Enter to save config and exit wizard
 			if msg.String() == "enter" {
@@ -126,6 +175,18 @@ func (w SetupWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		w.step = stepOnePasswordSetup
 		return w, nil
 
+	case passCheckCompleteMsg:
+		w.checking = false
+		if msg.error != "" {
+			w.passError = msg.error
+			w.step = stepPassSetup
+			return w, w.passPrefix.Focus()
+		}
+		w.passError = ""
+		w.passEntries = msg.entries
+		w.step = stepSummary
+		return w, nil
+
 	case configSavedMsg:
 		// Config saved successfully - quit wizard
 		return w, tea.Quit
@@ -143,9 +204,9 @@ func (w SetupWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (w SetupWizard) updateWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "j", "down":
-		w.cursor = (w.cursor + 1) % 3
+		w.cursor = (w.cursor + 1) % 4
 	case "k", "up":
-		w.cursor = (w.cursor - 1 + 3) % 3
+		w.cursor = (w.cursor - 1 + 4) % 4
 	case "enter":
 		switch w.cursor {
 		case 0:
@@ -153,10 +214,17 @@ func (w SetupWizard) updateWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			w.step = stepSummary
 		case 1:
 			w.backendChoice = "onepassword"
-			return w.transitionToOpCheck()
+			w.step = stepOnePasswordClient
+			w.cursor = 0
 		case 2:
 			w.backendChoice = "both"
-			return w.transitionToOpCheck()
+			w.step = stepOnePasswordClient
+			w.cursor = 0
+		case 3:
+			w.backendChoice = "pass"
+			w.step = stepPassSetup
+			w.passCommand.Blur()
+			return w, w.passPrefix.Focus()
 		}
 	case "q":
 		return w, tea.Quit
@@ -164,10 +232,96 @@ func (w SetupWizard) updateWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return w, nil
 }
 
-// transitionToOpCheck starts the 1Password CLI check flow.
+// updateOnePasswordClient handles the choice between the op CLI and a Connect server.
+func (w SetupWizard) updateOnePasswordClient(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		w.cursor = (w.cursor + 1) % 2
+	case "k", "up":
+		w.cursor = (w.cursor - 1 + 2) % 2
+	case "enter":
+		w.useConnect = w.cursor == 1
+		if w.useConnect {
+			w.step = stepConnectSetup
+			w.connectToken.Blur()
+			return w, w.connectURL.Focus()
+		}
+		return w.transitionToOpCheck()
+	case "esc":
+		w.step = stepWelcome
+		w.cursor = 0
+	}
+	return w, nil
+}
+
+// updateConnectSetup handles input for the Connect URL and token fields.
+func (w SetupWizard) updateConnectSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		w.step = stepOnePasswordClient
+		w.cursor = 1
+		return w, nil
+	case "tab", "shift+tab", "up", "down":
+		if w.connectURL.Focused() {
+			w.connectURL.Blur()
+			return w, w.connectToken.Focus()
+		}
+		w.connectToken.Blur()
+		return w, w.connectURL.Focus()
+	case "enter":
+		if w.connectURL.Focused() {
+			w.connectURL.Blur()
+			return w, w.connectToken.Focus()
+		}
+		return w.transitionToOpCheck()
+	}
+
+	var cmd tea.Cmd
+	if w.connectURL.Focused() {
+		w.connectURL, cmd = w.connectURL.Update(msg)
+	} else {
+		w.connectToken, cmd = w.connectToken.Update(msg)
+	}
+	return w, cmd
+}
+
+// updatePassSetup handles input for the password store command and prefix fields.
+func (w SetupWizard) updatePassSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		w.step = stepWelcome
+		w.cursor = 3
+		w.passError = ""
+		return w, nil
+	case "tab", "shift+tab", "up", "down":
+		if w.passPrefix.Focused() {
+			w.passPrefix.Blur()
+			return w, w.passCommand.Focus()
+		}
+		w.passCommand.Blur()
+		return w, w.passPrefix.Focus()
+	case "enter":
+		w.step = stepCheckingPass
+		w.checking = true
+		return w, tea.Batch(w.spinner.Tick, checkPass(w.passCommand.Value(), w.passPrefix.Value()))
+	}
+
+	var cmd tea.Cmd
+	if w.passPrefix.Focused() {
+		w.passPrefix, cmd = w.passPrefix.Update(msg)
+	} else {
+		w.passCommand, cmd = w.passCommand.Update(msg)
+	}
+	return w, cmd
+}
+
+// transitionToOpCheck starts the 1Password check flow (op CLI or Connect server).
 func (w SetupWizard) transitionToOpCheck() (tea.Model, tea.Cmd) {
 	w.step = stepCheckingOnePassword
 	w.checking = true
+	if w.useConnect {
+		return w, tea.Batch(w.spinner.Tick, checkConnect(w.connectURL.Value(), w.connectToken.Value()))
+	}
 	return w, tea.Batch(w.spinner.Tick, checkOpCLI())
 }
 
@@ -184,6 +338,9 @@ func (w SetupWizard) updateOnePasswordSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return w, nil
 	case "esc":
 		w.step = stepWelcome
+		if w.useConnect {
+			w.step = stepConnectSetup
+		}
 		w.checkResult = onePasswordCheckResult{}
 		return w, nil
 	}
@@ -212,12 +369,20 @@ func (w SetupWizard) View() string {
 	switch w.step {
 	case stepWelcome:
 		return w.renderWelcome()
+	case stepOnePasswordClient:
+		return w.renderOnePasswordClient()
+	case stepConnectSetup:
+		return w.renderConnectSetup()
 	case stepCheckingOnePassword:
 		return w.renderCheckingOnePassword()
 	case stepOnePasswordSetup:
 		return w.renderOnePasswordSetup()
 	case stepMigrationOffer:
 		return w.renderMigrationOffer()
+	case stepPassSetup:
+		return w.renderPassSetup()
+	case stepCheckingPass:
+		return w.renderCheckingPass()
 	case stepSummary:
 		return w.renderSummary()
 	default:
@@ -238,6 +403,7 @@ func (w SetupWizard) renderWelcome() string {
 		"SSH Config only      Uses ~/.ssh/config (already working)",
 		"1Password            Store servers in 1Password for team sharing",
 		"Both                 SSH Config + 1Password (recommended for teams)",
+		"pass                 Servers as entries in your password store (pass/gopass)",
 	}
 
 	for i, opt := range options {
@@ -255,6 +421,54 @@ func (w SetupWizard) renderWelcome() string {
 	return wizardBoxStyle.Render(b.String())
 }
 
+// renderOnePasswordClient renders the choice between the op CLI and a Connect server.
+func (w SetupWizard) renderOnePasswordClient() string {
+	var b strings.Builder
+
+	title := titleStyle.Render("1Password Setup")
+	b.WriteString(title + "\n\n")
+
+	b.WriteString("How should ssherpa reach 1Password?\n\n")
+
+	options := []string{
+		"1Password CLI        op with the desktop app (workstations)",
+		"1Password Connect    A Connect server (CI, headless jump boxes)",
+	}
+
+	for i, opt := range options {
+		cursor := "  "
+		if i == w.cursor {
+			cursor = "> "
+			opt = selectedStyle.Render(opt)
+		}
+		b.WriteString(cursor + opt + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(wizardDimStyle.Render("Use j/k to navigate, Enter to select, Esc to go back"))
+
+	return wizardBoxStyle.Render(b.String())
+}
+
+// renderConnectSetup renders the Connect URL and token form.
+func (w SetupWizard) renderConnectSetup() string {
+	var b strings.Builder
+
+	title := titleStyle.Render("1Password Connect")
+	b.WriteString(title + "\n\n")
+
+	b.WriteString("  Server URL\n")
+	b.WriteString("  " + w.connectURL.View() + "\n\n")
+	b.WriteString("  Access token\n")
+	b.WriteString("  " + w.connectToken.View() + "\n\n")
+
+	fmt.Fprintf(&b, "  %s\n", wizardDimStyle.Render(fmt.Sprintf("Leave empty to use %s and %s at runtime.", onepassword.ConnectHostEnv, onepassword.ConnectTokenEnv)))
+	b.WriteString("\n")
+	b.WriteString(wizardDimStyle.Render("Tab to switch fields, Enter to connect, Esc to go back"))
+
+	return wizardBoxStyle.Render(b.String())
+}
+
 // renderCheckingOnePassword renders the 1Password CLI detection screen.
 func (w SetupWizard) renderCheckingOnePassword() string {
 	var b strings.Builder
@@ -262,7 +476,11 @@ func (w SetupWizard) renderCheckingOnePassword() string {
 	title := titleStyle.Render("1Password Setup")
 	b.WriteString(title + "\n\n")
 
-	fmt.Fprintf(&b, "  %s Checking 1Password CLI...\n", w.spinner.View())
+	if w.useConnect {
+		fmt.Fprintf(&b, "  %s Connecting to 1Password Connect...\n", w.spinner.View())
+	} else {
+		fmt.Fprintf(&b, "  %s Checking 1Password CLI...\n", w.spinner.View())
+	}
 
 	return wizardBoxStyle.Render(b.String())
 }
@@ -275,7 +493,11 @@ func (w SetupWizard) renderOnePasswordSetup() string {
 	b.WriteString(title + "\n\n")
 
 	if w.checkResult.available {
-		b.WriteString(wizardSuccessStyle.Render("  1Password CLI is ready") + "\n")
+		if w.useConnect {
+			b.WriteString(wizardSuccessStyle.Render("  1Password Connect is ready") + "\n")
+		} else {
+			b.WriteString(wizardSuccessStyle.Render("  1Password CLI is ready") + "\n")
+		}
 		fmt.Fprintf(&b, "  Found %d vault(s)\n\n", len(w.vaults))
 
 		if w.totalServers > 0 {
@@ -313,6 +535,14 @@ func (w SetupWizard) renderOnePasswordSetup() string {
 		b.WriteString(templateStyle.Render(template) + "\n\n")
 
 		b.WriteString("  Press Enter to continue")
+	} else if w.useConnect {
+		b.WriteString(wizardErrorStyle.Render("  Could not connect to 1Password Connect") + "\n\n")
+		if w.checkResult.error != "" {
+			b.WriteString(wizardDimStyle.Render("  "+w.checkResult.error) + "\n\n")
+		}
+		b.WriteString("  Check that the Connect server is reachable from this machine\n")
+		b.WriteString("  and that the token has read access to your vaults.\n\n")
+		b.WriteString("  Press Enter to use SSH Config only, or Esc to edit the settings")
 	} else {
 		b.WriteString(wizardErrorStyle.Render("  Could not connect to 1Password CLI") + "\n\n")
 		if w.checkResult.error != "" {
@@ -330,6 +560,53 @@ func (w SetupWizard) renderOnePasswordSetup() string {
 	return wizardBoxStyle.Render(b.String())
 }
 
+// renderPassSetup renders the password store form.
+func (w SetupWizard) renderPassSetup() string {
+	var b strings.Builder
+
+	title := titleStyle.Render("Password Store Setup")
+	b.WriteString(title + "\n\n")
+
+	b.WriteString("  Servers folder\n")
+	b.WriteString("  " + w.passPrefix.View() + "\n\n")
+	b.WriteString("  Command\n")
+	b.WriteString("  " + w.passCommand.View() + "\n\n")
+
+	if w.passError != "" {
+		b.WriteString(wizardErrorStyle.Render("  "+w.passError) + "\n\n")
+	}
+
+	b.WriteString("  Each entry under the folder is a server, e.g. ssh/<project>/<host>:\n\n")
+
+	templateStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#555555", Dark: "#aaaaaa"}).
+		PaddingLeft(4)
+
+	template := "" +
+		"<password, may be empty>\n" +
+		"hostname: dev.example.com         (required)\n" +
+		"user: ubuntu                      (required)\n" +
+		"port: 22                          (optional)\n" +
+		"identity_file: ~/.ssh/id_ed25519  (optional)"
+
+	b.WriteString(templateStyle.Render(template) + "\n\n")
+	b.WriteString(wizardDimStyle.Render("Tab to switch fields, Enter to continue, Esc to go back"))
+
+	return wizardBoxStyle.Render(b.String())
+}
+
+// renderCheckingPass renders the password store detection screen.
+func (w SetupWizard) renderCheckingPass() string {
+	var b strings.Builder
+
+	title := titleStyle.Render("Password Store Setup")
+	b.WriteString(title + "\n\n")
+
+	fmt.Fprintf(&b, "  %s Reading password store...\n", w.spinner.View())
+
+	return wizardBoxStyle.Render(b.String())
+}
+
 // renderMigrationOffer renders the migration offer screen.
 func (w SetupWizard) renderMigrationOffer() string {
 	var b strings.Builder
@@ -376,6 +653,11 @@ func (w SetupWizard) renderSummary() string {
 		backendName = "1Password"
 	case "both":
 		backendName = "SSH Config + 1Password"
+	case "pass":
+		backendName = "Password store (" + w.passCommandName() + ")"
+	}
+	if w.useConnect && (w.backendChoice == "onepassword" || w.backendChoice == "both") {
+		backendName += " (Connect)"
 	}
 	fmt.Fprintf(&b, "  Backend: %s\n", wizardSuccessStyle.Render(backendName))
 
@@ -389,6 +671,10 @@ func (w SetupWizard) renderSummary() string {
 		}
 	}
 
+	if w.backendChoice == "pass" {
+		fmt.Fprintf(&b, "  Servers: %d entries under %s/\n", w.passEntries, w.passPrefixName())
+	}
+
 	b.WriteString("\n")
 	configPath := w.configPath
 	if configPath == "" {
@@ -477,6 +763,71 @@ func checkOpCLI() tea.Cmd {
 	}
 }
 
+// checkConnect verifies that a 1Password Connect server is reachable with the
+// given URL and token (empty values fall back to the environment) and counts
+// the ssherpa servers in each vault.
+func checkConnect(url, token string) tea.Cmd {
+	return func() tea.Msg {
+		client, err := onepassword.NewConnectClient(strings.TrimSpace(url), strings.TrimSpace(token))
+		if err != nil {
+			return onePasswordCheckCompleteMsg{available: false, error: err.Error()}
+		}
+		defer func() { _ = client.Close() }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		opVaults, err := client.ListVaults(ctx)
+		if err != nil {
+			return onePasswordCheckCompleteMsg{available: false, error: err.Error()}
+		}
+
+		vaults := make([]vaultDiscovery, 0, len(opVaults))
+		totalServers := 0
+		for _, v := range opVaults {
+			count := 0
+			if items, err := client.ListItems(ctx, v.ID); err == nil {
+				for _, item := range items {
+					if onepassword.HasSshjesusTag(item.Tags) {
+						count++
+					}
+				}
+			}
+			vaults = append(vaults, vaultDiscovery{ID: v.ID, Name: v.Name, ServerCount: count})
+			totalServers += count
+		}
+
+		return onePasswordCheckCompleteMsg{
+			available:    true,
+			vaults:       vaults,
+			totalServers: totalServers,
+		}
+	}
+}
+
+// checkPass verifies that the pass/gopass CLI is installed and counts the
+// entries under prefix.
+func checkPass(command, prefix string) tea.Cmd {
+	return func() tea.Msg {
+		client, err := passstore.NewCLIClient(strings.TrimSpace(command), "")
+		if err != nil {
+			return passCheckCompleteMsg{error: err.Error()}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if strings.TrimSpace(prefix) == "" {
+			prefix = passstore.DefaultPrefix
+		}
+		names, err := client.List(ctx, strings.TrimSpace(prefix))
+		if err != nil {
+			return passCheckCompleteMsg{error: err.Error()}
+		}
+		return passCheckCompleteMsg{entries: len(names)}
+	}
+}
+
 // countSsherpaItems counts items tagged "ssherpa" in a specific vault.
 func countSsherpaItems(ctx context.Context, opPath, vaultID string) int {
 	cmd := exec.CommandContext(ctx, opPath, "item", "list",
@@ -507,6 +858,12 @@ type onePasswordCheckCompleteMsg struct {
 	error        string
 }
 
+// passCheckCompleteMsg is sent when the password store check completes.
+type passCheckCompleteMsg struct {
+	entries int
+	error   string
+}
+
 // configSavedMsg is sent when config is saved successfully.
 type configSavedMsg struct{}
 
@@ -523,6 +880,16 @@ func (w SetupWizard) saveConfig() tea.Cmd {
 
 		if w.backendChoice == "onepassword" || w.backendChoice == "both" {
 			cfg.MigrationDone = w.runMigration
+			if w.useConnect {
+				cfg.OnePassword.Client = "connect"
+				cfg.OnePassword.ConnectURL = configuredUnlessEnv(w.connectURL.Value(), onepassword.ConnectHostEnv)
+				cfg.OnePassword.ConnectToken = configuredUnlessEnv(w.connectToken.Value(), onepassword.ConnectTokenEnv)
+			}
+		}
+
+		if w.backendChoice == "pass" {
+			cfg.Pass.Command = strings.TrimSpace(w.passCommand.Value())
+			cfg.Pass.Prefix = strings.TrimSpace(w.passPrefix.Value())
 		}
 
 		err := config.Save(cfg, w.configPath)
@@ -533,3 +900,30 @@ func (w SetupWizard) saveConfig() tea.Cmd {
 		return configSavedMsg{}
 	}
 }
+
+// passCommandName returns the entered password store command, or the default.
+func (w SetupWizard) passCommandName() string {
+	if command := strings.TrimSpace(w.passCommand.Value()); command != "" {
+		return command
+	}
+	return "pass"
+}
+
+// passPrefixName returns the entered servers folder, or the default.
+func (w SetupWizard) passPrefixName() string {
+	if prefix := strings.Trim(strings.TrimSpace(w.passPrefix.Value()), "/"); prefix != "" {
+		return prefix
+	}
+	return passstore.DefaultPrefix
+}
+
+// configuredUnlessEnv returns value, or "" when it only repeats the
+// environment variable, so secrets from the environment stay out of the
+// config file.
+func configuredUnlessEnv(value, env string) string {
+	value = strings.TrimSpace(value)
+	if value == os.Getenv(env) {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,156 @@
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// All runs every checker in order and returns the first failure.
+type All []Checker
+
+// Check implements Checker.
+func (a All) Check(ctx context.Context) error {
+	for _, c := range a {
+		if err := c.Check(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TCPChecker succeeds when a TCP connection to Address (host:port) can be opened.
+// Point it at a host that is only reachable through the VPN.
+type TCPChecker struct {
+	Address string
+	Timeout time.Duration
+
+	// Dial opens the connection (defaults to net.Dialer.DialContext). Injectable for tests.
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Check implements Checker.
+func (c *TCPChecker) Check(ctx context.Context) error {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dial := c.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	conn, err := dial(ctx, "tcp", c.Address)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", c.Address, err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// InterfaceChecker succeeds when the named network interface exists and is up
+// (e.g. "wg0" for WireGuard, "utun4" for macOS VPN clients).
+type InterfaceChecker struct {
+	Name string
+
+	// Interfaces lists the host's interfaces (defaults to net.Interfaces). Injectable for tests.
+	Interfaces func() ([]net.Interface, error)
+}
+
+// Check implements Checker.
+func (c *InterfaceChecker) Check(ctx context.Context) error {
+	list := c.Interfaces
+	if list == nil {
+		list = net.Interfaces
+	}
+
+	ifaces, err := list()
+	if err != nil {
+		return fmt.Errorf("listing network interfaces: %w", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Name != c.Name {
+			continue
+		}
+		if iface.Flags&net.FlagUp == 0 {
+			return fmt.Errorf("interface %s is down", c.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("interface %s not found", c.Name)
+}
+
+// RouteChecker succeeds when Target (an IP or CIDR) lies inside the network of
+// one of the host's non-loopback interface addresses, i.e. the VPN has handed
+// out an address on that network.
+type RouteChecker struct {
+	Target string
+
+	// Addrs lists the host's interface addresses (defaults to net.InterfaceAddrs). Injectable for tests.
+	Addrs func() ([]net.Addr, error)
+}
+
+// Check implements Checker.
+func (c *RouteChecker) Check(ctx context.Context) error {
+	target := net.ParseIP(c.Target)
+	if target == nil {
+		ip, _, err := net.ParseCIDR(c.Target)
+		if err != nil {
+			return fmt.Errorf("invalid route %q: expected an IP or CIDR", c.Target)
+		}
+		target = ip
+	}
+
+	list := c.Addrs
+	if list == nil {
+		list = net.InterfaceAddrs
+	}
+
+	addrs, err := list()
+	if err != nil {
+		return fmt.Errorf("listing interface addresses: %w", err)
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ipNet.Contains(target) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no local network routes to %s", c.Target)
+}
+
+// CommandChecker succeeds when Command, run through sh, exits 0.
+// Useful for VPN clients with a status command (e.g. "tailscale status").
+type CommandChecker struct {
+	Command string
+	Timeout time.Duration
+}
+
+// Check implements Checker.
+func (c *CommandChecker) Check(ctx context.Context) error {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "sh", "-c", c.Command).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg != "" {
+			return fmt.Errorf("check command %q failed: %w: %s", c.Command, err, msg)
+		}
+		return fmt.Errorf("check command %q failed: %w", c.Command, err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package vpn verifies VPN connectivity before connecting to servers that require it.
+//
+// A server requires the VPN when its VPNRequired flag is set (1Password field,
+// TUI form), when its [[host]] entry in the ssherpa config says so, or when it
+// belongs to a project with vpn_required = true. Which checks run comes from the
+// project's [project.vpn] section, falling back to the global [vpn] section.
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	"github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+// DefaultTimeout bounds each check when the config doesn't set timeout_seconds.
+const DefaultTimeout = 3 * time.Second
+
+// Checker verifies that the VPN is up. Check returns nil when it is.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Required reports whether connecting to srv needs the VPN.
+// cfg may be nil, in which case only the server's own flag counts.
+func Required(srv *domain.Server, cfg *config.Config) bool {
+	if srv.VPNRequired {
+		return true
+	}
+	if cfg == nil {
+		return false
+	}
+	if h := cfg.Host(srv.DisplayName); h != nil && h.VPNRequired {
+		return true
+	}
+	for _, p := range projectsFor(srv, cfg.Projects) {
+		if p.VPNRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// Settings returns the VPN settings that apply to srv: the first project of the
+// server that overrides [vpn], otherwise the global section.
+func Settings(srv *domain.Server, cfg *config.Config) config.VPNConfig {
+	if cfg == nil {
+		return config.VPNConfig{}
+	}
+	for _, p := range projectsFor(srv, cfg.Projects) {
+		if p.VPN != nil {
+			return *p.VPN
+		}
+	}
+	return cfg.VPN
+}
+
+// projectsFor returns the projects srv belongs to, either by project ID
+// (1Password servers) or by alias (SSH config hosts listed in server_names).
+func projectsFor(srv *domain.Server, projects []config.ProjectConfig) []config.ProjectConfig {
+	var matched []config.ProjectConfig
+	for _, p := range projects {
+		if slices.Contains(srv.ProjectIDs, p.ID) || slices.Contains(p.ServerNames, srv.DisplayName) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// FromConfig builds a checker running every check configured in s.
+// Returns nil when s configures no checks.
+func FromConfig(s config.VPNConfig) Checker {
+	timeout := DefaultTimeout
+	if s.TimeoutSeconds > 0 {
+		timeout = time.Duration(s.TimeoutSeconds) * time.Second
+	}
+
+	var checks All
+	if s.Interface != "" {
+		checks = append(checks, &InterfaceChecker{Name: s.Interface})
+	}
+	if s.Route != "" {
+		checks = append(checks, &RouteChecker{Target: s.Route})
+	}
+	if s.Canary != "" {
+		checks = append(checks, &TCPChecker{Address: s.Canary, Timeout: timeout})
+	}
+	if s.CheckCommand != "" {
+		checks = append(checks, &CommandChecker{Command: s.CheckCommand, Timeout: timeout})
+	}
+
+	if len(checks) == 0 {
+		return nil
+	}
+	return checks
+}
+
+// CheckerFor returns the checker for srv.
+// Without configured checks it dials the server itself, which only works for
+// direct connections; servers behind a ProxyJump are not checked (nil).
+func CheckerFor(srv *domain.Server, cfg *config.Config) Checker {
+	s := Settings(srv, cfg)
+	if c := FromConfig(s); c != nil {
+		return c
+	}
+
+	if srv.Proxy != "" || srv.Host == "" {
+		return nil
+	}
+	port := srv.Port
+	if port == 0 {
+		port = 22
+	}
+	timeout := DefaultTimeout
+	if s.TimeoutSeconds > 0 {
+		timeout = time.Duration(s.TimeoutSeconds) * time.Second
+	}
+	return &TCPChecker{Address: net.JoinHostPort(srv.Host, strconv.Itoa(port)), Timeout: timeout}
+}
+
+// Check verifies the VPN for srv if it requires one.
+// Returns nil for servers that don't need the VPN, and an error wrapping
+// errors.ErrVPNNotConnected when a check fails.
+func Check(ctx context.Context, srv *domain.Server, cfg *config.Config) error {
+	if !Required(srv, cfg) {
+		return nil
+	}
+	checker := CheckerFor(srv, cfg)
+	if checker == nil {
+		return nil
+	}
+	if err := checker.Check(ctx); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrVPNNotConnected, err)
+	}
+	return nil
+}
+
+// UpCommand returns the configured command that brings the VPN up, run through
+// the shell. Returns nil if connect_command is not set.
+func UpCommand(s config.VPNConfig) *exec.Cmd {
+	if strings.TrimSpace(s.ConnectCommand) == "" {
+		return nil
+	}
+	return exec.Command("sh", "-c", s.ConnectCommand)
+}
@@ -0,0 +1,221 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/florianriquelme/ssherpa/internal/config"
+	"github.com/florianriquelme/ssherpa/internal/domain"
+	sherrors "github.com/florianriquelme/ssherpa/internal/errors"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		VPN: config.VPNConfig{Canary: "10.0.0.1:443"},
+		Projects: []config.ProjectConfig{
+			{ID: "payments", VPNRequired: true, VPN: &config.VPNConfig{Interface: "wg0"}},
+			{ID: "infra", ServerNames: []string{"bastion"}, VPNRequired: true},
+			{ID: "web"},
+		},
+		Hosts: []config.HostConfig{{Alias: "db-prod", VPNRequired: true}},
+	}
+}
+
+func TestRequired(t *testing.T) {
+	cfg := testConfig()
+
+	tests := []struct {
+		name string
+		srv  *domain.Server
+		cfg  *config.Config
+		want bool
+	}{
+		{"server flag", &domain.Server{DisplayName: "x", VPNRequired: true}, nil, true},
+		{"no config", &domain.Server{DisplayName: "db-prod"}, nil, false},
+		{"host entry", &domain.Server{DisplayName: "DB-PROD"}, cfg, true},
+		{"project by ID", &domain.Server{DisplayName: "api", ProjectIDs: []string{"payments"}}, cfg, true},
+		{"project by server name", &domain.Server{DisplayName: "bastion"}, cfg, true},
+		{"project without flag", &domain.Server{DisplayName: "www", ProjectIDs: []string{"web"}}, cfg, false},
+		{"unrelated", &domain.Server{DisplayName: "laptop"}, cfg, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Required(tt.srv, tt.cfg))
+		})
+	}
+}
+
+func TestSettings_ProjectOverride(t *testing.T) {
+	cfg := testConfig()
+
+	s := Settings(&domain.Server{DisplayName: "api", ProjectIDs: []string{"payments"}}, cfg)
+	assert.Equal(t, "wg0", s.Interface)
+	assert.Empty(t, s.Canary)
+
+	s = Settings(&domain.Server{DisplayName: "bastion"}, cfg)
+	assert.Equal(t, "10.0.0.1:443", s.Canary)
+
+	assert.Equal(t, config.VPNConfig{}, Settings(&domain.Server{}, nil))
+}
+
+func TestFromConfig(t *testing.T) {
+	assert.Nil(t, FromConfig(config.VPNConfig{ConnectCommand: "vpn up"}))
+
+	c := FromConfig(config.VPNConfig{Canary: "10.0.0.1:443", Interface: "wg0", Route: "10.0.0.0/8", CheckCommand: "true", TimeoutSeconds: 7})
+	all, ok := c.(All)
+	require.True(t, ok)
+	require.Len(t, all, 4)
+	assert.IsType(t, &InterfaceChecker{}, all[0])
+	assert.IsType(t, &RouteChecker{}, all[1])
+	assert.Equal(t, 7, int(all[2].(*TCPChecker).Timeout.Seconds()))
+	assert.IsType(t, &CommandChecker{}, all[3])
+}
+
+func TestCheckerFor_Fallback(t *testing.T) {
+	// No checks configured: dial the server itself
+	c := CheckerFor(&domain.Server{Host: "10.1.2.3", Port: 2222}, &config.Config{})
+	tcp, ok := c.(*TCPChecker)
+	require.True(t, ok)
+	assert.Equal(t, "10.1.2.3:2222", tcp.Address)
+
+	c = CheckerFor(&domain.Server{Host: "10.1.2.3"}, nil)
+	assert.Equal(t, "10.1.2.3:22", c.(*TCPChecker).Address)
+
+	// Behind a jump host the target isn't directly reachable anyway
+	assert.Nil(t, CheckerFor(&domain.Server{Host: "10.1.2.3", Proxy: "bastion"}, nil))
+}
+
+func TestCheck(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	ctx := context.Background()
+	srv := &domain.Server{DisplayName: "db", VPNRequired: true}
+
+	up := &config.Config{VPN: config.VPNConfig{CheckCommand: "true"}}
+	assert.NoError(t, Check(ctx, srv, up))
+
+	down := &config.Config{VPN: config.VPNConfig{CheckCommand: "echo not connected; exit 1"}}
+	err := Check(ctx, srv, down)
+	require.Error(t, err)
+	assert.True(t, sherrors.Is(err, sherrors.ErrVPNNotConnected))
+	assert.Contains(t, err.Error(), "not connected")
+
+	// Servers that don't need the VPN are never checked
+	assert.NoError(t, Check(ctx, &domain.Server{DisplayName: "web"}, down))
+}
+
+func TestTCPChecker(t *testing.T) {
+	var dialed string
+	ok := &TCPChecker{
+		Address: "10.0.0.1:443",
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = address
+			client, server := net.Pipe()
+			_ = server.Close()
+			return client, nil
+		},
+	}
+	require.NoError(t, ok.Check(context.Background()))
+	assert.Equal(t, "10.0.0.1:443", dialed)
+
+	failing := &TCPChecker{
+		Address: "10.0.0.1:443",
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	err := failing.Check(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "10.0.0.1:443")
+}
+
+func TestTCPChecker_RealListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	assert.NoError(t, (&TCPChecker{Address: addr}).Check(context.Background()))
+
+	require.NoError(t, ln.Close())
+	assert.Error(t, (&TCPChecker{Address: addr}).Check(context.Background()))
+}
+
+func TestInterfaceChecker(t *testing.T) {
+	ifaces := func() ([]net.Interface, error) {
+		return []net.Interface{
+			{Name: "lo", Flags: net.FlagUp | net.FlagLoopback},
+			{Name: "wg0", Flags: net.FlagUp},
+			{Name: "tun1"},
+		}, nil
+	}
+
+	tests := []struct {
+		name    string
+		iface   string
+		wantErr string
+	}{
+		{"up", "wg0", ""},
+		{"down", "tun1", "is down"},
+		{"missing", "utun4", "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&InterfaceChecker{Name: tt.iface, Interfaces: ifaces}).Check(context.Background())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRouteChecker(t *testing.T) {
+	addrs := func() ([]net.Addr, error) {
+		_, loopback, _ := net.ParseCIDR("127.0.0.1/8")
+		_, lan, _ := net.ParseCIDR("192.168.1.10/24")
+		_, vpn, _ := net.ParseCIDR("10.8.0.2/16")
+		return []net.Addr{loopback, lan, vpn}, nil
+	}
+
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{"10.8.4.4", false},
+		{"10.8.0.0/16", false},
+		{"192.168.1.1", false},
+		{"10.9.0.1", true},
+		{"127.0.0.1", true}, // loopback never counts
+		{"not-an-ip", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			err := (&RouteChecker{Target: tt.target, Addrs: addrs}).Check(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpCommand(t *testing.T) {
+	assert.Nil(t, UpCommand(config.VPNConfig{}))
+
+	cmd := UpCommand(config.VPNConfig{ConnectCommand: "wg-quick up work"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, []string{"sh", "-c", "wg-quick up work"}, cmd.Args)
+}